@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"time"
 	"ppi-100-sis/internal/config"
@@ -121,12 +122,46 @@ func main() {
 		}
 	}
 
+	// Seed bell periods: 40-minute lessons, a break after jam ke-4, short Fridays
+	for _, unitID := range []uint{domain.UnitMTS, domain.UnitMA} {
+		for day := domain.Monday; day <= domain.Saturday; day++ {
+			lessons := 8
+			if day == domain.Friday {
+				lessons = 5
+			}
+			start := domain.Clock(7, 0)
+			for n := 1; n <= lessons; n++ {
+				if n == 5 {
+					rest := domain.Period{UnitID: unitID, Day: day, Label: "Istirahat", StartTime: start, EndTime: start + 20, IsBreak: true}
+					db.FirstOrCreate(&rest, domain.Period{UnitID: unitID, Day: day, StartTime: start})
+					start += 20
+				}
+				period := domain.Period{UnitID: unitID, Day: day, Number: n, Label: fmt.Sprintf("Jam ke-%d", n), StartTime: start, EndTime: start + 40}
+				db.FirstOrCreate(&period, domain.Period{UnitID: unitID, Day: day, StartTime: start})
+				start += 40
+			}
+		}
+	}
+
+	// Seed Rooms
+	rooms := []domain.Room{
+		{UnitID: domain.UnitMTS, Name: "Lab IPA MTs", Type: domain.RoomLab, Capacity: 32},
+		{UnitID: domain.UnitMTS, Name: "Aula MTs", Type: domain.RoomHall, Capacity: 200},
+		{UnitID: domain.UnitMA, Name: "Lab Komputer MA", Type: domain.RoomLab, Capacity: 36},
+		{UnitID: domain.UnitMA, Name: "Aula MA", Type: domain.RoomHall, Capacity: 250},
+	}
+	for _, r := range rooms {
+		if err := db.FirstOrCreate(&r, domain.Room{UnitID: r.UnitID, Name: r.Name}).Error; err != nil {
+			log.Printf("Failed to seed room %s: %v", r.Name, err)
+		}
+	}
+
 	// Seed Schedules for 7A
 	if len(createdTeachers) > 0 {
 		schedules := []domain.Schedule{
-			{ClassID: 1, SubjectID: 1, TeacherID: createdTeachers[0].ID, Day: "Monday", StartTime: "07:00", EndTime: "08:30"},
-			{ClassID: 1, SubjectID: 2, TeacherID: createdTeachers[1].ID, Day: "Monday", StartTime: "08:30", EndTime: "10:00"},
-			{ClassID: 1, SubjectID: 3, TeacherID: createdTeachers[0].ID, Day: "Tuesday", StartTime: "07:00", EndTime: "08:30"},
+			{ClassID: 1, SubjectID: 1, TeacherID: createdTeachers[0].ID, Day: domain.Monday, StartTime: domain.Clock(7, 0), EndTime: domain.Clock(8, 30)},
+			{ClassID: 1, SubjectID: 2, TeacherID: createdTeachers[1].ID, Day: domain.Monday, StartTime: domain.Clock(8, 30), EndTime: domain.Clock(10, 0)},
+			{ClassID: 1, SubjectID: 3, TeacherID: createdTeachers[0].ID, Day: domain.Tuesday, StartTime: domain.Clock(7, 0), EndTime: domain.Clock(8, 30)},
 		}
 		for _, sch := range schedules {
 			db.FirstOrCreate(&sch, domain.Schedule{ClassID: sch.ClassID, SubjectID: sch.SubjectID, Day: sch.Day, StartTime: sch.StartTime})
@@ -249,6 +284,9 @@ func main() {
 }
 
 func seedUser(db *gorm.DB, user domain.User) domain.User {
+	// Seeded passwords are shared defaults and must be changed on first login
+	user.MustChangePassword = true
+
 	var existingUser domain.User
 	if err := db.Where("email = ?", user.Email).First(&existingUser).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -260,6 +298,7 @@ func seedUser(db *gorm.DB, user domain.User) domain.User {
 	} else {
 		// Update password if user exists
 		existingUser.PasswordHash = user.PasswordHash
+		existingUser.MustChangePassword = true
 		if err := db.Save(&existingUser).Error; err != nil {
 			log.Printf("Failed to update password for %s: %v", user.Name, err)
 		}
@@ -60,6 +60,8 @@ func main() {
 			PasswordHash: hashedPassword,
 			RoleID:       role.ID,
 			UnitID:       1, // Default to Unit 1
+
+			MustChangePassword: true,
 		}
 
 		if err := db.Create(&user).Error; err != nil {
@@ -29,9 +29,9 @@ func main() {
 		ClassID:   5, // Test Class
 		SubjectID: 1, // Matematika
 		TeacherID: teacher.ID,
-		Day:       "Monday",
-		StartTime: "08:00",
-		EndTime:   "09:30",
+		Day:       domain.Monday,
+		StartTime: domain.Clock(8, 0),
+		EndTime:   domain.Clock(9, 30),
 	}
 
 	if err := db.Create(&schedule).Error; err != nil {
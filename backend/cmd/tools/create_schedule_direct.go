@@ -24,9 +24,9 @@ func main() {
 		ClassID:   6,
 		SubjectID: 5,
 		TeacherID: uuid.MustParse("d4793adb-f681-483b-9989-a7de248821ef"),
-		Day:       "Monday",
-		StartTime: "08:00",
-		EndTime:   "09:30",
+		Day:       domain.Monday,
+		StartTime: domain.Clock(8, 0),
+		EndTime:   domain.Clock(9, 30),
 	}
 
 	if err := db.Create(&schedule).Error; err != nil {
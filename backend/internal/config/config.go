@@ -1,7 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +18,57 @@ type Config struct {
 	DBName     string
 	DBPort     string
 	JWTSecret  string
+
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	AppURL           string // Frontend base URL used in emailed links
+	PasswordResetTTL time.Duration
+	MailDriver       string // "smtp" or "log"
+	MailLogPath      string
+	MailFrom         string
+	SMTPHost         string
+	SMTPPort         string
+	SMTPUsername     string
+	SMTPPassword     string
+
+	AllowSelfRegistration bool // Only Siswa and Orang Tua may ever self-register
+	InvitationTTL         time.Duration
+
+	LoginAttemptStore     string // "memory" or "postgres"
+	LoginMaxAttempts      int    // Per account, before lockout
+	LoginMaxAttemptsPerIP int
+	LoginAttemptWindow    time.Duration
+	LoginLockoutDuration  time.Duration
+
+	// Reverse proxies whose X-Forwarded-For is believed when resolving the
+	// client IP for login throttling and audit logs. Empty trusts none.
+	TrustedProxies []string
+
+	MFAIssuer        string
+	MFARequiredRoles []uint // Roles that must enroll in TOTP before using the app
+	MFAChallengeTTL  time.Duration
+
+	ImpersonationTTL time.Duration
+
+	PasswordMinLength     int
+	PasswordRequireUpper  bool
+	PasswordRequireLower  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+	PasswordDenylist      bool // Reject passwords from the embedded common-password list
+
+	SchoolLocation *time.Location // Wall clock for "what's on now" schedule queries
+
+	LessonMinutes   int // Length of one JP (jam pelajaran) in units without bell periods
+	TeachingLoadMax int // Weekly JP above which a teacher is flagged as overloaded
+
+	DefaultKKM     int    // Passing score of subjects without a configured KKM
+	RemedialPolicy string // "cap": a passed remedial counts as the KKM; "replace": its result replaces the score
+
+	SchoolName    string // Printed on the rapor header
+	SchoolAddress string
+	SchoolCity    string // Place in the rapor signature date line
 }
 
 func LoadConfig() (*Config, error) {
@@ -21,7 +76,7 @@ func LoadConfig() (*Config, error) {
 		// It's okay if .env file is not found, we might be using system env vars
 	}
 
-	return &Config{
+	cfg := &Config{
 		Port:       getEnv("PORT", "8080"),
 		DBHost:     getEnv("DB_HOST", "localhost"),
 		DBUser:     getEnv("DB_USER", "postgres"),
@@ -29,7 +84,61 @@ func LoadConfig() (*Config, error) {
 		DBName:     getEnv("DB_NAME", "ppi_sis"),
 		DBPort:     getEnv("DB_PORT", "5432"),
 		JWTSecret:  getEnv("JWT_SECRET", "secret"),
-	}, nil
+
+		AccessTokenTTL:  getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getEnvDuration("REFRESH_TOKEN_TTL", 7*24*time.Hour),
+
+		AppURL:           getEnv("APP_URL", "http://localhost:3000"),
+		PasswordResetTTL: getEnvDuration("PASSWORD_RESET_TTL", time.Hour),
+		MailDriver:       getEnv("MAIL_DRIVER", "log"),
+		MailLogPath:      getEnv("MAIL_LOG_PATH", ""),
+		MailFrom:         getEnv("MAIL_FROM", "no-reply@ppi100.sch.id"),
+		SMTPHost:         getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:         getEnv("SMTP_PORT", "587"),
+		SMTPUsername:     getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:     getEnv("SMTP_PASSWORD", ""),
+
+		AllowSelfRegistration: getEnvBool("ALLOW_SELF_REGISTRATION", false),
+		InvitationTTL:         getEnvDuration("INVITATION_TTL", 72*time.Hour),
+
+		LoginAttemptStore:     getEnv("LOGIN_ATTEMPT_STORE", "memory"),
+		LoginMaxAttempts:      getEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginMaxAttemptsPerIP: getEnvInt("LOGIN_MAX_ATTEMPTS_PER_IP", 20),
+		LoginAttemptWindow:    getEnvDuration("LOGIN_ATTEMPT_WINDOW", 15*time.Minute),
+		LoginLockoutDuration:  getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		MFAIssuer:        getEnv("MFA_ISSUER", "PPI 100 SIS"),
+		MFARequiredRoles: getEnvUintList("MFA_REQUIRED_ROLES"),
+		MFAChallengeTTL:  getEnvDuration("MFA_CHALLENGE_TTL", 5*time.Minute),
+
+		ImpersonationTTL: getEnvDuration("IMPERSONATION_TTL", 15*time.Minute),
+
+		PasswordMinLength:     getEnvInt("PASSWORD_MIN_LENGTH", 8),
+		PasswordRequireUpper:  getEnvBool("PASSWORD_REQUIRE_UPPER", false),
+		PasswordRequireLower:  getEnvBool("PASSWORD_REQUIRE_LOWER", true),
+		PasswordRequireDigit:  getEnvBool("PASSWORD_REQUIRE_DIGIT", true),
+		PasswordRequireSymbol: getEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+		PasswordDenylist:      getEnvBool("PASSWORD_DENYLIST", true),
+
+		SchoolLocation: getEnvLocation("SCHOOL_TIMEZONE", "Asia/Jakarta"),
+
+		LessonMinutes:   getEnvInt("LESSON_MINUTES", 40),
+		TeachingLoadMax: getEnvInt("TEACHING_LOAD_MAX", 40),
+
+		DefaultKKM:     getEnvInt("KKM_DEFAULT", 75),
+		RemedialPolicy: getEnv("REMEDIAL_POLICY", "cap"),
+
+		SchoolName:    getEnv("SCHOOL_NAME", "Pesantren Persis 100 Banjarsari"),
+		SchoolAddress: getEnv("SCHOOL_ADDRESS", "Jl. Raya Banjarsari No. 100, Kec. Banjarsari, Kab. Ciamis, Jawa Barat"),
+		SchoolCity:    getEnv("SCHOOL_CITY", "Banjarsari"),
+	}
+
+	if cfg.RemedialPolicy != "cap" && cfg.RemedialPolicy != "replace" {
+		return nil, fmt.Errorf("REMEDIAL_POLICY must be \"cap\" or \"replace\", got %q", cfg.RemedialPolicy)
+	}
+	return cfg, nil
 }
 
 func getEnv(key, fallback string) string {
@@ -38,3 +147,61 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return fallback
+}
+
+// getEnvList parses a comma-separated list such as "10.0.0.1,10.0.1.0/24".
+func getEnvList(key string) []string {
+	var list []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
+
+// getEnvUintList parses a comma-separated list such as "1,2,3".
+func getEnvUintList(key string) []uint {
+	var list []uint
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if n, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32); err == nil {
+			list = append(list, uint(n))
+		}
+	}
+	return list
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+// getEnvLocation loads a time zone, falling back to WIB (UTC+7) when the zone
+// database is unavailable.
+func getEnvLocation(key, fallback string) *time.Location {
+	if loc, err := time.LoadLocation(getEnv(key, fallback)); err == nil {
+		return loc
+	}
+	return time.FixedZone("WIB", 7*60*60)
+}
@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"errors"
 	"net/http"
+	"ppi-100-sis/internal/delivery/http/middleware"
 	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
 	"ppi-100-sis/internal/usecase"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,10 +17,11 @@ import (
 
 type AcademicHandler struct {
 	academicUsecase *usecase.AcademicUsecase
+	studentUsecase  *usecase.StudentUsecase
 }
 
-func NewAcademicHandler(academicUsecase *usecase.AcademicUsecase) *AcademicHandler {
-	return &AcademicHandler{academicUsecase: academicUsecase}
+func NewAcademicHandler(academicUsecase *usecase.AcademicUsecase, studentUsecase *usecase.StudentUsecase) *AcademicHandler {
+	return &AcademicHandler{academicUsecase: academicUsecase, studentUsecase: studentUsecase}
 }
 
 // Class Handlers
@@ -29,7 +35,7 @@ func (h *AcademicHandler) CreateClass(c *gin.Context) {
 		return
 	}
 
-	if err := h.academicUsecase.CreateClass(req.Name, req.UnitID); err != nil {
+	if err := h.academicUsecase.CreateClass(req.Name, targetUnitID(c, req.UnitID)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -39,7 +45,7 @@ func (h *AcademicHandler) CreateClass(c *gin.Context) {
 
 func (h *AcademicHandler) DeleteClass(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	if err := h.academicUsecase.DeleteClass(uint(id)); err != nil {
+	if err := h.academicUsecase.DeleteClass(uint(id), scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -48,8 +54,7 @@ func (h *AcademicHandler) DeleteClass(c *gin.Context) {
 }
 
 func (h *AcademicHandler) GetAllClasses(c *gin.Context) {
-	unitID, _ := strconv.Atoi(c.Query("unit_id"))
-	classes, err := h.academicUsecase.GetAllClasses(uint(unitID))
+	classes, err := h.academicUsecase.GetAllClasses(scopeUnitID(c), termFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -64,7 +69,7 @@ func (h *AcademicHandler) GetHomeroomClass(c *gin.Context) {
 		return
 	}
 
-	class, err := h.academicUsecase.GetHomeroomClass(teacherID)
+	class, err := h.academicUsecase.GetHomeroomClass(teacherID, scopeUnitID(c), termFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -96,7 +101,7 @@ func (h *AcademicHandler) GetStudentReportCard(c *gin.Context) {
 			return
 		}
 
-		reportCard, err := h.academicUsecase.GetStudentReportCardByUserID(userID)
+		reportCard, err := h.academicUsecase.GetStudentReportCardByUserID(userID, termFilter(c))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -110,8 +115,15 @@ func (h *AcademicHandler) GetStudentReportCard(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid student_id"})
 		return
 	}
+	if !studentScope(c, h.studentUsecase, studentID) {
+		return
+	}
 
-	reportCard, err := h.academicUsecase.GetStudentReportCard(id)
+	reportCard, err := h.academicUsecase.GetStudentReportCard(id, 0, scopeUnitID(c), termFilter(c))
+	if errors.Is(err, usecase.ErrNotEnrolled) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -130,7 +142,7 @@ func (h *AcademicHandler) CreateSubject(c *gin.Context) {
 		return
 	}
 
-	if err := h.academicUsecase.CreateSubject(req.Name, req.UnitID); err != nil {
+	if err := h.academicUsecase.CreateSubject(req.Name, targetUnitID(c, req.UnitID)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -139,8 +151,7 @@ func (h *AcademicHandler) CreateSubject(c *gin.Context) {
 }
 
 func (h *AcademicHandler) GetAllSubjects(c *gin.Context) {
-	unitID, _ := strconv.Atoi(c.Query("unit_id"))
-	subjects, err := h.academicUsecase.GetAllSubjects(uint(unitID))
+	subjects, err := h.academicUsecase.GetAllSubjects(scopeUnitID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -156,8 +167,8 @@ func (h *AcademicHandler) CreateSchedule(c *gin.Context) {
 		return
 	}
 
-	if err := h.academicUsecase.CreateSchedule(req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.academicUsecase.CreateSchedule(req, scopeUnitID(c)); err != nil {
+		respondScheduleError(c, err)
 		return
 	}
 
@@ -165,7 +176,41 @@ func (h *AcademicHandler) CreateSchedule(c *gin.Context) {
 }
 
 func (h *AcademicHandler) GetAllSchedules(c *gin.Context) {
-	unitID, _ := strconv.Atoi(c.Query("unit_id"))
+	// ?room_id= lists a room's weekly occupancy instead of a class or teacher
+	roomID, _ := strconv.Atoi(c.Query("room_id"))
+	filter := postgres.ScheduleFilter{RoomID: uint(roomID)}
+	if filter.RoomID == 0 {
+		filter.ClassID, filter.TeacherID = h.scheduleOwner(c)
+	}
+
+	schedules, err := h.academicUsecase.GetAllSchedules(scopeUnitID(c), filter, termFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, schedules)
+}
+
+// GetCurrentSchedule returns the period and lesson running now for the
+// requested class or teacher, defaulting to the caller's own.
+func (h *AcademicHandler) GetCurrentSchedule(c *gin.Context) {
+	classID, teacherID := h.scheduleOwner(c)
+	if classID == 0 && teacherID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "class_id or teacher_id is required"})
+		return
+	}
+
+	current, err := h.academicUsecase.GetCurrentSchedule(scopeUnitID(c), classID, teacherID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, current)
+}
+
+// scheduleOwner reads class_id/teacher_id from the query, falling back to the
+// caller's class when a student or own id when a teacher.
+func (h *AcademicHandler) scheduleOwner(c *gin.Context) (uint, string) {
 	classID, _ := strconv.Atoi(c.Query("class_id"))
 	teacherID := c.Query("teacher_id")
 
@@ -194,13 +239,7 @@ func (h *AcademicHandler) GetAllSchedules(c *gin.Context) {
 			}
 		}
 	}
-
-	schedules, err := h.academicUsecase.GetAllSchedules(uint(unitID), uint(classID), teacherID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, schedules)
+	return uint(classID), teacherID
 }
 
 func (h *AcademicHandler) UpdateClass(c *gin.Context) {
@@ -213,7 +252,7 @@ func (h *AcademicHandler) UpdateClass(c *gin.Context) {
 		return
 	}
 
-	if err := h.academicUsecase.UpdateClass(uint(id), req.Name); err != nil {
+	if err := h.academicUsecase.UpdateClass(uint(id), req.Name, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -231,7 +270,7 @@ func (h *AcademicHandler) UpdateSubject(c *gin.Context) {
 		return
 	}
 
-	if err := h.academicUsecase.UpdateSubject(uint(id), req.Name); err != nil {
+	if err := h.academicUsecase.UpdateSubject(uint(id), req.Name, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -241,7 +280,7 @@ func (h *AcademicHandler) UpdateSubject(c *gin.Context) {
 
 func (h *AcademicHandler) DeleteSubject(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	if err := h.academicUsecase.DeleteSubject(uint(id)); err != nil {
+	if err := h.academicUsecase.DeleteSubject(uint(id), scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -249,6 +288,76 @@ func (h *AcademicHandler) DeleteSubject(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Subject deleted successfully"})
 }
 
+// Learning objective Handlers
+type learningObjectiveRequest struct {
+	Code        string `json:"code"`
+	Description string `json:"description" binding:"required"`
+	Position    int    `json:"position"`
+}
+
+func (h *AcademicHandler) GetLearningObjectives(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	objectives, err := h.academicUsecase.GetLearningObjectives(uint(id), scopeUnitID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, objectives)
+}
+
+func (h *AcademicHandler) CreateLearningObjective(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req learningObjectiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermScoreManage)
+	if !ok {
+		return
+	}
+
+	objective, err := h.academicUsecase.CreateLearningObjective(uint(id), req.Code, req.Description, req.Position, teacherID, scopeUnitID(c))
+	if err != nil {
+		respondScoreError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, objective)
+}
+
+func (h *AcademicHandler) UpdateLearningObjective(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req learningObjectiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermScoreManage)
+	if !ok {
+		return
+	}
+
+	objective, err := h.academicUsecase.UpdateLearningObjective(uint(id), req.Code, req.Description, req.Position, teacherID, scopeUnitID(c))
+	if err != nil {
+		respondScoreError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, objective)
+}
+
+func (h *AcademicHandler) DeleteLearningObjective(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermScoreManage)
+	if !ok {
+		return
+	}
+	if err := h.academicUsecase.DeleteLearningObjective(uint(id), teacherID, scopeUnitID(c)); err != nil {
+		respondScoreError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Learning objective deleted successfully"})
+}
+
 func (h *AcademicHandler) UpdateSchedule(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 	var req domain.Schedule
@@ -257,17 +366,96 @@ func (h *AcademicHandler) UpdateSchedule(c *gin.Context) {
 		return
 	}
 
-	if err := h.academicUsecase.UpdateSchedule(uint(id), req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.academicUsecase.UpdateSchedule(uint(id), req, scopeUnitID(c)); err != nil {
+		respondScheduleError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Schedule updated successfully"})
 }
 
+type TimetableRequest struct {
+	DryRun bool `json:"dry_run"`
+	// Periods may be left out to use the unit's stored periods
+	Periods []struct {
+		Day       domain.Weekday   `json:"day" binding:"required"`
+		StartTime domain.TimeOfDay `json:"start_time"`
+		EndTime   domain.TimeOfDay `json:"end_time"`
+	} `json:"periods" binding:"dive"`
+	Requirements []struct {
+		ClassID      uint      `json:"class_id" binding:"required"`
+		SubjectID    uint      `json:"subject_id" binding:"required"`
+		TeacherID    uuid.UUID `json:"teacher_id" binding:"required"`
+		HoursPerWeek int       `json:"hours_per_week" binding:"required,min=1"`
+	} `json:"requirements" binding:"required,min=1,dive"`
+	Unavailable []struct {
+		TeacherID uuid.UUID        `json:"teacher_id" binding:"required"`
+		Day       domain.Weekday   `json:"day" binding:"required"`
+		StartTime domain.TimeOfDay `json:"start_time"`
+		EndTime   domain.TimeOfDay `json:"end_time"`
+	} `json:"unavailable" binding:"dive"`
+	MaxTeacherHours      int  `json:"max_teacher_hours"`
+	MaxDailySubjectHours int  `json:"max_daily_subject_hours"`
+	AllowPartial         bool `json:"allow_partial"` // Save even if some lessons do not fit
+}
+
+// GenerateTimetable proposes a timetable; without dry_run it is saved.
+func (h *AcademicHandler) GenerateTimetable(c *gin.Context) {
+	var req TimetableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	input := usecase.TimetableInput{MaxTeacherHours: req.MaxTeacherHours, MaxDailySubjectHours: req.MaxDailySubjectHours, AllowPartial: req.AllowPartial}
+	for _, p := range req.Periods {
+		input.Periods = append(input.Periods, usecase.TimetablePeriod{Day: p.Day, StartTime: p.StartTime, EndTime: p.EndTime})
+	}
+	for _, r := range req.Requirements {
+		input.Requirements = append(input.Requirements, usecase.TimetableRequirement{ClassID: r.ClassID, SubjectID: r.SubjectID, TeacherID: r.TeacherID, HoursPerWeek: r.HoursPerWeek})
+	}
+	for _, u := range req.Unavailable {
+		input.Unavailable = append(input.Unavailable, usecase.TeacherUnavailability{TeacherID: u.TeacherID, Day: u.Day, StartTime: u.StartTime, EndTime: u.EndTime})
+	}
+
+	result, err := h.academicUsecase.GenerateTimetable(input, req.DryRun, scopeUnitID(c))
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+	if req.DryRun {
+		middleware.SkipAudit(c)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetScheduleConflicts lists overlapping schedules already stored.
+func (h *AcademicHandler) GetScheduleConflicts(c *gin.Context) {
+	conflicts, err := h.academicUsecase.GetScheduleConflicts(scopeUnitID(c), termFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, conflicts)
+}
+
+// respondScheduleError answers 409 with the clashing rows for conflicts and
+// 400 for malformed days or times.
+func respondScheduleError(c *gin.Context, err error) {
+	var conflict *usecase.ScheduleConflictError
+	switch {
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, gin.H{"error": conflict.Error(), "conflicts": conflict.Conflicts})
+	case errors.Is(err, usecase.ErrInvalidSchedule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 func (h *AcademicHandler) DeleteSchedule(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	if err := h.academicUsecase.DeleteSchedule(uint(id)); err != nil {
+	if err := h.academicUsecase.DeleteSchedule(uint(id), scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -275,3 +463,857 @@ func (h *AcademicHandler) DeleteSchedule(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Schedule deleted successfully"})
 }
 
+// Period Handlers
+type PeriodRequest struct {
+	Day       domain.Weekday   `json:"day" binding:"required"`
+	Number    int              `json:"number"`
+	Label     string           `json:"label"`
+	StartTime domain.TimeOfDay `json:"start_time"`
+	EndTime   domain.TimeOfDay `json:"end_time"`
+	IsBreak   bool             `json:"is_break"`
+	UnitID    uint             `json:"unit_id"`
+}
+
+func (r PeriodRequest) period() domain.Period {
+	return domain.Period{Day: r.Day, Number: r.Number, Label: r.Label, StartTime: r.StartTime, EndTime: r.EndTime, IsBreak: r.IsBreak}
+}
+
+func (h *AcademicHandler) CreatePeriod(c *gin.Context) {
+	var req PeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	unitID := targetUnitID(c, req.UnitID)
+	if unitID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unit_id is required"})
+		return
+	}
+
+	period, err := h.academicUsecase.CreatePeriod(req.period(), unitID)
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, period)
+}
+
+func (h *AcademicHandler) GetPeriods(c *gin.Context) {
+	periods, err := h.academicUsecase.GetPeriods(scopeUnitID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, periods)
+}
+
+func (h *AcademicHandler) UpdatePeriod(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req PeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.academicUsecase.UpdatePeriod(uint(id), req.period(), scopeUnitID(c)); err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Period updated successfully"})
+}
+
+func (h *AcademicHandler) DeletePeriod(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.academicUsecase.DeletePeriod(uint(id), scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Period deleted successfully"})
+}
+
+// Substitution Handlers
+type SubstitutionRequest struct {
+	ScheduleID uint      `json:"schedule_id" binding:"required"`
+	Date       string    `json:"date" binding:"required"`
+	TeacherID  uuid.UUID `json:"teacher_id" binding:"required"`
+	Reason     string    `json:"reason"`
+}
+
+func (h *AcademicHandler) CreateSubstitution(c *gin.Context) {
+	var req SubstitutionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format (YYYY-MM-DD)"})
+		return
+	}
+
+	substitution, err := h.academicUsecase.CreateSubstitution(req.ScheduleID, date, req.TeacherID, req.Reason, c.MustGet("userID").(uuid.UUID), scopeUnitID(c))
+	if err != nil {
+		respondSubstitutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, substitution)
+}
+
+// GetSubstitutions lists substitutions from ?from= to ?to= (default today
+// only). Teachers without write access only see the lessons they cover.
+func (h *AcademicHandler) GetSubstitutions(c *gin.Context) {
+	from, err := parseOptionalDate(c.Query("from"), h.academicUsecase.Today())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := parseOptionalDate(c.Query("to"), from)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	teacherID := c.Query("teacher_id")
+	if !middleware.HasPermission(c.GetUint("roleID"), middleware.PermSubstitutionWrite) {
+		teacherID, err = h.academicUsecase.GetTeacherIDByUserID(c.MustGet("userID").(uuid.UUID).String())
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	substitutions, err := h.academicUsecase.GetSubstitutions(scopeUnitID(c), teacherID, from, to)
+	if err != nil {
+		respondSubstitutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, substitutions)
+}
+
+// SuggestSubstitutes lists teachers free during ?schedule_id= on ?date=.
+func (h *AcademicHandler) SuggestSubstitutes(c *gin.Context) {
+	scheduleID, _ := strconv.Atoi(c.Query("schedule_id"))
+	if scheduleID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "schedule_id is required"})
+		return
+	}
+	date, err := parseOptionalDate(c.Query("date"), h.academicUsecase.Today())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	candidates, err := h.academicUsecase.SuggestSubstitutes(uint(scheduleID), date, scopeUnitID(c))
+	if err != nil {
+		respondSubstitutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, candidates)
+}
+
+func (h *AcademicHandler) DeleteSubstitution(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.academicUsecase.DeleteSubstitution(uint(id), scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Substitution deleted successfully"})
+}
+
+func respondSubstitutionError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrInvalidSubstitution) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	respondScheduleError(c, err)
+}
+
+// Room Handlers
+type RoomRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Type     string `json:"type"`
+	Capacity int    `json:"capacity"`
+	UnitID   uint   `json:"unit_id"`
+}
+
+func (h *AcademicHandler) CreateRoom(c *gin.Context) {
+	var req RoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	unitID := targetUnitID(c, req.UnitID)
+	if unitID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unit_id is required"})
+		return
+	}
+
+	room, err := h.academicUsecase.CreateRoom(domain.Room{Name: req.Name, Type: req.Type, Capacity: req.Capacity}, unitID)
+	if err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, room)
+}
+
+func (h *AcademicHandler) GetRooms(c *gin.Context) {
+	rooms, err := h.academicUsecase.GetRooms(scopeUnitID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rooms)
+}
+
+func (h *AcademicHandler) UpdateRoom(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req RoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.academicUsecase.UpdateRoom(uint(id), domain.Room{Name: req.Name, Type: req.Type, Capacity: req.Capacity}, scopeUnitID(c)); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Room updated successfully"})
+}
+
+func (h *AcademicHandler) DeleteRoom(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.academicUsecase.DeleteRoom(uint(id), scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Room deleted successfully"})
+}
+
+// GetRoomOccupancy shows lessons and bookings per room on ?date= (default
+// today), optionally for one ?room_id=.
+func (h *AcademicHandler) GetRoomOccupancy(c *gin.Context) {
+	date, err := parseOptionalDate(c.Query("date"), h.academicUsecase.Today())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	roomID, _ := strconv.Atoi(c.Query("room_id"))
+
+	occupancy, err := h.academicUsecase.GetRoomOccupancy(scopeUnitID(c), uint(roomID), date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, occupancy)
+}
+
+type RoomBookingRequest struct {
+	RoomID    uint             `json:"room_id" binding:"required"`
+	Title     string           `json:"title" binding:"required"`
+	Notes     string           `json:"notes"`
+	Date      string           `json:"date" binding:"required"`
+	StartTime domain.TimeOfDay `json:"start_time"`
+	EndTime   domain.TimeOfDay `json:"end_time"`
+}
+
+func (h *AcademicHandler) BookRoom(c *gin.Context) {
+	var req RoomBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format (YYYY-MM-DD)"})
+		return
+	}
+
+	booking := domain.RoomBooking{RoomID: req.RoomID, Title: req.Title, Notes: req.Notes, Date: date, StartTime: req.StartTime, EndTime: req.EndTime}
+	created, err := h.academicUsecase.BookRoom(booking, c.MustGet("userID").(uuid.UUID), scopeUnitID(c))
+	if err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetRoomBookings lists bookings from ?from= to ?to= (default the next 30
+// days), optionally for one ?room_id=.
+func (h *AcademicHandler) GetRoomBookings(c *gin.Context) {
+	from, err := parseOptionalDate(c.Query("from"), h.academicUsecase.Today())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := parseOptionalDate(c.Query("to"), from.AddDate(0, 0, 30))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	roomID, _ := strconv.Atoi(c.Query("room_id"))
+
+	bookings, err := h.academicUsecase.GetRoomBookings(scopeUnitID(c), uint(roomID), from, to)
+	if err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, bookings)
+}
+
+func (h *AcademicHandler) CancelRoomBooking(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	manageAll := middleware.HasPermission(c.GetUint("roleID"), middleware.PermRoomWrite)
+	if err := h.academicUsecase.CancelRoomBooking(uint(id), c.MustGet("userID").(uuid.UUID), manageAll, scopeUnitID(c)); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Booking cancelled successfully"})
+}
+
+// respondRoomError extends respondScheduleError with room validation,
+// booking clashes and booking ownership.
+func respondRoomError(c *gin.Context, err error) {
+	var conflict *usecase.RoomConflictError
+	switch {
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, gin.H{"error": conflict.Error(), "schedules": conflict.Schedules, "bookings": conflict.Bookings})
+	case errors.Is(err, usecase.ErrInvalidRoom), errors.Is(err, usecase.ErrBookingRangeTooBig):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrBookingNotOwned):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		respondScheduleError(c, err)
+	}
+}
+
+func parseOptionalDate(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.New("Invalid date format (YYYY-MM-DD)")
+	}
+	return date, nil
+}
+
+// Calendar Handlers
+type CalendarEventRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	Type        string `json:"type" binding:"required"`
+	StartDate   string `json:"start_date" binding:"required"`
+	EndDate     string `json:"end_date"`     // Defaults to start_date
+	NonTeaching *bool  `json:"non_teaching"` // Defaults to true for holidays
+	UnitID      uint   `json:"unit_id"`
+}
+
+func (r CalendarEventRequest) event() (domain.CalendarEvent, error) {
+	if r.EndDate == "" {
+		r.EndDate = r.StartDate
+	}
+	startDate, endDate, err := parseDateRange(r.StartDate, r.EndDate)
+	if err != nil {
+		return domain.CalendarEvent{}, err
+	}
+	nonTeaching := r.Type == domain.CalendarHoliday
+	if r.NonTeaching != nil {
+		nonTeaching = *r.NonTeaching
+	}
+	return domain.CalendarEvent{
+		Title:       r.Title,
+		Description: r.Description,
+		Type:        r.Type,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		NonTeaching: nonTeaching,
+	}, nil
+}
+
+func (h *AcademicHandler) CreateCalendarEvent(c *gin.Context) {
+	var req CalendarEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	event, err := req.event()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	unitID := targetUnitID(c, req.UnitID)
+	if unitID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unit_id is required"})
+		return
+	}
+
+	created, err := h.academicUsecase.CreateCalendarEvent(event, unitID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetCalendarEvents lists events overlapping the optional from/to dates.
+func (h *AcademicHandler) GetCalendarEvents(c *gin.Context) {
+	from, err := parseOptionalDate(c.Query("from"), time.Time{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := parseOptionalDate(c.Query("to"), time.Time{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := h.academicUsecase.GetCalendarEvents(scopeUnitID(c), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+func (h *AcademicHandler) UpdateCalendarEvent(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req CalendarEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	event, err := req.event()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.academicUsecase.UpdateCalendarEvent(uint(id), event, scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar event updated successfully"})
+}
+
+func (h *AcademicHandler) DeleteCalendarEvent(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.academicUsecase.DeleteCalendarEvent(uint(id), scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar event deleted successfully"})
+}
+
+// GetCalendarFeed serves a unit's calendar as a public iCalendar feed.
+func (h *AcademicHandler) GetCalendarFeed(c *gin.Context) {
+	unitID, _ := strconv.ParseUint(c.Query("unit_id"), 10, 0)
+	if uint(unitID) != domain.UnitMTS && uint(unitID) != domain.UnitMA {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unit_id is required"})
+		return
+	}
+
+	feed, err := h.academicUsecase.CalendarFeed(uint(unitID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="kalender.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
+
+// Workload Handlers
+func (h *AcademicHandler) GetTeacherWorkload(c *gin.Context) {
+	report, err := h.academicUsecase.GetTeacherWorkload(scopeUnitID(c), termFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+var workloadStatusLabels = map[string]string{
+	usecase.WorkloadUnder:   "Kurang dari minimum",
+	usecase.WorkloadOK:      "Sesuai",
+	usecase.WorkloadOver:    "Melebihi maksimum",
+	usecase.WorkloadPartial: "Sebagian (guru unit lain)",
+}
+
+// ExportTeacherWorkload writes the workload report as CSV, one row per
+// teacher and subject.
+func (h *AcademicHandler) ExportTeacherWorkload(c *gin.Context) {
+	report, err := h.academicUsecase.GetTeacherWorkload(scopeUnitID(c), termFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="beban-mengajar.csv"`)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"Guru", "NIP", "Mata Pelajaran", "Pertemuan", "JP Mapel", "Total JP", "Status"})
+	for _, load := range report.Teachers {
+		row := []string{load.Teacher.User.Name, load.Teacher.NIP, "", "0", "0", strconv.Itoa(load.JP), workloadStatusLabels[load.Status]}
+		if len(load.Subjects) == 0 {
+			w.Write(row)
+			continue
+		}
+		for _, subject := range load.Subjects {
+			row[2], row[3], row[4] = subject.Subject.Name, strconv.Itoa(subject.Lessons), strconv.Itoa(subject.JP)
+			w.Write(row)
+		}
+	}
+	w.Flush()
+}
+
+// Academic Year Handlers
+type AcademicYearRequest struct {
+	Name      string `json:"name" binding:"required"` // e.g. 2025/2026
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+	UnitID    uint   `json:"unit_id"`
+}
+
+func (h *AcademicHandler) CreateAcademicYear(c *gin.Context) {
+	var req AcademicYearRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	year, err := h.academicUsecase.CreateAcademicYear(req.Name, startDate, endDate, targetUnitID(c, req.UnitID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, year)
+}
+
+func (h *AcademicHandler) GetAcademicYears(c *gin.Context) {
+	years, err := h.academicUsecase.GetAcademicYears(scopeUnitID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, years)
+}
+
+func (h *AcademicHandler) UpdateAcademicYear(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req AcademicYearRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.academicUsecase.UpdateAcademicYear(uint(id), req.Name, startDate, endDate, scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Academic year updated successfully"})
+}
+
+func (h *AcademicHandler) DeleteAcademicYear(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.academicUsecase.DeleteAcademicYear(uint(id), scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Academic year deleted successfully"})
+}
+
+// Semester Handlers
+type SemesterRequest struct {
+	AcademicYearID uint   `json:"academic_year_id"`        // Only read on create
+	Name           string `json:"name" binding:"required"` // Ganjil, Genap
+	StartDate      string `json:"start_date" binding:"required"`
+	EndDate        string `json:"end_date" binding:"required"`
+}
+
+func (h *AcademicHandler) CreateSemester(c *gin.Context) {
+	var req SemesterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	semester, err := h.academicUsecase.CreateSemester(req.AcademicYearID, req.Name, startDate, endDate, scopeUnitID(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, semester)
+}
+
+func (h *AcademicHandler) UpdateSemester(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req SemesterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.academicUsecase.UpdateSemester(uint(id), req.Name, startDate, endDate, scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Semester updated successfully"})
+}
+
+func (h *AcademicHandler) DeleteSemester(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.academicUsecase.DeleteSemester(uint(id), scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Semester deleted successfully"})
+}
+
+// ActivateSemester makes the semester, and its year, the active term of its unit.
+func (h *AcademicHandler) ActivateSemester(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.academicUsecase.ActivateSemester(uint(id), scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Semester activated successfully"})
+}
+
+func (h *AcademicHandler) GetActiveSemester(c *gin.Context) {
+	semester, err := h.academicUsecase.GetActiveSemester(scopeUnitID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No active semester"})
+		return
+	}
+	c.JSON(http.StatusOK, semester)
+}
+
+func parseDateRange(start, end string) (time.Time, time.Time, error) {
+	startDate, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Invalid date format (YYYY-MM-DD)")
+	}
+	endDate, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Invalid date format (YYYY-MM-DD)")
+	}
+	return startDate, endDate, nil
+}
+
+// Gradebook Handlers
+func (h *AcademicHandler) GetGradeWeights(c *gin.Context) {
+	weights, err := h.academicUsecase.GetGradeWeights(scopeUnitID(c), termFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, weights)
+}
+
+func (h *AcademicHandler) SetGradeWeights(c *gin.Context) {
+	var req struct {
+		SemesterID uint               `json:"semester_id" binding:"required"`
+		Weights    map[string]float64 `json:"weights" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.academicUsecase.SetGradeWeights(scopeUnitID(c), req.SemesterID, req.Weights); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Grade weights saved successfully"})
+}
+
+func respondScoreError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrNotSubjectTeacher):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidScore), errors.Is(err, usecase.ErrInvalidAttempt), errors.Is(err, usecase.ErrInvalidObjective):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *AcademicHandler) GetScores(c *gin.Context) {
+	classID, _ := strconv.Atoi(c.Query("class_id"))
+	subjectID, _ := strconv.Atoi(c.Query("subject_id"))
+	if classID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "class_id is required"})
+		return
+	}
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermScoreManage)
+	if !ok {
+		return
+	}
+
+	scores, err := h.academicUsecase.GetScores(uint(classID), uint(subjectID), teacherID, scopeUnitID(c), termFilter(c))
+	if err != nil {
+		respondScoreError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, scores)
+}
+
+func (h *AcademicHandler) RecordScores(c *gin.Context) {
+	var req struct {
+		ClassID   uint                 `json:"class_id" binding:"required"`
+		SubjectID uint                 `json:"subject_id" binding:"required"`
+		Component string               `json:"component" binding:"required"`
+		Title     string               `json:"title" binding:"required"`
+		Scores    []usecase.ScoreEntry `json:"scores" binding:"required,dive"`
+		// Learning objectives the assessment covers
+		LearningObjectiveIDs []uint `json:"learning_objective_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermScoreManage)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err := h.academicUsecase.RecordScores(req.ClassID, req.SubjectID, req.Component, req.Title, req.LearningObjectiveIDs, req.Scores, userID, teacherID, scopeUnitID(c)); err != nil {
+		respondScoreError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Scores saved successfully"})
+}
+
+func (h *AcademicHandler) DeleteScore(c *gin.Context) {
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermScoreManage)
+	if !ok {
+		return
+	}
+	if err := h.academicUsecase.DeleteScore(c.Param("id"), teacherID, scopeUnitID(c)); err != nil {
+		respondScoreError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Score deleted successfully"})
+}
+
+// KKM and remedial Handlers
+func (h *AcademicHandler) GetKKMs(c *gin.Context) {
+	kkms, err := h.academicUsecase.GetKKMs(scopeUnitID(c), termFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, kkms)
+}
+
+func (h *AcademicHandler) SetKKM(c *gin.Context) {
+	var req struct {
+		SubjectID  uint    `json:"subject_id" binding:"required"`
+		ClassID    uint    `json:"class_id"` // 0 for every class
+		SemesterID uint    `json:"semester_id" binding:"required"`
+		Value      float64 `json:"value" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	kkm, err := h.academicUsecase.SetKKM(req.SubjectID, req.ClassID, req.SemesterID, req.Value, scopeUnitID(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, kkm)
+}
+
+func (h *AcademicHandler) DeleteKKM(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.academicUsecase.DeleteKKM(uint(id), scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "KKM deleted successfully"})
+}
+
+func (h *AcademicHandler) RecordScoreAttempt(c *gin.Context) {
+	var req struct {
+		Kind  string  `json:"kind" binding:"required"`
+		Value float64 `json:"value"`
+		Note  string  `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermScoreManage)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	attempt, err := h.academicUsecase.RecordScoreAttempt(c.Param("id"), req.Kind, req.Value, req.Note, userID, teacherID, scopeUnitID(c))
+	if err != nil {
+		respondScoreError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, attempt)
+}
+
+func (h *AcademicHandler) DeleteScoreAttempt(c *gin.Context) {
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermScoreManage)
+	if !ok {
+		return
+	}
+	if err := h.academicUsecase.DeleteScoreAttempt(c.Param("id"), teacherID, scopeUnitID(c)); err != nil {
+		respondScoreError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Attempt deleted successfully"})
+}
+
+// GetRemedialList lists students with scores below the KKM. Teachers get
+// their own classes and subjects; admins may narrow with ?teacher_id=.
+func (h *AcademicHandler) GetRemedialList(c *gin.Context) {
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermScoreManage)
+	if !ok {
+		return
+	}
+	if middleware.HasPermission(c.GetUint("roleID"), middleware.PermScoreManage) {
+		teacherID = c.Query("teacher_id")
+	}
+
+	needs, err := h.academicUsecase.GetRemedialList(teacherID, scopeUnitID(c), termFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, needs)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"ppi-100-sis/internal/repository/postgres"
+	"ppi-100-sis/internal/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditUsecase *usecase.AuditUsecase
+}
+
+func NewAuditHandler(auditUsecase *usecase.AuditUsecase) *AuditHandler {
+	return &AuditHandler{auditUsecase: auditUsecase}
+}
+
+// GET /admin/audit-logs?actor_id=&impersonator_id=&action=&entity_type=&entity_id=&from=&to=&page=&limit=
+// from and to accept RFC 3339 timestamps or YYYY-MM-DD dates; to is exclusive.
+func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	filter := postgres.AuditLogFilter{
+		UnitID:         scopeUnitID(c),
+		ActorID:        c.Query("actor_id"),
+		ImpersonatorID: c.Query("impersonator_id"),
+		Action:         c.Query("action"),
+		EntityType:     c.Query("entity_type"),
+		EntityID:       c.Query("entity_id"),
+		Limit:          limit,
+		Offset:         (page - 1) * limit,
+	}
+
+	var err error
+	if filter.From, err = parseAuditTime(c.Query("from")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+		return
+	}
+	if filter.To, err = parseAuditTime(c.Query("to")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+		return
+	}
+
+	logs, total, err := h.auditUsecase.GetLogs(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  logs,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"ppi-100-sis/internal/ratelimit"
 	"ppi-100-sis/internal/usecase"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,7 +23,7 @@ func NewAuthHandler(authUsecase *usecase.AuthUsecase) *AuthHandler {
 type RegisterRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 	RoleID   uint   `json:"role_id" binding:"required"`
 	UnitID   uint   `json:"unit_id" binding:"required"`
 }
@@ -32,6 +36,10 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	if err := h.authUsecase.Register(req.Name, req.Email, req.Password, req.RoleID, req.UnitID); err != nil {
+		if errors.Is(err, usecase.ErrRegistrationClosed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -51,13 +59,154 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authUsecase.Login(req.Email, req.Password)
+	tokens, err := h.authUsecase.Login(req.Email, req.Password, c.ClientIP())
+	var throttled *ratelimit.ThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": throttled.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP or recovery code
+}
+
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var req LoginMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.authUsecase.VerifyMFA(req.MFAToken, req.Code, c.ClientIP())
+	var throttled *ratelimit.ThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": throttled.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.authUsecase.Refresh(req.RefreshToken)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, tokens)
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+	AllDevices   bool   `json:"all_devices"`
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authUsecase.Logout(req.RefreshToken, req.AllDevices); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authUsecase.ForgotPassword(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send reset email"})
+		return
+	}
+
+	// Same answer whether or not the email is registered
+	c.JSON(http.StatusOK, gin.H{"message": "If the email is registered, a reset link has been sent"})
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authUsecase.ResetPassword(req.Token, req.NewPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+// POST /admin/users/:id/unlock
+func (h *AuthHandler) UnlockUser(c *gin.Context) {
+	if err := h.authUsecase.UnlockUser(c.Param("id"), scopeUnitID(c), c.GetUint("roleID")); err != nil {
+		respondUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User unlocked successfully"})
+}
+
+// POST /admin/users/:id/2fa/reset
+func (h *AuthHandler) ResetMFA(c *gin.Context) {
+	if err := h.authUsecase.ResetMFA(c.Param("id"), scopeUnitID(c), c.GetUint("roleID")); err != nil {
+		respondUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication reset successfully"})
+}
+
+// POST /admin/impersonate/:user_id
+func (h *AuthHandler) Impersonate(c *gin.Context) {
+	result, err := h.authUsecase.Impersonate(c.MustGet("userID").(uuid.UUID), c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
 }
 
 func (h *AuthHandler) GetProfile(c *gin.Context) {
@@ -99,7 +248,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	if err := h.authUsecase.UpdateProfile(userID.(string), req.Name, req.Email); err != nil {
+	if err := h.authUsecase.UpdateProfile(userID.(uuid.UUID).String(), req.Name, req.Email); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -109,7 +258,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=6"`
+	NewPassword string `json:"new_password" binding:"required"`
 }
 
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
@@ -120,12 +269,13 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	if err := h.authUsecase.ChangePassword(userID.(string), req.OldPassword, req.NewPassword); err != nil {
+	tokens, err := h.authUsecase.ChangePassword(userID.(uuid.UUID).String(), req.OldPassword, req.NewPassword)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully", "tokens": tokens})
 }
 
 func (h *AuthHandler) UploadProfilePicture(c *gin.Context) {
@@ -149,7 +299,7 @@ func (h *AuthHandler) UploadProfilePicture(c *gin.Context) {
 	// Update user profile with photo URL
 	// Assuming we serve static files from /uploads
 	photoURL := "/uploads/" + filename
-	if err := h.authUsecase.UpdateProfilePicture(userID.(string), photoURL); err != nil {
+	if err := h.authUsecase.UpdateProfilePicture(userID.(uuid.UUID).String(), photoURL); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
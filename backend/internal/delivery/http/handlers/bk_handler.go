@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"ppi-100-sis/internal/delivery/http/middleware"
 	"ppi-100-sis/internal/domain"
 	"ppi-100-sis/internal/usecase"
 	"strconv"
@@ -12,11 +13,12 @@ import (
 )
 
 type BKHandler struct {
-	bkUsecase *usecase.BKUsecase
+	bkUsecase      *usecase.BKUsecase
+	studentUsecase *usecase.StudentUsecase
 }
 
-func NewBKHandler(bkUsecase *usecase.BKUsecase) *BKHandler {
-	return &BKHandler{bkUsecase: bkUsecase}
+func NewBKHandler(bkUsecase *usecase.BKUsecase, studentUsecase *usecase.StudentUsecase) *BKHandler {
+	return &BKHandler{bkUsecase: bkUsecase, studentUsecase: studentUsecase}
 }
 
 type CreateViolationRequest struct {
@@ -81,7 +83,7 @@ func (h *BKHandler) CreateBKCall(c *gin.Context) {
 		return
 	}
 
-	if err := h.bkUsecase.CreateBKCall(studentUUID, teacherUUID, req.Reason, date); err != nil {
+	if err := h.bkUsecase.CreateBKCall(studentUUID, teacherUUID, req.Reason, date, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -92,7 +94,10 @@ func (h *BKHandler) CreateBKCall(c *gin.Context) {
 func (h *BKHandler) GetAllBKCalls(c *gin.Context) {
 	studentID := c.Query("student_id")
 	if studentID != "" {
-		calls, err := h.bkUsecase.GetStudentBKCalls(studentID)
+		if !studentScope(c, h.studentUsecase, studentID) {
+			return
+		}
+		calls, err := h.bkUsecase.GetStudentBKCalls(studentID, scopeUnitID(c))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -101,8 +106,13 @@ func (h *BKHandler) GetAllBKCalls(c *gin.Context) {
 		return
 	}
 
-	unitID, _ := strconv.Atoi(c.Query("unit_id"))
-	calls, err := h.bkUsecase.GetAllBKCalls(uint(unitID))
+	// Students and parents only list the calls of one student they may see
+	if !middleware.HasPermission(c.GetUint("roleID"), middleware.PermStudentRead) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "student_id is required"})
+		return
+	}
+
+	calls, err := h.bkUsecase.GetAllBKCalls(scopeUnitID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -185,7 +195,7 @@ func (h *BKHandler) UpdateBKCall(c *gin.Context) {
 		Date:      date,
 	}
 
-	if err := h.bkUsecase.UpdateBKCall(call); err != nil {
+	if err := h.bkUsecase.UpdateBKCall(call, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -195,7 +205,7 @@ func (h *BKHandler) UpdateBKCall(c *gin.Context) {
 
 func (h *BKHandler) DeleteBKCall(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.bkUsecase.DeleteBKCall(id); err != nil {
+	if err := h.bkUsecase.DeleteBKCall(id, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
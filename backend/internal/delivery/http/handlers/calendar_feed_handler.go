@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"ppi-100-sis/internal/usecase"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CalendarFeedHandler struct {
+	calendarFeedUsecase *usecase.CalendarFeedUsecase
+}
+
+func NewCalendarFeedHandler(calendarFeedUsecase *usecase.CalendarFeedUsecase) *CalendarFeedHandler {
+	return &CalendarFeedHandler{calendarFeedUsecase: calendarFeedUsecase}
+}
+
+// GET /profile/calendar-feed - Whether a feed URL has been issued
+func (h *CalendarFeedHandler) GetFeedStatus(c *gin.Context) {
+	status, err := h.calendarFeedUsecase.GetFeedStatus(c.MustGet("userID").(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// POST /profile/calendar-feed - Issue a new feed URL, revoking the old one
+func (h *CalendarFeedHandler) CreateFeedToken(c *gin.Context) {
+	token, err := h.calendarFeedUsecase.CreateFeedToken(c.MustGet("userID").(uuid.UUID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, usecase.ErrFeedUnavailable) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"token": token, "path": "/api/public/calendar/feed/" + token + ".ics"})
+}
+
+// DELETE /profile/calendar-feed - Revoke the feed URL
+func (h *CalendarFeedHandler) RevokeFeedToken(c *gin.Context) {
+	if err := h.calendarFeedUsecase.RevokeFeedToken(c.MustGet("userID").(uuid.UUID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar feed revoked"})
+}
+
+// GET /public/calendar/feed/:token - The .ics feed itself, for calendar apps
+func (h *CalendarFeedHandler) GetFeed(c *gin.Context) {
+	feed, err := h.calendarFeedUsecase.Feed(strings.TrimSuffix(c.Param("token"), ".ics"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, usecase.ErrInvalidFeedToken) || errors.Is(err, usecase.ErrFeedUnavailable) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"ppi-100-sis/internal/domain"
 	"ppi-100-sis/internal/usecase"
@@ -41,7 +42,7 @@ func (h *ElearningHandler) CreateMaterial(c *gin.Context) {
 		return
 	}
 
-	if err := h.elearningUsecase.CreateMaterial(req.Title, req.Description, req.FileURL, req.ClassID, req.SubjectID, teacherUUID); err != nil {
+	if err := h.elearningUsecase.CreateMaterial(req.Title, req.Description, req.FileURL, req.ClassID, req.SubjectID, teacherUUID, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -51,21 +52,14 @@ func (h *ElearningHandler) CreateMaterial(c *gin.Context) {
 
 func (h *ElearningHandler) GetMaterials(c *gin.Context) {
 	classID, _ := strconv.Atoi(c.Query("class_id"))
-	unitID, _ := strconv.Atoi(c.Query("unit_id"))
 
 	var materials []domain.Material
 	var err error
 
 	if classID != 0 {
-		materials, err = h.elearningUsecase.GetMaterials(uint(classID))
-	} else if unitID != 0 {
-		materials, err = h.elearningUsecase.GetMaterialsByUnit(uint(unitID))
+		materials, err = h.elearningUsecase.GetMaterials(uint(classID), scopeUnitID(c), termFilter(c))
 	} else {
-		// If no filter, maybe return empty or all? For now, let's return all if no filter (or handle in usecase)
-		// But usecase GetMaterials takes classID.
-		// We need to update usecase interface too.
-		// For now let's assume usecase has GetMaterialsByUnit.
-		materials, err = h.elearningUsecase.GetMaterialsByUnit(uint(unitID)) // Default to unit filter if provided
+		materials, err = h.elearningUsecase.GetMaterialsByUnit(scopeUnitID(c), termFilter(c))
 	}
 
 	if err != nil {
@@ -76,12 +70,21 @@ func (h *ElearningHandler) GetMaterials(c *gin.Context) {
 }
 
 type CreateTaskRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
-	Deadline    string `json:"deadline" binding:"required"`
-	ClassID     uint   `json:"class_id" binding:"required"`
-	SubjectID   uint   `json:"subject_id" binding:"required"`
-	TeacherID   string `json:"teacher_id" binding:"required"`
+	Title                string `json:"title" binding:"required"`
+	Description          string `json:"description"`
+	Deadline             string `json:"deadline" binding:"required"`
+	ClassID              uint   `json:"class_id" binding:"required"`
+	SubjectID            uint   `json:"subject_id" binding:"required"`
+	TeacherID            string `json:"teacher_id" binding:"required"`
+	LearningObjectiveIDs []uint `json:"learning_objective_ids"`
+}
+
+func respondTaskError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrInvalidObjective) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 }
 
 func (h *ElearningHandler) CreateTask(c *gin.Context) {
@@ -103,8 +106,8 @@ func (h *ElearningHandler) CreateTask(c *gin.Context) {
 		return
 	}
 
-	if err := h.elearningUsecase.CreateTask(req.Title, req.Description, deadline, req.ClassID, req.SubjectID, teacherUUID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.elearningUsecase.CreateTask(req.Title, req.Description, deadline, req.ClassID, req.SubjectID, req.LearningObjectiveIDs, teacherUUID, scopeUnitID(c)); err != nil {
+		respondTaskError(c, err)
 		return
 	}
 
@@ -113,17 +116,14 @@ func (h *ElearningHandler) CreateTask(c *gin.Context) {
 
 func (h *ElearningHandler) GetTasks(c *gin.Context) {
 	classID, _ := strconv.Atoi(c.Query("class_id"))
-	unitID, _ := strconv.Atoi(c.Query("unit_id"))
 
 	var tasks []domain.Task
 	var err error
 
 	if classID != 0 {
-		tasks, err = h.elearningUsecase.GetTasks(uint(classID))
-	} else if unitID != 0 {
-		tasks, err = h.elearningUsecase.GetTasksByUnit(uint(unitID))
+		tasks, err = h.elearningUsecase.GetTasks(uint(classID), scopeUnitID(c), termFilter(c))
 	} else {
-		tasks, err = h.elearningUsecase.GetTasksByUnit(uint(unitID))
+		tasks, err = h.elearningUsecase.GetTasksByUnit(scopeUnitID(c), termFilter(c))
 	}
 
 	if err != nil {
@@ -145,7 +145,7 @@ func (h *ElearningHandler) GradeSubmission(c *gin.Context) {
 		return
 	}
 
-	if err := h.elearningUsecase.GradeSubmission(id, req.Grade); err != nil {
+	if err := h.elearningUsecase.GradeSubmission(id, req.Grade, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -155,7 +155,7 @@ func (h *ElearningHandler) GradeSubmission(c *gin.Context) {
 
 func (h *ElearningHandler) GetSubmissions(c *gin.Context) {
 	taskID, _ := strconv.Atoi(c.Param("id"))
-	submissions, err := h.elearningUsecase.GetSubmissions(uint(taskID))
+	submissions, err := h.elearningUsecase.GetSubmissions(uint(taskID), scopeUnitID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -182,7 +182,7 @@ func (h *ElearningHandler) SubmitTask(c *gin.Context) {
 		return
 	}
 
-	if err := h.elearningUsecase.SubmitTask(req.TaskID, studentUUID, req.FileURL); err != nil {
+	if err := h.elearningUsecase.SubmitTask(req.TaskID, studentUUID, req.FileURL, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -209,7 +209,7 @@ func (h *ElearningHandler) GetStudentSubmissions(c *gin.Context) {
 			return
 		}
 
-		submissions, err := h.elearningUsecase.GetStudentSubmissionsByUserID(userID)
+		submissions, err := h.elearningUsecase.GetStudentSubmissionsByUserID(userID, termFilter(c))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -224,7 +224,7 @@ func (h *ElearningHandler) GetStudentSubmissions(c *gin.Context) {
 		return
 	}
 
-	submissions, err := h.elearningUsecase.GetStudentSubmissions(studentUUID)
+	submissions, err := h.elearningUsecase.GetStudentSubmissions(studentUUID, scopeUnitID(c), termFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -257,7 +257,7 @@ func (h *ElearningHandler) UpdateMaterial(c *gin.Context) {
 		TeacherID:   teacherUUID,
 	}
 
-	if err := h.elearningUsecase.UpdateMaterial(material); err != nil {
+	if err := h.elearningUsecase.UpdateMaterial(material, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -267,7 +267,7 @@ func (h *ElearningHandler) UpdateMaterial(c *gin.Context) {
 
 func (h *ElearningHandler) DeleteMaterial(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	if err := h.elearningUsecase.DeleteMaterial(uint(id)); err != nil {
+	if err := h.elearningUsecase.DeleteMaterial(uint(id), scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -306,8 +306,8 @@ func (h *ElearningHandler) UpdateTask(c *gin.Context) {
 		TeacherID:   teacherUUID,
 	}
 
-	if err := h.elearningUsecase.UpdateTask(task); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.elearningUsecase.UpdateTask(task, req.LearningObjectiveIDs, scopeUnitID(c)); err != nil {
+		respondTaskError(c, err)
 		return
 	}
 
@@ -316,7 +316,7 @@ func (h *ElearningHandler) UpdateTask(c *gin.Context) {
 
 func (h *ElearningHandler) DeleteTask(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	if err := h.elearningUsecase.DeleteTask(uint(id)); err != nil {
+	if err := h.elearningUsecase.DeleteTask(uint(id), scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -333,7 +333,7 @@ func (h *ElearningHandler) DeleteSubmission(c *gin.Context) {
 		return
 	}
 
-	if err := h.elearningUsecase.DeleteSubmission(uuid); err != nil {
+	if err := h.elearningUsecase.DeleteSubmission(uuid, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
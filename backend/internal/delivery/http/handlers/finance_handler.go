@@ -2,9 +2,9 @@ package handlers
 
 import (
 	"net/http"
+	"ppi-100-sis/internal/delivery/http/middleware"
 	"ppi-100-sis/internal/domain"
 	"ppi-100-sis/internal/usecase"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,10 +13,11 @@ import (
 
 type FinanceHandler struct {
 	financeUsecase *usecase.FinanceUsecase
+	studentUsecase *usecase.StudentUsecase
 }
 
-func NewFinanceHandler(financeUsecase *usecase.FinanceUsecase) *FinanceHandler {
-	return &FinanceHandler{financeUsecase: financeUsecase}
+func NewFinanceHandler(financeUsecase *usecase.FinanceUsecase, studentUsecase *usecase.StudentUsecase) *FinanceHandler {
+	return &FinanceHandler{financeUsecase: financeUsecase, studentUsecase: studentUsecase}
 }
 
 type CreateBillRequest struct {
@@ -45,7 +46,7 @@ func (h *FinanceHandler) CreateBill(c *gin.Context) {
 		return
 	}
 
-	if err := h.financeUsecase.CreateBill(studentUUID, req.Title, req.Amount, dueDate); err != nil {
+	if err := h.financeUsecase.CreateBill(studentUUID, req.Title, req.Amount, dueDate, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -56,7 +57,10 @@ func (h *FinanceHandler) CreateBill(c *gin.Context) {
 func (h *FinanceHandler) GetAllBills(c *gin.Context) {
 	studentID := c.Query("student_id")
 	if studentID != "" {
-		bills, err := h.financeUsecase.GetStudentBills(studentID)
+		if !studentScope(c, h.studentUsecase, studentID) {
+			return
+		}
+		bills, err := h.financeUsecase.GetStudentBills(studentID, scopeUnitID(c))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -65,10 +69,8 @@ func (h *FinanceHandler) GetAllBills(c *gin.Context) {
 		return
 	}
 
-	unitIDStr := c.Query("unit_id")
-	if unitIDStr != "" {
-		unitID, _ := strconv.Atoi(unitIDStr)
-		bills, err := h.financeUsecase.GetAllBills(uint(unitID))
+	if middleware.HasPermission(c.GetUint("roleID"), middleware.PermFinanceBillWrite) {
+		bills, err := h.financeUsecase.GetAllBills(scopeUnitID(c))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -93,17 +95,13 @@ func (h *FinanceHandler) GetAllBills(c *gin.Context) {
 				c.JSON(http.StatusOK, bills)
 				return
 			}
-			// If error (e.g. not a student), we might want to return it or fall through
-			// But since no other params matched, returning error is appropriate if we assume this endpoint is for bills.
-			// However, if the user is Admin and just calls /bills without unit_id, maybe they expect all bills?
-			// But GetAllBills usecase requires unitID.
-			// So let's return the error from GetStudentBillsByUserID if it fails.
+			// Finance staff were handled above, so a failure here means the user is not a student
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 	}
 
-	c.JSON(http.StatusBadRequest, gin.H{"error": "unit_id or student_id is required"})
+	c.JSON(http.StatusBadRequest, gin.H{"error": "student_id is required"})
 }
 
 type PaymentRequest struct {
@@ -125,7 +123,7 @@ func (h *FinanceHandler) RecordPayment(c *gin.Context) {
 		return
 	}
 
-	if err := h.financeUsecase.RecordPayment(billUUID, req.Amount, req.Method); err != nil {
+	if err := h.financeUsecase.RecordPayment(billUUID, req.Amount, req.Method, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -168,7 +166,7 @@ func (h *FinanceHandler) UpdateBill(c *gin.Context) {
 		DueDate:   dueDate,
 	}
 
-	if err := h.financeUsecase.UpdateBill(bill); err != nil {
+	if err := h.financeUsecase.UpdateBill(bill, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -178,7 +176,7 @@ func (h *FinanceHandler) UpdateBill(c *gin.Context) {
 
 func (h *FinanceHandler) DeleteBill(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.financeUsecase.DeleteBill(id); err != nil {
+	if err := h.financeUsecase.DeleteBill(id, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -214,7 +212,7 @@ func (h *FinanceHandler) UpdatePayment(c *gin.Context) {
 		PaymentMethod: req.Method,
 	}
 
-	if err := h.financeUsecase.UpdatePayment(payment); err != nil {
+	if err := h.financeUsecase.UpdatePayment(payment, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -224,7 +222,7 @@ func (h *FinanceHandler) UpdatePayment(c *gin.Context) {
 
 func (h *FinanceHandler) DeletePayment(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.financeUsecase.DeletePayment(id); err != nil {
+	if err := h.financeUsecase.DeletePayment(id, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"ppi-100-sis/internal/delivery/http/middleware"
+	"ppi-100-sis/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type HomeroomHandler struct {
+	homeroomUsecase *usecase.HomeroomUsecase
+	academicUsecase *usecase.AcademicUsecase
+}
+
+func NewHomeroomHandler(homeroomUsecase *usecase.HomeroomUsecase, academicUsecase *usecase.AcademicUsecase) *HomeroomHandler {
+	return &HomeroomHandler{homeroomUsecase: homeroomUsecase, academicUsecase: academicUsecase}
+}
+
+func respondHomeroomError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrNotHomeroomTeacher):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidHomeroomNote), errors.Is(err, usecase.ErrInvalidP5), errors.Is(err, usecase.ErrNoSemester), errors.Is(err, usecase.ErrNotEnrolled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GET /academic/classes/:id/homeroom-notes - Notes and attitude grades of a class
+func (h *HomeroomHandler) GetClassNotes(c *gin.Context) {
+	classID, _ := strconv.Atoi(c.Param("id"))
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermHomeroomManage)
+	if !ok {
+		return
+	}
+	notes, err := h.homeroomUsecase.GetClassNotes(uint(classID), teacherID, scopeUnitID(c), termFilter(c))
+	if err != nil {
+		respondHomeroomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, notes)
+}
+
+// POST /academic/homeroom-notes - Record a student's note and attitude grades
+func (h *HomeroomHandler) CreateNote(c *gin.Context) {
+	var req struct {
+		StudentID string `json:"student_id" binding:"required"`
+		usecase.HomeroomNoteInput
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermHomeroomManage)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	note, err := h.homeroomUsecase.CreateNote(req.StudentID, req.HomeroomNoteInput, userID, teacherID, scopeUnitID(c), termFilter(c))
+	if err != nil {
+		respondHomeroomError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, note)
+}
+
+func (h *HomeroomHandler) UpdateNote(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req usecase.HomeroomNoteInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermHomeroomManage)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	note, err := h.homeroomUsecase.UpdateNote(uint(id), req, userID, teacherID, scopeUnitID(c))
+	if err != nil {
+		respondHomeroomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, note)
+}
+
+// GET /academic/classes/:id/p5-projects - P5 projects of a class with their assessments
+func (h *HomeroomHandler) GetClassProjects(c *gin.Context) {
+	classID, _ := strconv.Atoi(c.Param("id"))
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermHomeroomManage)
+	if !ok {
+		return
+	}
+	projects, err := h.homeroomUsecase.GetClassProjects(uint(classID), teacherID, scopeUnitID(c), termFilter(c))
+	if err != nil {
+		respondHomeroomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, projects)
+}
+
+func (h *HomeroomHandler) CreateProject(c *gin.Context) {
+	var req struct {
+		ClassID uint `json:"class_id" binding:"required"`
+		usecase.P5ProjectInput
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermHomeroomManage)
+	if !ok {
+		return
+	}
+
+	project, err := h.homeroomUsecase.CreateProject(req.ClassID, req.P5ProjectInput, teacherID, scopeUnitID(c))
+	if err != nil {
+		respondHomeroomError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, project)
+}
+
+func (h *HomeroomHandler) UpdateProject(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req usecase.P5ProjectInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermHomeroomManage)
+	if !ok {
+		return
+	}
+
+	project, err := h.homeroomUsecase.UpdateProject(uint(id), req, teacherID, scopeUnitID(c))
+	if err != nil {
+		respondHomeroomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *HomeroomHandler) DeleteProject(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermHomeroomManage)
+	if !ok {
+		return
+	}
+	if err := h.homeroomUsecase.DeleteProject(uint(id), teacherID, scopeUnitID(c)); err != nil {
+		respondHomeroomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Project deleted successfully"})
+}
+
+// PUT /academic/p5-projects/:id/assessments - Replace every assessment of a project
+func (h *HomeroomHandler) AssessProject(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req struct {
+		Assessments []usecase.P5Entry `json:"assessments" binding:"dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermHomeroomManage)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err := h.homeroomUsecase.AssessProject(uint(id), req.Assessments, userID, teacherID, scopeUnitID(c)); err != nil {
+		respondHomeroomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Assessments saved successfully"})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"ppi-100-sis/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InvitationHandler struct {
+	invitationUsecase *usecase.InvitationUsecase
+}
+
+func NewInvitationHandler(invitationUsecase *usecase.InvitationUsecase) *InvitationHandler {
+	return &InvitationHandler{invitationUsecase: invitationUsecase}
+}
+
+type CreateInvitationRequest struct {
+	Email  string `json:"email" binding:"required,email"`
+	Name   string `json:"name"`
+	RoleID uint   `json:"role_id" binding:"required"`
+	UnitID uint   `json:"unit_id"`
+}
+
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var req CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	unitID := targetUnitID(c, req.UnitID)
+	if unitID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unit_id is required"})
+		return
+	}
+
+	invitation, code, err := h.invitationUsecase.CreateInvitation(req.Email, req.Name, req.RoleID, unitID, c.MustGet("userID").(uuid.UUID), c.GetUint("roleID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"invitation": invitation, "code": code})
+}
+
+func (h *InvitationHandler) GetInvitations(c *gin.Context) {
+	invitations, err := h.invitationUsecase.GetInvitations(scopeUnitID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+func (h *InvitationHandler) DeleteInvitation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.invitationUsecase.DeleteInvitation(id, scopeUnitID(c)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation deleted successfully"})
+}
+
+type AcceptInvitationRequest struct {
+	Code     string `json:"code" binding:"required"`
+	Name     string `json:"name"`
+	Password string `json:"password" binding:"required"`
+}
+
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var req AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.invitationUsecase.AcceptInvitation(req.Code, req.Name, req.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Account created successfully"})
+}
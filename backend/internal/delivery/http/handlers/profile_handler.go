@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"ppi-100-sis/internal/usecase"
 	"ppi-100-sis/pkg/utils"
@@ -11,10 +12,11 @@ import (
 
 type ProfileHandler struct {
 	userUsecase *usecase.UserUsecase
+	authUsecase *usecase.AuthUsecase
 }
 
-func NewProfileHandler(userUsecase *usecase.UserUsecase) *ProfileHandler {
-	return &ProfileHandler{userUsecase: userUsecase}
+func NewProfileHandler(userUsecase *usecase.UserUsecase, authUsecase *usecase.AuthUsecase) *ProfileHandler {
+	return &ProfileHandler{userUsecase: userUsecase, authUsecase: authUsecase}
 }
 
 // GET /profile - Get current user profile
@@ -73,26 +75,8 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	uuid, err := uuid.Parse(userID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
-		return
-	}
-
-	// Get existing user
-	user, err := h.userUsecase.GetUserByID(userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Update fields
-	user.ID = uuid
-	user.Name = req.Name
-	user.Email = req.Email
-
-	if err := h.userUsecase.UpdateUser(user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.authUsecase.UpdateProfile(userID, req.Name, req.Email); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -101,7 +85,7 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 
 type ProfileChangePasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=6"`
+	NewPassword string `json:"new_password" binding:"required"`
 }
 
 // PUT /profile/password - Change password
@@ -128,34 +112,22 @@ func (h *ProfileHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	// Get user
-	user, err := h.userUsecase.GetUserByID(userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Verify old password
-	if !utils.CheckPasswordHash(req.OldPassword, user.PasswordHash) {
+	// Other sessions are revoked; the caller gets a fresh token pair
+	tokens, err := h.authUsecase.ChangePassword(userID, req.OldPassword, req.NewPassword)
+	if errors.Is(err, usecase.ErrInvalidOldPassword) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Password lama tidak sesuai"})
 		return
 	}
-
-	// Hash new password
-	newHash, err := utils.HashPassword(req.NewPassword)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+	if errors.Is(err, utils.ErrWeakPassword) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	// Update password
-	user.PasswordHash = newHash
-	if err := h.userUsecase.UpdateUser(user); err != nil {
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully", "tokens": tokens})
 }
 
 // POST /profile/photo - Upload profile photo
@@ -193,20 +165,97 @@ func (h *ProfileHandler) UploadPhoto(c *gin.Context) {
 	}
 
 	// Update user photo URL
-	user, err := h.userUsecase.GetUserByID(userID)
-	if err != nil {
+	photoURL := "/uploads/profiles/" + filename
+	if err := h.authUsecase.UpdateProfilePicture(userID, photoURL); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	user.PhotoURL = "/uploads/profiles/" + filename
-	if err := h.userUsecase.UpdateUser(user); err != nil {
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Photo uploaded successfully",
+		"photo_url": photoURL,
+	})
+}
+
+// GET /profile/2fa - 2FA status
+func (h *ProfileHandler) GetMFAStatus(c *gin.Context) {
+	status, err := h.authUsecase.GetMFAStatus(c.MustGet("userID").(uuid.UUID).String())
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, status)
+}
+
+// POST /profile/2fa/setup - Start enrollment, returns secret and otpauth URI
+func (h *ProfileHandler) SetupMFA(c *gin.Context) {
+	setup, err := h.authUsecase.SetupMFA(c.MustGet("userID").(uuid.UUID).String())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, setup)
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// POST /profile/2fa/enable - Confirm enrollment with a code
+func (h *ProfileHandler) EnableMFA(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, tokens, err := h.authUsecase.EnableMFA(c.MustGet("userID").(uuid.UUID).String(), req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":   "Photo uploaded successfully",
-		"photo_url": user.PhotoURL,
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": codes,
+		"tokens":         tokens,
 	})
 }
+
+type DisableMFARequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// POST /profile/2fa/disable
+func (h *ProfileHandler) DisableMFA(c *gin.Context) {
+	var req DisableMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authUsecase.DisableMFA(c.MustGet("userID").(uuid.UUID).String(), req.Password, req.Code); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// POST /profile/2fa/recovery-codes - Replace recovery codes
+func (h *ProfileHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.authUsecase.RegenerateRecoveryCodes(c.MustGet("userID").(uuid.UUID).String(), req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
@@ -60,17 +60,7 @@ func (h *PublicHandler) RegisterPPDB(c *gin.Context) {
 }
 
 func (h *PublicHandler) GetPPDBRegistrations(c *gin.Context) {
-	unitID, _ := strconv.Atoi(c.Query("unit_id"))
-
-	var registrations []domain.PPDBRegistration
-	var err error
-
-	if unitID != 0 {
-		registrations, err = h.publicUsecase.GetPPDBRegistrationsByUnit(uint(unitID))
-	} else {
-		registrations, err = h.publicUsecase.GetAllPPDBRegistrations()
-	}
-
+	registrations, err := h.publicUsecase.GetPPDBRegistrations(scopeUnitID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -90,7 +80,7 @@ func (h *PublicHandler) UpdatePPDBStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.publicUsecase.UpdatePPDBStatus(id, req.Status); err != nil {
+	if err := h.publicUsecase.UpdatePPDBStatus(id, req.Status, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -235,13 +225,24 @@ func (h *PublicHandler) DeleteDownload(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Download deleted successfully"})
 }
 
+// GetAllAlumni lists every alumni entry, including unpublished graduates.
+func (h *PublicHandler) GetAllAlumni(c *gin.Context) {
+	alumni, err := h.publicUsecase.GetAllAlumni()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, alumni)
+}
+
 func (h *PublicHandler) UpdateAlumni(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
 	var req domain.Alumni
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.publicUsecase.UpdateAlumni(req); err != nil {
+	if err := h.publicUsecase.UpdateAlumni(uint(id), req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -259,7 +260,7 @@ func (h *PublicHandler) DeleteAlumni(c *gin.Context) {
 
 func (h *PublicHandler) DeletePPDBRegistration(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.publicUsecase.DeletePPDBRegistration(id); err != nil {
+	if err := h.publicUsecase.DeletePPDBRegistration(id, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
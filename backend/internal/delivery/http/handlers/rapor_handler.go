@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"ppi-100-sis/internal/delivery/http/middleware"
+	"ppi-100-sis/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RaporHandler struct {
+	raporUsecase    *usecase.RaporUsecase
+	academicUsecase *usecase.AcademicUsecase
+}
+
+func NewRaporHandler(raporUsecase *usecase.RaporUsecase, academicUsecase *usecase.AcademicUsecase) *RaporHandler {
+	return &RaporHandler{raporUsecase: raporUsecase, academicUsecase: academicUsecase}
+}
+
+func respondRaporError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrNotHomeroomTeacher):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrNoSemester), errors.Is(err, usecase.ErrNotEnrolled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GET /academic/report-cards/:student_id/pdf - One student's rapor
+func (h *RaporHandler) GetStudentRapor(c *gin.Context) {
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermRaporPrintAll)
+	if !ok {
+		return
+	}
+	file, name, err := h.raporUsecase.StudentRapor(c.Param("student_id"), teacherID, scopeUnitID(c), termFilter(c))
+	if err != nil {
+		respondRaporError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+	c.Data(http.StatusOK, "application/pdf", file)
+}
+
+// GET /academic/classes/:id/report-cards - Every rapor of a class as a ZIP
+func (h *RaporHandler) GetClassRapors(c *gin.Context) {
+	classID, _ := strconv.Atoi(c.Param("id"))
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermRaporPrintAll)
+	if !ok {
+		return
+	}
+	file, name, err := h.raporUsecase.ClassRapors(uint(classID), teacherID, scopeUnitID(c), termFilter(c))
+	if err != nil {
+		respondRaporError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+	c.Data(http.StatusOK, "application/zip", file)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"ppi-100-sis/internal/delivery/http/middleware"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"ppi-100-sis/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// scopeUnitID returns the unit a request is confined to, taken from the token
+// claims set by AuthMiddleware rather than from the query string. Super Admin is
+// not confined: 0 means all units, or ?unit_id= narrows it down explicitly.
+func scopeUnitID(c *gin.Context) uint {
+	if c.GetUint("roleID") == domain.RoleSuperAdmin {
+		unitID, _ := strconv.Atoi(c.Query("unit_id"))
+		return uint(unitID)
+	}
+	return c.GetUint("unitID")
+}
+
+// termFilter reads ?semester_id= for list endpoints. Without it lists show the
+// active term; "all" shows every term.
+func termFilter(c *gin.Context) postgres.TermFilter {
+	switch value := c.Query("semester_id"); value {
+	case "":
+		return postgres.TermFilter{}
+	case "all":
+		return postgres.TermFilter{All: true}
+	default:
+		semesterID, _ := strconv.Atoi(value)
+		return postgres.TermFilter{SemesterID: uint(semesterID)}
+	}
+}
+
+// targetUnitID returns the unit a created or updated record is written to.
+// Only Super Admin may write into a unit other than their own.
+func targetUnitID(c *gin.Context, requested uint) uint {
+	if c.GetUint("roleID") == domain.RoleSuperAdmin {
+		return requested
+	}
+	return c.GetUint("unitID")
+}
+
+// teacherScope returns the caller's teacher ID, so teachers only reach their
+// own classes or subjects. Roles granted manageAll get an empty ID. Callers
+// without a teacher profile are answered 403 and ok is false.
+func teacherScope(c *gin.Context, academicUsecase *usecase.AcademicUsecase, manageAll string) (teacherID string, ok bool) {
+	if middleware.HasPermission(c.GetUint("roleID"), manageAll) {
+		return "", true
+	}
+	teacherID, err := academicUsecase.GetTeacherIDByUserID(c.MustGet("userID").(uuid.UUID).String())
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return teacherID, true
+}
+
+// studentScope checks that the caller may read studentID's records. Roles
+// granted PermStudentRead reach any student of their unit; students only reach
+// themselves and parents their children. Anyone else is answered 403.
+func studentScope(c *gin.Context, studentUsecase *usecase.StudentUsecase, studentID string) bool {
+	if middleware.HasPermission(c.GetUint("roleID"), middleware.PermStudentRead) {
+		return true
+	}
+	if !studentUsecase.OwnsStudent(c.MustGet("userID").(uuid.UUID).String(), studentID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only view your own records"})
+		return false
+	}
+	return true
+}
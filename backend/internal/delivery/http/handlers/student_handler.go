@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"ppi-100-sis/internal/delivery/http/middleware"
 	"ppi-100-sis/internal/usecase"
 	"strconv"
 
@@ -10,16 +12,16 @@ import (
 )
 
 type StudentHandler struct {
-	studentUsecase *usecase.StudentUsecase
+	studentUsecase  *usecase.StudentUsecase
+	academicUsecase *usecase.AcademicUsecase
 }
 
-func NewStudentHandler(studentUsecase *usecase.StudentUsecase) *StudentHandler {
-	return &StudentHandler{studentUsecase: studentUsecase}
+func NewStudentHandler(studentUsecase *usecase.StudentUsecase, academicUsecase *usecase.AcademicUsecase) *StudentHandler {
+	return &StudentHandler{studentUsecase: studentUsecase, academicUsecase: academicUsecase}
 }
 
 func (h *StudentHandler) GetAllStudents(c *gin.Context) {
-	unitID, _ := strconv.Atoi(c.Query("unit_id"))
-	students, err := h.studentUsecase.GetAllStudents(uint(unitID))
+	students, err := h.studentUsecase.GetAllStudents(scopeUnitID(c), termFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -47,7 +49,7 @@ func (h *StudentHandler) RecordAttendance(c *gin.Context) {
 		return
 	}
 
-	if err := h.studentUsecase.RecordAttendance(studentUUID, req.ScheduleID, req.Method, req.Status); err != nil {
+	if err := h.studentUsecase.RecordAttendance(studentUUID, req.ScheduleID, req.Method, req.Status, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -57,7 +59,7 @@ func (h *StudentHandler) RecordAttendance(c *gin.Context) {
 
 func (h *StudentHandler) GetScheduleAttendance(c *gin.Context) {
 	scheduleID, _ := strconv.Atoi(c.Param("schedule_id"))
-	attendances, err := h.studentUsecase.GetScheduleAttendance(uint(scheduleID))
+	attendances, err := h.studentUsecase.GetScheduleAttendance(uint(scheduleID), scopeUnitID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -117,7 +119,7 @@ func (h *StudentHandler) CreateStudent(c *gin.Context) {
 		parentUUID = &parsedUUID
 	}
 
-	if err := h.studentUsecase.CreateStudent(req.Name, req.Email, req.Password, req.NISN, req.ClassID, req.UnitID, parentUUID); err != nil {
+	if err := h.studentUsecase.CreateStudent(req.Name, req.Email, req.Password, req.NISN, req.ClassID, targetUnitID(c, req.UnitID), parentUUID); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -151,8 +153,17 @@ func (h *StudentHandler) UpdateStudent(c *gin.Context) {
 		}
 		parentUUID = &parsedUUID
 	}
+	teacherID, ok := teacherScope(c, h.academicUsecase, middleware.PermStudentWrite)
+	if !ok {
+		return
+	}
 
-	if err := h.studentUsecase.UpdateStudent(id, req.Name, req.Email, req.NISN, req.ClassID, req.UnitID, parentUUID); err != nil {
+	err := h.studentUsecase.UpdateStudent(id, req.Name, req.Email, req.NISN, req.ClassID, targetUnitID(c, req.UnitID), parentUUID, teacherID, scopeUnitID(c))
+	if errors.Is(err, usecase.ErrNotHomeroomTeacher) || errors.Is(err, usecase.ErrHomeroomStudentField) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -162,7 +173,7 @@ func (h *StudentHandler) UpdateStudent(c *gin.Context) {
 
 func (h *StudentHandler) DeleteStudent(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.studentUsecase.DeleteStudent(id); err != nil {
+	if err := h.studentUsecase.DeleteStudent(id, scopeUnitID(c)); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -176,11 +187,93 @@ func (h *StudentHandler) GetStudentAttendance(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "student_id is required"})
 		return
 	}
+	if !studentScope(c, h.studentUsecase, studentID) {
+		return
+	}
 
-	attendances, err := h.studentUsecase.GetStudentAttendance(studentID)
+	attendances, err := h.studentUsecase.GetStudentAttendance(studentID, scopeUnitID(c), termFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, attendances)
 }
+
+// GetAttendanceRecap totals a student's attendance over school days only.
+func (h *StudentHandler) GetAttendanceRecap(c *gin.Context) {
+	studentID := c.Query("student_id")
+	if studentID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "student_id is required"})
+		return
+	}
+	if !studentScope(c, h.studentUsecase, studentID) {
+		return
+	}
+
+	recap, err := h.studentUsecase.GetAttendanceRecap(studentID, scopeUnitID(c), termFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, recap)
+}
+
+func (h *StudentHandler) GetEnrollmentHistory(c *gin.Context) {
+	enrollments, err := h.studentUsecase.GetEnrollmentHistory(c.Param("id"), scopeUnitID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		return
+	}
+	c.JSON(http.StatusOK, enrollments)
+}
+
+type PromotionRequest struct {
+	AcademicYearID uint `json:"academic_year_id" binding:"required"`
+	DryRun         bool `json:"dry_run"`
+	Classes        []struct {
+		FromClassID uint `json:"from_class_id" binding:"required"`
+		ToClassID   uint `json:"to_class_id"`
+		Graduate    bool `json:"graduate"`
+	} `json:"classes" binding:"required,min=1,dive"`
+	Repeaters []struct {
+		StudentID string `json:"student_id" binding:"required"`
+		ClassID   uint   `json:"class_id" binding:"required"`
+	} `json:"repeaters" binding:"dive"`
+}
+
+// PromoteStudents runs the year-end promotion; dry_run returns the plan only.
+func (h *StudentHandler) PromoteStudents(c *gin.Context) {
+	var req PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rules := make([]usecase.PromotionRule, 0, len(req.Classes))
+	for _, class := range req.Classes {
+		if class.Graduate == (class.ToClassID != 0) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Each class needs either to_class_id or graduate"})
+			return
+		}
+		rules = append(rules, usecase.PromotionRule{FromClassID: class.FromClassID, ToClassID: class.ToClassID, Graduate: class.Graduate})
+	}
+	repeaters := make([]usecase.Repeater, 0, len(req.Repeaters))
+	for _, repeater := range req.Repeaters {
+		studentID, err := uuid.Parse(repeater.StudentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+			return
+		}
+		repeaters = append(repeaters, usecase.Repeater{StudentID: studentID, ClassID: repeater.ClassID})
+	}
+
+	result, err := h.studentUsecase.PromoteStudents(req.AcademicYearID, rules, repeaters, req.DryRun, scopeUnitID(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.DryRun {
+		middleware.SkipAudit(c)
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -3,7 +3,6 @@ package handlers
 import (
 	"net/http"
 	"ppi-100-sis/internal/usecase"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,8 +16,7 @@ func NewTeacherHandler(teacherUsecase *usecase.TeacherUsecase) *TeacherHandler {
 }
 
 func (h *TeacherHandler) GetAllTeachers(c *gin.Context) {
-	unitID, _ := strconv.Atoi(c.Query("unit_id"))
-	teachers, err := h.teacherUsecase.GetAllTeachers(uint(unitID))
+	teachers, err := h.teacherUsecase.GetAllTeachers(scopeUnitID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
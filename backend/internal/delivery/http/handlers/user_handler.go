@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"ppi-100-sis/internal/domain"
 	"ppi-100-sis/internal/usecase"
+	"ppi-100-sis/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type UserHandler struct {
@@ -17,8 +20,21 @@ func NewUserHandler(userUsecase *usecase.UserUsecase) *UserHandler {
 	return &UserHandler{userUsecase: userUsecase}
 }
 
+func respondUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrWeakPassword), errors.Is(err, usecase.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAdminRoleForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	users, err := h.userUsecase.GetAllUsers()
+	users, err := h.userUsecase.GetAllUsers(scopeUnitID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -29,7 +45,7 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 type CreateUserRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 	RoleID   uint   `json:"role_id" binding:"required"`
 	UnitID   uint   `json:"unit_id" binding:"required"`
 }
@@ -41,8 +57,8 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	if err := h.userUsecase.CreateUser(req.Name, req.Email, req.Password, req.RoleID, req.UnitID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userUsecase.CreateUser(req.Name, req.Email, req.Password, req.RoleID, targetUnitID(c, req.UnitID), c.GetUint("roleID")); err != nil {
+		respondUserError(c, err)
 		return
 	}
 
@@ -51,8 +67,8 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.userUsecase.DeleteUser(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userUsecase.DeleteUser(id, scopeUnitID(c), c.GetUint("roleID")); err != nil {
+		respondUserError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
@@ -88,9 +104,12 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		RoleID:       req.RoleID,
 		UnitID:       req.UnitID,
 	}
+	if req.UnitID != 0 {
+		user.UnitID = targetUnitID(c, req.UnitID)
+	}
 
-	if err := h.userUsecase.UpdateUser(user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userUsecase.UpdateUser(user, scopeUnitID(c), c.GetUint("roleID")); err != nil {
+		respondUserError(c, err)
 		return
 	}
 
@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/usecase"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Auditor writes an AuditLog entry for every successful mutation on the
+// routes it is attached to. Must run after AuthMiddleware.
+type Auditor struct {
+	auditUsecase *usecase.AuditUsecase
+}
+
+func NewAuditor(auditUsecase *usecase.AuditUsecase) *Auditor {
+	return &Auditor{auditUsecase: auditUsecase}
+}
+
+// Record audits a create, update or delete of model (a pointer to the domain
+// struct the route writes), chosen by HTTP method. The row named by the first
+// route parameter is snapshotted before and after the handler runs.
+func (a *Auditor) Record(model interface{}) gin.HandlerFunc {
+	return a.RecordAction(model, "")
+}
+
+// RecordAction is Record with an explicit action name such as "unlock".
+func (a *Auditor) RecordAction(model interface{}, action string) gin.HandlerFunc {
+	// The first route parameter names the entity, e.g. :id or :user_id
+	return a.audit(model, action, func(c *gin.Context) string {
+		if len(c.Params) > 0 {
+			return c.Params[0].Value
+		}
+		return ""
+	})
+}
+
+// RecordSelf audits an action users take on their own account, such as a
+// password change. The acting user is the entity.
+func (a *Auditor) RecordSelf(action string) gin.HandlerFunc {
+	return a.audit(&domain.User{}, action, func(c *gin.Context) string {
+		return fmt.Sprint(c.MustGet("userID"))
+	})
+}
+
+const skipAuditKey = "skipAudit"
+
+// SkipAudit tells the auditor the request changed nothing, e.g. a dry run.
+func SkipAudit(c *gin.Context) {
+	c.Set(skipAuditKey, true)
+}
+
+func (a *Auditor) audit(model interface{}, action string, entityIDOf func(*gin.Context) string) gin.HandlerFunc {
+	modelType := reflect.TypeOf(model).Elem()
+
+	return func(c *gin.Context) {
+		name := action
+		if name == "" {
+			name = actionForMethod(c.Request.Method)
+		}
+		entity := reflect.New(modelType).Interface()
+		entityID := entityIDOf(c)
+
+		var before map[string]interface{}
+		var body []byte
+		var recorder *responseRecorder
+		if entityID != "" {
+			before = a.snapshot(entity, entityID)
+		} else {
+			// Creates: keep the request body and the response to find the new ID
+			if strings.HasPrefix(c.ContentType(), "application/json") {
+				body, _ = io.ReadAll(c.Request.Body)
+				c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			recorder = &responseRecorder{ResponseWriter: c.Writer}
+			c.Writer = recorder
+		}
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusBadRequest || c.GetBool(skipAuditKey) {
+			return
+		}
+
+		var after map[string]interface{}
+		switch {
+		case name == "delete":
+		case entityID != "":
+			after = a.snapshot(entity, entityID)
+		default:
+			entityID = createdID(recorder.body.Bytes())
+			if entityID != "" {
+				after = a.snapshot(entity, entityID)
+			}
+			if after == nil && len(body) > 0 {
+				_ = json.Unmarshal(body, &after)
+			}
+		}
+
+		entry := &domain.AuditLog{
+			ActorRoleID: c.GetUint("roleID"),
+			UnitID:      c.GetUint("unitID"),
+			Action:      name,
+			EntityType:  modelType.Name(),
+			EntityID:    entityID,
+			IPAddress:   c.ClientIP(),
+		}
+		a.record(c, entry, before, after)
+	}
+}
+
+// Impersonation records every request made with an impersonation token,
+// reads and refused writes included, against the impersonated user.
+func (a *Auditor) Impersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("impersonatorID"); !ok {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		userID, _ := c.Get("userID")
+		entry := &domain.AuditLog{
+			ActorRoleID: c.GetUint("roleID"),
+			UnitID:      c.GetUint("unitID"),
+			Action:      "impersonated_request",
+			EntityType:  "User",
+			EntityID:    fmt.Sprint(userID),
+			IPAddress:   c.ClientIP(),
+		}
+		a.record(c, entry, nil, map[string]interface{}{
+			"method": c.Request.Method,
+			"path":   c.Request.URL.RequestURI(),
+			"status": c.Writer.Status(),
+		})
+	}
+}
+
+func (a *Auditor) record(c *gin.Context, entry *domain.AuditLog, before, after map[string]interface{}) {
+	if actorID, ok := c.Get("userID"); ok {
+		entry.ActorID, _ = actorID.(uuid.UUID)
+	}
+	if impersonatorID, ok := c.Get("impersonatorID"); ok {
+		id := impersonatorID.(uuid.UUID)
+		entry.ImpersonatorID = &id
+	}
+	if err := a.auditUsecase.Record(entry, before, after); err != nil {
+		log.Printf("audit: failed to record %s %s %s: %v", entry.Action, entry.EntityType, entry.EntityID, err)
+	}
+}
+
+func (a *Auditor) snapshot(entity interface{}, id string) map[string]interface{} {
+	row, err := a.auditUsecase.Snapshot(entity, id)
+	if err != nil {
+		log.Printf("audit: failed to snapshot %T %s: %v", entity, id, err)
+	}
+	return row
+}
+
+func actionForMethod(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodDelete:
+		return "delete"
+	default:
+		return "update"
+	}
+}
+
+// createdID reads a top-level "id" from a JSON response body.
+func createdID(body []byte) string {
+	var resp map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&resp); err != nil {
+		return ""
+	}
+	if id, ok := resp["id"]; ok && id != nil {
+		return fmt.Sprint(id)
+	}
+	return ""
+}
+
+// responseRecorder keeps a copy of the response body.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
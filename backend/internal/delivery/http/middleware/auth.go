@@ -2,14 +2,13 @@ package middleware
 
 import (
 	"net/http"
-	"ppi-100-sis/internal/config"
-	"ppi-100-sis/pkg/utils"
+	"ppi-100-sis/internal/usecase"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+func AuthMiddleware(authUsecase *usecase.AuthUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -25,7 +24,7 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := utils.ValidateToken(parts[1], cfg)
+		claims, err := authUsecase.VerifyAccessToken(parts[1])
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
 			c.Abort()
@@ -35,6 +34,61 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		c.Set("userID", claims.UserID)
 		c.Set("roleID", claims.RoleID)
 		c.Set("unitID", claims.UnitID)
+		c.Set("mfaEnrollmentRequired", claims.MFAEnrollmentRequired)
+		c.Set("passwordChangeRequired", claims.PasswordChangeRequired)
+		if claims.ImpersonatorID != nil {
+			c.Set("impersonatorID", *claims.ImpersonatorID)
+			c.Header("X-Impersonating", claims.UserID.String())
+		}
+		c.Next()
+	}
+}
+
+// RequirePasswordChange blocks users with an admin-assigned password until
+// they have chosen their own. Must run after AuthMiddleware.
+func RequirePasswordChange() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("passwordChangeRequired") {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":                    "You must change your password before continuing",
+				"password_change_required": true,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// BlockImpersonatedWrites keeps impersonation sessions read-only.
+// Must run after AuthMiddleware.
+func BlockImpersonatedWrites() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("impersonatorID"); ok {
+			switch c.Request.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+			default:
+				c.JSON(http.StatusForbidden, gin.H{"error": "This action is not allowed while impersonating"})
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireMFAEnrollment blocks users whose role enforces 2FA until they have
+// enrolled. Must run after AuthMiddleware.
+func RequireMFAEnrollment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("mfaEnrollmentRequired") {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":                   "Two-factor authentication must be enabled for your role",
+				"mfa_enrollment_required": true,
+			})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
@@ -0,0 +1,224 @@
+package middleware
+
+import (
+	"net/http"
+	"ppi-100-sis/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Permissions checked by RequirePermission
+const (
+	PermAcademicClassRead     = "academic.class.read"
+	PermAcademicClassWrite    = "academic.class.write"
+	PermAcademicHomeroomRead  = "academic.homeroom.read"
+	PermAcademicReportRead    = "academic.report_card.read"
+	PermAcademicSubjectRead   = "academic.subject.read"
+	PermAcademicSubjectWrite  = "academic.subject.write"
+	PermAcademicScheduleRead  = "academic.schedule.read"
+	PermAcademicScheduleWrite = "academic.schedule.write"
+	PermAcademicTermRead      = "academic.term.read"
+	PermAcademicTermWrite     = "academic.term.write"
+	PermAcademicPromotion     = "academic.promotion"
+
+	PermSubstitutionRead  = "substitution.read"
+	PermSubstitutionWrite = "substitution.write"
+
+	PermRoomRead  = "room.read"
+	PermRoomWrite = "room.write"
+	PermRoomBook  = "room.book"
+
+	PermCalendarRead  = "calendar.read"
+	PermCalendarWrite = "calendar.write"
+
+	PermWorkloadRead = "workload.read"
+
+	PermGradeWeightWrite = "gradebook.weight.write"
+	PermScoreWrite       = "gradebook.score.write"
+	PermScoreManage      = "gradebook.score.manage" // Any subject, not only one's own
+	PermKKMWrite         = "gradebook.kkm.write"
+	PermObjectiveWrite   = "gradebook.objective.write"
+
+	PermRaporPrint    = "rapor.print"
+	PermRaporPrintAll = "rapor.print.all" // Any class, not only one's homeroom class
+
+	PermHomeroomWrite  = "homeroom.write"
+	PermHomeroomManage = "homeroom.manage" // Any class, not only one's homeroom class
+
+	PermTeacherRead = "teacher.read"
+
+	PermStudentRead         = "student.read" // Any student of the unit, not only oneself or one's children
+	PermStudentWrite        = "student.write"
+	PermStudentUpdate       = "student.update"
+	PermStudentChildrenRead = "student.children.read"
+	PermStudentHistoryRead  = "student.history.read"
+
+	PermAttendanceWrite        = "attendance.write"
+	PermAttendanceScheduleRead = "attendance.schedule.read"
+	PermAttendanceStudentRead  = "attendance.student.read"
+
+	PermFinanceBillRead     = "finance.bill.read"
+	PermFinanceBillWrite    = "finance.bill.write"
+	PermFinancePaymentWrite = "finance.payment.write"
+
+	PermUserRead         = "user.read"
+	PermUserWrite        = "user.write"
+	PermInvitationManage = "user.invitation.manage"
+
+	PermBKViolationRead   = "bk.violation.read"
+	PermBKViolationCreate = "bk.violation.create"
+	PermBKViolationWrite  = "bk.violation.write"
+	PermBKCallRead        = "bk.call.read"
+	PermBKCallCreate      = "bk.call.create"
+	PermBKCallWrite       = "bk.call.write"
+
+	PermElearningRead             = "elearning.read"
+	PermElearningWrite            = "elearning.write"
+	PermElearningSubmissionRead   = "elearning.submission.read"
+	PermElearningSubmissionGrade  = "elearning.submission.grade"
+	PermElearningSubmissionCreate = "elearning.submission.create"
+	PermElearningSubmissionOwn    = "elearning.submission.own"
+
+	PermNotificationRead   = "notification.read"
+	PermNotificationManage = "notification.manage"
+
+	PermPPDBManage          = "ppdb.manage"
+	PermPublicContentManage = "public_content.manage"
+	PermContactManage       = "contact.manage"
+
+	PermAuditLogRead = "audit_log.read"
+)
+
+var (
+	adminRoles   = []uint{domain.RoleSuperAdmin, domain.RoleAdminMTS, domain.RoleAdminMA}
+	staffRoles   = []uint{domain.RoleSuperAdmin, domain.RoleAdminMTS, domain.RoleAdminMA, domain.RoleGuru, domain.RoleWaliKelas}
+	allRoles     = []uint{domain.RoleSuperAdmin, domain.RoleAdminMTS, domain.RoleAdminMA, domain.RoleGuru, domain.RoleWaliKelas, domain.RoleSiswa, domain.RoleOrangTua}
+	studentRoles = []uint{domain.RoleSiswa}
+	parentRoles  = []uint{domain.RoleOrangTua}
+)
+
+// RolePermissions is the role matrix for the seven seeded roles.
+// Any permission missing from this table is denied for everyone.
+var RolePermissions = map[string][]uint{
+	PermAcademicClassRead:     staffRoles,
+	PermAcademicClassWrite:    adminRoles,
+	PermAcademicHomeroomRead:  staffRoles,
+	PermAcademicReportRead:    allRoles,
+	PermAcademicSubjectRead:   staffRoles,
+	PermAcademicSubjectWrite:  adminRoles,
+	PermAcademicScheduleRead:  {domain.RoleSuperAdmin, domain.RoleAdminMTS, domain.RoleAdminMA, domain.RoleGuru, domain.RoleWaliKelas, domain.RoleSiswa},
+	PermAcademicScheduleWrite: adminRoles,
+	PermAcademicTermRead:      allRoles,
+	PermAcademicTermWrite:     adminRoles,
+	PermAcademicPromotion:     adminRoles,
+
+	PermSubstitutionRead:  staffRoles,
+	PermSubstitutionWrite: adminRoles,
+
+	PermRoomRead:  staffRoles,
+	PermRoomWrite: adminRoles,
+	PermRoomBook:  staffRoles,
+
+	PermCalendarRead:  allRoles,
+	PermCalendarWrite: adminRoles,
+
+	PermWorkloadRead: adminRoles,
+
+	PermGradeWeightWrite: adminRoles,
+	PermScoreWrite:       staffRoles,
+	PermScoreManage:      adminRoles,
+	PermKKMWrite:         adminRoles,
+	PermObjectiveWrite:   staffRoles,
+
+	PermRaporPrint:    {domain.RoleSuperAdmin, domain.RoleAdminMTS, domain.RoleAdminMA, domain.RoleWaliKelas},
+	PermRaporPrintAll: adminRoles,
+
+	PermHomeroomWrite:  {domain.RoleSuperAdmin, domain.RoleAdminMTS, domain.RoleAdminMA, domain.RoleWaliKelas},
+	PermHomeroomManage: adminRoles,
+
+	PermTeacherRead: staffRoles,
+
+	PermStudentRead:         staffRoles,
+	PermStudentWrite:        adminRoles,
+	PermStudentUpdate:       {domain.RoleSuperAdmin, domain.RoleAdminMTS, domain.RoleAdminMA, domain.RoleWaliKelas},
+	PermStudentChildrenRead: parentRoles,
+	PermStudentHistoryRead:  staffRoles,
+
+	PermAttendanceWrite:        staffRoles,
+	PermAttendanceScheduleRead: staffRoles,
+	PermAttendanceStudentRead:  allRoles,
+
+	PermFinanceBillRead:     {domain.RoleSuperAdmin, domain.RoleAdminMTS, domain.RoleAdminMA, domain.RoleSiswa, domain.RoleOrangTua},
+	PermFinanceBillWrite:    adminRoles,
+	PermFinancePaymentWrite: adminRoles,
+
+	PermUserRead:         staffRoles,
+	PermUserWrite:        adminRoles,
+	PermInvitationManage: adminRoles,
+
+	PermBKViolationRead:   staffRoles,
+	PermBKViolationCreate: staffRoles,
+	PermBKViolationWrite:  adminRoles,
+	PermBKCallRead:        allRoles,
+	PermBKCallCreate:      staffRoles,
+	PermBKCallWrite:       adminRoles,
+
+	PermElearningRead:             allRoles,
+	PermElearningWrite:            staffRoles,
+	PermElearningSubmissionRead:   staffRoles,
+	PermElearningSubmissionGrade:  staffRoles,
+	PermElearningSubmissionCreate: studentRoles,
+	PermElearningSubmissionOwn:    allRoles,
+
+	PermNotificationRead:   allRoles,
+	PermNotificationManage: adminRoles,
+
+	PermPPDBManage:          adminRoles,
+	PermPublicContentManage: adminRoles,
+	PermContactManage:       adminRoles,
+
+	PermAuditLogRead: adminRoles,
+}
+
+// HasPermission reports whether roleID is granted permission in RolePermissions
+func HasPermission(roleID uint, permission string) bool {
+	return hasRole(roleID, RolePermissions[permission])
+}
+
+func hasRole(roleID uint, roles []uint) bool {
+	for _, r := range roles {
+		if r == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRoles only lets through users whose role is one of roles.
+// Must run after AuthMiddleware.
+func RequireRoles(roles ...uint) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasRole(c.GetUint("roleID"), roles) {
+			forbidden(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission only lets through users whose role is granted permission.
+// Must run after AuthMiddleware.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPermission(c.GetUint("roleID"), permission) {
+			forbidden(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+func forbidden(c *gin.Context) {
+	c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this resource"})
+	c.Abort()
+}
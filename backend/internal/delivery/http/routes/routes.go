@@ -4,8 +4,11 @@ import (
 	"ppi-100-sis/internal/config"
 	"ppi-100-sis/internal/delivery/http/handlers"
 	"ppi-100-sis/internal/delivery/http/middleware"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/ratelimit"
 	"ppi-100-sis/internal/repository/postgres"
 	"ppi-100-sis/internal/usecase"
+	"ppi-100-sis/pkg/mailer"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -16,50 +19,75 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 	userRepo := postgres.NewUserRepository(db)
 	academicRepo := postgres.NewAcademicRepository(db)
 	elearningRepo := postgres.NewElearningRepository(db)
+	tokenRepo := postgres.NewTokenRepository(db)
 
 	// Usecases
-	authUsecase := usecase.NewAuthUsecase(userRepo, cfg)
-	academicUsecase := usecase.NewAcademicUsecase(academicRepo, elearningRepo, userRepo)
+	mailSender := mailer.New(cfg)
+	var attemptStore ratelimit.Store = ratelimit.NewMemoryStore(cfg.LoginAttemptWindow)
+	if cfg.LoginAttemptStore == "postgres" {
+		attemptStore = postgres.NewLoginAttemptRepository(db)
+	}
+	loginLimiter := ratelimit.NewLoginLimiter(attemptStore, cfg.LoginMaxAttempts, cfg.LoginMaxAttemptsPerIP, cfg.LoginAttemptWindow, cfg.LoginLockoutDuration)
+	authUsecase := usecase.NewAuthUsecase(userRepo, tokenRepo, mailSender, loginLimiter, cfg)
+	teacherRepo := postgres.NewTeacherRepository(db)
+	notificationRepo := postgres.NewNotificationRepository(db)
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepo)
+	enrollmentRepo := postgres.NewEnrollmentRepository(db)
+	academicUsecase := usecase.NewAcademicUsecase(academicRepo, elearningRepo, userRepo, teacherRepo, enrollmentRepo, notificationUsecase, cfg)
 
 	// Handlers
 	authHandler := handlers.NewAuthHandler(authUsecase)
-	academicHandler := handlers.NewAcademicHandler(academicUsecase)
 
-	teacherRepo := postgres.NewTeacherRepository(db)
 	teacherUsecase := usecase.NewTeacherUsecase(teacherRepo)
 	teacherHandler := handlers.NewTeacherHandler(teacherUsecase)
 
 	studentRepo := postgres.NewStudentRepository(db)
 	attendanceRepo := postgres.NewAttendanceRepository(db)
-	studentUsecase := usecase.NewStudentUsecase(studentRepo, attendanceRepo, userRepo)
-	studentHandler := handlers.NewStudentHandler(studentUsecase)
+	studentUsecase := usecase.NewStudentUsecase(studentRepo, attendanceRepo, userRepo, academicRepo, enrollmentRepo, cfg)
+	studentHandler := handlers.NewStudentHandler(studentUsecase, academicUsecase)
+	academicHandler := handlers.NewAcademicHandler(academicUsecase, studentUsecase)
 
 	publicRepo := postgres.NewPublicRepository(db)
 	publicUsecase := usecase.NewPublicUsecase(publicRepo)
 	publicHandler := handlers.NewPublicHandler(publicUsecase)
 
-	notificationRepo := postgres.NewNotificationRepository(db)
-	notificationUsecase := usecase.NewNotificationUsecase(notificationRepo)
 	notificationHandler := handlers.NewNotificationHandler(notificationUsecase)
 
 	// Reuse existing userRepo
-	userUsecase := usecase.NewUserUsecase(userRepo)
+	userUsecase := usecase.NewUserUsecase(userRepo, tokenRepo, cfg)
 	userHandler := handlers.NewUserHandler(userUsecase)
 
 	bkRepo := postgres.NewBKRepository(db)
-	bkUsecase := usecase.NewBKUsecase(bkRepo)
-	bkHandler := handlers.NewBKHandler(bkUsecase)
+	bkUsecase := usecase.NewBKUsecase(bkRepo, studentRepo)
+	bkHandler := handlers.NewBKHandler(bkUsecase, studentUsecase)
 
 	// elearningRepo already declared above
-	elearningUsecase := usecase.NewElearningUsecase(elearningRepo, notificationUsecase, userRepo)
+	elearningUsecase := usecase.NewElearningUsecase(elearningRepo, notificationUsecase, userRepo, academicRepo)
 	elearningHandler := handlers.NewElearningHandler(elearningUsecase)
 
 	financeRepo := postgres.NewFinanceRepository(db)
-	financeUsecase := usecase.NewFinanceUsecase(financeRepo, notificationUsecase, userRepo)
-	financeHandler := handlers.NewFinanceHandler(financeUsecase)
+	financeUsecase := usecase.NewFinanceUsecase(financeRepo, notificationUsecase, userRepo, studentRepo)
+	financeHandler := handlers.NewFinanceHandler(financeUsecase, studentUsecase)
+
+	invitationRepo := postgres.NewInvitationRepository(db)
+	invitationUsecase := usecase.NewInvitationUsecase(invitationRepo, userRepo, mailSender, cfg)
+	invitationHandler := handlers.NewInvitationHandler(invitationUsecase)
+
+	auditRepo := postgres.NewAuditRepository(db)
+	auditUsecase := usecase.NewAuditUsecase(auditRepo)
+	auditHandler := handlers.NewAuditHandler(auditUsecase)
+	audit := middleware.NewAuditor(auditUsecase)
 
 	// Profile Handler
-	profileHandler := handlers.NewProfileHandler(userUsecase)
+	profileHandler := handlers.NewProfileHandler(userUsecase, authUsecase)
+
+	calendarFeedUsecase := usecase.NewCalendarFeedUsecase(tokenRepo, userRepo, academicRepo, elearningRepo, academicUsecase, cfg)
+	calendarFeedHandler := handlers.NewCalendarFeedHandler(calendarFeedUsecase)
+	homeroomRepo := postgres.NewHomeroomRepository(db)
+	homeroomUsecase := usecase.NewHomeroomUsecase(homeroomRepo, academicRepo, studentRepo, enrollmentRepo)
+	homeroomHandler := handlers.NewHomeroomHandler(homeroomUsecase, academicUsecase)
+	raporUsecase := usecase.NewRaporUsecase(academicRepo, studentRepo, homeroomRepo, enrollmentRepo, academicUsecase, studentUsecase, cfg)
+	raporHandler := handlers.NewRaporHandler(raporUsecase, academicUsecase)
 
 	// Public Routes
 	api := r.Group("/api")
@@ -71,144 +99,240 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 			public.GET("/alumni", publicHandler.GetAlumni)
 			public.POST("/ppdb", publicHandler.RegisterPPDB)
 			public.POST("/contact", publicHandler.SubmitContact)
+			public.GET("/calendar.ics", academicHandler.GetCalendarFeed)
+			public.GET("/calendar/feed/:token", calendarFeedHandler.GetFeed)
 		}
 
 		auth := api.Group("/auth")
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/login/mfa", authHandler.LoginMFA)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/logout", authHandler.Logout)
+			auth.POST("/forgot-password", authHandler.ForgotPassword)
+			auth.POST("/reset-password", authHandler.ResetPassword)
+			auth.POST("/invitations/accept", invitationHandler.AcceptInvitation)
 		}
 	}
 
 	// Protected Routes
 	protected := api.Group("/")
-	protected.Use(middleware.AuthMiddleware(cfg))
+	protected.Use(middleware.AuthMiddleware(authUsecase), audit.Impersonation(), middleware.BlockImpersonatedWrites())
 	{
 		// Profile routes
 		protected.GET("/profile", profileHandler.GetProfile)
-		protected.PUT("/profile", profileHandler.UpdateProfile)
-		protected.POST("/profile/photo", profileHandler.UploadPhoto)
-		protected.PUT("/profile/password", profileHandler.ChangePassword)
+		protected.PUT("/profile/password", audit.RecordSelf("change_password"), profileHandler.ChangePassword)
+
+		// Everything below stays closed until an admin-assigned password is changed
+		active := protected.Group("/", middleware.RequirePasswordChange())
+		active.PUT("/profile", profileHandler.UpdateProfile)
+		active.POST("/profile/photo", profileHandler.UploadPhoto)
+		active.GET("/profile/2fa", profileHandler.GetMFAStatus)
+		active.POST("/profile/2fa/setup", profileHandler.SetupMFA)
+		active.POST("/profile/2fa/enable", audit.RecordSelf("enable_2fa"), profileHandler.EnableMFA)
+		active.POST("/profile/2fa/disable", audit.RecordSelf("disable_2fa"), profileHandler.DisableMFA)
+		active.POST("/profile/2fa/recovery-codes", audit.RecordSelf("regenerate_recovery_codes"), profileHandler.RegenerateRecoveryCodes)
+
+		// Everything below stays closed to roles that enforce 2FA until they enroll
+		enrolled := active.Group("/", middleware.RequireMFAEnrollment())
+		enrolled.GET("/profile/calendar-feed", calendarFeedHandler.GetFeedStatus)
+		enrolled.POST("/profile/calendar-feed", audit.RecordSelf("create_calendar_feed"), calendarFeedHandler.CreateFeedToken)
+		enrolled.DELETE("/profile/calendar-feed", audit.RecordSelf("revoke_calendar_feed"), calendarFeedHandler.RevokeFeedToken)
 
-		academic := protected.Group("/academic")
+		academic := enrolled.Group("/academic")
 		{
-			academic.POST("/classes", academicHandler.CreateClass)
-			academic.GET("/classes", academicHandler.GetAllClasses)
-			academic.PUT("/classes/:id", academicHandler.UpdateClass)
-			academic.DELETE("/classes/:id", academicHandler.DeleteClass)
-			academic.GET("/classes/homeroom", academicHandler.GetHomeroomClass)
-			academic.GET("/report-cards/:student_id", academicHandler.GetStudentReportCard)
-			academic.POST("/subjects", academicHandler.CreateSubject)
-			academic.GET("/subjects", academicHandler.GetAllSubjects)
-			academic.PUT("/subjects/:id", academicHandler.UpdateSubject)
-			academic.DELETE("/subjects/:id", academicHandler.DeleteSubject)
-			academic.POST("/schedules", academicHandler.CreateSchedule)
-			academic.GET("/schedules", academicHandler.GetAllSchedules)
-			academic.PUT("/schedules/:id", academicHandler.UpdateSchedule)
-			academic.DELETE("/schedules/:id", academicHandler.DeleteSchedule)
+			academic.POST("/years", middleware.RequirePermission(middleware.PermAcademicTermWrite), audit.Record(&domain.AcademicYear{}), academicHandler.CreateAcademicYear)
+			academic.GET("/years", middleware.RequirePermission(middleware.PermAcademicTermRead), academicHandler.GetAcademicYears)
+			academic.PUT("/years/:id", middleware.RequirePermission(middleware.PermAcademicTermWrite), audit.Record(&domain.AcademicYear{}), academicHandler.UpdateAcademicYear)
+			academic.DELETE("/years/:id", middleware.RequirePermission(middleware.PermAcademicTermWrite), audit.Record(&domain.AcademicYear{}), academicHandler.DeleteAcademicYear)
+			academic.POST("/semesters", middleware.RequirePermission(middleware.PermAcademicTermWrite), audit.Record(&domain.Semester{}), academicHandler.CreateSemester)
+			academic.GET("/semesters/active", middleware.RequirePermission(middleware.PermAcademicTermRead), academicHandler.GetActiveSemester)
+			academic.PUT("/semesters/:id", middleware.RequirePermission(middleware.PermAcademicTermWrite), audit.Record(&domain.Semester{}), academicHandler.UpdateSemester)
+			academic.DELETE("/semesters/:id", middleware.RequirePermission(middleware.PermAcademicTermWrite), audit.Record(&domain.Semester{}), academicHandler.DeleteSemester)
+			academic.POST("/semesters/:id/activate", middleware.RequirePermission(middleware.PermAcademicTermWrite), audit.RecordAction(&domain.Semester{}, "activate"), academicHandler.ActivateSemester)
+			academic.POST("/promotions", middleware.RequirePermission(middleware.PermAcademicPromotion), audit.RecordAction(&domain.ClassEnrollment{}, "promote"), studentHandler.PromoteStudents)
+			academic.POST("/classes", middleware.RequirePermission(middleware.PermAcademicClassWrite), audit.Record(&domain.Class{}), academicHandler.CreateClass)
+			academic.GET("/classes", middleware.RequirePermission(middleware.PermAcademicClassRead), academicHandler.GetAllClasses)
+			academic.PUT("/classes/:id", middleware.RequirePermission(middleware.PermAcademicClassWrite), audit.Record(&domain.Class{}), academicHandler.UpdateClass)
+			academic.DELETE("/classes/:id", middleware.RequirePermission(middleware.PermAcademicClassWrite), audit.Record(&domain.Class{}), academicHandler.DeleteClass)
+			academic.GET("/classes/homeroom", middleware.RequirePermission(middleware.PermAcademicHomeroomRead), academicHandler.GetHomeroomClass)
+			academic.GET("/report-cards/remedial", middleware.RequirePermission(middleware.PermScoreWrite), academicHandler.GetRemedialList)
+			academic.GET("/report-cards/:student_id", middleware.RequirePermission(middleware.PermAcademicReportRead), academicHandler.GetStudentReportCard)
+			academic.GET("/report-cards/:student_id/pdf", middleware.RequirePermission(middleware.PermRaporPrint), raporHandler.GetStudentRapor)
+			academic.GET("/classes/:id/report-cards", middleware.RequirePermission(middleware.PermRaporPrint), raporHandler.GetClassRapors)
+			academic.GET("/classes/:id/homeroom-notes", middleware.RequirePermission(middleware.PermHomeroomWrite), homeroomHandler.GetClassNotes)
+			academic.POST("/homeroom-notes", middleware.RequirePermission(middleware.PermHomeroomWrite), audit.Record(&domain.HomeroomNote{}), homeroomHandler.CreateNote)
+			academic.PUT("/homeroom-notes/:id", middleware.RequirePermission(middleware.PermHomeroomWrite), audit.Record(&domain.HomeroomNote{}), homeroomHandler.UpdateNote)
+			academic.GET("/classes/:id/p5-projects", middleware.RequirePermission(middleware.PermHomeroomWrite), homeroomHandler.GetClassProjects)
+			academic.POST("/p5-projects", middleware.RequirePermission(middleware.PermHomeroomWrite), audit.Record(&domain.P5Project{}), homeroomHandler.CreateProject)
+			academic.PUT("/p5-projects/:id", middleware.RequirePermission(middleware.PermHomeroomWrite), audit.Record(&domain.P5Project{}), homeroomHandler.UpdateProject)
+			academic.DELETE("/p5-projects/:id", middleware.RequirePermission(middleware.PermHomeroomWrite), audit.Record(&domain.P5Project{}), homeroomHandler.DeleteProject)
+			academic.PUT("/p5-projects/:id/assessments", middleware.RequirePermission(middleware.PermHomeroomWrite), audit.RecordAction(&domain.P5Project{}, "assess"), homeroomHandler.AssessProject)
+			academic.POST("/subjects", middleware.RequirePermission(middleware.PermAcademicSubjectWrite), audit.Record(&domain.Subject{}), academicHandler.CreateSubject)
+			academic.GET("/subjects", middleware.RequirePermission(middleware.PermAcademicSubjectRead), academicHandler.GetAllSubjects)
+			academic.PUT("/subjects/:id", middleware.RequirePermission(middleware.PermAcademicSubjectWrite), audit.Record(&domain.Subject{}), academicHandler.UpdateSubject)
+			academic.DELETE("/subjects/:id", middleware.RequirePermission(middleware.PermAcademicSubjectWrite), audit.Record(&domain.Subject{}), academicHandler.DeleteSubject)
+			academic.GET("/subjects/:id/objectives", middleware.RequirePermission(middleware.PermAcademicSubjectRead), academicHandler.GetLearningObjectives)
+			academic.POST("/subjects/:id/objectives", middleware.RequirePermission(middleware.PermObjectiveWrite), audit.RecordAction(&domain.Subject{}, "add_objective"), academicHandler.CreateLearningObjective)
+			academic.PUT("/objectives/:id", middleware.RequirePermission(middleware.PermObjectiveWrite), audit.Record(&domain.LearningObjective{}), academicHandler.UpdateLearningObjective)
+			academic.DELETE("/objectives/:id", middleware.RequirePermission(middleware.PermObjectiveWrite), audit.Record(&domain.LearningObjective{}), academicHandler.DeleteLearningObjective)
+			academic.GET("/rooms", middleware.RequirePermission(middleware.PermRoomRead), academicHandler.GetRooms)
+			academic.POST("/rooms", middleware.RequirePermission(middleware.PermRoomWrite), audit.Record(&domain.Room{}), academicHandler.CreateRoom)
+			academic.GET("/rooms/occupancy", middleware.RequirePermission(middleware.PermRoomRead), academicHandler.GetRoomOccupancy)
+			academic.GET("/rooms/bookings", middleware.RequirePermission(middleware.PermRoomRead), academicHandler.GetRoomBookings)
+			academic.POST("/rooms/bookings", middleware.RequirePermission(middleware.PermRoomBook), audit.Record(&domain.RoomBooking{}), academicHandler.BookRoom)
+			academic.DELETE("/rooms/bookings/:id", middleware.RequirePermission(middleware.PermRoomBook), audit.Record(&domain.RoomBooking{}), academicHandler.CancelRoomBooking)
+			academic.PUT("/rooms/:id", middleware.RequirePermission(middleware.PermRoomWrite), audit.Record(&domain.Room{}), academicHandler.UpdateRoom)
+			academic.DELETE("/rooms/:id", middleware.RequirePermission(middleware.PermRoomWrite), audit.Record(&domain.Room{}), academicHandler.DeleteRoom)
+			academic.GET("/substitutions", middleware.RequirePermission(middleware.PermSubstitutionRead), academicHandler.GetSubstitutions)
+			academic.GET("/substitutions/suggestions", middleware.RequirePermission(middleware.PermSubstitutionWrite), academicHandler.SuggestSubstitutes)
+			academic.POST("/substitutions", middleware.RequirePermission(middleware.PermSubstitutionWrite), audit.Record(&domain.Substitution{}), academicHandler.CreateSubstitution)
+			academic.DELETE("/substitutions/:id", middleware.RequirePermission(middleware.PermSubstitutionWrite), audit.Record(&domain.Substitution{}), academicHandler.DeleteSubstitution)
+			academic.GET("/periods", middleware.RequirePermission(middleware.PermAcademicScheduleRead), academicHandler.GetPeriods)
+			academic.POST("/periods", middleware.RequirePermission(middleware.PermAcademicScheduleWrite), audit.Record(&domain.Period{}), academicHandler.CreatePeriod)
+			academic.PUT("/periods/:id", middleware.RequirePermission(middleware.PermAcademicScheduleWrite), audit.Record(&domain.Period{}), academicHandler.UpdatePeriod)
+			academic.DELETE("/periods/:id", middleware.RequirePermission(middleware.PermAcademicScheduleWrite), audit.Record(&domain.Period{}), academicHandler.DeletePeriod)
+			academic.GET("/workload", middleware.RequirePermission(middleware.PermWorkloadRead), academicHandler.GetTeacherWorkload)
+			academic.GET("/workload/export", middleware.RequirePermission(middleware.PermWorkloadRead), academicHandler.ExportTeacherWorkload)
+			academic.GET("/grade-weights", middleware.RequirePermission(middleware.PermAcademicReportRead), academicHandler.GetGradeWeights)
+			academic.PUT("/grade-weights", middleware.RequirePermission(middleware.PermGradeWeightWrite), audit.Record(&domain.GradeWeight{}), academicHandler.SetGradeWeights)
+			academic.GET("/scores", middleware.RequirePermission(middleware.PermScoreWrite), academicHandler.GetScores)
+			academic.POST("/scores", middleware.RequirePermission(middleware.PermScoreWrite), audit.Record(&domain.Score{}), academicHandler.RecordScores)
+			academic.DELETE("/scores/:id", middleware.RequirePermission(middleware.PermScoreWrite), audit.Record(&domain.Score{}), academicHandler.DeleteScore)
+			academic.POST("/scores/:id/attempts", middleware.RequirePermission(middleware.PermScoreWrite), audit.RecordAction(&domain.Score{}, "record_attempt"), academicHandler.RecordScoreAttempt)
+			academic.DELETE("/scores/attempts/:id", middleware.RequirePermission(middleware.PermScoreWrite), audit.Record(&domain.ScoreAttempt{}), academicHandler.DeleteScoreAttempt)
+			academic.GET("/kkm", middleware.RequirePermission(middleware.PermScoreWrite), academicHandler.GetKKMs)
+			academic.PUT("/kkm", middleware.RequirePermission(middleware.PermKKMWrite), audit.Record(&domain.KKM{}), academicHandler.SetKKM)
+			academic.DELETE("/kkm/:id", middleware.RequirePermission(middleware.PermKKMWrite), audit.Record(&domain.KKM{}), academicHandler.DeleteKKM)
+			academic.GET("/calendar", middleware.RequirePermission(middleware.PermCalendarRead), academicHandler.GetCalendarEvents)
+			academic.POST("/calendar", middleware.RequirePermission(middleware.PermCalendarWrite), audit.Record(&domain.CalendarEvent{}), academicHandler.CreateCalendarEvent)
+			academic.PUT("/calendar/:id", middleware.RequirePermission(middleware.PermCalendarWrite), audit.Record(&domain.CalendarEvent{}), academicHandler.UpdateCalendarEvent)
+			academic.DELETE("/calendar/:id", middleware.RequirePermission(middleware.PermCalendarWrite), audit.Record(&domain.CalendarEvent{}), academicHandler.DeleteCalendarEvent)
+			academic.POST("/schedules", middleware.RequirePermission(middleware.PermAcademicScheduleWrite), audit.Record(&domain.Schedule{}), academicHandler.CreateSchedule)
+			academic.GET("/schedules", middleware.RequirePermission(middleware.PermAcademicScheduleRead), academicHandler.GetAllSchedules)
+			academic.POST("/timetable", middleware.RequirePermission(middleware.PermAcademicScheduleWrite), audit.RecordAction(&domain.Schedule{}, "generate_timetable"), academicHandler.GenerateTimetable)
+			academic.GET("/schedules/now", middleware.RequirePermission(middleware.PermAcademicScheduleRead), academicHandler.GetCurrentSchedule)
+			academic.GET("/schedules/conflicts", middleware.RequirePermission(middleware.PermAcademicScheduleWrite), academicHandler.GetScheduleConflicts)
+			academic.PUT("/schedules/:id", middleware.RequirePermission(middleware.PermAcademicScheduleWrite), audit.Record(&domain.Schedule{}), academicHandler.UpdateSchedule)
+			academic.DELETE("/schedules/:id", middleware.RequirePermission(middleware.PermAcademicScheduleWrite), audit.Record(&domain.Schedule{}), academicHandler.DeleteSchedule)
 		}
 
-		teachers := protected.Group("/teachers")
+		teachers := enrolled.Group("/teachers")
 		{
-			teachers.GET("/", teacherHandler.GetAllTeachers)
+			teachers.GET("/", middleware.RequirePermission(middleware.PermTeacherRead), teacherHandler.GetAllTeachers)
 		}
 
-		students := protected.Group("/students")
+		students := enrolled.Group("/students")
 		{
-			students.GET("/", studentHandler.GetAllStudents)
-			students.POST("/", studentHandler.CreateStudent)
-			students.PUT("/:id", studentHandler.UpdateStudent)
-			students.DELETE("/:id", studentHandler.DeleteStudent)
-			students.GET("/children", studentHandler.GetChildren)
-			students.POST("/attendance", studentHandler.RecordAttendance)
-			students.GET("/attendance/:schedule_id", studentHandler.GetScheduleAttendance)
-			students.GET("/attendance", studentHandler.GetStudentAttendance)
+			students.GET("/", middleware.RequirePermission(middleware.PermStudentRead), studentHandler.GetAllStudents)
+			students.POST("/", middleware.RequirePermission(middleware.PermStudentWrite), audit.Record(&domain.Student{}), studentHandler.CreateStudent)
+			students.PUT("/:id", middleware.RequirePermission(middleware.PermStudentUpdate), audit.Record(&domain.Student{}), studentHandler.UpdateStudent)
+			students.DELETE("/:id", middleware.RequirePermission(middleware.PermStudentWrite), audit.Record(&domain.Student{}), studentHandler.DeleteStudent)
+			students.GET("/:id/enrollments", middleware.RequirePermission(middleware.PermStudentHistoryRead), studentHandler.GetEnrollmentHistory)
+			students.GET("/children", middleware.RequirePermission(middleware.PermStudentChildrenRead), studentHandler.GetChildren)
+			students.POST("/attendance", middleware.RequirePermission(middleware.PermAttendanceWrite), audit.Record(&domain.Attendance{}), studentHandler.RecordAttendance)
+			students.GET("/attendance/recap", middleware.RequirePermission(middleware.PermAttendanceStudentRead), studentHandler.GetAttendanceRecap)
+			students.GET("/attendance/:schedule_id", middleware.RequirePermission(middleware.PermAttendanceScheduleRead), studentHandler.GetScheduleAttendance)
+			students.GET("/attendance", middleware.RequirePermission(middleware.PermAttendanceStudentRead), studentHandler.GetStudentAttendance)
 		}
 
-		finance := protected.Group("/finance")
+		finance := enrolled.Group("/finance")
 		{
-			finance.POST("/bills", financeHandler.CreateBill)
-			finance.GET("/bills", financeHandler.GetAllBills)
-			finance.PUT("/bills/:id", financeHandler.UpdateBill)
-			finance.DELETE("/bills/:id", financeHandler.DeleteBill)
-			finance.POST("/payments", financeHandler.RecordPayment)
-			finance.PUT("/payments/:id", financeHandler.UpdatePayment)
-			finance.DELETE("/payments/:id", financeHandler.DeletePayment)
+			finance.POST("/bills", middleware.RequirePermission(middleware.PermFinanceBillWrite), audit.Record(&domain.Bill{}), financeHandler.CreateBill)
+			finance.GET("/bills", middleware.RequirePermission(middleware.PermFinanceBillRead), financeHandler.GetAllBills)
+			finance.PUT("/bills/:id", middleware.RequirePermission(middleware.PermFinanceBillWrite), audit.Record(&domain.Bill{}), financeHandler.UpdateBill)
+			finance.DELETE("/bills/:id", middleware.RequirePermission(middleware.PermFinanceBillWrite), audit.Record(&domain.Bill{}), financeHandler.DeleteBill)
+			finance.POST("/payments", middleware.RequirePermission(middleware.PermFinancePaymentWrite), audit.Record(&domain.Payment{}), financeHandler.RecordPayment)
+			finance.PUT("/payments/:id", middleware.RequirePermission(middleware.PermFinancePaymentWrite), audit.Record(&domain.Payment{}), financeHandler.UpdatePayment)
+			finance.DELETE("/payments/:id", middleware.RequirePermission(middleware.PermFinancePaymentWrite), audit.Record(&domain.Payment{}), financeHandler.DeletePayment)
 		}
 
-		users := protected.Group("/users")
+		users := enrolled.Group("/users")
 		{
-			users.GET("/", userHandler.GetAllUsers)
-			users.POST("/", userHandler.CreateUser)
-			users.PUT("/:id", userHandler.UpdateUser)
-			users.DELETE("/:id", userHandler.DeleteUser)
+			users.GET("/", middleware.RequirePermission(middleware.PermUserRead), userHandler.GetAllUsers)
+			users.POST("/", middleware.RequirePermission(middleware.PermUserWrite), audit.Record(&domain.User{}), userHandler.CreateUser)
+			users.PUT("/:id", middleware.RequirePermission(middleware.PermUserWrite), audit.Record(&domain.User{}), userHandler.UpdateUser)
+			users.DELETE("/:id", middleware.RequirePermission(middleware.PermUserWrite), audit.Record(&domain.User{}), userHandler.DeleteUser)
 		}
 
-		bk := protected.Group("/bk")
+		bk := enrolled.Group("/bk")
 		{
-			bk.POST("/violations", bkHandler.CreateViolation)
-			bk.GET("/violations", bkHandler.GetAllViolations)
-			bk.PUT("/violations/:id", bkHandler.UpdateViolation)
-			bk.DELETE("/violations/:id", bkHandler.DeleteViolation)
-			bk.POST("/calls", bkHandler.CreateBKCall)
-			bk.GET("/calls", bkHandler.GetAllBKCalls)
-			bk.PUT("/calls/:id", bkHandler.UpdateBKCall)
-			bk.DELETE("/calls/:id", bkHandler.DeleteBKCall)
+			bk.POST("/violations", middleware.RequirePermission(middleware.PermBKViolationCreate), audit.Record(&domain.Violation{}), bkHandler.CreateViolation)
+			bk.GET("/violations", middleware.RequirePermission(middleware.PermBKViolationRead), bkHandler.GetAllViolations)
+			bk.PUT("/violations/:id", middleware.RequirePermission(middleware.PermBKViolationWrite), audit.Record(&domain.Violation{}), bkHandler.UpdateViolation)
+			bk.DELETE("/violations/:id", middleware.RequirePermission(middleware.PermBKViolationWrite), audit.Record(&domain.Violation{}), bkHandler.DeleteViolation)
+			bk.POST("/calls", middleware.RequirePermission(middleware.PermBKCallCreate), audit.Record(&domain.BKCall{}), bkHandler.CreateBKCall)
+			bk.GET("/calls", middleware.RequirePermission(middleware.PermBKCallRead), bkHandler.GetAllBKCalls)
+			bk.PUT("/calls/:id", middleware.RequirePermission(middleware.PermBKCallWrite), audit.Record(&domain.BKCall{}), bkHandler.UpdateBKCall)
+			bk.DELETE("/calls/:id", middleware.RequirePermission(middleware.PermBKCallWrite), audit.Record(&domain.BKCall{}), bkHandler.DeleteBKCall)
 		}
 
-		elearning := protected.Group("/elearning")
+		elearning := enrolled.Group("/elearning")
 		{
-			elearning.POST("/materials", elearningHandler.CreateMaterial)
-			elearning.GET("/materials", elearningHandler.GetMaterials)
-			elearning.PUT("/materials/:id", elearningHandler.UpdateMaterial)
-			elearning.DELETE("/materials/:id", elearningHandler.DeleteMaterial)
-			elearning.POST("/tasks", elearningHandler.CreateTask)
-			elearning.GET("/tasks", elearningHandler.GetTasks)
-			elearning.PUT("/tasks/:id", elearningHandler.UpdateTask)
-			elearning.DELETE("/tasks/:id", elearningHandler.DeleteTask)
-			elearning.GET("/tasks/:id/submissions", elearningHandler.GetSubmissions)
-			elearning.PUT("/submissions/:id/grade", elearningHandler.GradeSubmission)
-			elearning.DELETE("/submissions/:id", elearningHandler.DeleteSubmission)
-			elearning.POST("/submissions", elearningHandler.SubmitTask)
-			elearning.GET("/submissions", elearningHandler.GetStudentSubmissions)
+			elearning.POST("/materials", middleware.RequirePermission(middleware.PermElearningWrite), audit.Record(&domain.Material{}), elearningHandler.CreateMaterial)
+			elearning.GET("/materials", middleware.RequirePermission(middleware.PermElearningRead), elearningHandler.GetMaterials)
+			elearning.PUT("/materials/:id", middleware.RequirePermission(middleware.PermElearningWrite), audit.Record(&domain.Material{}), elearningHandler.UpdateMaterial)
+			elearning.DELETE("/materials/:id", middleware.RequirePermission(middleware.PermElearningWrite), audit.Record(&domain.Material{}), elearningHandler.DeleteMaterial)
+			elearning.POST("/tasks", middleware.RequirePermission(middleware.PermElearningWrite), audit.Record(&domain.Task{}), elearningHandler.CreateTask)
+			elearning.GET("/tasks", middleware.RequirePermission(middleware.PermElearningRead), elearningHandler.GetTasks)
+			elearning.PUT("/tasks/:id", middleware.RequirePermission(middleware.PermElearningWrite), audit.Record(&domain.Task{}), elearningHandler.UpdateTask)
+			elearning.DELETE("/tasks/:id", middleware.RequirePermission(middleware.PermElearningWrite), audit.Record(&domain.Task{}), elearningHandler.DeleteTask)
+			elearning.GET("/tasks/:id/submissions", middleware.RequirePermission(middleware.PermElearningSubmissionRead), elearningHandler.GetSubmissions)
+			elearning.PUT("/submissions/:id/grade", middleware.RequirePermission(middleware.PermElearningSubmissionGrade), audit.RecordAction(&domain.TaskSubmission{}, "grade"), elearningHandler.GradeSubmission)
+			elearning.DELETE("/submissions/:id", middleware.RequirePermission(middleware.PermElearningSubmissionGrade), audit.Record(&domain.TaskSubmission{}), elearningHandler.DeleteSubmission)
+			elearning.POST("/submissions", middleware.RequirePermission(middleware.PermElearningSubmissionCreate), audit.Record(&domain.TaskSubmission{}), elearningHandler.SubmitTask)
+			elearning.GET("/submissions", middleware.RequirePermission(middleware.PermElearningSubmissionOwn), elearningHandler.GetStudentSubmissions)
 		}
 
-		notifications := protected.Group("/notifications")
+		notifications := enrolled.Group("/notifications")
 		{
-			notifications.GET("/", notificationHandler.GetNotifications)
-			notifications.GET("/all", notificationHandler.GetAllNotifications)
-			notifications.PUT("/:id/read", notificationHandler.MarkAsRead)
-			notifications.POST("/", notificationHandler.SendNotification)
-			notifications.DELETE("/:id", notificationHandler.DeleteNotification)
+			notifications.GET("/", middleware.RequirePermission(middleware.PermNotificationRead), notificationHandler.GetNotifications)
+			notifications.GET("/all", middleware.RequirePermission(middleware.PermNotificationManage), notificationHandler.GetAllNotifications)
+			notifications.PUT("/:id/read", middleware.RequirePermission(middleware.PermNotificationRead), notificationHandler.MarkAsRead)
+			notifications.POST("/", middleware.RequirePermission(middleware.PermNotificationManage), audit.Record(&domain.Notification{}), notificationHandler.SendNotification)
+			notifications.DELETE("/:id", middleware.RequirePermission(middleware.PermNotificationManage), audit.Record(&domain.Notification{}), notificationHandler.DeleteNotification)
 		}
 
 		// PPDB Management (Admin)
-		ppdb := protected.Group("/ppdb")
+		ppdb := enrolled.Group("/ppdb")
 		{
-			ppdb.GET("/", publicHandler.GetPPDBRegistrations)
-			ppdb.PUT("/:id/status", publicHandler.UpdatePPDBStatus)
-			ppdb.DELETE("/:id", publicHandler.DeletePPDBRegistration)
+			ppdb.GET("/", middleware.RequirePermission(middleware.PermPPDBManage), publicHandler.GetPPDBRegistrations)
+			ppdb.PUT("/:id/status", middleware.RequirePermission(middleware.PermPPDBManage), audit.Record(&domain.PPDBRegistration{}), publicHandler.UpdatePPDBStatus)
+			ppdb.DELETE("/:id", middleware.RequirePermission(middleware.PermPPDBManage), audit.Record(&domain.PPDBRegistration{}), publicHandler.DeletePPDBRegistration)
 		}
 
 		// Public Content Management (Admin)
-		publicContent := protected.Group("/public-content")
+		publicContent := enrolled.Group("/public-content")
 		{
-			publicContent.POST("/teachers", publicHandler.CreatePublicTeacher)
-			publicContent.PUT("/teachers/:id", publicHandler.UpdatePublicTeacher)
-			publicContent.DELETE("/teachers/:id", publicHandler.DeletePublicTeacher)
-			publicContent.POST("/downloads", publicHandler.CreateDownload)
-			publicContent.PUT("/downloads/:id", publicHandler.UpdateDownload)
-			publicContent.DELETE("/downloads/:id", publicHandler.DeleteDownload)
-			publicContent.POST("/alumni", publicHandler.CreateAlumni)
-			publicContent.PUT("/alumni/:id", publicHandler.UpdateAlumni)
-			publicContent.DELETE("/alumni/:id", publicHandler.DeleteAlumni)
+			publicContent.POST("/teachers", middleware.RequirePermission(middleware.PermPublicContentManage), audit.Record(&domain.PublicTeacher{}), publicHandler.CreatePublicTeacher)
+			publicContent.PUT("/teachers/:id", middleware.RequirePermission(middleware.PermPublicContentManage), audit.Record(&domain.PublicTeacher{}), publicHandler.UpdatePublicTeacher)
+			publicContent.DELETE("/teachers/:id", middleware.RequirePermission(middleware.PermPublicContentManage), audit.Record(&domain.PublicTeacher{}), publicHandler.DeletePublicTeacher)
+			publicContent.POST("/downloads", middleware.RequirePermission(middleware.PermPublicContentManage), audit.Record(&domain.Download{}), publicHandler.CreateDownload)
+			publicContent.PUT("/downloads/:id", middleware.RequirePermission(middleware.PermPublicContentManage), audit.Record(&domain.Download{}), publicHandler.UpdateDownload)
+			publicContent.DELETE("/downloads/:id", middleware.RequirePermission(middleware.PermPublicContentManage), audit.Record(&domain.Download{}), publicHandler.DeleteDownload)
+			publicContent.GET("/alumni", middleware.RequirePermission(middleware.PermPublicContentManage), publicHandler.GetAllAlumni)
+			publicContent.POST("/alumni", middleware.RequirePermission(middleware.PermPublicContentManage), audit.Record(&domain.Alumni{}), publicHandler.CreateAlumni)
+			publicContent.PUT("/alumni/:id", middleware.RequirePermission(middleware.PermPublicContentManage), audit.Record(&domain.Alumni{}), publicHandler.UpdateAlumni)
+			publicContent.DELETE("/alumni/:id", middleware.RequirePermission(middleware.PermPublicContentManage), audit.Record(&domain.Alumni{}), publicHandler.DeleteAlumni)
 		}
 
 		// Contact Messages (Admin)
-		admin := protected.Group("/admin")
+		admin := enrolled.Group("/admin")
 		{
-			admin.GET("/contacts", publicHandler.GetContactMessages)
-			admin.DELETE("/contacts/:id", publicHandler.DeleteContactMessage)
+			admin.GET("/contacts", middleware.RequirePermission(middleware.PermContactManage), publicHandler.GetContactMessages)
+			admin.DELETE("/contacts/:id", middleware.RequirePermission(middleware.PermContactManage), audit.Record(&domain.ContactMessage{}), publicHandler.DeleteContactMessage)
+
+			admin.POST("/users/:id/unlock", middleware.RequirePermission(middleware.PermUserWrite), audit.RecordAction(&domain.User{}, "unlock"), authHandler.UnlockUser)
+			admin.POST("/users/:id/2fa/reset", middleware.RequirePermission(middleware.PermUserWrite), audit.RecordAction(&domain.User{}, "reset_2fa"), authHandler.ResetMFA)
+
+			admin.POST("/impersonate/:user_id", middleware.RequireRoles(domain.RoleSuperAdmin), audit.RecordAction(&domain.User{}, "impersonate"), authHandler.Impersonate)
+
+			admin.GET("/audit-logs", middleware.RequirePermission(middleware.PermAuditLogRead), auditHandler.GetAuditLogs)
+
+			admin.POST("/invitations", middleware.RequirePermission(middleware.PermInvitationManage), audit.Record(&domain.Invitation{}), invitationHandler.CreateInvitation)
+			admin.GET("/invitations", middleware.RequirePermission(middleware.PermInvitationManage), invitationHandler.GetInvitations)
+			admin.DELETE("/invitations/:id", middleware.RequirePermission(middleware.PermInvitationManage), audit.Record(&domain.Invitation{}), invitationHandler.DeleteInvitation)
 		}
 	}
 }
@@ -0,0 +1,379 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"ppi-100-sis/internal/config"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/pkg/utils"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	pg "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeDB answers every query with no rows, except the per-request token
+// version check, so requests get past AuthMiddleware without a database.
+type fakeDB struct{}
+
+func (fakeDB) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query: query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return fakeConn{}, nil }
+func (fakeConn) Commit() error                             { return nil }
+func (fakeConn) Rollback() error                           { return nil }
+
+type fakeStmt struct{ query string }
+
+func (fakeStmt) Close() error                               { return nil }
+func (fakeStmt) NumInput() int                              { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(0), nil }
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	if strings.Contains(s.query, "token_version") {
+		return &fakeRows{columns: []string{"token_version"}, values: [][]driver.Value{{int64(0)}}}, nil
+	}
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("routes-fake", fakeDB{})
+}
+
+// Role IDs as seeded, shortened for the table below
+const (
+	su    = domain.RoleSuperAdmin
+	mts   = domain.RoleAdminMTS
+	ma    = domain.RoleAdminMA
+	guru  = domain.RoleGuru
+	wali  = domain.RoleWaliKelas
+	siswa = domain.RoleSiswa
+	ortu  = domain.RoleOrangTua
+)
+
+// openRoutes need no role: public pages, sign-in and one's own profile.
+var openRoutes = map[string]bool{
+	"GET /api/public/teachers":             true,
+	"GET /api/public/downloads":            true,
+	"GET /api/public/alumni":               true,
+	"POST /api/public/ppdb":                true,
+	"POST /api/public/contact":             true,
+	"GET /api/public/calendar.ics":         true,
+	"GET /api/public/calendar/feed/:token": true,
+	"POST /api/auth/register":              true,
+	"POST /api/auth/login":                 true,
+	"POST /api/auth/login/mfa":             true,
+	"POST /api/auth/refresh":               true,
+	"POST /api/auth/logout":                true,
+	"POST /api/auth/forgot-password":       true,
+	"POST /api/auth/reset-password":        true,
+	"POST /api/auth/invitations/accept":    true,
+	"GET /api/profile":                     true,
+	"PUT /api/profile/password":            true,
+	"PUT /api/profile":                     true,
+	"POST /api/profile/photo":              true,
+	"GET /api/profile/2fa":                 true,
+	"POST /api/profile/2fa/setup":          true,
+	"POST /api/profile/2fa/enable":         true,
+	"POST /api/profile/2fa/disable":        true,
+	"POST /api/profile/2fa/recovery-codes": true,
+	"GET /api/profile/calendar-feed":       true,
+	"POST /api/profile/calendar-feed":      true,
+	"DELETE /api/profile/calendar-feed":    true,
+}
+
+// gatedRoutes lists the roles each route lets through, with the permission
+// guarding it. Changing a grant in RolePermissions must change this table too.
+var gatedRoutes = map[string][]uint{
+	"POST /api/academic/years":                       {su, mts, ma},                          // PermAcademicTermWrite
+	"GET /api/academic/years":                        {su, mts, ma, guru, wali, siswa, ortu}, // PermAcademicTermRead
+	"PUT /api/academic/years/:id":                    {su, mts, ma},                          // PermAcademicTermWrite
+	"DELETE /api/academic/years/:id":                 {su, mts, ma},                          // PermAcademicTermWrite
+	"POST /api/academic/semesters":                   {su, mts, ma},                          // PermAcademicTermWrite
+	"GET /api/academic/semesters/active":             {su, mts, ma, guru, wali, siswa, ortu}, // PermAcademicTermRead
+	"PUT /api/academic/semesters/:id":                {su, mts, ma},                          // PermAcademicTermWrite
+	"DELETE /api/academic/semesters/:id":             {su, mts, ma},                          // PermAcademicTermWrite
+	"POST /api/academic/semesters/:id/activate":      {su, mts, ma},                          // PermAcademicTermWrite
+	"POST /api/academic/promotions":                  {su, mts, ma},                          // PermAcademicPromotion
+	"POST /api/academic/classes":                     {su, mts, ma},                          // PermAcademicClassWrite
+	"GET /api/academic/classes":                      {su, mts, ma, guru, wali},              // PermAcademicClassRead
+	"PUT /api/academic/classes/:id":                  {su, mts, ma},                          // PermAcademicClassWrite
+	"DELETE /api/academic/classes/:id":               {su, mts, ma},                          // PermAcademicClassWrite
+	"GET /api/academic/classes/homeroom":             {su, mts, ma, guru, wali},              // PermAcademicHomeroomRead
+	"GET /api/academic/report-cards/remedial":        {su, mts, ma, guru, wali},              // PermScoreWrite
+	"GET /api/academic/report-cards/:student_id":     {su, mts, ma, guru, wali, siswa, ortu}, // PermAcademicReportRead
+	"GET /api/academic/report-cards/:student_id/pdf": {su, mts, ma, wali},                    // PermRaporPrint
+	"GET /api/academic/classes/:id/report-cards":     {su, mts, ma, wali},                    // PermRaporPrint
+	"GET /api/academic/classes/:id/homeroom-notes":   {su, mts, ma, wali},                    // PermHomeroomWrite
+	"POST /api/academic/homeroom-notes":              {su, mts, ma, wali},                    // PermHomeroomWrite
+	"PUT /api/academic/homeroom-notes/:id":           {su, mts, ma, wali},                    // PermHomeroomWrite
+	"GET /api/academic/classes/:id/p5-projects":      {su, mts, ma, wali},                    // PermHomeroomWrite
+	"POST /api/academic/p5-projects":                 {su, mts, ma, wali},                    // PermHomeroomWrite
+	"PUT /api/academic/p5-projects/:id":              {su, mts, ma, wali},                    // PermHomeroomWrite
+	"DELETE /api/academic/p5-projects/:id":           {su, mts, ma, wali},                    // PermHomeroomWrite
+	"PUT /api/academic/p5-projects/:id/assessments":  {su, mts, ma, wali},                    // PermHomeroomWrite
+	"POST /api/academic/subjects":                    {su, mts, ma},                          // PermAcademicSubjectWrite
+	"GET /api/academic/subjects":                     {su, mts, ma, guru, wali},              // PermAcademicSubjectRead
+	"PUT /api/academic/subjects/:id":                 {su, mts, ma},                          // PermAcademicSubjectWrite
+	"DELETE /api/academic/subjects/:id":              {su, mts, ma},                          // PermAcademicSubjectWrite
+	"GET /api/academic/subjects/:id/objectives":      {su, mts, ma, guru, wali},              // PermAcademicSubjectRead
+	"POST /api/academic/subjects/:id/objectives":     {su, mts, ma, guru, wali},              // PermObjectiveWrite
+	"PUT /api/academic/objectives/:id":               {su, mts, ma, guru, wali},              // PermObjectiveWrite
+	"DELETE /api/academic/objectives/:id":            {su, mts, ma, guru, wali},              // PermObjectiveWrite
+	"GET /api/academic/rooms":                        {su, mts, ma, guru, wali},              // PermRoomRead
+	"POST /api/academic/rooms":                       {su, mts, ma},                          // PermRoomWrite
+	"GET /api/academic/rooms/occupancy":              {su, mts, ma, guru, wali},              // PermRoomRead
+	"GET /api/academic/rooms/bookings":               {su, mts, ma, guru, wali},              // PermRoomRead
+	"POST /api/academic/rooms/bookings":              {su, mts, ma, guru, wali},              // PermRoomBook
+	"DELETE /api/academic/rooms/bookings/:id":        {su, mts, ma, guru, wali},              // PermRoomBook
+	"PUT /api/academic/rooms/:id":                    {su, mts, ma},                          // PermRoomWrite
+	"DELETE /api/academic/rooms/:id":                 {su, mts, ma},                          // PermRoomWrite
+	"GET /api/academic/substitutions":                {su, mts, ma, guru, wali},              // PermSubstitutionRead
+	"GET /api/academic/substitutions/suggestions":    {su, mts, ma},                          // PermSubstitutionWrite
+	"POST /api/academic/substitutions":               {su, mts, ma},                          // PermSubstitutionWrite
+	"DELETE /api/academic/substitutions/:id":         {su, mts, ma},                          // PermSubstitutionWrite
+	"GET /api/academic/periods":                      {su, mts, ma, guru, wali, siswa},       // PermAcademicScheduleRead
+	"POST /api/academic/periods":                     {su, mts, ma},                          // PermAcademicScheduleWrite
+	"PUT /api/academic/periods/:id":                  {su, mts, ma},                          // PermAcademicScheduleWrite
+	"DELETE /api/academic/periods/:id":               {su, mts, ma},                          // PermAcademicScheduleWrite
+	"GET /api/academic/workload":                     {su, mts, ma},                          // PermWorkloadRead
+	"GET /api/academic/workload/export":              {su, mts, ma},                          // PermWorkloadRead
+	"GET /api/academic/grade-weights":                {su, mts, ma, guru, wali, siswa, ortu}, // PermAcademicReportRead
+	"PUT /api/academic/grade-weights":                {su, mts, ma},                          // PermGradeWeightWrite
+	"GET /api/academic/scores":                       {su, mts, ma, guru, wali},              // PermScoreWrite
+	"POST /api/academic/scores":                      {su, mts, ma, guru, wali},              // PermScoreWrite
+	"DELETE /api/academic/scores/:id":                {su, mts, ma, guru, wali},              // PermScoreWrite
+	"POST /api/academic/scores/:id/attempts":         {su, mts, ma, guru, wali},              // PermScoreWrite
+	"DELETE /api/academic/scores/attempts/:id":       {su, mts, ma, guru, wali},              // PermScoreWrite
+	"GET /api/academic/kkm":                          {su, mts, ma, guru, wali},              // PermScoreWrite
+	"PUT /api/academic/kkm":                          {su, mts, ma},                          // PermKKMWrite
+	"DELETE /api/academic/kkm/:id":                   {su, mts, ma},                          // PermKKMWrite
+	"GET /api/academic/calendar":                     {su, mts, ma, guru, wali, siswa, ortu}, // PermCalendarRead
+	"POST /api/academic/calendar":                    {su, mts, ma},                          // PermCalendarWrite
+	"PUT /api/academic/calendar/:id":                 {su, mts, ma},                          // PermCalendarWrite
+	"DELETE /api/academic/calendar/:id":              {su, mts, ma},                          // PermCalendarWrite
+	"POST /api/academic/schedules":                   {su, mts, ma},                          // PermAcademicScheduleWrite
+	"GET /api/academic/schedules":                    {su, mts, ma, guru, wali, siswa},       // PermAcademicScheduleRead
+	"POST /api/academic/timetable":                   {su, mts, ma},                          // PermAcademicScheduleWrite
+	"GET /api/academic/schedules/now":                {su, mts, ma, guru, wali, siswa},       // PermAcademicScheduleRead
+	"GET /api/academic/schedules/conflicts":          {su, mts, ma},                          // PermAcademicScheduleWrite
+	"PUT /api/academic/schedules/:id":                {su, mts, ma},                          // PermAcademicScheduleWrite
+	"DELETE /api/academic/schedules/:id":             {su, mts, ma},                          // PermAcademicScheduleWrite
+	"GET /api/teachers/":                             {su, mts, ma, guru, wali},              // PermTeacherRead
+	"GET /api/students/":                             {su, mts, ma, guru, wali},              // PermStudentRead
+	"POST /api/students/":                            {su, mts, ma},                          // PermStudentWrite
+	"PUT /api/students/:id":                          {su, mts, ma, wali},                    // PermStudentUpdate
+	"DELETE /api/students/:id":                       {su, mts, ma},                          // PermStudentWrite
+	"GET /api/students/:id/enrollments":              {su, mts, ma, guru, wali},              // PermStudentHistoryRead
+	"GET /api/students/children":                     {ortu},                                 // PermStudentChildrenRead
+	"POST /api/students/attendance":                  {su, mts, ma, guru, wali},              // PermAttendanceWrite
+	"GET /api/students/attendance/recap":             {su, mts, ma, guru, wali, siswa, ortu}, // PermAttendanceStudentRead
+	"GET /api/students/attendance/:schedule_id":      {su, mts, ma, guru, wali},              // PermAttendanceScheduleRead
+	"GET /api/students/attendance":                   {su, mts, ma, guru, wali, siswa, ortu}, // PermAttendanceStudentRead
+	"POST /api/finance/bills":                        {su, mts, ma},                          // PermFinanceBillWrite
+	"GET /api/finance/bills":                         {su, mts, ma, siswa, ortu},             // PermFinanceBillRead
+	"PUT /api/finance/bills/:id":                     {su, mts, ma},                          // PermFinanceBillWrite
+	"DELETE /api/finance/bills/:id":                  {su, mts, ma},                          // PermFinanceBillWrite
+	"POST /api/finance/payments":                     {su, mts, ma},                          // PermFinancePaymentWrite
+	"PUT /api/finance/payments/:id":                  {su, mts, ma},                          // PermFinancePaymentWrite
+	"DELETE /api/finance/payments/:id":               {su, mts, ma},                          // PermFinancePaymentWrite
+	"GET /api/users/":                                {su, mts, ma, guru, wali},              // PermUserRead
+	"POST /api/users/":                               {su, mts, ma},                          // PermUserWrite
+	"PUT /api/users/:id":                             {su, mts, ma},                          // PermUserWrite
+	"DELETE /api/users/:id":                          {su, mts, ma},                          // PermUserWrite
+	"POST /api/bk/violations":                        {su, mts, ma, guru, wali},              // PermBKViolationCreate
+	"GET /api/bk/violations":                         {su, mts, ma, guru, wali},              // PermBKViolationRead
+	"PUT /api/bk/violations/:id":                     {su, mts, ma},                          // PermBKViolationWrite
+	"DELETE /api/bk/violations/:id":                  {su, mts, ma},                          // PermBKViolationWrite
+	"POST /api/bk/calls":                             {su, mts, ma, guru, wali},              // PermBKCallCreate
+	"GET /api/bk/calls":                              {su, mts, ma, guru, wali, siswa, ortu}, // PermBKCallRead
+	"PUT /api/bk/calls/:id":                          {su, mts, ma},                          // PermBKCallWrite
+	"DELETE /api/bk/calls/:id":                       {su, mts, ma},                          // PermBKCallWrite
+	"POST /api/elearning/materials":                  {su, mts, ma, guru, wali},              // PermElearningWrite
+	"GET /api/elearning/materials":                   {su, mts, ma, guru, wali, siswa, ortu}, // PermElearningRead
+	"PUT /api/elearning/materials/:id":               {su, mts, ma, guru, wali},              // PermElearningWrite
+	"DELETE /api/elearning/materials/:id":            {su, mts, ma, guru, wali},              // PermElearningWrite
+	"POST /api/elearning/tasks":                      {su, mts, ma, guru, wali},              // PermElearningWrite
+	"GET /api/elearning/tasks":                       {su, mts, ma, guru, wali, siswa, ortu}, // PermElearningRead
+	"PUT /api/elearning/tasks/:id":                   {su, mts, ma, guru, wali},              // PermElearningWrite
+	"DELETE /api/elearning/tasks/:id":                {su, mts, ma, guru, wali},              // PermElearningWrite
+	"GET /api/elearning/tasks/:id/submissions":       {su, mts, ma, guru, wali},              // PermElearningSubmissionRead
+	"PUT /api/elearning/submissions/:id/grade":       {su, mts, ma, guru, wali},              // PermElearningSubmissionGrade
+	"DELETE /api/elearning/submissions/:id":          {su, mts, ma, guru, wali},              // PermElearningSubmissionGrade
+	"POST /api/elearning/submissions":                {siswa},                                // PermElearningSubmissionCreate
+	"GET /api/elearning/submissions":                 {su, mts, ma, guru, wali, siswa, ortu}, // PermElearningSubmissionOwn
+	"GET /api/notifications/":                        {su, mts, ma, guru, wali, siswa, ortu}, // PermNotificationRead
+	"GET /api/notifications/all":                     {su, mts, ma},                          // PermNotificationManage
+	"PUT /api/notifications/:id/read":                {su, mts, ma, guru, wali, siswa, ortu}, // PermNotificationRead
+	"POST /api/notifications/":                       {su, mts, ma},                          // PermNotificationManage
+	"DELETE /api/notifications/:id":                  {su, mts, ma},                          // PermNotificationManage
+	"GET /api/ppdb/":                                 {su, mts, ma},                          // PermPPDBManage
+	"PUT /api/ppdb/:id/status":                       {su, mts, ma},                          // PermPPDBManage
+	"DELETE /api/ppdb/:id":                           {su, mts, ma},                          // PermPPDBManage
+	"POST /api/public-content/teachers":              {su, mts, ma},                          // PermPublicContentManage
+	"PUT /api/public-content/teachers/:id":           {su, mts, ma},                          // PermPublicContentManage
+	"DELETE /api/public-content/teachers/:id":        {su, mts, ma},                          // PermPublicContentManage
+	"POST /api/public-content/downloads":             {su, mts, ma},                          // PermPublicContentManage
+	"PUT /api/public-content/downloads/:id":          {su, mts, ma},                          // PermPublicContentManage
+	"DELETE /api/public-content/downloads/:id":       {su, mts, ma},                          // PermPublicContentManage
+	"GET /api/public-content/alumni":                 {su, mts, ma},                          // PermPublicContentManage
+	"POST /api/public-content/alumni":                {su, mts, ma},                          // PermPublicContentManage
+	"PUT /api/public-content/alumni/:id":             {su, mts, ma},                          // PermPublicContentManage
+	"DELETE /api/public-content/alumni/:id":          {su, mts, ma},                          // PermPublicContentManage
+	"GET /api/admin/contacts":                        {su, mts, ma},                          // PermContactManage
+	"DELETE /api/admin/contacts/:id":                 {su, mts, ma},                          // PermContactManage
+	"POST /api/admin/users/:id/unlock":               {su, mts, ma},                          // PermUserWrite
+	"POST /api/admin/users/:id/2fa/reset":            {su, mts, ma},                          // PermUserWrite
+	"POST /api/admin/impersonate/:user_id":           {su},
+	"GET /api/admin/audit-logs":                      {su, mts, ma}, // PermAuditLogRead
+	"POST /api/admin/invitations":                    {su, mts, ma}, // PermInvitationManage
+	"GET /api/admin/invitations":                     {su, mts, ma}, // PermInvitationManage
+	"DELETE /api/admin/invitations/:id":              {su, mts, ma}, // PermInvitationManage
+}
+
+var allRoleIDs = []uint{su, mts, ma, guru, wali, siswa, ortu}
+
+const forbiddenBody = `{"error":"You do not have permission to access this resource"}`
+
+func setupTestRouter(t *testing.T) (*gin.Engine, *config.Config) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	conn, err := sql.Open("routes-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(pg.New(pg.Config{Conn: conn}), &gorm.Config{DisableAutomaticPing: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.MailLogPath = t.TempDir() + "/mail.log"
+
+	r := gin.New()
+	r.Use(gin.Recovery())
+	SetupRoutes(r, db, cfg)
+	return r, cfg
+}
+
+// forbidden reports whether the permission middleware turned roleID away.
+func forbidden(t *testing.T, r *gin.Engine, cfg *config.Config, method, path string, roleID uint) bool {
+	t.Helper()
+	token, err := utils.GenerateToken(&utils.Claims{UserID: uuid.New(), RoleID: roleID, UnitID: domain.UnitMTS}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequestWithContext(context.Background(), method, path, strings.NewReader("{}"))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code == http.StatusForbidden && w.Body.String() == forbiddenBody
+}
+
+func TestRoutePermissions(t *testing.T) {
+	r, cfg := setupTestRouter(t)
+
+	seen := map[string]bool{}
+	for _, route := range r.Routes() {
+		key := route.Method + " " + route.Path
+		seen[key] = true
+		if openRoutes[key] {
+			continue
+		}
+		allowed, ok := gatedRoutes[key]
+		if !ok {
+			t.Errorf("%s has no entry in gatedRoutes; guard it with RequirePermission or RequireRoles", key)
+			continue
+		}
+
+		t.Run(key, func(t *testing.T) {
+			path := strings.NewReplacer(":id", "1", ":schedule_id", "1", ":student_id", uuid.NewString(), ":user_id", uuid.NewString()).Replace(route.Path)
+			if !forbidden(t, r, cfg, route.Method, path, 0) {
+				t.Fatal("route has no RequirePermission or RequireRoles")
+			}
+			for _, roleID := range allRoleIDs {
+				want := true
+				for _, a := range allowed {
+					if a == roleID {
+						want = false
+					}
+				}
+				if got := forbidden(t, r, cfg, route.Method, path, roleID); got != want {
+					t.Errorf("role %d: forbidden = %v, want %v", roleID, got, want)
+				}
+			}
+		})
+	}
+
+	for key := range gatedRoutes {
+		if !seen[key] {
+			t.Errorf("gatedRoutes lists %s, which is not registered", key)
+		}
+	}
+	for key := range openRoutes {
+		if !seen[key] {
+			t.Errorf("openRoutes lists %s, which is not registered", key)
+		}
+	}
+}
+
+// Students and parents reach only their own records, so another student's ID
+// is turned away even though the route lets their role through.
+func TestStudentRecordsOwnOnly(t *testing.T) {
+	r, cfg := setupTestRouter(t)
+	other := uuid.NewString()
+
+	paths := []string{
+		"/api/academic/report-cards/" + other,
+		"/api/students/attendance?student_id=" + other,
+		"/api/students/attendance/recap?student_id=" + other,
+		"/api/finance/bills?student_id=" + other,
+		"/api/bk/calls?student_id=" + other,
+		"/api/bk/calls",
+	}
+	for _, roleID := range []uint{siswa, ortu} {
+		token, err := utils.GenerateToken(&utils.Claims{UserID: uuid.New(), RoleID: roleID, UnitID: domain.UnitMTS}, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range paths {
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusForbidden {
+				t.Errorf("role %d GET %s: status = %d, want %d", roleID, path, w.Code, http.StatusForbidden)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,19 +11,24 @@ import (
 // Core Tables
 
 type User struct {
-	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	Name         string    `gorm:"not null" json:"name"`
-	Email        string    `gorm:"uniqueIndex;not null" json:"email"`
-	PasswordHash string    `gorm:"not null" json:"-"`
-	PhotoURL     string    `json:"photo_url"`
-	RoleID       uint      `gorm:"not null" json:"role_id"`
-	UnitID       uint      `gorm:"not null" json:"unit_id"`
-	Teacher      *Teacher  `gorm:"foreignKey:UserID" json:"teacher,omitempty"`
-	Parent       *Parent   `gorm:"foreignKey:UserID" json:"parent,omitempty"`
-	Student      *Student  `gorm:"foreignKey:UserID" json:"student,omitempty"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                 uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name               string         `gorm:"not null" json:"name"`
+	Email              string         `gorm:"uniqueIndex;not null" json:"email"`
+	PasswordHash       string         `gorm:"not null" json:"-"`
+	PhotoURL           string         `json:"photo_url"`
+	RoleID             uint           `gorm:"not null" json:"role_id"`
+	UnitID             uint           `gorm:"not null" json:"unit_id"`
+	Teacher            *Teacher       `gorm:"foreignKey:UserID" json:"teacher,omitempty"`
+	Parent             *Parent        `gorm:"foreignKey:UserID" json:"parent,omitempty"`
+	Student            *Student       `gorm:"foreignKey:UserID" json:"student,omitempty"`
+	TokenVersion       int            `gorm:"not null;default:0" json:"-"` // Bumped to revoke every issued token
+	TOTPSecret         string         `json:"-"`
+	TOTPEnabled        bool           `gorm:"not null;default:false" json:"totp_enabled"`
+	TOTPLastStep       int64          `json:"-"`                                                  // Last accepted TOTP time step, blocks code replay
+	MustChangePassword bool           `gorm:"not null;default:false" json:"must_change_password"` // Set for admin-assigned passwords
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type Role struct {
@@ -35,8 +41,117 @@ type Unit struct {
 	Name string `gorm:"unique;not null"` // MTS, MA, PUBLIC
 }
 
+// Auth
+
+type RefreshToken struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	TokenHash  string     `gorm:"uniqueIndex;not null" json:"-"` // SHA-256 of the opaque token
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	ReplacedBy *uuid.UUID `gorm:"type:uuid" json:"replaced_by"` // Set when rotated
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"` // Single use
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CalendarFeedToken authenticates a user's personal .ics subscription URL.
+// A user has at most one; deleting it revokes the URL.
+type CalendarFeedToken struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	TokenHash  string     `gorm:"uniqueIndex;not null" json:"-"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// LoginAttempt holds failed login counters for one key, either
+// "account:<email>" or "ip:<address>".
+type LoginAttempt struct {
+	Key           string     `gorm:"primaryKey" json:"key"`
+	Failures      int        `gorm:"not null;default:0" json:"failures"`
+	WindowStart   time.Time  `gorm:"not null" json:"window_start"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until"`
+}
+
+type Invitation struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Email       string     `gorm:"not null;index" json:"email"`
+	Name        string     `json:"name"`
+	RoleID      uint       `gorm:"not null" json:"role_id"`
+	UnitID      uint       `gorm:"not null" json:"unit_id"`
+	InvitedByID uuid.UUID  `gorm:"type:uuid;not null" json:"invited_by_id"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
 // Akademik
 
+// AcademicYear is a tahun ajaran such as "2025/2026". Each unit has at most
+// one active year, holding its active semester.
+type AcademicYear struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Name      string     `gorm:"not null" json:"name"`
+	UnitID    uint       `gorm:"not null;index" json:"unit_id"`
+	StartDate time.Time  `gorm:"not null" json:"start_date"`
+	EndDate   time.Time  `gorm:"not null" json:"end_date"`
+	IsActive  bool       `gorm:"not null;default:false" json:"is_active"`
+	Semesters []Semester `gorm:"foreignKey:AcademicYearID" json:"semesters,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type Semester struct {
+	ID             uint          `gorm:"primaryKey" json:"id"`
+	AcademicYearID uint          `gorm:"not null;index" json:"academic_year_id"`
+	AcademicYear   *AcademicYear `gorm:"foreignKey:AcademicYearID" json:"academic_year,omitempty"`
+	Name           string        `gorm:"not null" json:"name"` // Ganjil, Genap
+	StartDate      time.Time     `gorm:"not null" json:"start_date"`
+	EndDate        time.Time     `gorm:"not null" json:"end_date"`
+	IsActive       bool          `gorm:"not null;default:false" json:"is_active"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// Calendar event types
+const (
+	CalendarHoliday  = "holiday"
+	CalendarExamWeek = "exam"
+	CalendarActivity = "event"
+)
+
+// CalendarEvent is an entry of a unit's school calendar spanning StartDate to
+// EndDate inclusive. NonTeaching days have no regular lessons.
+type CalendarEvent struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UnitID      uint      `gorm:"not null;index" json:"unit_id"`
+	Title       string    `gorm:"not null" json:"title"`
+	Description string    `json:"description"`
+	Type        string    `gorm:"not null" json:"type"`
+	StartDate   time.Time `gorm:"type:date;not null;index" json:"start_date"`
+	EndDate     time.Time `gorm:"type:date;not null" json:"end_date"`
+	NonTeaching bool      `gorm:"not null;default:false" json:"non_teaching"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 type Student struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
@@ -50,6 +165,30 @@ type Student struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Enrollment statuses; every enrollment but the current one is closed with
+// the outcome of that year.
+const (
+	EnrollmentActive    = "Active"
+	EnrollmentPromoted  = "Promoted"
+	EnrollmentRetained  = "Retained"
+	EnrollmentGraduated = "Graduated"
+	EnrollmentMoved     = "Moved"
+)
+
+// ClassEnrollment records a student's stay in a class; Student.ClassID is the
+// class of the Active enrollment.
+type ClassEnrollment struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	StudentID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"student_id"`
+	ClassID        uint       `gorm:"not null;index" json:"class_id"`
+	Class          *Class     `gorm:"foreignKey:ClassID" json:"class,omitempty"`
+	AcademicYearID *uint      `gorm:"index" json:"academic_year_id"`
+	Status         string     `gorm:"not null;default:'Active'" json:"status"`
+	StartedAt      time.Time  `gorm:"not null" json:"started_at"`
+	EndedAt        *time.Time `json:"ended_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
 type Parent struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
@@ -74,42 +213,126 @@ type Class struct {
 	UnitID            uint       `gorm:"not null" json:"unit_id"`
 	HomeroomTeacherID *uuid.UUID `gorm:"type:uuid" json:"homeroom_teacher_id"`
 	HomeroomTeacher   *Teacher   `gorm:"foreignKey:HomeroomTeacherID" json:"homeroom_teacher,omitempty"`
+	AcademicYearID    *uint      `gorm:"index" json:"academic_year_id"`
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
 type Subject struct {
+	ID                 uint                `gorm:"primaryKey" json:"id"`
+	Name               string              `gorm:"not null" json:"name"`
+	UnitID             uint                `gorm:"not null" json:"unit_id"`
+	LearningObjectives []LearningObjective `gorm:"foreignKey:SubjectID;constraint:OnDelete:CASCADE" json:"learning_objectives,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+}
+
+// LearningObjective is a tujuan pembelajaran (TP) of a subject under
+// Kurikulum Merdeka. Tasks and assessments are tagged with the objectives
+// they assess.
+type LearningObjective struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	SubjectID   uint      `gorm:"not null;index" json:"subject_id"`
+	Code        string    `json:"code"` // e.g. "TP 1"
+	Description string    `gorm:"not null" json:"description"`
+	Position    int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type Schedule struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ClassID    uint      `gorm:"not null" json:"class_id"`
+	Class      Class     `gorm:"foreignKey:ClassID" json:"class"`
+	SubjectID  uint      `gorm:"not null" json:"subject_id"`
+	Subject    Subject   `gorm:"foreignKey:SubjectID" json:"subject"`
+	TeacherID  uuid.UUID `gorm:"type:uuid;not null" json:"teacher_id"`
+	Teacher    Teacher   `gorm:"foreignKey:TeacherID" json:"teacher"`
+	Day        Weekday   `gorm:"type:smallint;not null" json:"day"`
+	StartTime  TimeOfDay `gorm:"type:time;not null" json:"start_time"`
+	EndTime    TimeOfDay `gorm:"type:time;not null" json:"end_time"`
+	SemesterID *uint     `gorm:"index" json:"semester_id"`
+	RoomID     *uint     `gorm:"index" json:"room_id"`
+	Room       *Room     `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+}
+
+// Period is a bell period of a unit's day (jam ke-N or a break). Days with a
+// different timetable, such as short Fridays, have their own rows.
+type Period struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	Name      string    `gorm:"not null" json:"name"`
-	UnitID    uint      `gorm:"not null" json:"unit_id"`
+	UnitID    uint      `gorm:"not null;index" json:"unit_id"`
+	Day       Weekday   `gorm:"type:smallint;not null" json:"day"`
+	Number    int       `json:"number"` // jam ke-; 0 for breaks
+	Label     string    `json:"label"`
+	StartTime TimeOfDay `gorm:"type:time;not null" json:"start_time"`
+	EndTime   TimeOfDay `gorm:"type:time;not null" json:"end_time"`
+	IsBreak   bool      `gorm:"not null;default:false" json:"is_break"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-type Schedule struct {
+// Substitution hands one occurrence of a weekly lesson to a guru pengganti,
+// overriding Schedule.TeacherID on that date only.
+type Substitution struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ScheduleID  uint      `gorm:"not null;uniqueIndex:idx_substitution_schedule_date" json:"schedule_id"`
+	Schedule    *Schedule `gorm:"foreignKey:ScheduleID" json:"schedule,omitempty"`
+	Date        time.Time `gorm:"type:date;not null;uniqueIndex:idx_substitution_schedule_date" json:"date"`
+	TeacherID   uuid.UUID `gorm:"type:uuid;not null;index" json:"teacher_id"`
+	Teacher     *Teacher  `gorm:"foreignKey:TeacherID" json:"teacher,omitempty"`
+	Reason      string    `json:"reason"` // Sakit, Izin, Dinas Luar, etc.
+	CreatedByID uuid.UUID `gorm:"type:uuid;not null" json:"created_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Room types
+const (
+	RoomClassroom = "classroom"
+	RoomLab       = "lab"
+	RoomHall      = "hall" // aula
+	RoomLibrary   = "library"
+	RoomField     = "field"
+	RoomOther     = "other"
+)
+
+type Room struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	ClassID   uint      `gorm:"not null" json:"class_id"`
-	Class     Class     `gorm:"foreignKey:ClassID" json:"class"`
-	SubjectID uint      `gorm:"not null" json:"subject_id"`
-	Subject   Subject   `gorm:"foreignKey:SubjectID" json:"subject"`
-	TeacherID uuid.UUID `gorm:"type:uuid;not null" json:"teacher_id"`
-	Teacher   Teacher   `gorm:"foreignKey:TeacherID" json:"teacher"`
-	Day       string    `gorm:"not null" json:"day"` // Monday, Tuesday, etc.
-	StartTime string    `gorm:"not null" json:"start_time"` // HH:MM
-	EndTime   string    `gorm:"not null" json:"end_time"` // HH:MM
+	UnitID    uint      `gorm:"not null;index" json:"unit_id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Type      string    `gorm:"not null" json:"type"`
+	Capacity  int       `json:"capacity"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RoomBooking reserves a room on one date for an event outside the weekly
+// schedule.
+type RoomBooking struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	RoomID     uint      `gorm:"not null;index" json:"room_id"`
+	Room       *Room     `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+	Title      string    `gorm:"not null" json:"title"`
+	Notes      string    `json:"notes"`
+	Date       time.Time `gorm:"type:date;not null;index" json:"date"`
+	StartTime  TimeOfDay `gorm:"type:time;not null" json:"start_time"`
+	EndTime    TimeOfDay `gorm:"type:time;not null" json:"end_time"`
+	BookedByID uuid.UUID `gorm:"type:uuid;not null" json:"booked_by_id"`
+	BookedBy   *User     `gorm:"foreignKey:BookedByID" json:"booked_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 // Presensi
 
 type Attendance struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	StudentID  uuid.UUID `gorm:"type:uuid;not null" json:"student_id"`
-	Student    Student   `gorm:"foreignKey:StudentID" json:"student"`
-	ScheduleID uint      `gorm:"not null" json:"schedule_id"`
-	Schedule   Schedule  `gorm:"foreignKey:ScheduleID" json:"schedule"`
-	Timestamp  time.Time `gorm:"not null" json:"timestamp"`
-	Method     string    `gorm:"not null" json:"method"` // Manual, QR
-	Status     string    `gorm:"not null" json:"status"` // Present, Absent, Late, Permission, Sick
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	StudentID  uuid.UUID  `gorm:"type:uuid;not null" json:"student_id"`
+	Student    Student    `gorm:"foreignKey:StudentID" json:"student"`
+	ScheduleID uint       `gorm:"not null" json:"schedule_id"`
+	Schedule   Schedule   `gorm:"foreignKey:ScheduleID" json:"schedule"`
+	TeacherID  *uuid.UUID `gorm:"type:uuid;index" json:"teacher_id"` // who taught the lesson, the substitute if any
+	Timestamp  time.Time  `gorm:"not null" json:"timestamp"`
+	Method     string     `gorm:"not null" json:"method"` // Manual, QR
+	Status     string     `gorm:"not null" json:"status"` // Present, Absent, Late, Permission, Sick
 }
 
 type Bill struct {
@@ -171,19 +394,22 @@ type Material struct {
 	SubjectID   uint      `gorm:"not null" json:"subject_id"`
 	Subject     Subject   `gorm:"foreignKey:SubjectID" json:"subject"`
 	TeacherID   uuid.UUID `gorm:"type:uuid;not null" json:"teacher_id"`
+	SemesterID  *uint     `gorm:"index" json:"semester_id"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
 type Task struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Title       string    `gorm:"not null" json:"title"`
-	Description string    `json:"description"`
-	Deadline    time.Time `json:"deadline"`
-	ClassID     uint      `gorm:"not null" json:"class_id"`
-	SubjectID   uint      `gorm:"not null" json:"subject_id"`
-	Subject     Subject   `gorm:"foreignKey:SubjectID" json:"subject"`
-	TeacherID   uuid.UUID `gorm:"type:uuid;not null" json:"teacher_id"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID                 uint                `gorm:"primaryKey" json:"id"`
+	Title              string              `gorm:"not null" json:"title"`
+	Description        string              `json:"description"`
+	Deadline           time.Time           `json:"deadline"`
+	ClassID            uint                `gorm:"not null" json:"class_id"`
+	SubjectID          uint                `gorm:"not null" json:"subject_id"`
+	Subject            Subject             `gorm:"foreignKey:SubjectID" json:"subject"`
+	TeacherID          uuid.UUID           `gorm:"type:uuid;not null" json:"teacher_id"`
+	SemesterID         *uint               `gorm:"index" json:"semester_id"`
+	LearningObjectives []LearningObjective `gorm:"many2many:task_learning_objectives;constraint:OnDelete:CASCADE" json:"learning_objectives"`
+	CreatedAt          time.Time           `json:"created_at"`
 }
 
 type TaskSubmission struct {
@@ -191,12 +417,156 @@ type TaskSubmission struct {
 	TaskID    uint      `gorm:"not null" json:"task_id"`
 	Task      Task      `gorm:"foreignKey:TaskID" json:"task"`
 	StudentID uuid.UUID `gorm:"type:uuid;not null" json:"student_id"`
-	FileURL   string    `json:"file_url"`
-	Grade     float64   `json:"grade"`
-	CreatedAt time.Time `json:"created_at"`
+	Student   *Student   `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	FileURL   string     `json:"file_url"`
+	Grade     float64    `json:"grade"`
+	GradedAt  *time.Time `json:"graded_at"` // Nil until graded; ungraded submissions stay out of report cards
+	CreatedAt time.Time  `json:"created_at"`
 }
 
+// Gradebook assessment components
+const (
+	ComponentTask     = "tugas" // Tugas harian; graded task submissions count here too
+	ComponentQuiz     = "uh"    // Ulangan harian
+	ComponentMidterm  = "pts"   // Penilaian tengah semester (UTS)
+	ComponentFinal    = "pas"   // Penilaian akhir semester (UAS)
+	ComponentPractice = "praktik"
+)
+
+var GradeComponents = []string{ComponentTask, ComponentQuiz, ComponentMidterm, ComponentFinal, ComponentPractice}
+
+// GradeWeight is the weight, in percent, of one component in the report-card
+// score of every subject in a unit's semester.
+type GradeWeight struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UnitID     uint      `gorm:"not null;uniqueIndex:idx_grade_weight" json:"unit_id"`
+	SemesterID uint      `gorm:"not null;uniqueIndex:idx_grade_weight" json:"semester_id"`
+	Component  string    `gorm:"not null;uniqueIndex:idx_grade_weight" json:"component"`
+	Weight     float64   `gorm:"not null" json:"weight"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
 
+// Score is a manually entered assessment result, such as one UH or the PAS.
+// Title tells apart several assessments of one component ("UH 1", "UH 2").
+type Score struct {
+	ID                 uuid.UUID           `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	StudentID          uuid.UUID           `gorm:"type:uuid;not null;uniqueIndex:idx_score_entry" json:"student_id"`
+	Student            *Student            `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	SubjectID          uint                `gorm:"not null;uniqueIndex:idx_score_entry" json:"subject_id"`
+	Subject            *Subject            `gorm:"foreignKey:SubjectID" json:"subject,omitempty"`
+	SemesterID         uint                `gorm:"not null;uniqueIndex:idx_score_entry" json:"semester_id"`
+	Component          string              `gorm:"not null;uniqueIndex:idx_score_entry" json:"component"`
+	Title              string              `gorm:"not null;uniqueIndex:idx_score_entry" json:"title"`
+	Value              float64             `gorm:"not null" json:"value"`
+	RecordedByID       uuid.UUID           `gorm:"type:uuid;not null" json:"recorded_by_id"`
+	Attempts           []ScoreAttempt      `gorm:"foreignKey:ScoreID;constraint:OnDelete:CASCADE" json:"attempts,omitempty"`
+	LearningObjectives []LearningObjective `gorm:"many2many:score_learning_objectives;constraint:OnDelete:CASCADE" json:"learning_objectives,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+}
+
+// Score attempt kinds
+const (
+	AttemptRemedial   = "remedial"  // Retake of a score below the KKM
+	AttemptEnrichment = "pengayaan" // Extra work for a score that passed; recorded only
+)
+
+// ScoreAttempt is a remedial or enrichment result recorded on a score. How a
+// remedial changes the score depends on the configured remedial policy.
+type ScoreAttempt struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ScoreID      uuid.UUID `gorm:"type:uuid;not null;index" json:"score_id"`
+	Kind         string    `gorm:"not null" json:"kind"`
+	Value        float64   `gorm:"not null" json:"value"`
+	Note         string    `json:"note"`
+	RecordedByID uuid.UUID `gorm:"type:uuid;not null" json:"recorded_by_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// KKM (kriteria ketuntasan minimal) is the passing score of a subject in a
+// semester, for one class or, with ClassID 0, every class.
+type KKM struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	SubjectID  uint      `gorm:"not null;uniqueIndex:idx_kkm" json:"subject_id"`
+	Subject    *Subject  `gorm:"foreignKey:SubjectID" json:"subject,omitempty"`
+	ClassID    uint      `gorm:"not null;default:0;uniqueIndex:idx_kkm" json:"class_id"`
+	SemesterID uint      `gorm:"not null;uniqueIndex:idx_kkm" json:"semester_id"`
+	Value      float64   `gorm:"not null" json:"value"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+
+
+// Attitude predicates of the rapor
+const (
+	AttitudeVeryGood  = "SB" // Sangat Baik
+	AttitudeGood      = "B"  // Baik
+	AttitudeFair      = "C"  // Cukup
+	AttitudeNeedsWork = "K"  // Kurang
+)
+
+var AttitudeGrades = []string{AttitudeVeryGood, AttitudeGood, AttitudeFair, AttitudeNeedsWork}
+
+// HomeroomNote is what the wali kelas records about a student for a
+// semester: the note on the rapor and the spiritual and social attitude.
+type HomeroomNote struct {
+	ID                   uint      `gorm:"primaryKey" json:"id"`
+	StudentID            uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_homeroom_note" json:"student_id"`
+	SemesterID           uint      `gorm:"not null;uniqueIndex:idx_homeroom_note" json:"semester_id"`
+	Note                 string    `json:"note"`
+	SpiritualGrade       string    `json:"spiritual_grade"` // One of AttitudeGrades, empty until assessed
+	SpiritualDescription string    `json:"spiritual_description"`
+	SocialGrade          string    `json:"social_grade"`
+	SocialDescription    string    `json:"social_description"`
+	RecordedByID         uuid.UUID `gorm:"type:uuid;not null" json:"recorded_by_id"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// Dimensions of the Profil Pelajar Pancasila
+const (
+	P5Faith        = "beriman"         // Beriman, bertakwa kepada Tuhan YME, dan berakhlak mulia
+	P5Diversity    = "berkebinekaan"   // Berkebinekaan global
+	P5Cooperation  = "gotong_royong"   // Bergotong royong
+	P5Independence = "mandiri"         // Mandiri
+	P5CriticalMind = "bernalar_kritis" // Bernalar kritis
+	P5Creativity   = "kreatif"         // Kreatif
+)
+
+var P5Dimensions = []string{P5Faith, P5Diversity, P5Cooperation, P5Independence, P5CriticalMind, P5Creativity}
+
+// P5 achievement levels, lowest first
+const (
+	P5NotYet     = "BB"  // Belum Berkembang
+	P5Starting   = "MB"  // Mulai Berkembang
+	P5AsExpected = "BSH" // Berkembang Sesuai Harapan
+	P5Exceeding  = "SB"  // Sangat Berkembang
+)
+
+var P5Levels = []string{P5NotYet, P5Starting, P5AsExpected, P5Exceeding}
+
+// P5Project is a Projek Penguatan Profil Pelajar Pancasila run by a class
+// in a semester.
+type P5Project struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	ClassID     uint           `gorm:"not null;index" json:"class_id"`
+	SemesterID  uint           `gorm:"not null;index" json:"semester_id"`
+	Theme       string         `json:"theme"` // e.g. "Gaya Hidup Berkelanjutan"
+	Title       string         `gorm:"not null" json:"title"`
+	Description string         `json:"description"`
+	Assessments []P5Assessment `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"assessments,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// P5Assessment is the level a student reached in one dimension of a project.
+type P5Assessment struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ProjectID    uint      `gorm:"not null;uniqueIndex:idx_p5_assessment" json:"project_id"`
+	StudentID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_p5_assessment" json:"student_id"`
+	Dimension    string    `gorm:"not null;uniqueIndex:idx_p5_assessment" json:"dimension"`
+	Level        string    `gorm:"not null" json:"level"`
+	RecordedByID uuid.UUID `gorm:"type:uuid;not null" json:"recorded_by_id"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
 
 // Notifikasi
 
@@ -234,19 +604,21 @@ type PublicTeacher struct {
 type Download struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	Title     string    `gorm:"not null" json:"title"`
-	Category  string    `json:"category"` // Brosur, Kalender
+	Category  string    `json:"category"` // Brosur, Dokumen, Lainnya
 	FileURL   string    `gorm:"not null" json:"file_url"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
 type Alumni struct {
-	ID             uint      `gorm:"primaryKey" json:"id"`
-	Name           string    `gorm:"not null" json:"name"`
-	GraduationYear int       `json:"graduation_year"`
-	Profession     string    `json:"profession"`
-	Testimony      string    `json:"testimony"`
-	PhotoURL       string    `json:"photo_url"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Name           string     `gorm:"not null" json:"name"`
+	GraduationYear int        `json:"graduation_year"`
+	Profession     string     `json:"profession"`
+	Testimony      string     `json:"testimony"`
+	PhotoURL       string     `json:"photo_url"`
+	StudentID      *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"student_id"` // set for graduates of the promotion workflow
+	IsPublished    bool       `gorm:"not null;default:true" json:"is_published"`
+	CreatedAt      time.Time  `json:"created_at"`
 }
 
 type PPDBRegistration struct {
@@ -271,3 +643,21 @@ type ContactMessage struct {
 	IsRead    bool      `gorm:"default:false" json:"is_read"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// Audit
+
+type AuditLog struct {
+	ID             uint            `gorm:"primaryKey" json:"id"`
+	ActorID        uuid.UUID       `gorm:"type:uuid;not null;index" json:"actor_id"`
+	Actor          User            `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	ActorRoleID    uint            `gorm:"not null" json:"actor_role_id"`
+	ImpersonatorID *uuid.UUID      `gorm:"type:uuid;index" json:"impersonator_id"` // Super Admin acting as ActorID, if impersonating
+	UnitID         uint            `gorm:"index" json:"unit_id"`                   // Actor's unit, used to scope the log for unit admins
+	Action         string          `gorm:"not null;index" json:"action"`           // create, update, delete or a named action
+	EntityType     string          `gorm:"not null;index" json:"entity_type"`
+	EntityID       string          `gorm:"index" json:"entity_id"`
+	Before         json.RawMessage `gorm:"type:jsonb" json:"before"` // Changed fields only on update
+	After          json.RawMessage `gorm:"type:jsonb" json:"after"`
+	IPAddress      string          `json:"ip_address"`
+	CreatedAt      time.Time       `gorm:"index" json:"created_at"`
+}
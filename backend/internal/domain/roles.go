@@ -0,0 +1,24 @@
+package domain
+
+// Role IDs as seeded by cmd/seeder
+const (
+	RoleSuperAdmin uint = 1
+	RoleAdminMTS   uint = 2
+	RoleAdminMA    uint = 3
+	RoleGuru       uint = 4
+	RoleWaliKelas  uint = 5
+	RoleSiswa      uint = 6
+	RoleOrangTua   uint = 7
+)
+
+// IsAdminRole reports whether roleID is Super Admin or a unit admin.
+func IsAdminRole(roleID uint) bool {
+	return roleID == RoleSuperAdmin || roleID == RoleAdminMTS || roleID == RoleAdminMA
+}
+
+// Unit IDs as seeded by cmd/seeder
+const (
+	UnitMTS    uint = 1
+	UnitMA     uint = 2
+	UnitPublic uint = 3
+)
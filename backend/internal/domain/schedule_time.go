@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Weekday is stored as 1 (Monday) to 7 (Sunday) and serialized by name.
+type Weekday int
+
+const (
+	Monday Weekday = iota + 1
+	Tuesday
+	Wednesday
+	Thursday
+	Friday
+	Saturday
+	Sunday
+)
+
+var weekdayNames = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+// Indonesian names are accepted on input as well
+var weekdayNamesID = []string{"Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu", "Minggu"}
+
+func ParseWeekday(s string) (Weekday, error) {
+	s = strings.TrimSpace(s)
+	for i := range weekdayNames {
+		if strings.EqualFold(s, weekdayNames[i]) || strings.EqualFold(s, weekdayNamesID[i]) {
+			return Weekday(i + 1), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day %q, expected one of %s", s, strings.Join(weekdayNames, ", "))
+}
+
+// WeekdayOf returns the school weekday of t.
+func WeekdayOf(t time.Time) Weekday {
+	if t.Weekday() == time.Sunday {
+		return Sunday
+	}
+	return Weekday(t.Weekday())
+}
+
+func (d Weekday) Valid() bool {
+	return d >= Monday && d <= Sunday
+}
+
+func (d Weekday) String() string {
+	if !d.Valid() {
+		return ""
+	}
+	return weekdayNames[d-1]
+}
+
+func (d Weekday) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts a day name or its number.
+func (d *Weekday) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		if !Weekday(n).Valid() {
+			return fmt.Errorf("invalid day %d", n)
+		}
+		*d = Weekday(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseWeekday(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// TimeOfDay is a wall-clock time in minutes after midnight, stored in a
+// postgres time column and serialized as "HH:MM".
+type TimeOfDay int
+
+// Clock returns the TimeOfDay for hour:minute.
+func Clock(hour, minute int) TimeOfDay {
+	return TimeOfDay(hour*60 + minute)
+}
+
+// TimeOfDayOf returns the wall-clock time of t.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return Clock(t.Hour(), t.Minute())
+}
+
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	for _, layout := range []string{"15:04", "15:04:05.999999"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return TimeOfDayOf(t), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", int(t)/60, int(t)%60)
+}
+
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseTimeOfDay(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+func (t TimeOfDay) Value() (driver.Value, error) {
+	return t.String() + ":00", nil
+}
+
+func (t *TimeOfDay) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case time.Time:
+		*t = TimeOfDayOf(v)
+		return nil
+	case string:
+		return t.scanString(v)
+	case []byte:
+		return t.scanString(string(v))
+	}
+	return errors.New("unsupported time of day value")
+}
+
+func (t *TimeOfDay) scanString(s string) error {
+	parsed, err := ParseTimeOfDay(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Failures allowed before each further attempt has to wait
+	freeAttempts = 2
+	maxDelay     = 30 * time.Second
+)
+
+// ThrottledError is returned while an account or IP has to wait.
+type ThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *ThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("too many failed login attempts, login locked, try again in %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("too many failed login attempts, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// LoginLimiter tracks failed logins per account and per client IP. Each
+// failure past freeAttempts doubles the wait before the next try, and
+// reaching the maximum locks the key for the lockout duration.
+type LoginLimiter struct {
+	store         Store
+	maxPerAccount int
+	maxPerIP      int
+	window        time.Duration
+	lockout       time.Duration
+}
+
+func NewLoginLimiter(store Store, maxPerAccount, maxPerIP int, window, lockout time.Duration) *LoginLimiter {
+	return &LoginLimiter{
+		store:         store,
+		maxPerAccount: maxPerAccount,
+		maxPerIP:      maxPerIP,
+		window:        window,
+		lockout:       lockout,
+	}
+}
+
+// Allow returns a *ThrottledError if a login for email from ip must wait.
+func (l *LoginLimiter) Allow(email, ip string) error {
+	now := time.Now()
+	var worst *ThrottledError
+
+	for _, key := range []string{accountKey(email), ipKey(ip)} {
+		entry, err := l.store.Get(key)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			continue
+		}
+
+		var wait *ThrottledError
+		if entry.LockedUntil != nil && now.Before(*entry.LockedUntil) {
+			wait = &ThrottledError{RetryAfter: entry.LockedUntil.Sub(now), Locked: true}
+		} else if entry.WindowStart.After(now.Add(-l.window)) {
+			if next := entry.LastFailureAt.Add(backoff(entry.Failures)); now.Before(next) {
+				wait = &ThrottledError{RetryAfter: next.Sub(now)}
+			}
+		}
+		if wait != nil && (worst == nil || wait.RetryAfter > worst.RetryAfter) {
+			worst = wait
+		}
+	}
+
+	if worst != nil {
+		return worst
+	}
+	return nil
+}
+
+// Fail records a failed login and locks the account or IP once it reaches
+// its limit within the window.
+func (l *LoginLimiter) Fail(email, ip string) error {
+	limits := map[string]int{
+		accountKey(email): l.maxPerAccount,
+		ipKey(ip):         l.maxPerIP,
+	}
+	for key, max := range limits {
+		entry, err := l.store.RecordFailure(key, l.window)
+		if err != nil {
+			return err
+		}
+		if entry.Failures >= max {
+			if err := l.store.Lock(key, time.Now().Add(l.lockout)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Succeed clears the account counters after a successful login. IP counters
+// are left to expire so one valid account cannot reset them for an attacker.
+func (l *LoginLimiter) Succeed(email string) error {
+	return l.store.Reset(accountKey(email))
+}
+
+// Unlock clears the account counters and lock, for admins.
+func (l *LoginLimiter) Unlock(email string) error {
+	return l.store.Reset(accountKey(email))
+}
+
+func backoff(failures int) time.Duration {
+	if failures <= freeAttempts {
+		return 0
+	}
+	shift := failures - freeAttempts - 1
+	if shift > 5 {
+		return maxDelay
+	}
+	delay := time.Second << shift
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
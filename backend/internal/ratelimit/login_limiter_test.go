@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 0},
+		{freeAttempts, 0},
+		{freeAttempts + 1, time.Second},
+		{freeAttempts + 2, 2 * time.Second},
+		{freeAttempts + 3, 4 * time.Second},
+		{freeAttempts + 5, 16 * time.Second},
+		{freeAttempts + 6, maxDelay}, // 32s capped
+		{freeAttempts + 7, maxDelay},
+		{100, maxDelay},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.failures); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+// throttle returns the wait Allow reports, or nil when the login may go ahead.
+func throttle(t *testing.T, l *LoginLimiter, email, ip string) *ThrottledError {
+	t.Helper()
+	err := l.Allow(email, ip)
+	if err == nil {
+		return nil
+	}
+	var throttled *ThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("Allow: %v", err)
+	}
+	return throttled
+}
+
+func fail(t *testing.T, l *LoginLimiter, email, ip string, times int) {
+	t.Helper()
+	for i := 0; i < times; i++ {
+		if err := l.Fail(email, ip); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoginLimiter(t *testing.T) {
+	const (
+		maxPerAccount = 5
+		maxPerIP      = 8
+		lockout       = 15 * time.Minute
+	)
+	newLimiter := func() *LoginLimiter {
+		return NewLoginLimiter(NewMemoryStore(time.Hour), maxPerAccount, maxPerIP, time.Hour, lockout)
+	}
+
+	t.Run("free attempts are not delayed", func(t *testing.T) {
+		l := newLimiter()
+		fail(t, l, "a@example.com", "10.0.0.1", freeAttempts)
+		if wait := throttle(t, l, "a@example.com", "10.0.0.1"); wait != nil {
+			t.Errorf("throttled after %d failures: %v", freeAttempts, wait)
+		}
+	})
+
+	t.Run("further failures wait without locking", func(t *testing.T) {
+		l := newLimiter()
+		fail(t, l, "a@example.com", "10.0.0.1", freeAttempts+1)
+		wait := throttle(t, l, "a@example.com", "10.0.0.1")
+		if wait == nil || wait.Locked || wait.RetryAfter > time.Second {
+			t.Errorf("Allow = %v, want a wait of at most 1s without lock", wait)
+		}
+	})
+
+	t.Run("account locks at its limit", func(t *testing.T) {
+		l := newLimiter()
+		fail(t, l, "a@example.com", "10.0.0.1", maxPerAccount-1)
+		if wait := throttle(t, l, "a@example.com", "10.0.0.1"); wait == nil || wait.Locked {
+			t.Fatalf("Allow before the limit = %v, want a delay without lock", wait)
+		}
+		fail(t, l, "a@example.com", "10.0.0.1", 1)
+		wait := throttle(t, l, "a@example.com", "10.0.0.1")
+		if wait == nil || !wait.Locked || wait.RetryAfter > lockout || wait.RetryAfter < lockout-time.Minute {
+			t.Errorf("Allow at the limit = %v, want locked for about %s", wait, lockout)
+		}
+		if wait := throttle(t, l, "a@example.com", "10.0.0.2"); wait == nil || !wait.Locked {
+			t.Errorf("locked account allowed from another IP: %v", wait)
+		}
+	})
+
+	t.Run("IP locks across accounts", func(t *testing.T) {
+		l := newLimiter()
+		for i := 0; i < maxPerIP; i++ {
+			fail(t, l, string(rune('a'+i))+"@example.com", "10.0.0.1", 1)
+		}
+		if wait := throttle(t, l, "new@example.com", "10.0.0.1"); wait == nil || !wait.Locked {
+			t.Errorf("Allow from a locked IP = %v, want locked", wait)
+		}
+		if wait := throttle(t, l, "new@example.com", "10.0.0.2"); wait != nil {
+			t.Errorf("Allow from another IP = %v, want nil", wait)
+		}
+	})
+
+	t.Run("success clears the account but not the IP", func(t *testing.T) {
+		l := newLimiter()
+		fail(t, l, "a@example.com", "10.0.0.1", maxPerIP)
+		if err := l.Succeed("a@example.com"); err != nil {
+			t.Fatal(err)
+		}
+		if wait := throttle(t, l, "a@example.com", "10.0.0.2"); wait != nil {
+			t.Errorf("account still throttled after success: %v", wait)
+		}
+		if wait := throttle(t, l, "a@example.com", "10.0.0.1"); wait == nil || !wait.Locked {
+			t.Errorf("IP lock cleared by a success: %v", wait)
+		}
+	})
+
+	t.Run("unlock clears the account lock", func(t *testing.T) {
+		l := newLimiter()
+		fail(t, l, "A@Example.com ", "10.0.0.1", maxPerAccount)
+		if err := l.Unlock("a@example.com"); err != nil {
+			t.Fatal(err)
+		}
+		if wait := throttle(t, l, "a@example.com", "10.0.0.2"); wait != nil {
+			t.Errorf("account still throttled after unlock: %v", wait)
+		}
+	})
+}
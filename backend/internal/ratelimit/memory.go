@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"ppi-100-sis/internal/domain"
+	"sync"
+	"time"
+)
+
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*domain.LoginAttempt
+	retention time.Duration
+	lastSweep time.Time
+}
+
+// NewMemoryStore keeps counters in process memory. Entries idle for longer
+// than retention and no longer locked are dropped.
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	return &MemoryStore{
+		entries:   make(map[string]*domain.LoginAttempt),
+		retention: retention,
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryStore) Get(key string) (*domain.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	copied := *entry
+	return &copied, nil
+}
+
+func (s *MemoryStore) RecordFailure(key string, window time.Duration) (*domain.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &domain.LoginAttempt{Key: key}
+		s.entries[key] = entry
+	}
+	if entry.WindowStart.Before(now.Add(-window)) {
+		entry.Failures = 0
+		entry.WindowStart = now
+	}
+	entry.Failures++
+	entry.LastFailureAt = now
+
+	copied := *entry
+	return &copied, nil
+}
+
+func (s *MemoryStore) Lock(key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &domain.LoginAttempt{Key: key, WindowStart: time.Now()}
+		s.entries[key] = entry
+	}
+	entry.LockedUntil = &until
+	return nil
+}
+
+func (s *MemoryStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep drops stale entries at most once per retention period so the map
+// cannot grow without bound. Caller must hold mu.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.retention {
+		return
+	}
+	s.lastSweep = now
+
+	cutoff := now.Add(-s.retention)
+	for key, entry := range s.entries {
+		if entry.LastFailureAt.Before(cutoff) && (entry.LockedUntil == nil || entry.LockedUntil.Before(now)) {
+			delete(s.entries, key)
+		}
+	}
+}
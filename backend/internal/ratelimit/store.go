@@ -0,0 +1,18 @@
+package ratelimit
+
+import (
+	"ppi-100-sis/internal/domain"
+	"time"
+)
+
+// Store keeps failed login counters. MemoryStore suits a single instance;
+// postgres.LoginAttemptRepository shares counters between instances.
+type Store interface {
+	// Get returns the counters for key, or nil if nothing is recorded.
+	Get(key string) (*domain.LoginAttempt, error)
+	// RecordFailure adds a failure to key, starting a new window when the
+	// current one is older than window, and returns the updated counters.
+	RecordFailure(key string, window time.Duration) (*domain.LoginAttempt, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+}
@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"ppi-100-sis/internal/domain"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AcademicRepository struct {
@@ -14,20 +17,323 @@ func NewAcademicRepository(db *gorm.DB) *AcademicRepository {
 	return &AcademicRepository{db: db}
 }
 
+// Academic year and semester
+func (r *AcademicRepository) CreateAcademicYear(year *domain.AcademicYear) error {
+	return r.db.Create(year).Error
+}
+
+func (r *AcademicRepository) GetAcademicYears(unitID uint) ([]domain.AcademicYear, error) {
+	var years []domain.AcademicYear
+	err := r.db.Scopes(byUnit("unit_id", unitID)).
+		Preload("Semesters", func(db *gorm.DB) *gorm.DB { return db.Order("start_date") }).
+		Order("start_date DESC").
+		Find(&years).Error
+	return years, err
+}
+
+func (r *AcademicRepository) GetAcademicYearByID(id, unitID uint) (*domain.AcademicYear, error) {
+	var year domain.AcademicYear
+	err := r.db.Scopes(byUnit("unit_id", unitID)).First(&year, id).Error
+	return &year, err
+}
+
+func (r *AcademicRepository) UpdateAcademicYear(year *domain.AcademicYear) error {
+	return r.db.Save(year).Error
+}
+
+// DeleteAcademicYear removes the year together with its semesters.
+func (r *AcademicRepository) DeleteAcademicYear(id, unitID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkAffected(tx.Scopes(byUnit("unit_id", unitID)).Delete(&domain.AcademicYear{}, id)); err != nil {
+			return err
+		}
+		return tx.Where("academic_year_id = ?", id).Delete(&domain.Semester{}).Error
+	})
+}
+
+func (r *AcademicRepository) CreateSemester(semester *domain.Semester) error {
+	return r.db.Create(semester).Error
+}
+
+func (r *AcademicRepository) GetSemesterByID(id, unitID uint) (*domain.Semester, error) {
+	var semester domain.Semester
+	err := r.db.Scopes(byAcademicYearUnit("academic_year_id", unitID)).Preload("AcademicYear").First(&semester, id).Error
+	return &semester, err
+}
+
+func (r *AcademicRepository) UpdateSemester(semester *domain.Semester) error {
+	return r.db.Omit("AcademicYear").Save(semester).Error
+}
+
+func (r *AcademicRepository) DeleteSemester(id, unitID uint) error {
+	return checkAffected(r.db.Scopes(byAcademicYearUnit("academic_year_id", unitID)).Delete(&domain.Semester{}, id))
+}
+
+func (r *AcademicRepository) GetActiveSemester(unitID uint) (*domain.Semester, error) {
+	var semester domain.Semester
+	err := r.db.Scopes(byAcademicYearUnit("academic_year_id", unitID)).
+		Where("is_active = ?", true).
+		Preload("AcademicYear").
+		First(&semester).Error
+	return &semester, err
+}
+
+// ActivateSemester makes semester and its year the only active term of
+// unitID. Rows created before the unit had any term are adopted by it.
+func (r *AcademicRepository) ActivateSemester(semester *domain.Semester, unitID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		yearIDs := tx.Model(&domain.AcademicYear{}).Select("id").Where("unit_id = ?", unitID)
+		if err := tx.Model(&domain.Semester{}).Where("academic_year_id IN (?)", yearIDs).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.AcademicYear{}).Where("unit_id = ?", unitID).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Semester{}).Where("id = ?", semester.ID).Update("is_active", true).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.AcademicYear{}).Where("id = ?", semester.AcademicYearID).Update("is_active", true).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&domain.Class{}).Where("unit_id = ? AND academic_year_id IS NULL", unitID).
+			Update("academic_year_id", semester.AcademicYearID).Error; err != nil {
+			return err
+		}
+		classIDs := tx.Model(&domain.Class{}).Select("id").Where("unit_id = ?", unitID)
+		for _, model := range []interface{}{&domain.Schedule{}, &domain.Task{}, &domain.Material{}} {
+			if err := tx.Model(model).Where("semester_id IS NULL AND class_id IN (?)", classIDs).
+				Update("semester_id", semester.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Calendar
+func (r *AcademicRepository) CreateCalendarEvent(event *domain.CalendarEvent) error {
+	return r.db.Create(event).Error
+}
+
+// GetCalendarEvents lists events overlapping from..to inclusive; zero bounds
+// are open.
+func (r *AcademicRepository) GetCalendarEvents(unitID uint, from, to time.Time) ([]domain.CalendarEvent, error) {
+	var events []domain.CalendarEvent
+	query := r.db.Scopes(byUnit("unit_id", unitID))
+	if !from.IsZero() {
+		query = query.Where("end_date >= ?", from.Format(dateLayout))
+	}
+	if !to.IsZero() {
+		query = query.Where("start_date <= ?", to.Format(dateLayout))
+	}
+	err := query.Order("start_date").Find(&events).Error
+	return events, err
+}
+
+func (r *AcademicRepository) GetCalendarEventByID(id, unitID uint) (*domain.CalendarEvent, error) {
+	var event domain.CalendarEvent
+	err := r.db.Scopes(byUnit("unit_id", unitID)).First(&event, id).Error
+	return &event, err
+}
+
+func (r *AcademicRepository) UpdateCalendarEvent(event *domain.CalendarEvent) error {
+	return r.db.Save(event).Error
+}
+
+func (r *AcademicRepository) DeleteCalendarEvent(id, unitID uint) error {
+	return checkAffected(r.db.Scopes(byUnit("unit_id", unitID)).Delete(&domain.CalendarEvent{}, id))
+}
+
+// Period
+func (r *AcademicRepository) CreatePeriod(period *domain.Period) error {
+	return r.db.Create(period).Error
+}
+
+func (r *AcademicRepository) GetPeriods(unitID uint) ([]domain.Period, error) {
+	var periods []domain.Period
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Order("unit_id, day, start_time").Find(&periods).Error
+	return periods, err
+}
+
+func (r *AcademicRepository) GetPeriodByID(id, unitID uint) (*domain.Period, error) {
+	var period domain.Period
+	err := r.db.Scopes(byUnit("unit_id", unitID)).First(&period, id).Error
+	return &period, err
+}
+
+// GetOverlappingPeriods returns the other periods of the unit and day that
+// overlap period.
+func (r *AcademicRepository) GetOverlappingPeriods(period *domain.Period) ([]domain.Period, error) {
+	var periods []domain.Period
+	err := r.db.Where("id <> ? AND unit_id = ? AND day = ? AND start_time < ? AND end_time > ?",
+		period.ID, period.UnitID, period.Day, period.EndTime, period.StartTime).Find(&periods).Error
+	return periods, err
+}
+
+func (r *AcademicRepository) UpdatePeriod(period *domain.Period) error {
+	return r.db.Save(period).Error
+}
+
+func (r *AcademicRepository) DeletePeriod(id, unitID uint) error {
+	return checkAffected(r.db.Scopes(byUnit("unit_id", unitID)).Delete(&domain.Period{}, id))
+}
+
+// dateLayout formats values compared against date columns so the session
+// time zone cannot shift them.
+const dateLayout = "2006-01-02"
+
+// Room
+func (r *AcademicRepository) CreateRoom(room *domain.Room) error {
+	return r.db.Create(room).Error
+}
+
+func (r *AcademicRepository) GetRooms(unitID uint) ([]domain.Room, error) {
+	var rooms []domain.Room
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Order("name").Find(&rooms).Error
+	return rooms, err
+}
+
+func (r *AcademicRepository) GetRoomByID(id, unitID uint) (*domain.Room, error) {
+	var room domain.Room
+	err := r.db.Scopes(byUnit("unit_id", unitID)).First(&room, id).Error
+	return &room, err
+}
+
+func (r *AcademicRepository) UpdateRoom(room *domain.Room) error {
+	return r.db.Save(room).Error
+}
+
+// DeleteRoom removes a room with its bookings; schedules held there keep
+// running without a room.
+func (r *AcademicRepository) DeleteRoom(id, unitID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkAffected(tx.Scopes(byUnit("unit_id", unitID)).Delete(&domain.Room{}, id)); err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Schedule{}).Where("room_id = ?", id).Update("room_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Where("room_id = ?", id).Delete(&domain.RoomBooking{}).Error
+	})
+}
+
+func (r *AcademicRepository) CreateRoomBooking(booking *domain.RoomBooking) error {
+	return r.db.Omit("Room", "BookedBy").Create(booking).Error
+}
+
+// GetRoomBookings lists bookings between from and to inclusive; roomID 0
+// means every room of the unit.
+func (r *AcademicRepository) GetRoomBookings(unitID, roomID uint, from, to time.Time) ([]domain.RoomBooking, error) {
+	var bookings []domain.RoomBooking
+	query := r.db.Preload("Room").Preload("BookedBy").
+		Scopes(byRoomUnit("room_id", unitID)).
+		Where("date BETWEEN ? AND ?", from.Format(dateLayout), to.Format(dateLayout))
+	if roomID != 0 {
+		query = query.Where("room_id = ?", roomID)
+	}
+	err := query.Order("date, start_time").Find(&bookings).Error
+	return bookings, err
+}
+
+func (r *AcademicRepository) GetRoomBookingByID(id, unitID uint) (*domain.RoomBooking, error) {
+	var booking domain.RoomBooking
+	err := r.db.Scopes(byRoomUnit("room_id", unitID)).First(&booking, id).Error
+	return &booking, err
+}
+
+// GetOverlappingBookings returns the other bookings of the room on the same
+// date that overlap booking.
+func (r *AcademicRepository) GetOverlappingBookings(booking *domain.RoomBooking) ([]domain.RoomBooking, error) {
+	var bookings []domain.RoomBooking
+	err := r.db.Preload("BookedBy").
+		Where("id <> ? AND room_id = ? AND date = ? AND start_time < ? AND end_time > ?",
+			booking.ID, booking.RoomID, booking.Date.Format(dateLayout), booking.EndTime, booking.StartTime).
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// GetRoomSchedulesOn returns the weekly lessons held in a room on date, taken
+// from the semester covering that date; roomID 0 means every room of the unit.
+func (r *AcademicRepository) GetRoomSchedulesOn(unitID, roomID uint, date time.Time) ([]domain.Schedule, error) {
+	var schedules []domain.Schedule
+	query := r.db.Preload("Class").Preload("Subject").Preload("Teacher.User").Preload("Room").
+		Scopes(byRoomUnit("schedules.room_id", unitID)).
+		Where("schedules.day = ?", domain.WeekdayOf(date)).
+		Where("schedules.semester_id IS NULL OR schedules.semester_id IN (SELECT id FROM semesters WHERE start_date <= ? AND end_date >= ?)", date, date)
+	if roomID != 0 {
+		query = query.Where("schedules.room_id = ?", roomID)
+	} else {
+		query = query.Where("schedules.room_id IS NOT NULL")
+	}
+	err := query.Order("schedules.start_time").Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *AcademicRepository) DeleteRoomBooking(id uint) error {
+	return checkAffected(r.db.Delete(&domain.RoomBooking{}, id))
+}
+
+// Substitution
+func (r *AcademicRepository) CreateSubstitution(substitution *domain.Substitution) error {
+	return r.db.Omit("Schedule", "Teacher").Create(substitution).Error
+}
+
+func (r *AcademicRepository) GetSubstitutionByID(id, unitID uint) (*domain.Substitution, error) {
+	var substitution domain.Substitution
+	err := r.db.Preload("Schedule.Class").Preload("Schedule.Subject").Preload("Schedule.Teacher.User").Preload("Teacher.User").
+		Scopes(byScheduleUnit("schedule_id", unitID)).First(&substitution, id).Error
+	return &substitution, err
+}
+
+// GetSubstitutionFor returns the substitution of a lesson on date, if any.
+func (r *AcademicRepository) GetSubstitutionFor(scheduleID uint, date time.Time) (*domain.Substitution, error) {
+	var substitution domain.Substitution
+	err := r.db.Preload("Teacher.User").
+		Where("schedule_id = ? AND date = ?", scheduleID, date.Format(dateLayout)).
+		First(&substitution).Error
+	return &substitution, err
+}
+
+// GetSubstitutions lists substitutions between from and to inclusive,
+// optionally only those taken over by teacherID.
+func (r *AcademicRepository) GetSubstitutions(unitID uint, teacherID string, from, to time.Time) ([]domain.Substitution, error) {
+	var substitutions []domain.Substitution
+	query := r.db.Preload("Schedule.Class").Preload("Schedule.Subject").Preload("Schedule.Teacher.User").Preload("Schedule.Room").Preload("Teacher.User").
+		Scopes(byScheduleUnit("schedule_id", unitID)).
+		Where("date BETWEEN ? AND ?", from.Format(dateLayout), to.Format(dateLayout))
+	if teacherID != "" {
+		query = query.Where("teacher_id = ?", teacherID)
+	}
+	err := query.Order("date").Find(&substitutions).Error
+	return substitutions, err
+}
+
+func (r *AcademicRepository) DeleteSubstitution(id uint) error {
+	return checkAffected(r.db.Delete(&domain.Substitution{}, id))
+}
+
 // Class
 func (r *AcademicRepository) CreateClass(class *domain.Class) error {
 	return r.db.Create(class).Error
 }
 
-func (r *AcademicRepository) GetAllClasses(unitID uint) ([]domain.Class, error) {
+func (r *AcademicRepository) GetAllClasses(unitID uint, term TermFilter) ([]domain.Class, error) {
 	var classes []domain.Class
-	err := r.db.Where("unit_id = ?", unitID).Find(&classes).Error
+	err := r.db.Scopes(byUnit("unit_id", unitID), byAcademicYear("academic_year_id", term)).Find(&classes).Error
 	return classes, err
 }
 
-func (r *AcademicRepository) GetClassByID(id uint) (*domain.Class, error) {
+func (r *AcademicRepository) GetClassByID(id, unitID uint) (*domain.Class, error) {
 	var class domain.Class
-	err := r.db.First(&class, id).Error
+	err := r.db.Scopes(byUnit("unit_id", unitID)).First(&class, id).Error
+	return &class, err
+}
+
+// GetClassWithHomeroom is GetClassByID with the homeroom teacher loaded.
+func (r *AcademicRepository) GetClassWithHomeroom(id, unitID uint) (*domain.Class, error) {
+	var class domain.Class
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Preload("HomeroomTeacher.User").First(&class, id).Error
 	return &class, err
 }
 
@@ -35,13 +341,13 @@ func (r *AcademicRepository) UpdateClass(class *domain.Class) error {
 	return r.db.Save(class).Error
 }
 
-func (r *AcademicRepository) DeleteClass(id uint) error {
-	return r.db.Delete(&domain.Class{}, id).Error
+func (r *AcademicRepository) DeleteClass(id, unitID uint) error {
+	return checkAffected(r.db.Scopes(byUnit("unit_id", unitID)).Delete(&domain.Class{}, id))
 }
 
-func (r *AcademicRepository) GetClassByHomeroomTeacher(teacherID string) (*domain.Class, error) {
+func (r *AcademicRepository) GetClassByHomeroomTeacher(teacherID string, unitID uint, term TermFilter) (*domain.Class, error) {
 	var class domain.Class
-	err := r.db.Where("homeroom_teacher_id = ?", teacherID).First(&class).Error
+	err := r.db.Scopes(byUnit("unit_id", unitID), byAcademicYear("academic_year_id", term)).Where("homeroom_teacher_id = ?", teacherID).First(&class).Error
 	return &class, err
 }
 
@@ -52,16 +358,57 @@ func (r *AcademicRepository) CreateSubject(subject *domain.Subject) error {
 
 func (r *AcademicRepository) GetAllSubjects(unitID uint) ([]domain.Subject, error) {
 	var subjects []domain.Subject
-	err := r.db.Where("unit_id = ?", unitID).Find(&subjects).Error
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Find(&subjects).Error
 	return subjects, err
 }
 
+func (r *AcademicRepository) GetSubjectByID(id, unitID uint) (*domain.Subject, error) {
+	var subject domain.Subject
+	err := r.db.Scopes(byUnit("unit_id", unitID)).First(&subject, id).Error
+	return &subject, err
+}
+
 func (r *AcademicRepository) UpdateSubject(subject *domain.Subject) error {
 	return r.db.Save(subject).Error
 }
 
-func (r *AcademicRepository) DeleteSubject(id uint) error {
-	return r.db.Delete(&domain.Subject{}, id).Error
+func (r *AcademicRepository) DeleteSubject(id, unitID uint) error {
+	return checkAffected(r.db.Scopes(byUnit("unit_id", unitID)).Delete(&domain.Subject{}, id))
+}
+
+// Learning objectives
+func (r *AcademicRepository) CreateLearningObjective(objective *domain.LearningObjective) error {
+	return r.db.Create(objective).Error
+}
+
+// GetLearningObjectives lists the objectives of the subjects in order.
+func (r *AcademicRepository) GetLearningObjectives(unitID uint, subjectIDs []uint) ([]domain.LearningObjective, error) {
+	var objectives []domain.LearningObjective
+	err := r.db.Scopes(bySubjectUnit("subject_id", unitID)).Where("subject_id IN ?", subjectIDs).
+		Order("subject_id, position, id").Find(&objectives).Error
+	return objectives, err
+}
+
+// GetSubjectObjectivesByIDs returns those of ids that are objectives of the
+// subject.
+func (r *AcademicRepository) GetSubjectObjectivesByIDs(subjectID uint, ids []uint) ([]domain.LearningObjective, error) {
+	var objectives []domain.LearningObjective
+	err := r.db.Where("subject_id = ? AND id IN ?", subjectID, ids).Find(&objectives).Error
+	return objectives, err
+}
+
+func (r *AcademicRepository) GetLearningObjectiveByID(id, unitID uint) (*domain.LearningObjective, error) {
+	var objective domain.LearningObjective
+	err := r.db.Scopes(bySubjectUnit("subject_id", unitID)).First(&objective, id).Error
+	return &objective, err
+}
+
+func (r *AcademicRepository) UpdateLearningObjective(objective *domain.LearningObjective) error {
+	return r.db.Save(objective).Error
+}
+
+func (r *AcademicRepository) DeleteLearningObjective(id, unitID uint) error {
+	return checkAffected(r.db.Scopes(bySubjectUnit("subject_id", unitID)).Delete(&domain.LearningObjective{}, id))
 }
 
 // Schedule
@@ -69,34 +416,197 @@ func (r *AcademicRepository) CreateSchedule(schedule *domain.Schedule) error {
 	return r.db.Create(schedule).Error
 }
 
-func (r *AcademicRepository) GetAllSchedules(unitID, classID uint, teacherID string) ([]domain.Schedule, error) {
+// CreateSchedules stores a generated timetable in one transaction.
+func (r *AcademicRepository) CreateSchedules(schedules []domain.Schedule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Omit("Class", "Subject", "Teacher").Create(&schedules).Error
+	})
+}
+
+// ScheduleFilter narrows GetAllSchedules; zero fields are ignored.
+type ScheduleFilter struct {
+	ClassID   uint
+	TeacherID string
+	RoomID    uint
+}
+
+func (r *AcademicRepository) GetAllSchedules(unitID uint, filter ScheduleFilter, term TermFilter) ([]domain.Schedule, error) {
 	var schedules []domain.Schedule
-	query := r.db.Preload("Class").Preload("Subject").Preload("Teacher.User")
+	query := r.db.Preload("Class").Preload("Subject").Preload("Teacher.User").Preload("Room").
+		Scopes(byClassUnit("schedules.class_id", unitID), bySemester("schedules.semester_id", term))
 
-	if unitID != 0 {
-		query = query.Joins("JOIN classes ON classes.id = schedules.class_id").Where("classes.unit_id = ?", unitID)
+	if filter.ClassID != 0 {
+		query = query.Where("schedules.class_id = ?", filter.ClassID)
 	}
-	if classID != 0 {
-		query = query.Where("schedules.class_id = ?", classID)
+	if filter.TeacherID != "" {
+		query = query.Where("schedules.teacher_id = ?", filter.TeacherID)
 	}
-	if teacherID != "" {
-		query = query.Where("schedules.teacher_id = ?", teacherID)
+	if filter.RoomID != 0 {
+		query = query.Where("schedules.room_id = ?", filter.RoomID)
 	}
 
 	err := query.Find(&schedules).Error
 	return schedules, err
 }
 
+// GetOverlappingSchedules returns the schedules of the same term that share
+// schedule's teacher or class and overlap its time slot.
+func (r *AcademicRepository) GetOverlappingSchedules(schedule *domain.Schedule) ([]domain.Schedule, error) {
+	var schedules []domain.Schedule
+	query := r.db.Preload("Class").Preload("Subject").Preload("Teacher.User").Preload("Room").
+		Where("id <> ? AND day = ? AND start_time < ? AND end_time > ?", schedule.ID, schedule.Day, schedule.EndTime, schedule.StartTime).
+		Where("teacher_id = ? OR class_id = ? OR room_id = ?", schedule.TeacherID, schedule.ClassID, schedule.RoomID)
+	if schedule.SemesterID != nil {
+		query = query.Where("semester_id = ?", *schedule.SemesterID)
+	} else {
+		query = query.Where("semester_id IS NULL")
+	}
+	err := query.Find(&schedules).Error
+	return schedules, err
+}
+
 func (r *AcademicRepository) UpdateSchedule(schedule *domain.Schedule) error {
 	return r.db.Save(schedule).Error
 }
 
-func (r *AcademicRepository) GetScheduleByID(id uint) (*domain.Schedule, error) {
+func (r *AcademicRepository) GetScheduleByID(id, unitID uint) (*domain.Schedule, error) {
 	var schedule domain.Schedule
-	err := r.db.First(&schedule, id).Error
+	err := r.db.Scopes(byClassUnit("class_id", unitID)).First(&schedule, id).Error
 	return &schedule, err
 }
 
-func (r *AcademicRepository) DeleteSchedule(id uint) error {
-	return r.db.Delete(&domain.Schedule{}, id).Error
+func (r *AcademicRepository) DeleteSchedule(id, unitID uint) error {
+	return checkAffected(r.db.Scopes(byClassUnit("class_id", unitID)).Delete(&domain.Schedule{}, id))
+}
+
+// Gradebook
+
+func (r *AcademicRepository) GetGradeWeights(unitID, semesterID uint) ([]domain.GradeWeight, error) {
+	var weights []domain.GradeWeight
+	err := r.db.Where("unit_id = ? AND semester_id = ?", unitID, semesterID).Find(&weights).Error
+	return weights, err
+}
+
+// ReplaceGradeWeights swaps every weight of the unit's semester for weights.
+func (r *AcademicRepository) ReplaceGradeWeights(unitID, semesterID uint, weights []domain.GradeWeight) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("unit_id = ? AND semester_id = ?", unitID, semesterID).Delete(&domain.GradeWeight{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&weights).Error
+	})
+}
+
+func (r *AcademicRepository) GetStudent(id string, unitID uint) (*domain.Student, error) {
+	var student domain.Student
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Where("id = ?", id).First(&student).Error
+	return &student, err
+}
+
+// CountClassStudents counts how many of studentIDs are in the class.
+func (r *AcademicRepository) CountClassStudents(classID uint, studentIDs []string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Student{}).Where("class_id = ? AND id IN ?", classID, studentIDs).Count(&count).Error
+	return count, err
+}
+
+// SaveScores inserts scores, overwriting the value of an assessment a student
+// already has a score for, and tags every score with the learning objectives.
+func (r *AcademicRepository) SaveScores(scores []domain.Score, objectives []domain.LearningObjective) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Student", "Subject", "LearningObjectives").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "student_id"}, {Name: "subject_id"}, {Name: "semester_id"}, {Name: "component"}, {Name: "title"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "recorded_by_id", "updated_at"}),
+		}).Create(&scores).Error; err != nil {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(scores))
+		var tags []map[string]interface{}
+		for i, score := range scores {
+			ids[i] = score.ID
+			for _, objective := range objectives {
+				tags = append(tags, map[string]interface{}{"score_id": score.ID, "learning_objective_id": objective.ID})
+			}
+		}
+		if err := tx.Exec("DELETE FROM score_learning_objectives WHERE score_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		return tx.Table("score_learning_objectives").Create(&tags).Error
+	})
+}
+
+type ScoreFilter struct {
+	ClassID   uint
+	SubjectID uint
+	StudentID string
+}
+
+func (r *AcademicRepository) GetScores(unitID uint, filter ScoreFilter, term TermFilter) ([]domain.Score, error) {
+	var scores []domain.Score
+	query := r.db.Preload("Student.User").Preload("Student.Class").Preload("Subject").
+		Scopes(byStudentUnit("student_id", unitID), bySemester("semester_id", term))
+
+	if filter.ClassID != 0 {
+		query = query.Where("student_id IN (SELECT id FROM students WHERE class_id = ?)", filter.ClassID)
+	}
+	if filter.SubjectID != 0 {
+		query = query.Where("subject_id = ?", filter.SubjectID)
+	}
+	if filter.StudentID != "" {
+		query = query.Where("student_id = ?", filter.StudentID)
+	}
+
+	err := query.Preload("Attempts", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).Preload("LearningObjectives").
+		Order("component, title").Find(&scores).Error
+	return scores, err
+}
+
+func (r *AcademicRepository) GetScoreByID(id string, unitID uint) (*domain.Score, error) {
+	var score domain.Score
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).Preload("Student").Preload("Attempts").Where("id = ?", id).First(&score).Error
+	return &score, err
+}
+
+func (r *AcademicRepository) DeleteScore(id string, unitID uint) error {
+	return checkAffected(r.db.Scopes(byStudentUnit("student_id", unitID)).Where("id = ?", id).Delete(&domain.Score{}))
+}
+
+func (r *AcademicRepository) CreateScoreAttempt(attempt *domain.ScoreAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+func (r *AcademicRepository) GetScoreAttemptByID(id string, unitID uint) (*domain.ScoreAttempt, error) {
+	var attempt domain.ScoreAttempt
+	err := r.db.Scopes(byScoreUnit("score_id", unitID)).Where("id = ?", id).First(&attempt).Error
+	return &attempt, err
+}
+
+func (r *AcademicRepository) DeleteScoreAttempt(id string, unitID uint) error {
+	return checkAffected(r.db.Scopes(byScoreUnit("score_id", unitID)).Where("id = ?", id).Delete(&domain.ScoreAttempt{}))
+}
+
+// KKM
+
+func (r *AcademicRepository) GetKKMs(unitID uint, term TermFilter) ([]domain.KKM, error) {
+	var kkms []domain.KKM
+	err := r.db.Preload("Subject").Scopes(bySubjectUnit("subject_id", unitID), bySemester("semester_id", term)).
+		Order("subject_id, class_id").Find(&kkms).Error
+	return kkms, err
+}
+
+// SaveKKM sets the KKM of a subject, class and semester, replacing any
+// earlier value.
+func (r *AcademicRepository) SaveKKM(kkm *domain.KKM) error {
+	return r.db.Omit("Subject").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subject_id"}, {Name: "class_id"}, {Name: "semester_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(kkm).Error
+}
+
+func (r *AcademicRepository) DeleteKKM(id, unitID uint) error {
+	return checkAffected(r.db.Scopes(bySubjectUnit("subject_id", unitID)).Delete(&domain.KKM{}, id))
 }
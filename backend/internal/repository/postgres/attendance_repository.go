@@ -19,15 +19,17 @@ func (r *AttendanceRepository) Create(attendance *domain.Attendance) error {
 	return r.db.Create(attendance).Error
 }
 
-func (r *AttendanceRepository) GetBySchedule(scheduleID uint) ([]domain.Attendance, error) {
+func (r *AttendanceRepository) GetBySchedule(scheduleID, unitID uint) ([]domain.Attendance, error) {
 	var attendances []domain.Attendance
-	err := r.db.Where("schedule_id = ?", scheduleID).Preload("Student.User").Find(&attendances).Error
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).Where("schedule_id = ?", scheduleID).Preload("Student.User").Find(&attendances).Error
 	return attendances, err
 }
 
-func (r *AttendanceRepository) GetByStudent(studentID string) ([]domain.Attendance, error) {
+// GetByStudent narrows by the term of the attended schedule.
+func (r *AttendanceRepository) GetByStudent(studentID string, unitID uint, term TermFilter) ([]domain.Attendance, error) {
 	var attendances []domain.Attendance
-	err := r.db.Where("student_id = ?", studentID).Preload("Schedule.Subject").Find(&attendances).Error
+	scheduleIDs := r.db.Model(&domain.Schedule{}).Select("id").Scopes(bySemester("semester_id", term))
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).Where("student_id = ? AND schedule_id IN (?)", studentID, scheduleIDs).Preload("Schedule.Subject").Find(&attendances).Error
 	return attendances, err
 }
 
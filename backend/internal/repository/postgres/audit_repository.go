@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"ppi-100-sis/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// AuditLogFilter narrows GetLogs. Zero values are ignored.
+type AuditLogFilter struct {
+	UnitID  uint
+	ActorID string
+	// Matches entries made while this Super Admin was impersonating someone
+	ImpersonatorID string
+	Action         string
+	EntityType     string
+	EntityID       string
+	From           time.Time
+	To             time.Time
+	Limit          int
+	Offset         int
+}
+
+func (r *AuditRepository) Create(log *domain.AuditLog) error {
+	return r.db.Create(log).Error
+}
+
+func (r *AuditRepository) GetLogs(filter AuditLogFilter) ([]domain.AuditLog, int64, error) {
+	query := r.db.Model(&domain.AuditLog{}).Scopes(byUnit("unit_id", filter.UnitID))
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.ImpersonatorID != "" {
+		query = query.Where("impersonator_id = ?", filter.ImpersonatorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []domain.AuditLog
+	err := query.Preload("Actor").Order("created_at desc").Limit(filter.Limit).Offset(filter.Offset).Find(&logs).Error
+	return logs, total, err
+}
+
+// Snapshot loads the row of model's table with the given primary key as a
+// column/value map, or nil if there is none.
+func (r *AuditRepository) Snapshot(model interface{}, id string) (map[string]interface{}, error) {
+	row := map[string]interface{}{}
+	err := r.db.Model(model).Where("id = ?", id).Take(&row).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return row, err
+}
@@ -30,17 +30,15 @@ func (r *BKRepository) CreateBKCall(call *domain.BKCall) error {
 	return r.db.Create(call).Error
 }
 
-func (r *BKRepository) GetBKCallsByStudent(studentID string) ([]domain.BKCall, error) {
+func (r *BKRepository) GetBKCallsByStudent(studentID string, unitID uint) ([]domain.BKCall, error) {
 	var calls []domain.BKCall
-	err := r.db.Where("student_id = ?", studentID).Preload("Student.User").Preload("Teacher.User").Find(&calls).Error
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).Where("student_id = ?", studentID).Preload("Student.User").Preload("Teacher.User").Find(&calls).Error
 	return calls, err
 }
 
 func (r *BKRepository) GetAllBKCalls(unitID uint) ([]domain.BKCall, error) {
 	var calls []domain.BKCall
-	// Join with students to filter by unitID
-	err := r.db.Joins("JOIN students ON students.id = bk_calls.student_id").
-		Where("students.unit_id = ?", unitID).
+	err := r.db.Scopes(byStudentUnit("bk_calls.student_id", unitID)).
 		Preload("Student.User").
 		Preload("Teacher.User").
 		Find(&calls).Error
@@ -57,11 +55,17 @@ func (r *BKRepository) DeleteViolation(id uint) error {
 }
 
 // Update/Delete for BKCall
+func (r *BKRepository) GetBKCallByID(id string, unitID uint) (*domain.BKCall, error) {
+	var call domain.BKCall
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).Where("id = ?", id).First(&call).Error
+	return &call, err
+}
+
 func (r *BKRepository) UpdateBKCall(call *domain.BKCall) error {
 	return r.db.Save(call).Error
 }
 
-func (r *BKRepository) DeleteBKCall(id string) error {
-	return r.db.Delete(&domain.BKCall{}, "id = ?", id).Error
+func (r *BKRepository) DeleteBKCall(id string, unitID uint) error {
+	return checkAffected(r.db.Scopes(byStudentUnit("student_id", unitID)).Delete(&domain.BKCall{}, "id = ?", id))
 }
 
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"ppi-100-sis/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -20,16 +21,15 @@ func (r *ElearningRepository) CreateMaterial(material *domain.Material) error {
 	return r.db.Create(material).Error
 }
 
-func (r *ElearningRepository) GetMaterialsByClass(classID uint) ([]domain.Material, error) {
+func (r *ElearningRepository) GetMaterialsByClass(classID, unitID uint, term TermFilter) ([]domain.Material, error) {
 	var materials []domain.Material
-	err := r.db.Where("class_id = ?", classID).Preload("Subject").Find(&materials).Error
+	err := r.db.Scopes(byClassUnit("class_id", unitID), bySemester("semester_id", term)).Where("class_id = ?", classID).Preload("Subject").Find(&materials).Error
 	return materials, err
 }
 
-func (r *ElearningRepository) GetMaterialsByUnit(unitID uint) ([]domain.Material, error) {
+func (r *ElearningRepository) GetMaterialsByUnit(unitID uint, term TermFilter) ([]domain.Material, error) {
 	var materials []domain.Material
-	err := r.db.Joins("JOIN classes ON classes.id = materials.class_id").
-		Where("classes.unit_id = ?", unitID).
+	err := r.db.Scopes(byClassUnit("materials.class_id", unitID), bySemester("materials.semester_id", term)).
 		Preload("Subject").
 		Find(&materials).Error
 	return materials, err
@@ -40,29 +40,40 @@ func (r *ElearningRepository) CreateTask(task *domain.Task) error {
 	return r.db.Create(task).Error
 }
 
-func (r *ElearningRepository) GetTasksByClass(classID uint) ([]domain.Task, error) {
+func (r *ElearningRepository) GetTasksByClass(classID, unitID uint, term TermFilter) ([]domain.Task, error) {
 	var tasks []domain.Task
-	err := r.db.Where("class_id = ?", classID).Preload("Subject").Find(&tasks).Error
+	err := r.db.Scopes(byClassUnit("class_id", unitID), bySemester("semester_id", term)).Where("class_id = ?", classID).Preload("Subject").Preload("LearningObjectives").Find(&tasks).Error
 	return tasks, err
 }
 
-func (r *ElearningRepository) GetTasksByUnit(unitID uint) ([]domain.Task, error) {
+func (r *ElearningRepository) GetTasksByUnit(unitID uint, term TermFilter) ([]domain.Task, error) {
 	var tasks []domain.Task
-	err := r.db.Joins("JOIN classes ON classes.id = tasks.class_id").
-		Where("classes.unit_id = ?", unitID).
-		Preload("Subject").
+	err := r.db.Scopes(byClassUnit("tasks.class_id", unitID), bySemester("tasks.semester_id", term)).
+		Preload("Subject").Preload("LearningObjectives").
 		Find(&tasks).Error
 	return tasks, err
 }
 
+func (r *ElearningRepository) GetTasksByTeacher(teacherID string, unitID uint, term TermFilter) ([]domain.Task, error) {
+	var tasks []domain.Task
+	err := r.db.Scopes(byClassUnit("class_id", unitID), bySemester("semester_id", term)).Where("teacher_id = ?", teacherID).Preload("Subject").Preload("LearningObjectives").Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *ElearningRepository) GetTaskByID(id, unitID uint) (*domain.Task, error) {
+	var task domain.Task
+	err := r.db.Scopes(byClassUnit("class_id", unitID)).First(&task, id).Error
+	return &task, err
+}
+
 // Submissions
 func (r *ElearningRepository) CreateSubmission(submission *domain.TaskSubmission) error {
 	return r.db.Create(submission).Error
 }
 
-func (r *ElearningRepository) GetSubmissionsByTask(taskID uint) ([]domain.TaskSubmission, error) {
+func (r *ElearningRepository) GetSubmissionsByTask(taskID, unitID uint) ([]domain.TaskSubmission, error) {
 	var submissions []domain.TaskSubmission
-	err := r.db.Where("task_id = ?", taskID).Preload("Student.User").Find(&submissions).Error
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).Where("task_id = ?", taskID).Preload("Student.User").Find(&submissions).Error
 	return submissions, err
 }
 
@@ -72,36 +83,59 @@ func (r *ElearningRepository) GetSubmissionByID(id uuid.UUID) (*domain.TaskSubmi
 	return &submission, err
 }
 
-func (r *ElearningRepository) UpdateSubmissionGrade(id uuid.UUID, grade float64) error {
-	return r.db.Model(&domain.TaskSubmission{}).Where("id = ?", id).Update("grade", grade).Error
+func (r *ElearningRepository) UpdateSubmissionGrade(id uuid.UUID, grade float64, unitID uint) error {
+	return checkAffected(r.db.Model(&domain.TaskSubmission{}).Scopes(byStudentUnit("student_id", unitID)).Where("id = ?", id).Updates(map[string]interface{}{"grade": grade, "graded_at": time.Now()}))
 }
 
-func (r *ElearningRepository) GetSubmissionsByStudent(studentID uuid.UUID) ([]domain.TaskSubmission, error) {
+// GetSubmissionsByStudent narrows by the term of the submitted task.
+func (r *ElearningRepository) GetSubmissionsByStudent(studentID uuid.UUID, unitID uint, term TermFilter) ([]domain.TaskSubmission, error) {
 	var submissions []domain.TaskSubmission
-	err := r.db.Where("student_id = ?", studentID).Preload("Task.Subject").Find(&submissions).Error
+	taskIDs := r.db.Model(&domain.Task{}).Select("id").Scopes(bySemester("semester_id", term))
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).Where("student_id = ? AND task_id IN (?)", studentID, taskIDs).Preload("Task.Subject").Preload("Task.LearningObjectives").Find(&submissions).Error
+	return submissions, err
+}
+
+// GetGradedSubmissions lists every graded submission, narrowed by the term of
+// the submitted task.
+func (r *ElearningRepository) GetGradedSubmissions(unitID uint, term TermFilter) ([]domain.TaskSubmission, error) {
+	var submissions []domain.TaskSubmission
+	taskIDs := r.db.Model(&domain.Task{}).Select("id").Scopes(bySemester("semester_id", term))
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).Where("graded_at IS NOT NULL AND task_id IN (?)", taskIDs).
+		Preload("Task.Subject").Preload("Student.User").Preload("Student.Class").Find(&submissions).Error
 	return submissions, err
 }
 
 // Update/Delete for Material
+func (r *ElearningRepository) GetMaterialByID(id, unitID uint) (*domain.Material, error) {
+	var material domain.Material
+	err := r.db.Scopes(byClassUnit("class_id", unitID)).First(&material, id).Error
+	return &material, err
+}
+
 func (r *ElearningRepository) UpdateMaterial(material *domain.Material) error {
 	return r.db.Save(material).Error
 }
 
-func (r *ElearningRepository) DeleteMaterial(id uint) error {
-	return r.db.Delete(&domain.Material{}, id).Error
+func (r *ElearningRepository) DeleteMaterial(id, unitID uint) error {
+	return checkAffected(r.db.Scopes(byClassUnit("class_id", unitID)).Delete(&domain.Material{}, id))
 }
 
 // Update/Delete for Task
+// UpdateTask saves a task and replaces its learning objectives.
 func (r *ElearningRepository) UpdateTask(task *domain.Task) error {
-	return r.db.Save(task).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("LearningObjectives").Save(task).Error; err != nil {
+			return err
+		}
+		return tx.Model(task).Association("LearningObjectives").Replace(task.LearningObjectives)
+	})
 }
 
-func (r *ElearningRepository) DeleteTask(id uint) error {
-	return r.db.Delete(&domain.Task{}, id).Error
+func (r *ElearningRepository) DeleteTask(id, unitID uint) error {
+	return checkAffected(r.db.Scopes(byClassUnit("class_id", unitID)).Delete(&domain.Task{}, id))
 }
 
 // Delete for Submission
-func (r *ElearningRepository) DeleteSubmission(id uuid.UUID) error {
-	return r.db.Delete(&domain.TaskSubmission{}, "id = ?", id).Error
+func (r *ElearningRepository) DeleteSubmission(id uuid.UUID, unitID uint) error {
+	return checkAffected(r.db.Scopes(byStudentUnit("student_id", unitID)).Delete(&domain.TaskSubmission{}, "id = ?", id))
 }
-
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"ppi-100-sis/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EnrollmentRepository struct {
+	db *gorm.DB
+}
+
+func NewEnrollmentRepository(db *gorm.DB) *EnrollmentRepository {
+	return &EnrollmentRepository{db: db}
+}
+
+// PromotionStep is the year-end outcome for one student. ToClassID is zero
+// for graduates.
+type PromotionStep struct {
+	Student   domain.Student
+	Status    string
+	ToClassID uint
+}
+
+func (r *EnrollmentRepository) GetByStudent(studentID string, unitID uint) ([]domain.ClassEnrollment, error) {
+	var enrollments []domain.ClassEnrollment
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).
+		Where("student_id = ?", studentID).
+		Preload("Class").
+		Order("started_at DESC").
+		Find(&enrollments).Error
+	return enrollments, err
+}
+
+// inSemester keeps the enrollments in force during the semester. The academic
+// year check keeps out a promotion applied before the semester's last day.
+func inSemester(semester *domain.Semester) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("started_at <= ? AND (ended_at IS NULL OR ended_at > ?) AND (academic_year_id IS NULL OR academic_year_id = ?)",
+			semester.EndDate, semester.StartDate, semester.AcademicYearID)
+	}
+}
+
+// GetClassRoster lists the students whose last class during the semester was
+// classID. Students without any enrollment history count by their current
+// class.
+func (r *EnrollmentRepository) GetClassRoster(classID uint, semester *domain.Semester, unitID uint) ([]domain.Student, error) {
+	last := r.db.Model(&domain.ClassEnrollment{}).Select("class_id").
+		Where("student_id = students.id").
+		Scopes(inSemester(semester)).
+		Order("started_at DESC").Limit(1)
+	var students []domain.Student
+	err := r.db.Scopes(byUnit("unit_id", unitID)).
+		Where("(?) = ? OR (students.class_id = ? AND NOT EXISTS (SELECT 1 FROM class_enrollments WHERE student_id = students.id))", last, classID, classID).
+		Scopes(withUser).Preload("Class").
+		Find(&students).Error
+	return students, err
+}
+
+// moveEnrollment closes the student's current enrollment and opens one in
+// class. student must still hold the class being left.
+func moveEnrollment(tx *gorm.DB, student *domain.Student, class *domain.Class) error {
+	now := time.Now()
+	if err := closeEnrollment(tx, student, domain.EnrollmentMoved, now); err != nil {
+		return err
+	}
+	return tx.Create(&domain.ClassEnrollment{
+		StudentID:      student.ID,
+		ClassID:        class.ID,
+		AcademicYearID: class.AcademicYearID,
+		Status:         domain.EnrollmentActive,
+		StartedAt:      now,
+	}).Error
+}
+
+// ApplyPromotion runs the whole year-end promotion in one transaction:
+// promoted and retained students are enrolled into their new class of year,
+// graduates get an unpublished Alumni entry and their account is archived
+// (soft-deleted) so it can no longer sign in. A graduate's class stays the one
+// they graduated from.
+func (r *EnrollmentRepository) ApplyPromotion(steps []PromotionStep, year *domain.AcademicYear) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for _, step := range steps {
+			student := step.Student
+			if err := closeEnrollment(tx, &student, step.Status, now); err != nil {
+				return err
+			}
+
+			if step.Status == domain.EnrollmentGraduated {
+				alumni := &domain.Alumni{
+					Name:           student.User.Name,
+					GraduationYear: year.StartDate.Year(),
+					StudentID:      &student.ID,
+				}
+				if err := createAlumni(tx, alumni); err != nil {
+					return err
+				}
+				if err := tx.Delete(&domain.User{}, "id = ?", student.UserID).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := tx.Create(&domain.ClassEnrollment{
+				StudentID:      student.ID,
+				ClassID:        step.ToClassID,
+				AcademicYearID: &year.ID,
+				Status:         domain.EnrollmentActive,
+				StartedAt:      now,
+			}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&domain.Student{}).Where("id = ?", student.ID).Update("class_id", step.ToClassID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// closeEnrollment ends the student's Active enrollment with status. Students
+// created before enrollments were recorded get a closed entry for their
+// current class instead.
+func closeEnrollment(tx *gorm.DB, student *domain.Student, status string, endedAt time.Time) error {
+	result := tx.Model(&domain.ClassEnrollment{}).
+		Where("student_id = ? AND status = ?", student.ID, domain.EnrollmentActive).
+		Updates(map[string]interface{}{"status": status, "ended_at": endedAt})
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+	return tx.Create(&domain.ClassEnrollment{
+		StudentID:      student.ID,
+		ClassID:        student.ClassID,
+		AcademicYearID: student.Class.AcademicYearID,
+		Status:         status,
+		StartedAt:      student.CreatedAt,
+		EndedAt:        &endedAt,
+	}).Error
+}
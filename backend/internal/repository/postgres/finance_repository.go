@@ -18,23 +18,26 @@ func (r *FinanceRepository) CreateBill(bill *domain.Bill) error {
 	return r.db.Create(bill).Error
 }
 
-func (r *FinanceRepository) GetBillsByStudent(studentID string) ([]domain.Bill, error) {
+func (r *FinanceRepository) GetBillsByStudent(studentID string, unitID uint) ([]domain.Bill, error) {
 	var bills []domain.Bill
-	err := r.db.Where("student_id = ?", studentID).Preload("Student.User").Find(&bills).Error
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).Where("student_id = ?", studentID).Preload("Student.User").Find(&bills).Error
 	return bills, err
 }
 
 func (r *FinanceRepository) GetAllBills(unitID uint) ([]domain.Bill, error) {
 	var bills []domain.Bill
-	// Join with students to filter by unitID if needed, or just return all for now.
-	// Assuming unitID filtering is done via Student relation
-	err := r.db.Joins("JOIN students ON students.id = bills.student_id").
-		Where("students.unit_id = ?", unitID).
+	err := r.db.Scopes(byStudentUnit("bills.student_id", unitID)).
 		Preload("Student.User").
 		Find(&bills).Error
 	return bills, err
 }
 
+func (r *FinanceRepository) GetBillByID(id string, unitID uint) (*domain.Bill, error) {
+	var bill domain.Bill
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).Where("id = ?", id).First(&bill).Error
+	return &bill, err
+}
+
 func (r *FinanceRepository) CreatePayment(payment *domain.Payment) error {
 	return r.db.Create(payment).Error
 }
@@ -48,16 +51,22 @@ func (r *FinanceRepository) UpdateBill(bill *domain.Bill) error {
 	return r.db.Save(bill).Error
 }
 
-func (r *FinanceRepository) DeleteBill(id string) error {
-	return r.db.Delete(&domain.Bill{}, "id = ?", id).Error
+func (r *FinanceRepository) DeleteBill(id string, unitID uint) error {
+	return checkAffected(r.db.Scopes(byStudentUnit("student_id", unitID)).Delete(&domain.Bill{}, "id = ?", id))
 }
 
 // Update/Delete for Payment
+func (r *FinanceRepository) GetPaymentByID(id string, unitID uint) (*domain.Payment, error) {
+	var payment domain.Payment
+	err := r.db.Scopes(byBillUnit("bill_id", unitID)).Where("id = ?", id).First(&payment).Error
+	return &payment, err
+}
+
 func (r *FinanceRepository) UpdatePayment(payment *domain.Payment) error {
 	return r.db.Save(payment).Error
 }
 
-func (r *FinanceRepository) DeletePayment(id string) error {
-	return r.db.Delete(&domain.Payment{}, "id = ?", id).Error
+func (r *FinanceRepository) DeletePayment(id string, unitID uint) error {
+	return checkAffected(r.db.Scopes(byBillUnit("bill_id", unitID)).Delete(&domain.Payment{}, "id = ?", id))
 }
 
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"ppi-100-sis/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type HomeroomRepository struct {
+	db *gorm.DB
+}
+
+func NewHomeroomRepository(db *gorm.DB) *HomeroomRepository {
+	return &HomeroomRepository{db: db}
+}
+
+// Homeroom notes
+
+func (r *HomeroomRepository) GetNotesByStudents(studentIDs []uuid.UUID, semesterID uint) ([]domain.HomeroomNote, error) {
+	notes := []domain.HomeroomNote{}
+	if len(studentIDs) == 0 {
+		return notes, nil
+	}
+	err := r.db.Where("semester_id = ? AND student_id IN ?", semesterID, studentIDs).Find(&notes).Error
+	return notes, err
+}
+
+// GetNote returns the student's note for the semester, or nil if there is none.
+func (r *HomeroomRepository) GetNote(studentID string, semesterID uint) (*domain.HomeroomNote, error) {
+	var notes []domain.HomeroomNote
+	if err := r.db.Where("student_id = ? AND semester_id = ?", studentID, semesterID).Limit(1).Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	if len(notes) == 0 {
+		return nil, nil
+	}
+	return &notes[0], nil
+}
+
+func (r *HomeroomRepository) GetNoteByID(id, unitID uint) (*domain.HomeroomNote, error) {
+	var note domain.HomeroomNote
+	err := r.db.Scopes(byStudentUnit("student_id", unitID)).First(&note, id).Error
+	return &note, err
+}
+
+func (r *HomeroomRepository) CreateNote(note *domain.HomeroomNote) error {
+	return r.db.Create(note).Error
+}
+
+func (r *HomeroomRepository) UpdateNote(note *domain.HomeroomNote) error {
+	return r.db.Save(note).Error
+}
+
+// P5 projects
+
+func (r *HomeroomRepository) CreateProject(project *domain.P5Project) error {
+	return r.db.Create(project).Error
+}
+
+func (r *HomeroomRepository) GetProjectsByClass(classID, semesterID uint) ([]domain.P5Project, error) {
+	var projects []domain.P5Project
+	err := r.db.Where("class_id = ? AND semester_id = ?", classID, semesterID).
+		Preload("Assessments").Order("created_at").Find(&projects).Error
+	return projects, err
+}
+
+func (r *HomeroomRepository) GetProjectByID(id, unitID uint) (*domain.P5Project, error) {
+	var project domain.P5Project
+	err := r.db.Scopes(byClassUnit("class_id", unitID)).First(&project, id).Error
+	return &project, err
+}
+
+func (r *HomeroomRepository) UpdateProject(project *domain.P5Project) error {
+	return r.db.Omit("Assessments").Save(project).Error
+}
+
+func (r *HomeroomRepository) DeleteProject(id, unitID uint) error {
+	return checkAffected(r.db.Scopes(byClassUnit("class_id", unitID)).Delete(&domain.P5Project{}, id))
+}
+
+// ReplaceAssessments sets every assessment of a project at once.
+func (r *HomeroomRepository) ReplaceAssessments(projectID uint, assessments []domain.P5Assessment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", projectID).Delete(&domain.P5Assessment{}).Error; err != nil {
+			return err
+		}
+		if len(assessments) == 0 {
+			return nil
+		}
+		return tx.Create(&assessments).Error
+	})
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"ppi-100-sis/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) *InvitationRepository {
+	return &InvitationRepository{db: db}
+}
+
+func (r *InvitationRepository) Create(invitation *domain.Invitation) error {
+	return r.db.Create(invitation).Error
+}
+
+func (r *InvitationRepository) GetAll(unitID uint) ([]domain.Invitation, error) {
+	var invitations []domain.Invitation
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Order("created_at desc").Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *InvitationRepository) GetByID(id uuid.UUID) (*domain.Invitation, error) {
+	var invitation domain.Invitation
+	err := r.db.Where("id = ?", id).First(&invitation).Error
+	return &invitation, err
+}
+
+func (r *InvitationRepository) Delete(id uuid.UUID, unitID uint) error {
+	return checkAffected(r.db.Scopes(byUnit("unit_id", unitID)).Delete(&domain.Invitation{}, "id = ?", id))
+}
+
+// Accept marks the invitation accepted and creates user in one transaction.
+// It fails with gorm.ErrRecordNotFound if the invitation was already accepted.
+func (r *InvitationRepository) Accept(invitation *domain.Invitation, user *domain.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkAffected(tx.Model(&domain.Invitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", time.Now())); err != nil {
+			return err
+		}
+		return tx.Create(user).Error
+	})
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"errors"
+	"ppi-100-sis/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepository is the Postgres-backed ratelimit.Store, for
+// deployments running more than one API instance.
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func (r *LoginAttemptRepository) Get(key string) (*domain.LoginAttempt, error) {
+	var attempt domain.LoginAttempt
+	err := r.db.Where("key = ?", key).First(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// RecordFailure upserts in a single statement so concurrent failures from
+// several instances are all counted.
+func (r *LoginAttemptRepository) RecordFailure(key string, window time.Duration) (*domain.LoginAttempt, error) {
+	now := time.Now()
+	var attempt domain.LoginAttempt
+	err := r.db.Raw(`
+		INSERT INTO login_attempts (key, failures, window_start, last_failure_at)
+		VALUES (@key, 1, @now, @now)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.window_start < @since THEN 1 ELSE login_attempts.failures + 1 END,
+			window_start = CASE WHEN login_attempts.window_start < @since THEN @now ELSE login_attempts.window_start END,
+			last_failure_at = @now
+		RETURNING *`,
+		map[string]interface{}{"key": key, "now": now, "since": now.Add(-window)},
+	).Scan(&attempt).Error
+	return &attempt, err
+}
+
+func (r *LoginAttemptRepository) Lock(key string, until time.Time) error {
+	return r.db.Model(&domain.LoginAttempt{}).Where("key = ?", key).Update("locked_until", until).Error
+}
+
+func (r *LoginAttemptRepository) Reset(key string) error {
+	return r.db.Where("key = ?", key).Delete(&domain.LoginAttempt{}).Error
+}
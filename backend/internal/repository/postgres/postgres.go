@@ -22,22 +22,50 @@ func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
 }
 
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := migrateScheduleTimes(db); err != nil {
+		return err
+	}
+	// Submissions graded before graded_at existed are recognised by their grade
+	backfillGradedAt := db.Migrator().HasTable(&domain.TaskSubmission{}) && !db.Migrator().HasColumn(&domain.TaskSubmission{}, "GradedAt")
+
+	if err := db.AutoMigrate(
 		&domain.User{},
 		&domain.Role{},
 		&domain.Unit{},
+		&domain.RefreshToken{},
+		&domain.PasswordResetToken{},
+		&domain.Invitation{},
+		&domain.LoginAttempt{},
+		&domain.RecoveryCode{},
+		&domain.CalendarFeedToken{},
+		&domain.AcademicYear{},
+		&domain.Semester{},
 		&domain.Student{},
+		&domain.ClassEnrollment{},
 		&domain.Parent{},
 		&domain.Teacher{},
 		&domain.Class{},
 		&domain.Subject{},
+		&domain.LearningObjective{},
+		&domain.CalendarEvent{},
+		&domain.Room{},
 		&domain.Schedule{},
+		&domain.Period{},
+		&domain.RoomBooking{},
+		&domain.Substitution{},
 		&domain.Attendance{},
 		&domain.Violation{},
 		&domain.BKCall{},
 		&domain.Material{},
 		&domain.Task{},
 		&domain.TaskSubmission{},
+		&domain.GradeWeight{},
+		&domain.Score{},
+		&domain.ScoreAttempt{},
+		&domain.KKM{},
+		&domain.HomeroomNote{},
+		&domain.P5Project{},
+		&domain.P5Assessment{},
 		&domain.Bill{},
 		&domain.Payment{},
 		&domain.Notification{},
@@ -47,5 +75,42 @@ func AutoMigrate(db *gorm.DB) error {
 		&domain.Alumni{},
 		&domain.PPDBRegistration{},
 		&domain.ContactMessage{},
-	)
+		&domain.AuditLog{},
+	); err != nil {
+		return err
+	}
+
+	if backfillGradedAt {
+		return db.Exec("UPDATE task_submissions SET graded_at = created_at WHERE grade <> 0").Error
+	}
+	return nil
+}
+
+// migrateScheduleTimes converts schedules created with free-text day and
+// "HH:MM" time columns to smallint days and time columns. Unrecognised days
+// become 0 and unparsable times 00:00; both show up in the conflict report.
+func migrateScheduleTimes(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&domain.Schedule{}) {
+		return nil
+	}
+	columns, err := db.Migrator().ColumnTypes(&domain.Schedule{})
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if column.Name() == "day" && column.DatabaseTypeName() != "text" && column.DatabaseTypeName() != "varchar" {
+			return nil
+		}
+	}
+
+	days := "CASE lower(trim(day))"
+	for i, names := range [][2]string{{"monday", "senin"}, {"tuesday", "selasa"}, {"wednesday", "rabu"}, {"thursday", "kamis"}, {"friday", "jumat"}, {"saturday", "sabtu"}, {"sunday", "minggu"}} {
+		days += fmt.Sprintf(" WHEN '%s' THEN %d WHEN '%s' THEN %d", names[0], i+1, names[1], i+1)
+	}
+	days += " ELSE 0 END"
+	clock := func(column string) string {
+		return fmt.Sprintf("CASE WHEN trim(%[1]s) ~ '^([01]?[0-9]|2[0-3]):[0-5][0-9](:[0-5][0-9])?$' THEN trim(%[1]s)::time ELSE '00:00'::time END", column)
+	}
+	return db.Exec(fmt.Sprintf("ALTER TABLE schedules ALTER COLUMN day TYPE smallint USING %s, ALTER COLUMN start_time TYPE time USING %s, ALTER COLUMN end_time TYPE time USING %s",
+		days, clock("start_time"), clock("end_time"))).Error
 }
@@ -37,14 +37,38 @@ func (r *PublicRepository) CreateDownload(download *domain.Download) error {
 }
 
 // Alumni
-func (r *PublicRepository) GetAllAlumni() ([]domain.Alumni, error) {
+// GetAllAlumni lists the alumni shown on the website, or every entry when
+// includeUnpublished is set.
+func (r *PublicRepository) GetAllAlumni(includeUnpublished bool) ([]domain.Alumni, error) {
 	var alumni []domain.Alumni
-	err := r.db.Find(&alumni).Error
+	query := r.db
+	if !includeUnpublished {
+		query = query.Where("is_published = ?", true)
+	}
+	err := query.Find(&alumni).Error
 	return alumni, err
 }
 
+func (r *PublicRepository) GetAlumniByID(id uint) (*domain.Alumni, error) {
+	var alumni domain.Alumni
+	err := r.db.First(&alumni, id).Error
+	return &alumni, err
+}
+
 func (r *PublicRepository) CreateAlumni(alumni *domain.Alumni) error {
-	return r.db.Create(alumni).Error
+	return createAlumni(r.db, alumni)
+}
+
+// createAlumni inserts alumni. IsPublished defaults to true in the table, so
+// an unpublished entry has to be written in a second step.
+func createAlumni(db *gorm.DB, alumni *domain.Alumni) error {
+	if err := db.Create(alumni).Error; err != nil {
+		return err
+	}
+	if alumni.IsPublished {
+		return nil
+	}
+	return db.Model(alumni).Update("is_published", false).Error
 }
 
 // PPDB
@@ -52,20 +76,14 @@ func (r *PublicRepository) CreatePPDBRegistration(reg *domain.PPDBRegistration)
 	return r.db.Create(reg).Error
 }
 
-func (r *PublicRepository) GetAllPPDBRegistrations() ([]domain.PPDBRegistration, error) {
-	var registrations []domain.PPDBRegistration
-	err := r.db.Order("created_at desc").Find(&registrations).Error
-	return registrations, err
-}
-
-func (r *PublicRepository) GetPPDBRegistrationsByUnit(unitID uint) ([]domain.PPDBRegistration, error) {
+func (r *PublicRepository) GetPPDBRegistrations(unitID uint) ([]domain.PPDBRegistration, error) {
 	var registrations []domain.PPDBRegistration
-	err := r.db.Where("unit_id = ?", unitID).Order("created_at desc").Find(&registrations).Error
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Order("created_at desc").Find(&registrations).Error
 	return registrations, err
 }
 
-func (r *PublicRepository) UpdatePPDBStatus(id string, status string) error {
-	return r.db.Model(&domain.PPDBRegistration{}).Where("id = ?", id).Update("status", status).Error
+func (r *PublicRepository) UpdatePPDBStatus(id string, status string, unitID uint) error {
+	return checkAffected(r.db.Model(&domain.PPDBRegistration{}).Scopes(byUnit("unit_id", unitID)).Where("id = ?", id).Update("status", status))
 }
 
 // Contact
@@ -108,6 +126,6 @@ func (r *PublicRepository) DeleteAlumni(id string) error {
 	return r.db.Delete(&domain.Alumni{}, "id = ?", id).Error
 }
 
-func (r *PublicRepository) DeletePPDBRegistration(id string) error {
-	return r.db.Delete(&domain.PPDBRegistration{}, "id = ?", id).Error
+func (r *PublicRepository) DeletePPDBRegistration(id string, unitID uint) error {
+	return checkAffected(r.db.Scopes(byUnit("unit_id", unitID)).Delete(&domain.PPDBRegistration{}, "id = ?", id))
 }
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"gorm.io/gorm"
+)
+
+// Unit scopes restrict a query to rows belonging to one unit.
+// A unitID of 0 means no restriction and is only handed down for Super Admin.
+
+func byUnit(column string, unitID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID == 0 {
+			return db
+		}
+		return db.Where(column+" = ?", unitID)
+	}
+}
+
+func byClassUnit(column string, unitID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID == 0 {
+			return db
+		}
+		return db.Where(column+" IN (SELECT id FROM classes WHERE unit_id = ?)", unitID)
+	}
+}
+
+func byRoomUnit(column string, unitID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID == 0 {
+			return db
+		}
+		return db.Where(column+" IN (SELECT id FROM rooms WHERE unit_id = ?)", unitID)
+	}
+}
+
+func byScheduleUnit(column string, unitID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID == 0 {
+			return db
+		}
+		return db.Where(column+" IN (SELECT schedules.id FROM schedules JOIN classes ON classes.id = schedules.class_id WHERE classes.unit_id = ?)", unitID)
+	}
+}
+
+func bySubjectUnit(column string, unitID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID == 0 {
+			return db
+		}
+		return db.Where(column+" IN (SELECT id FROM subjects WHERE unit_id = ?)", unitID)
+	}
+}
+
+// byScoreUnit scopes score attempts through the student of their score.
+func byScoreUnit(column string, unitID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID == 0 {
+			return db
+		}
+		return db.Where(column+" IN (SELECT scores.id FROM scores JOIN students ON students.id = scores.student_id WHERE students.unit_id = ?)", unitID)
+	}
+}
+
+func byStudentUnit(column string, unitID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID == 0 {
+			return db
+		}
+		return db.Where(column+" IN (SELECT id FROM students WHERE unit_id = ?)", unitID)
+	}
+}
+
+func byBillUnit(column string, unitID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID == 0 {
+			return db
+		}
+		return db.Where(column+" IN (SELECT bills.id FROM bills JOIN students ON students.id = bills.student_id WHERE students.unit_id = ?)", unitID)
+	}
+}
+
+func byAcademicYearUnit(column string, unitID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID == 0 {
+			return db
+		}
+		return db.Where(column+" IN (SELECT id FROM academic_years WHERE unit_id = ?)", unitID)
+	}
+}
+
+// notGraduated excludes students who already left through graduation.
+func notGraduated(column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(column + " NOT IN (SELECT student_id FROM class_enrollments WHERE status = 'Graduated')")
+	}
+}
+
+// TermFilter narrows term-bound rows. The zero value means the active term.
+type TermFilter struct {
+	SemesterID uint // A given semester instead of the active one
+	All        bool // No term restriction at all
+}
+
+// Term scopes restrict a query to one semester, or to the academic year
+// holding it. Rows without a term only exist in units that have never
+// activated one, so the active-term default keeps them.
+
+func bySemester(column string, term TermFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch {
+		case term.All:
+			return db
+		case term.SemesterID != 0:
+			return db.Where(column+" = ?", term.SemesterID)
+		}
+		return db.Where("(" + column + " IS NULL OR " + column + " IN (SELECT id FROM semesters WHERE is_active))")
+	}
+}
+
+func byAcademicYear(column string, term TermFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch {
+		case term.All:
+			return db
+		case term.SemesterID != 0:
+			return db.Where(column+" IN (SELECT academic_year_id FROM semesters WHERE id = ?)", term.SemesterID)
+		}
+		return db.Where("(" + column + " IS NULL OR " + column + " IN (SELECT id FROM academic_years WHERE is_active))")
+	}
+}
+
+// checkAffected turns a scoped update/delete that matched nothing into
+// gorm.ErrRecordNotFound, so rows from another unit look like missing rows.
+func checkAffected(tx *gorm.DB) error {
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -14,25 +14,70 @@ func NewStudentRepository(db *gorm.DB) *StudentRepository {
 	return &StudentRepository{db: db}
 }
 
-func (r *StudentRepository) Create(student *domain.Student) error {
-	return r.db.Create(student).Error
+// withUser preloads the student's account, archived ones of graduates included.
+func withUser(db *gorm.DB) *gorm.DB {
+	return db.Preload("User", func(db *gorm.DB) *gorm.DB { return db.Unscoped() })
 }
 
-func (r *StudentRepository) GetAll(unitID uint) ([]domain.Student, error) {
+// CreateWithUser creates the student's account, the student and their first
+// enrollment in one transaction.
+func (r *StudentRepository) CreateWithUser(user *domain.User, student *domain.Student, enrollment *domain.ClassEnrollment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(student).Error; err != nil {
+			return err
+		}
+		return tx.Create(enrollment).Error
+	})
+}
+
+// UpdateWithUser saves the student and their account in one transaction. A
+// non-nil moveTo records a class change in the enrollment history.
+func (r *StudentRepository) UpdateWithUser(user *domain.User, student *domain.Student, moveTo *domain.Class) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		if moveTo != nil {
+			// The stored row still holds the class being left
+			var stored domain.Student
+			if err := tx.Preload("Class").First(&stored, "id = ?", student.ID).Error; err != nil {
+				return err
+			}
+			if err := moveEnrollment(tx, &stored, moveTo); err != nil {
+				return err
+			}
+		}
+		return tx.Save(student).Error
+	})
+}
+
+// GetAll narrows by the academic year of each student's class.
+func (r *StudentRepository) GetAll(unitID uint, term TermFilter) ([]domain.Student, error) {
 	var students []domain.Student
-	err := r.db.Where("unit_id = ?", unitID).Preload("User").Preload("Class").Find(&students).Error
+	classIDs := r.db.Model(&domain.Class{}).Select("id").Scopes(byAcademicYear("academic_year_id", term))
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Where("class_id IN (?)", classIDs).Scopes(withUser).Preload("Class").Find(&students).Error
 	return students, err
 }
 
-func (r *StudentRepository) GetByID(id string) (*domain.Student, error) {
+func (r *StudentRepository) GetByID(id string, unitID uint) (*domain.Student, error) {
 	var student domain.Student
-	err := r.db.Where("id = ?", id).Preload("User").Preload("Class").First(&student).Error
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Where("id = ?", id).Scopes(withUser).Preload("Class").First(&student).Error
 	return &student, err
 }
 
+// GetByClass lists the students currently in class, leaving out graduates.
+func (r *StudentRepository) GetByClass(classID, unitID uint) ([]domain.Student, error) {
+	var students []domain.Student
+	err := r.db.Scopes(byUnit("unit_id", unitID), notGraduated("id")).Where("class_id = ?", classID).Scopes(withUser).Preload("Class").Find(&students).Error
+	return students, err
+}
+
 func (r *StudentRepository) GetByParent(parentID string) ([]domain.Student, error) {
 	var students []domain.Student
-	err := r.db.Where("parent_id = ?", parentID).Preload("User").Preload("Class").Find(&students).Error
+	err := r.db.Where("parent_id = ?", parentID).Scopes(withUser).Preload("Class").Find(&students).Error
 	return students, err
 }
 
@@ -40,6 +85,6 @@ func (r *StudentRepository) Update(student *domain.Student) error {
 	return r.db.Save(student).Error
 }
 
-func (r *StudentRepository) Delete(id string) error {
-	return r.db.Delete(&domain.Student{}, "id = ?", id).Error
+func (r *StudentRepository) Delete(id string, unitID uint) error {
+	return checkAffected(r.db.Scopes(byUnit("unit_id", unitID)).Delete(&domain.Student{}, "id = ?", id))
 }
@@ -20,6 +20,12 @@ func (r *TeacherRepository) Create(teacher *domain.Teacher) error {
 
 func (r *TeacherRepository) GetAll(unitID uint) ([]domain.Teacher, error) {
 	var teachers []domain.Teacher
-	err := r.db.Where("unit_id = ?", unitID).Preload("User").Find(&teachers).Error
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Preload("User").Find(&teachers).Error
 	return teachers, err
 }
+
+func (r *TeacherRepository) GetByID(id string, unitID uint) (*domain.Teacher, error) {
+	var teacher domain.Teacher
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Where("id = ?", id).Preload("User").First(&teacher).Error
+	return &teacher, err
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"ppi-100-sis/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+func (r *TokenRepository) CreateRefreshToken(token *domain.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *TokenRepository) FindRefreshTokenByHash(hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	return &token, err
+}
+
+// RotateRefreshToken revokes old and stores next in one transaction. It fails
+// with gorm.ErrRecordNotFound if old was already revoked by a concurrent call.
+func (r *TokenRepository) RotateRefreshToken(old *domain.RefreshToken, next *domain.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		return checkAffected(tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", old.ID).
+			Updates(map[string]interface{}{"revoked_at": time.Now(), "replaced_by": next.ID}))
+	})
+}
+
+func (r *TokenRepository) RevokeRefreshToken(id uuid.UUID) error {
+	return r.db.Model(&domain.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *TokenRepository) RevokeAllForUser(userID uuid.UUID) error {
+	return r.db.Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// BumpTokenVersion invalidates every access token already issued to the user.
+func (r *TokenRepository) BumpTokenVersion(userID uuid.UUID) error {
+	return r.db.Model(&domain.User{}).Where("id = ?", userID).
+		UpdateColumn("token_version", gorm.Expr("token_version + 1")).Error
+}
+
+// Password reset tokens
+
+// CreatePasswordResetToken stores token and voids any earlier unused reset
+// tokens of the same user, so only the latest emailed link works.
+func (r *TokenRepository) CreatePasswordResetToken(token *domain.PasswordResetToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+func (r *TokenRepository) FindPasswordResetTokenByHash(hash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	return &token, err
+}
+
+// ConsumePasswordResetToken marks the token used. It fails with
+// gorm.ErrRecordNotFound if the token was already used.
+func (r *TokenRepository) ConsumePasswordResetToken(id uuid.UUID) error {
+	return checkAffected(r.db.Model(&domain.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now()))
+}
+
+// Recovery codes
+
+// ReplaceRecoveryCodes drops every recovery code of the user and stores codes.
+func (r *TokenRepository) ReplaceRecoveryCodes(userID uuid.UUID, codes []domain.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode marks a matching unused code as used. It fails with
+// gorm.ErrRecordNotFound if there is none.
+func (r *TokenRepository) UseRecoveryCode(userID uuid.UUID, hash string) error {
+	return checkAffected(r.db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now()))
+}
+
+func (r *TokenRepository) CountUnusedRecoveryCodes(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *TokenRepository) DeleteRecoveryCodes(userID uuid.UUID) error {
+	return r.db.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+}
+
+// Calendar feed tokens
+
+// ReplaceCalendarFeedToken stores token in place of the user's previous one.
+func (r *TokenRepository) ReplaceCalendarFeedToken(token *domain.CalendarFeedToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", token.UserID).Delete(&domain.CalendarFeedToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+func (r *TokenRepository) GetCalendarFeedToken(userID uuid.UUID) (*domain.CalendarFeedToken, error) {
+	var token domain.CalendarFeedToken
+	err := r.db.Where("user_id = ?", userID).First(&token).Error
+	return &token, err
+}
+
+func (r *TokenRepository) FindCalendarFeedTokenByHash(hash string) (*domain.CalendarFeedToken, error) {
+	var token domain.CalendarFeedToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	return &token, err
+}
+
+func (r *TokenRepository) TouchCalendarFeedToken(id uuid.UUID) error {
+	return r.db.Model(&domain.CalendarFeedToken{}).Where("id = ?", id).Update("last_used_at", time.Now()).Error
+}
+
+func (r *TokenRepository) DeleteCalendarFeedToken(userID uuid.UUID) error {
+	return r.db.Where("user_id = ?", userID).Delete(&domain.CalendarFeedToken{}).Error
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"ppi-100-sis/internal/domain"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -28,15 +29,41 @@ func (r *UserRepository) FindByEmail(email string) (*domain.User, error) {
 
 func (r *UserRepository) FindByID(id string) (*domain.User, error) {
 	var user domain.User
-	if err := r.db.Where("id = ?", id).Preload("Teacher").Preload("Parent").Preload("Student").First(&user).Error; err != nil {
+	if err := r.db.Where("id = ?", id).Preload("Teacher").Preload("Parent").Preload("Student.Class").First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) GetAll() ([]domain.User, error) {
+// FindInUnit is FindByID confined to a unit; 0 means any unit.
+func (r *UserRepository) FindInUnit(id string, unitID uint) (*domain.User, error) {
+	var user domain.User
+	if err := r.db.Scopes(byUnit("unit_id", unitID)).Where("id = ?", id).Preload("Teacher").Preload("Parent").Preload("Student").First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindTokenVersion returns only the token version, for per-request token checks.
+func (r *UserRepository) FindTokenVersion(id string) (int, error) {
+	var user domain.User
+	if err := r.db.Select("token_version").Where("id = ?", id).First(&user).Error; err != nil {
+		return 0, err
+	}
+	return user.TokenVersion, nil
+}
+
+// AdvanceTOTPStep records step as the last used TOTP step. It fails with
+// gorm.ErrRecordNotFound if step is not newer, i.e. the code was replayed.
+func (r *UserRepository) AdvanceTOTPStep(id uuid.UUID, step int64) error {
+	return checkAffected(r.db.Model(&domain.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		UpdateColumn("totp_last_step", step))
+}
+
+func (r *UserRepository) GetAll(unitID uint) ([]domain.User, error) {
 	var users []domain.User
-	err := r.db.Preload("Teacher").Preload("Parent").Preload("Student").Preload("Student.Class").Find(&users).Error
+	err := r.db.Scopes(byUnit("unit_id", unitID)).Preload("Teacher").Preload("Parent").Preload("Student").Preload("Student.Class").Find(&users).Error
 	return users, err
 }
 
@@ -2,95 +2,248 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
+	"ppi-100-sis/internal/config"
 	"ppi-100-sis/internal/domain"
 	"ppi-100-sis/internal/repository/postgres"
-
-	"github.com/google/uuid"
+	"time"
 )
 
+var ErrActiveTermDelete = errors.New("the active term cannot be deleted")
+
 type AcademicUsecase struct {
 	academicRepo *postgres.AcademicRepository
 	elearningRepo *postgres.ElearningRepository
 	userRepo      *postgres.UserRepository
+	teacherRepo   *postgres.TeacherRepository
+	enrollmentRepo *postgres.EnrollmentRepository
+	notificationUsecase *NotificationUsecase
+	cfg           *config.Config
 }
 
-func NewAcademicUsecase(academicRepo *postgres.AcademicRepository, elearningRepo *postgres.ElearningRepository, userRepo *postgres.UserRepository) *AcademicUsecase {
+func NewAcademicUsecase(academicRepo *postgres.AcademicRepository, elearningRepo *postgres.ElearningRepository, userRepo *postgres.UserRepository, teacherRepo *postgres.TeacherRepository, enrollmentRepo *postgres.EnrollmentRepository, notificationUsecase *NotificationUsecase, cfg *config.Config) *AcademicUsecase {
 	return &AcademicUsecase{
 		academicRepo: academicRepo,
 		elearningRepo: elearningRepo,
 		userRepo:      userRepo,
+		teacherRepo:   teacherRepo,
+		enrollmentRepo: enrollmentRepo,
+		notificationUsecase: notificationUsecase,
+		cfg:           cfg,
 	}
 }
 
-// Class
-func (u *AcademicUsecase) CreateClass(name string, unitID uint) error {
-	class := &domain.Class{
-		Name:   name,
-		UnitID: unitID,
+// Academic year and semester
+func (u *AcademicUsecase) CreateAcademicYear(name string, startDate, endDate time.Time, unitID uint) (*domain.AcademicYear, error) {
+	if !endDate.After(startDate) {
+		return nil, errors.New("end_date must be after start_date")
 	}
-	return u.academicRepo.CreateClass(class)
+	year := &domain.AcademicYear{
+		Name:      name,
+		UnitID:    unitID,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+	return year, u.academicRepo.CreateAcademicYear(year)
 }
 
-func (u *AcademicUsecase) GetAllClasses(unitID uint) ([]domain.Class, error) {
-	return u.academicRepo.GetAllClasses(unitID)
+func (u *AcademicUsecase) GetAcademicYears(unitID uint) ([]domain.AcademicYear, error) {
+	return u.academicRepo.GetAcademicYears(unitID)
 }
 
-func (u *AcademicUsecase) UpdateClass(id uint, name string) error {
-	class, err := u.academicRepo.GetClassByID(id)
+func (u *AcademicUsecase) UpdateAcademicYear(id uint, name string, startDate, endDate time.Time, unitID uint) error {
+	if !endDate.After(startDate) {
+		return errors.New("end_date must be after start_date")
+	}
+	year, err := u.academicRepo.GetAcademicYearByID(id, unitID)
 	if err != nil {
 		return err
 	}
-	class.Name = name
-	return u.academicRepo.UpdateClass(class)
+	year.Name = name
+	year.StartDate = startDate
+	year.EndDate = endDate
+	return u.academicRepo.UpdateAcademicYear(year)
+}
+
+func (u *AcademicUsecase) DeleteAcademicYear(id, unitID uint) error {
+	year, err := u.academicRepo.GetAcademicYearByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	if year.IsActive {
+		return ErrActiveTermDelete
+	}
+	return u.academicRepo.DeleteAcademicYear(id, unitID)
+}
+
+// CreateSemester adds a semester to a year; its dates must fall inside the year.
+func (u *AcademicUsecase) CreateSemester(academicYearID uint, name string, startDate, endDate time.Time, unitID uint) (*domain.Semester, error) {
+	year, err := u.academicRepo.GetAcademicYearByID(academicYearID, unitID)
+	if err != nil {
+		return nil, errors.New("academic year not found in unit")
+	}
+	if err := checkSemesterDates(year, startDate, endDate); err != nil {
+		return nil, err
+	}
+	semester := &domain.Semester{
+		AcademicYearID: year.ID,
+		Name:           name,
+		StartDate:      startDate,
+		EndDate:        endDate,
+	}
+	return semester, u.academicRepo.CreateSemester(semester)
+}
+
+func (u *AcademicUsecase) UpdateSemester(id uint, name string, startDate, endDate time.Time, unitID uint) error {
+	semester, err := u.academicRepo.GetSemesterByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	if err := checkSemesterDates(semester.AcademicYear, startDate, endDate); err != nil {
+		return err
+	}
+	semester.Name = name
+	semester.StartDate = startDate
+	semester.EndDate = endDate
+	return u.academicRepo.UpdateSemester(semester)
+}
+
+func (u *AcademicUsecase) DeleteSemester(id, unitID uint) error {
+	semester, err := u.academicRepo.GetSemesterByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	if semester.IsActive {
+		return ErrActiveTermDelete
+	}
+	return u.academicRepo.DeleteSemester(id, unitID)
 }
 
-func (u *AcademicUsecase) DeleteClass(id uint) error {
-	return u.academicRepo.DeleteClass(id)
+// ActivateSemester switches the unit owning the semester over to it.
+func (u *AcademicUsecase) ActivateSemester(id, unitID uint) error {
+	semester, err := u.academicRepo.GetSemesterByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	return u.academicRepo.ActivateSemester(semester, semester.AcademicYear.UnitID)
 }
 
-func (u *AcademicUsecase) GetHomeroomClass(teacherID string) (*domain.Class, error) {
-	return u.academicRepo.GetClassByHomeroomTeacher(teacherID)
+func (u *AcademicUsecase) GetActiveSemester(unitID uint) (*domain.Semester, error) {
+	return u.academicRepo.GetActiveSemester(unitID)
 }
 
-type SubjectGrade struct {
-	SubjectName string  `json:"subject_name"`
-	Average     float64 `json:"average"`
+func checkSemesterDates(year *domain.AcademicYear, startDate, endDate time.Time) error {
+	if !endDate.After(startDate) {
+		return errors.New("end_date must be after start_date")
+	}
+	if startDate.Before(year.StartDate) || endDate.After(year.EndDate) {
+		return errors.New("semester must fall within its academic year")
+	}
+	return nil
 }
 
-func (u *AcademicUsecase) GetStudentReportCard(studentID uuid.UUID) ([]SubjectGrade, error) {
-	submissions, err := u.elearningRepo.GetSubmissionsByStudent(studentID)
+// activeSemester returns the active semester of unitID, or nil when the unit
+// has none yet. New term-bound rows are filed under it.
+func activeSemester(academicRepo *postgres.AcademicRepository, unitID uint) *domain.Semester {
+	semester, err := academicRepo.GetActiveSemester(unitID)
 	if err != nil {
+		return nil
+	}
+	return semester
+}
+
+func activeSemesterID(academicRepo *postgres.AcademicRepository, unitID uint) *uint {
+	if semester := activeSemester(academicRepo, unitID); semester != nil {
+		return &semester.ID
+	}
+	return nil
+}
+
+// Period
+func (u *AcademicUsecase) CreatePeriod(period domain.Period, unitID uint) (*domain.Period, error) {
+	period.ID = 0
+	period.UnitID = unitID
+	if err := u.checkPeriod(&period); err != nil {
 		return nil, err
 	}
+	return &period, u.academicRepo.CreatePeriod(&period)
+}
+
+func (u *AcademicUsecase) GetPeriods(unitID uint) ([]domain.Period, error) {
+	return u.academicRepo.GetPeriods(unitID)
+}
+
+func (u *AcademicUsecase) UpdatePeriod(id uint, req domain.Period, unitID uint) error {
+	period, err := u.academicRepo.GetPeriodByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	period.Day = req.Day
+	period.Number = req.Number
+	period.Label = req.Label
+	period.StartTime = req.StartTime
+	period.EndTime = req.EndTime
+	period.IsBreak = req.IsBreak
+	if err := u.checkPeriod(period); err != nil {
+		return err
+	}
+	return u.academicRepo.UpdatePeriod(period)
+}
 
-	// Group by Subject
-	subjectScores := make(map[string][]float64)
-	for _, sub := range submissions {
-		subjectName := sub.Task.Subject.Name
-		subjectScores[subjectName] = append(subjectScores[subjectName], sub.Grade)
+func (u *AcademicUsecase) DeletePeriod(id, unitID uint) error {
+	return u.academicRepo.DeletePeriod(id, unitID)
+}
+
+// checkPeriod rejects periods overlapping another one of the same unit and day.
+func (u *AcademicUsecase) checkPeriod(period *domain.Period) error {
+	if err := validateScheduleTimes(period.Day, period.StartTime, period.EndTime); err != nil {
+		return err
 	}
+	overlaps, err := u.academicRepo.GetOverlappingPeriods(period)
+	if err != nil {
+		return err
+	}
+	if len(overlaps) > 0 {
+		other := overlaps[0]
+		return fmt.Errorf("%w: overlaps period %s %s-%s", ErrInvalidSchedule, other.Day, other.StartTime, other.EndTime)
+	}
+	return nil
+}
 
-	// Calculate Average
-	var reportCard []SubjectGrade
-	for subject, scores := range subjectScores {
-		total := 0.0
-		for _, score := range scores {
-			total += score
-		}
-		average := 0.0
-		if len(scores) > 0 {
-			average = total / float64(len(scores))
-		}
-		reportCard = append(reportCard, SubjectGrade{
-			SubjectName: subject,
-			Average:     average,
-		})
+// Class
+func (u *AcademicUsecase) CreateClass(name string, unitID uint) error {
+	class := &domain.Class{
+		Name:   name,
+		UnitID: unitID,
+	}
+	if semester := activeSemester(u.academicRepo, unitID); semester != nil {
+		class.AcademicYearID = &semester.AcademicYearID
+	}
+	return u.academicRepo.CreateClass(class)
+}
+
+func (u *AcademicUsecase) GetAllClasses(unitID uint, term postgres.TermFilter) ([]domain.Class, error) {
+	return u.academicRepo.GetAllClasses(unitID, term)
+}
+
+func (u *AcademicUsecase) UpdateClass(id uint, name string, unitID uint) error {
+	class, err := u.academicRepo.GetClassByID(id, unitID)
+	if err != nil {
+		return err
 	}
+	class.Name = name
+	return u.academicRepo.UpdateClass(class)
+}
 
-	return reportCard, nil
+func (u *AcademicUsecase) DeleteClass(id, unitID uint) error {
+	return u.academicRepo.DeleteClass(id, unitID)
 }
 
-func (u *AcademicUsecase) GetStudentReportCardByUserID(userID string) ([]SubjectGrade, error) {
+func (u *AcademicUsecase) GetHomeroomClass(teacherID string, unitID uint, term postgres.TermFilter) (*domain.Class, error) {
+	return u.academicRepo.GetClassByHomeroomTeacher(teacherID, unitID, term)
+}
+
+func (u *AcademicUsecase) GetStudentReportCardByUserID(userID string, term postgres.TermFilter) ([]SubjectGrade, error) {
 	user, err := u.userRepo.FindByID(userID)
 	if err != nil {
 		return nil, err
@@ -98,7 +251,7 @@ func (u *AcademicUsecase) GetStudentReportCardByUserID(userID string) ([]Subject
 	if user.Student == nil {
 		return nil, errors.New("user is not a student") // Or handle as error
 	}
-	return u.GetStudentReportCard(user.Student.ID)
+	return u.GetStudentReportCard(user.Student.ID, 0, user.Student.UnitID, term)
 }
 
 // Subject
@@ -136,32 +289,142 @@ func (u *AcademicUsecase) GetAllSubjects(unitID uint) ([]domain.Subject, error)
 	return u.academicRepo.GetAllSubjects(unitID)
 }
 
-func (u *AcademicUsecase) UpdateSubject(id uint, name string) error {
-	subject := &domain.Subject{
-		ID:   id,
-		Name: name,
+func (u *AcademicUsecase) UpdateSubject(id uint, name string, unitID uint) error {
+	subject, err := u.academicRepo.GetSubjectByID(id, unitID)
+	if err != nil {
+		return err
 	}
+	subject.Name = name
 	return u.academicRepo.UpdateSubject(subject)
 }
 
-func (u *AcademicUsecase) DeleteSubject(id uint) error {
-	return u.academicRepo.DeleteSubject(id)
+func (u *AcademicUsecase) DeleteSubject(id, unitID uint) error {
+	return u.academicRepo.DeleteSubject(id, unitID)
 }
 
 // Schedule
-func (u *AcademicUsecase) CreateSchedule(req domain.Schedule) error {
+func (u *AcademicUsecase) CreateSchedule(req domain.Schedule, unitID uint) error {
+	class, err := u.academicRepo.GetClassByID(req.ClassID, unitID)
+	if err != nil {
+		return err
+	}
+	if req.SemesterID == nil {
+		req.SemesterID = activeSemesterID(u.academicRepo, class.UnitID)
+	} else if err := u.checkScheduleSemester(*req.SemesterID, class.UnitID); err != nil {
+		return err
+	}
+	if err := u.checkScheduleRoom(req.RoomID, class.UnitID); err != nil {
+		return err
+	}
+	if err := validateScheduleTimes(req.Day, req.StartTime, req.EndTime); err != nil {
+		return err
+	}
+	if err := checkScheduleConflicts(u.academicRepo, &req); err != nil {
+		return err
+	}
 	return u.academicRepo.CreateSchedule(&req)
 }
 
-func (u *AcademicUsecase) GetAllSchedules(unitID, classID uint, teacherID string) ([]domain.Schedule, error) {
-	return u.academicRepo.GetAllSchedules(unitID, classID, teacherID)
+func (u *AcademicUsecase) GetAllSchedules(unitID uint, filter postgres.ScheduleFilter, term postgres.TermFilter) ([]domain.Schedule, error) {
+	return u.academicRepo.GetAllSchedules(unitID, filter, term)
+}
+
+// CurrentLesson is what a class or teacher has at a moment: the running
+// period of the unit's bell schedule and the lesson, either of which may be nil.
+// Substitute is set when a guru pengganti teaches the lesson today.
+type CurrentLesson struct {
+	Day        domain.Weekday   `json:"day"`
+	Time       domain.TimeOfDay `json:"time"`
+	Period     *domain.Period   `json:"period"`
+	Schedule   *domain.Schedule `json:"schedule"`
+	Substitute *domain.Teacher  `json:"substitute,omitempty"`
+}
+
+// GetCurrentSchedule returns the lesson running now, in the school's time
+// zone, for a class or teacher, following today's substitutions.
+func (u *AcademicUsecase) GetCurrentSchedule(unitID, classID uint, teacherID string) (*CurrentLesson, error) {
+	now := time.Now().In(u.cfg.SchoolLocation)
+	current := &CurrentLesson{Day: domain.WeekdayOf(now), Time: domain.TimeOfDayOf(now)}
+	running := func(s *domain.Schedule) bool {
+		return s.Day == current.Day && s.StartTime <= current.Time && current.Time < s.EndTime
+	}
+
+	schedules, err := u.academicRepo.GetAllSchedules(unitID, postgres.ScheduleFilter{ClassID: classID, TeacherID: teacherID}, postgres.TermFilter{})
+	if err != nil {
+		return nil, err
+	}
+	substitutions, err := u.academicRepo.GetSubstitutions(unitID, "", schoolToday(u.cfg), schoolToday(u.cfg))
+	if err != nil {
+		return nil, err
+	}
+	substituted := make(map[uint]*domain.Substitution, len(substitutions))
+	for i := range substitutions {
+		substituted[substitutions[i].ScheduleID] = &substitutions[i]
+	}
+
+	periodUnit := unitID
+	for i := range schedules {
+		s := &schedules[i]
+		if !running(s) {
+			continue
+		}
+		sub := substituted[s.ID]
+		if sub != nil && teacherID != "" && sub.TeacherID.String() != teacherID {
+			continue // handed to a substitute today
+		}
+		current.Schedule = s
+		if sub != nil {
+			current.Substitute = sub.Teacher
+		}
+		break
+	}
+	if current.Schedule == nil && teacherID != "" {
+		for _, sub := range substituted {
+			if sub.TeacherID.String() == teacherID && running(sub.Schedule) {
+				current.Schedule = sub.Schedule
+				current.Substitute = sub.Teacher
+				break
+			}
+		}
+	}
+	if current.Schedule != nil {
+		periodUnit = current.Schedule.Class.UnitID
+	}
+	if periodUnit == 0 && classID != 0 {
+		if class, err := u.academicRepo.GetClassByID(classID, 0); err == nil {
+			periodUnit = class.UnitID
+		}
+	}
+	if periodUnit == 0 {
+		return current, nil
+	}
+
+	periods, err := u.academicRepo.GetPeriods(periodUnit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range periods {
+		p := &periods[i]
+		if p.Day == current.Day && p.StartTime <= current.Time && current.Time < p.EndTime {
+			current.Period = p
+			break
+		}
+	}
+	return current, nil
 }
 
-func (u *AcademicUsecase) UpdateSchedule(id uint, req domain.Schedule) error {
-	schedule, err := u.academicRepo.GetScheduleByID(id)
+func (u *AcademicUsecase) UpdateSchedule(id uint, req domain.Schedule, unitID uint) error {
+	schedule, err := u.academicRepo.GetScheduleByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	class, err := u.academicRepo.GetClassByID(req.ClassID, unitID)
 	if err != nil {
 		return err
 	}
+	if err := u.checkScheduleRoom(req.RoomID, class.UnitID); err != nil {
+		return err
+	}
 	
 	schedule.ClassID = req.ClassID
 	schedule.SubjectID = req.SubjectID
@@ -169,10 +432,41 @@ func (u *AcademicUsecase) UpdateSchedule(id uint, req domain.Schedule) error {
 	schedule.Day = req.Day
 	schedule.StartTime = req.StartTime
 	schedule.EndTime = req.EndTime
-	
+	schedule.RoomID = req.RoomID
+	if req.SemesterID != nil {
+		if err := u.checkScheduleSemester(*req.SemesterID, class.UnitID); err != nil {
+			return err
+		}
+		schedule.SemesterID = req.SemesterID
+	}
+	if err := validateScheduleTimes(schedule.Day, schedule.StartTime, schedule.EndTime); err != nil {
+		return err
+	}
+	if err := checkScheduleConflicts(u.academicRepo, schedule); err != nil {
+		return err
+	}
+
 	return u.academicRepo.UpdateSchedule(schedule)
 }
 
-func (u *AcademicUsecase) DeleteSchedule(id uint) error {
-	return u.academicRepo.DeleteSchedule(id)
+// checkScheduleRoom makes sure a lesson's room, if any, belongs to the class's unit.
+func (u *AcademicUsecase) checkScheduleRoom(roomID *uint, unitID uint) error {
+	if roomID == nil {
+		return nil
+	}
+	if _, err := u.academicRepo.GetRoomByID(*roomID, unitID); err != nil {
+		return fmt.Errorf("%w: room not found in the class's unit", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+func (u *AcademicUsecase) checkScheduleSemester(semesterID, unitID uint) error {
+	if _, err := u.academicRepo.GetSemesterByID(semesterID, unitID); err != nil {
+		return fmt.Errorf("%w: semester not found in the class's unit", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+func (u *AcademicUsecase) DeleteSchedule(id, unitID uint) error {
+	return u.academicRepo.DeleteSchedule(id, unitID)
 }
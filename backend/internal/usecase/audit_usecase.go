@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"reflect"
+	"strings"
+)
+
+type AuditUsecase struct {
+	auditRepo *postgres.AuditRepository
+}
+
+func NewAuditUsecase(auditRepo *postgres.AuditRepository) *AuditUsecase {
+	return &AuditUsecase{auditRepo: auditRepo}
+}
+
+// Snapshot returns the current row of model (a pointer to a domain struct)
+// by id, for use as the before or after state of an audit entry.
+func (u *AuditUsecase) Snapshot(model interface{}, id string) (map[string]interface{}, error) {
+	return u.auditRepo.Snapshot(model, id)
+}
+
+// Record stores log with before and after states. When both are given only
+// the fields that changed are kept. Secrets are never written.
+func (u *AuditUsecase) Record(log *domain.AuditLog, before, after map[string]interface{}) error {
+	if before != nil && after != nil {
+		before, after = changedFields(before, after)
+	}
+
+	var err error
+	if log.Before, err = marshalAuditState(before); err != nil {
+		return err
+	}
+	if log.After, err = marshalAuditState(after); err != nil {
+		return err
+	}
+	return u.auditRepo.Create(log)
+}
+
+func (u *AuditUsecase) GetLogs(filter postgres.AuditLogFilter) ([]domain.AuditLog, int64, error) {
+	return u.auditRepo.GetLogs(filter)
+}
+
+func changedFields(before, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	oldValues := map[string]interface{}{}
+	newValues := map[string]interface{}{}
+	for key, value := range after {
+		if key == "updated_at" {
+			continue
+		}
+		if !reflect.DeepEqual(before[key], value) {
+			oldValues[key] = before[key]
+			newValues[key] = value
+		}
+	}
+	for key, value := range before {
+		if _, ok := after[key]; !ok {
+			oldValues[key] = value
+		}
+	}
+	return oldValues, newValues
+}
+
+func marshalAuditState(state map[string]interface{}) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+	redacted := make(map[string]interface{}, len(state))
+	for key, value := range state {
+		if isSecretField(key) {
+			value = "[REDACTED]"
+		} else if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		redacted[key] = value
+	}
+	data, err := json.Marshal(redacted)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit state: %w", err)
+	}
+	return data, nil
+}
+
+func isSecretField(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || strings.Contains(key, "secret") || strings.Contains(key, "hash") || strings.Contains(key, "token")
+}
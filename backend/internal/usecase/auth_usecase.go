@@ -2,29 +2,84 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
 	"ppi-100-sis/internal/config"
 	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/ratelimit"
 	"ppi-100-sis/internal/repository/postgres"
+	"ppi-100-sis/pkg/mailer"
 	"ppi-100-sis/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidOldPassword  = errors.New("invalid old password")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrTokenRevoked        = errors.New("token has been revoked")
+	ErrInvalidResetToken   = errors.New("invalid or expired reset token")
+	ErrRegistrationClosed  = errors.New("self-registration is disabled, ask an administrator for an invitation")
+	ErrInvalidMFAChallenge = errors.New("invalid or expired MFA challenge")
+	ErrInvalidMFACode      = errors.New("invalid two-factor code")
 )
 
 type AuthUsecase struct {
-	userRepo *postgres.UserRepository
-	cfg      *config.Config
+	userRepo  *postgres.UserRepository
+	tokenRepo *postgres.TokenRepository
+	mailer    mailer.Mailer
+	limiter   *ratelimit.LoginLimiter
+	cfg       *config.Config
 }
 
-func NewAuthUsecase(userRepo *postgres.UserRepository, cfg *config.Config) *AuthUsecase {
+func NewAuthUsecase(userRepo *postgres.UserRepository, tokenRepo *postgres.TokenRepository, mailSender mailer.Mailer, limiter *ratelimit.LoginLimiter, cfg *config.Config) *AuthUsecase {
 	return &AuthUsecase{
-		userRepo: userRepo,
-		cfg:      cfg,
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		mailer:    mailSender,
+		limiter:   limiter,
+		cfg:       cfg,
 	}
 }
 
+// TokenPair is returned on login and refresh. The access token keeps the
+// "token" key the frontend already reads.
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // Access token lifetime in seconds
+}
+
+// LoginResult holds either the tokens or, when 2FA is enabled, the challenge
+// token to send to /auth/login/mfa together with a code.
+type LoginResult struct {
+	*TokenPair
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+}
+
+// Register is public self-registration. It is off unless enabled in config
+// and never creates staff accounts; those come from invitations.
 func (u *AuthUsecase) Register(name, email, password string, roleID, unitID uint) error {
+	if !u.cfg.AllowSelfRegistration {
+		return ErrRegistrationClosed
+	}
+	if roleID != domain.RoleSiswa && roleID != domain.RoleOrangTua {
+		return errors.New("this role cannot be self-registered")
+	}
+	if unitID != domain.UnitMTS && unitID != domain.UnitMA {
+		return errors.New("invalid unit")
+	}
+
 	existingUser, _ := u.userRepo.FindByEmail(email)
 	if existingUser != nil {
 		return errors.New("email already registered")
 	}
+	if err := utils.ValidatePassword(password, u.cfg); err != nil {
+		return err
+	}
 
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
@@ -42,22 +97,216 @@ func (u *AuthUsecase) Register(name, email, password string, roleID, unitID uint
 	return u.userRepo.Create(user)
 }
 
-func (u *AuthUsecase) Login(email, password string) (string, error) {
+// Login is throttled per account and per client IP; a throttled attempt
+// returns a *ratelimit.ThrottledError without checking the password.
+func (u *AuthUsecase) Login(email, password, ip string) (*LoginResult, error) {
+	if err := u.limiter.Allow(email, ip); err != nil {
+		return nil, err
+	}
+
 	user, err := u.userRepo.FindByEmail(email)
+	if err != nil || !utils.CheckPasswordHash(password, user.PasswordHash) {
+		if err := u.limiter.Fail(email, ip); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("invalid email or password")
+	}
+
+	// Counters are only cleared once the second factor passes too
+	if user.TOTPEnabled {
+		challenge, err := utils.GenerateMFAChallengeToken(user.ID, user.TokenVersion, u.cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{MFARequired: true, MFAToken: challenge}, nil
+	}
+
+	if err := u.limiter.Succeed(email); err != nil {
+		return nil, err
+	}
+	tokens, err := u.issueTokens(user)
 	if err != nil {
-		return "", errors.New("invalid email or password")
+		return nil, err
 	}
+	return &LoginResult{TokenPair: tokens}, nil
+}
 
-	if !utils.CheckPasswordHash(password, user.PasswordHash) {
-		return "", errors.New("invalid email or password")
+// UnlockUser clears the failed login counters and lockout of a user.
+// unitID confines unit admins to their own users; 0 means any unit. Only
+// Super Admin may unlock administrators.
+func (u *AuthUsecase) UnlockUser(userID string, unitID, actorRoleID uint) error {
+	user, err := u.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if unitID != 0 && user.UnitID != unitID {
+		return gorm.ErrRecordNotFound
+	}
+	if err := checkAdminRole(user.RoleID, actorRoleID); err != nil {
+		return err
+	}
+	return u.limiter.Unlock(user.Email)
+}
+
+// Refresh rotates a refresh token: the presented token is revoked and a new
+// pair is issued. Presenting an already rotated token is treated as theft and
+// revokes every refresh token of that user.
+func (u *AuthUsecase) Refresh(refreshToken string) (*TokenPair, error) {
+	stored, err := u.tokenRepo.FindRefreshTokenByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		if err := u.tokenRepo.RevokeAllForUser(stored.UserID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
 	}
 
-	token, err := utils.GenerateToken(user.ID, user.RoleID, user.UnitID, u.cfg)
+	user, err := u.userRepo.FindByID(stored.UserID.String())
 	if err != nil {
-		return "", err
+		return nil, ErrInvalidRefreshToken
 	}
 
-	return token, nil
+	next, raw, err := u.newRefreshToken(user)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.tokenRepo.RotateRefreshToken(stored, next); err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return u.tokenPair(user, raw)
+}
+
+// Logout revokes the given refresh token. With allDevices it also revokes
+// every other refresh token and all access tokens issued to the user.
+func (u *AuthUsecase) Logout(refreshToken string, allDevices bool) error {
+	stored, err := u.tokenRepo.FindRefreshTokenByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		return nil // Unknown token, nothing to revoke
+	}
+
+	if !allDevices {
+		return u.tokenRepo.RevokeRefreshToken(stored.ID)
+	}
+
+	if err := u.tokenRepo.RevokeAllForUser(stored.UserID); err != nil {
+		return err
+	}
+	return u.tokenRepo.BumpTokenVersion(stored.UserID)
+}
+
+// VerifyAccessToken validates an access token and rejects it when the user is
+// gone or their token version moved on (password change, logout everywhere).
+func (u *AuthUsecase) VerifyAccessToken(token string) (*utils.Claims, error) {
+	claims, err := utils.ValidateToken(token, u.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := u.userRepo.FindTokenVersion(claims.UserID.String())
+	if err != nil {
+		return nil, ErrTokenRevoked
+	}
+	if version != claims.TokenVersion {
+		return nil, ErrTokenRevoked
+	}
+
+	// Impersonation ends as soon as the impersonator is no longer Super Admin
+	if claims.ImpersonatorID != nil {
+		impersonator, err := u.userRepo.FindByID(claims.ImpersonatorID.String())
+		if err != nil || impersonator.RoleID != domain.RoleSuperAdmin {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	return claims, nil
+}
+
+// ImpersonationResult is returned when a Super Admin starts impersonating.
+// There is no refresh token; the session ends when the token expires.
+type ImpersonationResult struct {
+	AccessToken   string       `json:"token"`
+	ExpiresIn     int64        `json:"expires_in"`
+	Impersonating bool         `json:"impersonating"`
+	User          *domain.User `json:"user"`
+}
+
+// Impersonate issues a read-only access token for targetID that also names
+// the Super Admin behind it. Other Super Admins cannot be impersonated.
+func (u *AuthUsecase) Impersonate(adminID uuid.UUID, targetID string) (*ImpersonationResult, error) {
+	target, err := u.userRepo.FindByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.ID == adminID || target.RoleID == domain.RoleSuperAdmin {
+		return nil, errors.New("cannot impersonate a Super Admin")
+	}
+
+	token, err := utils.GenerateToken(&utils.Claims{
+		UserID:         target.ID,
+		RoleID:         target.RoleID,
+		UnitID:         target.UnitID,
+		TokenVersion:   target.TokenVersion,
+		ImpersonatorID: &adminID,
+	}, u.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImpersonationResult{
+		AccessToken:   token,
+		ExpiresIn:     int64(u.cfg.ImpersonationTTL.Seconds()),
+		Impersonating: true,
+		User:          target,
+	}, nil
+}
+
+func (u *AuthUsecase) issueTokens(user *domain.User) (*TokenPair, error) {
+	refresh, raw, err := u.newRefreshToken(user)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.tokenRepo.CreateRefreshToken(refresh); err != nil {
+		return nil, err
+	}
+	return u.tokenPair(user, raw)
+}
+
+func (u *AuthUsecase) newRefreshToken(user *domain.User) (*domain.RefreshToken, string, error) {
+	raw, err := utils.GenerateRandomToken()
+	if err != nil {
+		return nil, "", err
+	}
+	return &domain.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(raw),
+		ExpiresAt: time.Now().Add(u.cfg.RefreshTokenTTL),
+	}, raw, nil
+}
+
+func (u *AuthUsecase) tokenPair(user *domain.User, refreshToken string) (*TokenPair, error) {
+	accessToken, err := utils.GenerateToken(&utils.Claims{
+		UserID:                 user.ID,
+		RoleID:                 user.RoleID,
+		UnitID:                 user.UnitID,
+		TokenVersion:           user.TokenVersion,
+		MFAEnrollmentRequired:  u.mfaRequired(user.RoleID) && !user.TOTPEnabled,
+		PasswordChangeRequired: user.MustChangePassword,
+	}, u.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(u.cfg.AccessTokenTTL.Seconds()),
+	}, nil
 }
 
 func (u *AuthUsecase) GetProfile(userID string) (*domain.User, error) {
@@ -83,23 +332,112 @@ func (u *AuthUsecase) UpdateProfile(userID string, name, email string) error {
 	return u.userRepo.Update(user)
 }
 
-func (u *AuthUsecase) ChangePassword(userID, oldPassword, newPassword string) error {
+// ChangePassword revokes every token issued before the change and returns a
+// fresh pair so the current session can carry on.
+func (u *AuthUsecase) ChangePassword(userID, oldPassword, newPassword string) (*TokenPair, error) {
 	user, err := u.userRepo.FindByID(userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !utils.CheckPasswordHash(oldPassword, user.PasswordHash) {
-		return errors.New("invalid old password")
+		return nil, ErrInvalidOldPassword
+	}
+	if newPassword == oldPassword {
+		return nil, fmt.Errorf("%w: it must differ from the current password", utils.ErrWeakPassword)
+	}
+	if err := utils.ValidatePassword(newPassword, u.cfg); err != nil {
+		return nil, err
 	}
 
 	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	user.PasswordHash = hashedPassword
+	user.MustChangePassword = false
+	user.TokenVersion++
+	if err := u.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	if err := u.tokenRepo.RevokeAllForUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	return u.issueTokens(user)
+}
+
+// ForgotPassword emails a single-use reset link. Unknown emails are ignored
+// so the endpoint does not reveal which addresses are registered.
+func (u *AuthUsecase) ForgotPassword(email string) error {
+	user, err := u.userRepo.FindByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	raw, err := utils.GenerateRandomToken()
 	if err != nil {
 		return err
 	}
+	token := &domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(raw),
+		ExpiresAt: time.Now().Add(u.cfg.PasswordResetTTL),
+	}
+	if err := u.tokenRepo.CreatePasswordResetToken(token); err != nil {
+		return err
+	}
+
+	link := u.cfg.AppURL + "/reset-password?token=" + raw
+	body := "Halo " + user.Name + ",\n\n" +
+		"Kami menerima permintaan untuk mengatur ulang password akun Anda. " +
+		"Buka tautan berikut untuk membuat password baru:\n\n" + link + "\n\n" +
+		"Tautan ini berlaku selama " + u.cfg.PasswordResetTTL.String() + " dan hanya dapat digunakan sekali. " +
+		"Abaikan email ini jika Anda tidak memintanya."
+
+	return u.mailer.Send(user.Email, "Reset Password", body)
+}
+
+// ResetPassword sets a new password from a reset token and revokes every
+// session of the user.
+func (u *AuthUsecase) ResetPassword(token, newPassword string) error {
+	stored, err := u.tokenRepo.FindPasswordResetTokenByHash(utils.HashToken(token))
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := u.userRepo.FindByID(stored.UserID.String())
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	if err := utils.ValidatePassword(newPassword, u.cfg); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := u.tokenRepo.ConsumePasswordResetToken(stored.ID); err != nil {
+		return ErrInvalidResetToken
+	}
 
 	user.PasswordHash = hashedPassword
-	return u.userRepo.Update(user)
+	user.MustChangePassword = false
+	user.TokenVersion++
+	if err := u.userRepo.Update(user); err != nil {
+		return err
+	}
+	if err := u.tokenRepo.RevokeAllForUser(user.ID); err != nil {
+		return err
+	}
+	// Proving ownership of the mailbox also lifts a lockout
+	return u.limiter.Unlock(user.Email)
 }
 
 func (u *AuthUsecase) UpdateProfilePicture(userID, photoURL string) error {
@@ -111,3 +449,240 @@ func (u *AuthUsecase) UpdateProfilePicture(userID, photoURL string) error {
 	user.PhotoURL = photoURL
 	return u.userRepo.Update(user)
 }
+
+// Two-factor authentication
+
+// MFAStatus describes the 2FA state of a user for /profile/2fa.
+type MFAStatus struct {
+	Enabled           bool  `json:"enabled"`
+	Required          bool  `json:"required"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+// MFASetup is returned when enrollment starts. URI is meant for a QR code.
+type MFASetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+func (u *AuthUsecase) mfaRequired(roleID uint) bool {
+	for _, r := range u.cfg.MFARequiredRoles {
+		if r == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyMFA completes a login started by Login with the challenge token and
+// a TOTP or recovery code. Wrong codes count as failed logins.
+func (u *AuthUsecase) VerifyMFA(mfaToken, code, ip string) (*TokenPair, error) {
+	claims, err := utils.ValidateMFAChallengeToken(mfaToken, u.cfg)
+	if err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	user, err := u.userRepo.FindByID(claims.UserID.String())
+	if err != nil || user.TokenVersion != claims.TokenVersion || !user.TOTPEnabled {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	if err := u.limiter.Allow(user.Email, ip); err != nil {
+		return nil, err
+	}
+	if !u.checkSecondFactor(user, code, true) {
+		if err := u.limiter.Fail(user.Email, ip); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidMFACode
+	}
+
+	if err := u.limiter.Succeed(user.Email); err != nil {
+		return nil, err
+	}
+	return u.issueTokens(user)
+}
+
+// checkSecondFactor accepts a current TOTP code, or an unused recovery code
+// when allowRecovery is set. Either is consumed on success.
+func (u *AuthUsecase) checkSecondFactor(user *domain.User, code string, allowRecovery bool) bool {
+	code = strings.TrimSpace(code)
+	if step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		return u.userRepo.AdvanceTOTPStep(user.ID, step) == nil
+	}
+	if !allowRecovery {
+		return false
+	}
+	return u.tokenRepo.UseRecoveryCode(user.ID, utils.HashToken(utils.NormalizeRecoveryCode(code))) == nil
+}
+
+func (u *AuthUsecase) GetMFAStatus(userID string) (*MFAStatus, error) {
+	user, err := u.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	left, err := u.tokenRepo.CountUnusedRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MFAStatus{
+		Enabled:           user.TOTPEnabled,
+		Required:          u.mfaRequired(user.RoleID),
+		RecoveryCodesLeft: left,
+	}, nil
+}
+
+// SetupMFA generates a new secret. 2FA stays off until EnableMFA confirms a
+// code from it.
+func (u *AuthUsecase) SetupMFA(userID string) (*MFASetup, error) {
+	user, err := u.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = secret
+	if err := u.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return &MFASetup{
+		Secret: secret,
+		URI:    utils.TOTPURI(u.cfg.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// EnableMFA turns 2FA on once code matches the pending secret. It returns the
+// recovery codes, shown only this once, and fresh tokens without the
+// enrollment flag.
+func (u *AuthUsecase) EnableMFA(userID, code string) ([]string, *TokenPair, error) {
+	user, err := u.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, nil, errors.New("two-factor setup has not been started")
+	}
+
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, nil, ErrInvalidMFACode
+	}
+
+	user.TOTPEnabled = true
+	user.TOTPLastStep = step
+	if err := u.userRepo.Update(user); err != nil {
+		return nil, nil, err
+	}
+
+	codes, err := u.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := u.issueTokens(user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return codes, tokens, nil
+}
+
+// DisableMFA needs the password and a current code. Roles that enforce 2FA
+// cannot turn it off.
+func (u *AuthUsecase) DisableMFA(userID, password, code string) error {
+	user, err := u.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+	if u.mfaRequired(user.RoleID) {
+		return errors.New("two-factor authentication is required for your role")
+	}
+	if !utils.CheckPasswordHash(password, user.PasswordHash) {
+		return ErrInvalidOldPassword
+	}
+	if !u.checkSecondFactor(user, code, true) {
+		return ErrInvalidMFACode
+	}
+
+	return u.clearMFA(user)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes; needs a TOTP code.
+func (u *AuthUsecase) RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	user, err := u.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+	if !u.checkSecondFactor(user, code, false) {
+		return nil, ErrInvalidMFACode
+	}
+
+	return u.replaceRecoveryCodes(user.ID)
+}
+
+// ResetMFA turns off 2FA for a user who lost both device and recovery codes.
+// unitID confines unit admins to their own users; 0 means any unit. Only
+// Super Admin may reset administrators.
+func (u *AuthUsecase) ResetMFA(userID string, unitID, actorRoleID uint) error {
+	user, err := u.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if unitID != 0 && user.UnitID != unitID {
+		return gorm.ErrRecordNotFound
+	}
+	if err := checkAdminRole(user.RoleID, actorRoleID); err != nil {
+		return err
+	}
+	return u.clearMFA(user)
+}
+
+func (u *AuthUsecase) clearMFA(user *domain.User) error {
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	if err := u.userRepo.Update(user); err != nil {
+		return err
+	}
+	return u.tokenRepo.DeleteRecoveryCodes(user.ID)
+}
+
+func (u *AuthUsecase) replaceRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	const count = 10
+
+	codes := make([]string, 0, count)
+	stored := make([]domain.RecoveryCode, 0, count)
+	for i := 0; i < count; i++ {
+		code, err := utils.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		stored = append(stored, domain.RecoveryCode{
+			UserID:   userID,
+			CodeHash: utils.HashToken(utils.NormalizeRecoveryCode(code)),
+		})
+	}
+
+	if err := u.tokenRepo.ReplaceRecoveryCodes(userID, stored); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
@@ -9,11 +9,12 @@ import (
 )
 
 type BKUsecase struct {
-	bkRepo *postgres.BKRepository
+	bkRepo      *postgres.BKRepository
+	studentRepo *postgres.StudentRepository
 }
 
-func NewBKUsecase(bkRepo *postgres.BKRepository) *BKUsecase {
-	return &BKUsecase{bkRepo: bkRepo}
+func NewBKUsecase(bkRepo *postgres.BKRepository, studentRepo *postgres.StudentRepository) *BKUsecase {
+	return &BKUsecase{bkRepo: bkRepo, studentRepo: studentRepo}
 }
 
 func (u *BKUsecase) CreateViolation(name string, points int, description string) error {
@@ -29,7 +30,11 @@ func (u *BKUsecase) GetAllViolations() ([]domain.Violation, error) {
 	return u.bkRepo.GetAllViolations()
 }
 
-func (u *BKUsecase) CreateBKCall(studentID, teacherID uuid.UUID, reason string, date time.Time) error {
+func (u *BKUsecase) CreateBKCall(studentID, teacherID uuid.UUID, reason string, date time.Time, unitID uint) error {
+	if _, err := u.studentRepo.GetByID(studentID.String(), unitID); err != nil {
+		return err
+	}
+
 	call := &domain.BKCall{
 		StudentID: studentID,
 		TeacherID: teacherID,
@@ -44,8 +49,8 @@ func (u *BKUsecase) GetAllBKCalls(unitID uint) ([]domain.BKCall, error) {
 	return u.bkRepo.GetAllBKCalls(unitID)
 }
 
-func (u *BKUsecase) GetStudentBKCalls(studentID string) ([]domain.BKCall, error) {
-	return u.bkRepo.GetBKCallsByStudent(studentID)
+func (u *BKUsecase) GetStudentBKCalls(studentID string, unitID uint) ([]domain.BKCall, error) {
+	return u.bkRepo.GetBKCallsByStudent(studentID, unitID)
 }
 
 // Update/Delete Violation
@@ -58,11 +63,23 @@ func (u *BKUsecase) DeleteViolation(id uint) error {
 }
 
 // Update/Delete BKCall
-func (u *BKUsecase) UpdateBKCall(call *domain.BKCall) error {
-	return u.bkRepo.UpdateBKCall(call)
+func (u *BKUsecase) UpdateBKCall(call *domain.BKCall, unitID uint) error {
+	existing, err := u.bkRepo.GetBKCallByID(call.ID.String(), unitID)
+	if err != nil {
+		return err
+	}
+	if _, err := u.studentRepo.GetByID(call.StudentID.String(), unitID); err != nil {
+		return err
+	}
+
+	existing.StudentID = call.StudentID
+	existing.TeacherID = call.TeacherID
+	existing.Reason = call.Reason
+	existing.Date = call.Date
+	return u.bkRepo.UpdateBKCall(existing)
 }
 
-func (u *BKUsecase) DeleteBKCall(id string) error {
-	return u.bkRepo.DeleteBKCall(id)
+func (u *BKUsecase) DeleteBKCall(id string, unitID uint) error {
+	return u.bkRepo.DeleteBKCall(id, unitID)
 }
 
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"ppi-100-sis/pkg/ical"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCalendarEvent = errors.New("invalid calendar event")
+	ErrNonTeachingDay       = errors.New("no lessons on this day")
+)
+
+var calendarTypes = []string{domain.CalendarHoliday, domain.CalendarExamWeek, domain.CalendarActivity}
+
+// teachingCalendar tells school days from days off for one unit over a range
+// of dates: Sundays and days covered by a non-teaching event are off.
+type teachingCalendar struct {
+	off map[string]*domain.CalendarEvent
+}
+
+func loadTeachingCalendar(academicRepo *postgres.AcademicRepository, unitID uint, from, to time.Time) (*teachingCalendar, error) {
+	events, err := academicRepo.GetCalendarEvents(unitID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	cal := &teachingCalendar{off: map[string]*domain.CalendarEvent{}}
+	for i := range events {
+		if !events[i].NonTeaching {
+			continue
+		}
+		for d := events[i].StartDate; !d.After(events[i].EndDate); d = d.AddDate(0, 0, 1) {
+			cal.off[d.Format("2006-01-02")] = &events[i]
+		}
+	}
+	return cal, nil
+}
+
+// DayOff returns why date is not a school day, or "" when it is.
+func (c *teachingCalendar) DayOff(date time.Time) string {
+	if event := c.off[date.Format("2006-01-02")]; event != nil {
+		return event.Title
+	}
+	if date.Weekday() == time.Sunday {
+		return "Minggu"
+	}
+	return ""
+}
+
+// nextTeachingDay returns date, or the first school day after it.
+func nextTeachingDay(academicRepo *postgres.AcademicRepository, unitID uint, date time.Time) (time.Time, error) {
+	// Long breaks (Idul Fitri, end of year) stay well within two months
+	cal, err := loadTeachingCalendar(academicRepo, unitID, date, date.AddDate(0, 2, 0))
+	if err != nil {
+		return date, err
+	}
+	for d := date; d.Before(date.AddDate(0, 2, 0)); d = d.AddDate(0, 0, 1) {
+		if cal.DayOff(d) == "" {
+			return d, nil
+		}
+	}
+	return date, nil
+}
+
+// teachingDeadline moves a deadline falling on a day off to the same time on
+// the next school day.
+func teachingDeadline(academicRepo *postgres.AcademicRepository, unitID uint, deadline time.Time) (time.Time, error) {
+	if deadline.IsZero() {
+		return deadline, nil
+	}
+	y, m, d := deadline.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	next, err := nextTeachingDay(academicRepo, unitID, day)
+	if err != nil {
+		return deadline, err
+	}
+	return deadline.AddDate(0, 0, int(next.Sub(day).Hours()/24)), nil
+}
+
+func validateCalendarEvent(event *domain.CalendarEvent) error {
+	event.Title = strings.TrimSpace(event.Title)
+	if event.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidCalendarEvent)
+	}
+	valid := false
+	for _, t := range calendarTypes {
+		valid = valid || event.Type == t
+	}
+	if !valid {
+		return fmt.Errorf("%w: type must be one of %s", ErrInvalidCalendarEvent, strings.Join(calendarTypes, ", "))
+	}
+	if event.EndDate.IsZero() {
+		event.EndDate = event.StartDate
+	}
+	if event.EndDate.Before(event.StartDate) {
+		return fmt.Errorf("%w: end_date must not be before start_date", ErrInvalidCalendarEvent)
+	}
+	return nil
+}
+
+func (u *AcademicUsecase) CreateCalendarEvent(event domain.CalendarEvent, unitID uint) (*domain.CalendarEvent, error) {
+	event.ID = 0
+	event.UnitID = unitID
+	if err := validateCalendarEvent(&event); err != nil {
+		return nil, err
+	}
+	return &event, u.academicRepo.CreateCalendarEvent(&event)
+}
+
+func (u *AcademicUsecase) GetCalendarEvents(unitID uint, from, to time.Time) ([]domain.CalendarEvent, error) {
+	return u.academicRepo.GetCalendarEvents(unitID, from, to)
+}
+
+func (u *AcademicUsecase) UpdateCalendarEvent(id uint, req domain.CalendarEvent, unitID uint) error {
+	event, err := u.academicRepo.GetCalendarEventByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	event.Title = req.Title
+	event.Description = req.Description
+	event.Type = req.Type
+	event.StartDate = req.StartDate
+	event.EndDate = req.EndDate
+	event.NonTeaching = req.NonTeaching
+	if err := validateCalendarEvent(event); err != nil {
+		return err
+	}
+	return u.academicRepo.UpdateCalendarEvent(event)
+}
+
+func (u *AcademicUsecase) DeleteCalendarEvent(id, unitID uint) error {
+	return u.academicRepo.DeleteCalendarEvent(id, unitID)
+}
+
+// CalendarFeed renders a unit's calendar, a year back and onwards, as iCalendar.
+func (u *AcademicUsecase) CalendarFeed(unitID uint) ([]byte, error) {
+	events, err := u.academicRepo.GetCalendarEvents(unitID, u.Today().AddDate(-1, 0, 0), time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	name := "Kalender Akademik"
+	switch unitID {
+	case domain.UnitMTS:
+		name += " MTs"
+	case domain.UnitMA:
+		name += " MA"
+	}
+
+	cal := ical.Calendar{Name: name}
+	for _, e := range events {
+		cal.Events = append(cal.Events, ical.Event{
+			UID:         fmt.Sprintf("calendar-%d@ppi100", e.ID),
+			Summary:     e.Title,
+			Description: e.Description,
+			Categories:  e.Type,
+			Start:       e.StartDate,
+			End:         e.EndDate,
+			AllDay:      true,
+		})
+	}
+	return cal.Marshal(), nil
+}
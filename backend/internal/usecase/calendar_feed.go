@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"ppi-100-sis/internal/config"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"ppi-100-sis/pkg/ical"
+	"ppi-100-sis/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidFeedToken = errors.New("invalid calendar feed token")
+	ErrFeedUnavailable  = errors.New("personal calendar feeds are only available to teachers and students")
+)
+
+// CalendarFeedUsecase serves each teacher's or student's timetable and task
+// deadlines as an .ics subscription behind a revocable token.
+type CalendarFeedUsecase struct {
+	tokenRepo       *postgres.TokenRepository
+	userRepo        *postgres.UserRepository
+	academicRepo    *postgres.AcademicRepository
+	elearningRepo   *postgres.ElearningRepository
+	academicUsecase *AcademicUsecase
+	cfg             *config.Config
+}
+
+func NewCalendarFeedUsecase(tokenRepo *postgres.TokenRepository, userRepo *postgres.UserRepository, academicRepo *postgres.AcademicRepository, elearningRepo *postgres.ElearningRepository, academicUsecase *AcademicUsecase, cfg *config.Config) *CalendarFeedUsecase {
+	return &CalendarFeedUsecase{
+		tokenRepo:       tokenRepo,
+		userRepo:        userRepo,
+		academicRepo:    academicRepo,
+		elearningRepo:   elearningRepo,
+		academicUsecase: academicUsecase,
+		cfg:             cfg,
+	}
+}
+
+// CalendarFeedStatus tells whether the user has a feed URL; the URL itself is
+// only shown when issued.
+type CalendarFeedStatus struct {
+	Active     bool       `json:"active"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+func (u *CalendarFeedUsecase) GetFeedStatus(userID uuid.UUID) (*CalendarFeedStatus, error) {
+	token, err := u.tokenRepo.GetCalendarFeedToken(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &CalendarFeedStatus{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &CalendarFeedStatus{Active: true, CreatedAt: &token.CreatedAt, LastUsedAt: token.LastUsedAt}, nil
+}
+
+// CreateFeedToken issues a new feed token, revoking the previous one. The
+// token is only returned here; just its hash is stored.
+func (u *CalendarFeedUsecase) CreateFeedToken(userID uuid.UUID) (string, error) {
+	user, err := u.userRepo.FindByID(userID.String())
+	if err != nil {
+		return "", err
+	}
+	if user.Student == nil && user.Teacher == nil {
+		return "", ErrFeedUnavailable
+	}
+
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+	if err := u.tokenRepo.ReplaceCalendarFeedToken(&domain.CalendarFeedToken{UserID: userID, TokenHash: utils.HashToken(token)}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (u *CalendarFeedUsecase) RevokeFeedToken(userID uuid.UUID) error {
+	return u.tokenRepo.DeleteCalendarFeedToken(userID)
+}
+
+// Feed renders the calendar of the token's owner: weekly lessons of the
+// active semester, minus days off, and task deadlines.
+func (u *CalendarFeedUsecase) Feed(token string) ([]byte, error) {
+	record, err := u.tokenRepo.FindCalendarFeedTokenByHash(utils.HashToken(token))
+	if err != nil {
+		return nil, ErrInvalidFeedToken
+	}
+	user, err := u.userRepo.FindByID(record.UserID.String())
+	if err != nil {
+		return nil, ErrInvalidFeedToken
+	}
+	_ = u.tokenRepo.TouchCalendarFeedToken(record.ID) // Best effort
+
+	var (
+		unitID uint
+		filter postgres.ScheduleFilter
+	)
+	switch {
+	case user.Student != nil:
+		unitID = user.Student.UnitID
+		filter.ClassID = user.Student.ClassID
+	case user.Teacher != nil:
+		unitID = user.Teacher.UnitID
+		filter.TeacherID = user.Teacher.ID.String()
+	default:
+		return nil, ErrFeedUnavailable
+	}
+
+	// Lessons recur from the start of the active semester to its end; units
+	// without terms get open-ended lessons starting this week.
+	today := schoolToday(u.cfg)
+	from, until := today.AddDate(0, 0, -int(domain.WeekdayOf(today)-domain.Monday)), time.Time{}
+	term := postgres.TermFilter{}
+	if semester, err := u.academicRepo.GetActiveSemester(unitID); err == nil {
+		from, until = dateOnly(semester.StartDate), dateOnly(semester.EndDate)
+		term.SemesterID = semester.ID
+	}
+
+	schedules, err := u.academicUsecase.GetAllSchedules(unitID, filter, term)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []domain.Task
+	if user.Student != nil {
+		tasks, err = u.elearningRepo.GetTasksByClass(user.Student.ClassID, unitID, term)
+	} else {
+		tasks, err = u.elearningRepo.GetTasksByTeacher(user.Teacher.ID.String(), unitID, term)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	calendarEnd := until
+	if calendarEnd.IsZero() {
+		calendarEnd = from.AddDate(0, 6, 0)
+	}
+	cal, err := loadTeachingCalendar(u.academicRepo, unitID, from, calendarEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := ical.Calendar{Name: "Jadwal " + user.Name}
+	for _, schedule := range schedules {
+		// Legacy rows whose day could not be migrated never occur
+		if !schedule.Day.Valid() {
+			continue
+		}
+		feed.Events = append(feed.Events, u.lessonEvent(&schedule, cal, from, until, calendarEnd))
+	}
+	for _, task := range tasks {
+		if task.Deadline.IsZero() {
+			continue
+		}
+		feed.Events = append(feed.Events, ical.Event{
+			UID:         fmt.Sprintf("task-%d@ppi100", task.ID),
+			Summary:     fmt.Sprintf("Tenggat: %s (%s)", task.Title, task.Subject.Name),
+			Description: task.Description,
+			Categories:  "task",
+			Start:       task.Deadline,
+		})
+	}
+	return feed.Marshal(), nil
+}
+
+// lessonEvent turns a schedule into a weekly event from its first occurrence
+// on or after from, skipping days off up to calendarEnd.
+func (u *CalendarFeedUsecase) lessonEvent(schedule *domain.Schedule, cal *teachingCalendar, from, until, calendarEnd time.Time) ical.Event {
+	first := from
+	for domain.WeekdayOf(first) != schedule.Day {
+		first = first.AddDate(0, 0, 1)
+	}
+	at := func(day time.Time, t domain.TimeOfDay) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), int(t)/60, int(t)%60, 0, 0, u.cfg.SchoolLocation)
+	}
+
+	event := ical.Event{
+		UID:        fmt.Sprintf("schedule-%d@ppi100", schedule.ID),
+		Summary:    fmt.Sprintf("%s · %s", schedule.Subject.Name, schedule.Class.Name),
+		Categories: "lesson",
+		Start:      at(first, schedule.StartTime),
+		End:        at(first, schedule.EndTime),
+		RRule:      "FREQ=WEEKLY",
+	}
+	if schedule.Room != nil {
+		event.Location = schedule.Room.Name
+	}
+	if schedule.Teacher.User.Name != "" {
+		event.Description = "Guru: " + schedule.Teacher.User.Name
+	}
+	if !until.IsZero() {
+		event.RRule += ";UNTIL=" + ical.FormatUTC(at(until, schedule.EndTime))
+	}
+	for d := first; !d.After(calendarEnd); d = d.AddDate(0, 0, 7) {
+		if cal.DayOff(d) != "" {
+			event.ExDates = append(event.ExDates, at(d, schedule.StartTime))
+		}
+	}
+	return event
+}
+
+// dateOnly is the calendar date of t as UTC midnight.
+func dateOnly(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -13,13 +13,15 @@ type ElearningUsecase struct {
 	elearningRepo *postgres.ElearningRepository
 	notificationUsecase *NotificationUsecase
 	userRepo *postgres.UserRepository
+	academicRepo *postgres.AcademicRepository
 }
 
-func NewElearningUsecase(elearningRepo *postgres.ElearningRepository, notificationUsecase *NotificationUsecase, userRepo *postgres.UserRepository) *ElearningUsecase {
+func NewElearningUsecase(elearningRepo *postgres.ElearningRepository, notificationUsecase *NotificationUsecase, userRepo *postgres.UserRepository, academicRepo *postgres.AcademicRepository) *ElearningUsecase {
 	return &ElearningUsecase{
 		elearningRepo: elearningRepo,
 		notificationUsecase: notificationUsecase,
 		userRepo: userRepo,
+		academicRepo: academicRepo,
 	}
 }
 
@@ -27,14 +29,14 @@ func NewElearningUsecase(elearningRepo *postgres.ElearningRepository, notificati
 // Wait, I can't omit code in replace_file_content if I'm replacing a chunk.
 // I'll target the struct and constructor first.
 
-func (u *ElearningUsecase) GradeSubmission(id string, grade float64) error {
+func (u *ElearningUsecase) GradeSubmission(id string, grade float64, unitID uint) error {
 	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		return err
 	}
 
 	// Update grade
-	if err := u.elearningRepo.UpdateSubmissionGrade(uuidID, grade); err != nil {
+	if err := u.elearningRepo.UpdateSubmissionGrade(uuidID, grade, unitID); err != nil {
 		return err
 	}
 
@@ -54,7 +56,12 @@ func (u *ElearningUsecase) GradeSubmission(id string, grade float64) error {
 	)
 }
 
-func (u *ElearningUsecase) CreateMaterial(title, description, fileURL string, classID, subjectID uint, teacherID uuid.UUID) error {
+func (u *ElearningUsecase) CreateMaterial(title, description, fileURL string, classID, subjectID uint, teacherID uuid.UUID, unitID uint) error {
+	class, err := u.academicRepo.GetClassByID(classID, unitID)
+	if err != nil {
+		return err
+	}
+
 	material := &domain.Material{
 		Title:       title,
 		Description: description,
@@ -62,45 +69,64 @@ func (u *ElearningUsecase) CreateMaterial(title, description, fileURL string, cl
 		ClassID:     classID,
 		SubjectID:   subjectID,
 		TeacherID:   teacherID,
+		SemesterID:  activeSemesterID(u.academicRepo, class.UnitID),
 	}
 	return u.elearningRepo.CreateMaterial(material)
 }
 
-func (u *ElearningUsecase) GetMaterials(classID uint) ([]domain.Material, error) {
-	return u.elearningRepo.GetMaterialsByClass(classID)
+func (u *ElearningUsecase) GetMaterials(classID, unitID uint, term postgres.TermFilter) ([]domain.Material, error) {
+	return u.elearningRepo.GetMaterialsByClass(classID, unitID, term)
 }
 
-func (u *ElearningUsecase) GetMaterialsByUnit(unitID uint) ([]domain.Material, error) {
-	return u.elearningRepo.GetMaterialsByUnit(unitID)
+func (u *ElearningUsecase) GetMaterialsByUnit(unitID uint, term postgres.TermFilter) ([]domain.Material, error) {
+	return u.elearningRepo.GetMaterialsByUnit(unitID, term)
 }
 
-func (u *ElearningUsecase) CreateTask(title, description string, deadline time.Time, classID, subjectID uint, teacherID uuid.UUID) error {
+func (u *ElearningUsecase) CreateTask(title, description string, deadline time.Time, classID, subjectID uint, objectiveIDs []uint, teacherID uuid.UUID, unitID uint) error {
+	class, err := u.academicRepo.GetClassByID(classID, unitID)
+	if err != nil {
+		return err
+	}
+	if deadline, err = teachingDeadline(u.academicRepo, class.UnitID, deadline); err != nil {
+		return err
+	}
+	objectives, err := subjectObjectives(u.academicRepo, subjectID, objectiveIDs)
+	if err != nil {
+		return err
+	}
+
 	task := &domain.Task{
-		Title:       title,
-		Description: description,
-		Deadline:    deadline,
-		ClassID:     classID,
-		SubjectID:   subjectID,
-		TeacherID:   teacherID,
+		Title:              title,
+		Description:        description,
+		Deadline:           deadline,
+		ClassID:            classID,
+		SubjectID:          subjectID,
+		TeacherID:          teacherID,
+		SemesterID:         activeSemesterID(u.academicRepo, class.UnitID),
+		LearningObjectives: objectives,
 	}
 	return u.elearningRepo.CreateTask(task)
 }
 
-func (u *ElearningUsecase) GetTasks(classID uint) ([]domain.Task, error) {
-	return u.elearningRepo.GetTasksByClass(classID)
+func (u *ElearningUsecase) GetTasks(classID, unitID uint, term postgres.TermFilter) ([]domain.Task, error) {
+	return u.elearningRepo.GetTasksByClass(classID, unitID, term)
 }
 
-func (u *ElearningUsecase) GetTasksByUnit(unitID uint) ([]domain.Task, error) {
-	return u.elearningRepo.GetTasksByUnit(unitID)
+func (u *ElearningUsecase) GetTasksByUnit(unitID uint, term postgres.TermFilter) ([]domain.Task, error) {
+	return u.elearningRepo.GetTasksByUnit(unitID, term)
 }
 
 
 
-func (u *ElearningUsecase) GetSubmissions(taskID uint) ([]domain.TaskSubmission, error) {
-	return u.elearningRepo.GetSubmissionsByTask(taskID)
+func (u *ElearningUsecase) GetSubmissions(taskID, unitID uint) ([]domain.TaskSubmission, error) {
+	return u.elearningRepo.GetSubmissionsByTask(taskID, unitID)
 }
 
-func (u *ElearningUsecase) SubmitTask(taskID uint, studentID uuid.UUID, fileURL string) error {
+func (u *ElearningUsecase) SubmitTask(taskID uint, studentID uuid.UUID, fileURL string, unitID uint) error {
+	if _, err := u.elearningRepo.GetTaskByID(taskID, unitID); err != nil {
+		return err
+	}
+
 	submission := &domain.TaskSubmission{
 		TaskID:    taskID,
 		StudentID: studentID,
@@ -110,11 +136,11 @@ func (u *ElearningUsecase) SubmitTask(taskID uint, studentID uuid.UUID, fileURL
 	return u.elearningRepo.CreateSubmission(submission)
 }
 
-func (u *ElearningUsecase) GetStudentSubmissions(studentID uuid.UUID) ([]domain.TaskSubmission, error) {
-	return u.elearningRepo.GetSubmissionsByStudent(studentID)
+func (u *ElearningUsecase) GetStudentSubmissions(studentID uuid.UUID, unitID uint, term postgres.TermFilter) ([]domain.TaskSubmission, error) {
+	return u.elearningRepo.GetSubmissionsByStudent(studentID, unitID, term)
 }
 
-func (u *ElearningUsecase) GetStudentSubmissionsByUserID(userID string) ([]domain.TaskSubmission, error) {
+func (u *ElearningUsecase) GetStudentSubmissionsByUserID(userID string, term postgres.TermFilter) ([]domain.TaskSubmission, error) {
 	user, err := u.userRepo.FindByID(userID)
 	if err != nil {
 		return nil, err
@@ -122,29 +148,56 @@ func (u *ElearningUsecase) GetStudentSubmissionsByUserID(userID string) ([]domai
 	if user.Student == nil {
 		return nil, errors.New("user is not a student")
 	}
-	return u.GetStudentSubmissions(user.Student.ID)
+	return u.GetStudentSubmissions(user.Student.ID, user.Student.UnitID, term)
 }
 
 // Update/Delete Material
-func (u *ElearningUsecase) UpdateMaterial(material *domain.Material) error {
+func (u *ElearningUsecase) UpdateMaterial(material *domain.Material, unitID uint) error {
+	existing, err := u.elearningRepo.GetMaterialByID(material.ID, unitID)
+	if err != nil {
+		return err
+	}
+	if _, err := u.academicRepo.GetClassByID(material.ClassID, unitID); err != nil {
+		return err
+	}
+
+	material.CreatedAt = existing.CreatedAt
+	material.SemesterID = existing.SemesterID
 	return u.elearningRepo.UpdateMaterial(material)
 }
 
-func (u *ElearningUsecase) DeleteMaterial(id uint) error {
-	return u.elearningRepo.DeleteMaterial(id)
+func (u *ElearningUsecase) DeleteMaterial(id, unitID uint) error {
+	return u.elearningRepo.DeleteMaterial(id, unitID)
 }
 
 // Update/Delete Task
-func (u *ElearningUsecase) UpdateTask(task *domain.Task) error {
+func (u *ElearningUsecase) UpdateTask(task *domain.Task, objectiveIDs []uint, unitID uint) error {
+	existing, err := u.elearningRepo.GetTaskByID(task.ID, unitID)
+	if err != nil {
+		return err
+	}
+	class, err := u.academicRepo.GetClassByID(task.ClassID, unitID)
+	if err != nil {
+		return err
+	}
+	if task.Deadline, err = teachingDeadline(u.academicRepo, class.UnitID, task.Deadline); err != nil {
+		return err
+	}
+	if task.LearningObjectives, err = subjectObjectives(u.academicRepo, task.SubjectID, objectiveIDs); err != nil {
+		return err
+	}
+
+	task.CreatedAt = existing.CreatedAt
+	task.SemesterID = existing.SemesterID
 	return u.elearningRepo.UpdateTask(task)
 }
 
-func (u *ElearningUsecase) DeleteTask(id uint) error {
-	return u.elearningRepo.DeleteTask(id)
+func (u *ElearningUsecase) DeleteTask(id, unitID uint) error {
+	return u.elearningRepo.DeleteTask(id, unitID)
 }
 
 // Delete Submission
-func (u *ElearningUsecase) DeleteSubmission(id uuid.UUID) error {
-	return u.elearningRepo.DeleteSubmission(id)
+func (u *ElearningUsecase) DeleteSubmission(id uuid.UUID, unitID uint) error {
+	return u.elearningRepo.DeleteSubmission(id, unitID)
 }
 
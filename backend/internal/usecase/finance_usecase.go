@@ -13,17 +13,23 @@ type FinanceUsecase struct {
 	financeRepo      *postgres.FinanceRepository
 	notificationUsecase *NotificationUsecase
 	userRepo *postgres.UserRepository
+	studentRepo *postgres.StudentRepository
 }
 
-func NewFinanceUsecase(financeRepo *postgres.FinanceRepository, notificationUsecase *NotificationUsecase, userRepo *postgres.UserRepository) *FinanceUsecase {
+func NewFinanceUsecase(financeRepo *postgres.FinanceRepository, notificationUsecase *NotificationUsecase, userRepo *postgres.UserRepository, studentRepo *postgres.StudentRepository) *FinanceUsecase {
 	return &FinanceUsecase{
 		financeRepo:      financeRepo,
 		notificationUsecase: notificationUsecase,
 		userRepo: userRepo,
+		studentRepo: studentRepo,
 	}
 }
 
-func (u *FinanceUsecase) CreateBill(studentID uuid.UUID, title string, amount float64, dueDate time.Time) error {
+func (u *FinanceUsecase) CreateBill(studentID uuid.UUID, title string, amount float64, dueDate time.Time, unitID uint) error {
+	if _, err := u.studentRepo.GetByID(studentID.String(), unitID); err != nil {
+		return err
+	}
+
 	bill := &domain.Bill{
 		StudentID: studentID,
 		Title:     title,
@@ -49,8 +55,8 @@ func (u *FinanceUsecase) GetAllBills(unitID uint) ([]domain.Bill, error) {
 	return u.financeRepo.GetAllBills(unitID)
 }
 
-func (u *FinanceUsecase) GetStudentBills(studentID string) ([]domain.Bill, error) {
-	return u.financeRepo.GetBillsByStudent(studentID)
+func (u *FinanceUsecase) GetStudentBills(studentID string, unitID uint) ([]domain.Bill, error) {
+	return u.financeRepo.GetBillsByStudent(studentID, unitID)
 }
 
 func (u *FinanceUsecase) GetStudentBillsByUserID(userID string) ([]domain.Bill, error) {
@@ -61,10 +67,14 @@ func (u *FinanceUsecase) GetStudentBillsByUserID(userID string) ([]domain.Bill,
 	if user.Student == nil {
 		return nil, errors.New("user is not a student")
 	}
-	return u.GetStudentBills(user.Student.ID.String())
+	return u.GetStudentBills(user.Student.ID.String(), user.Student.UnitID)
 }
 
-func (u *FinanceUsecase) RecordPayment(billID uuid.UUID, amount float64, method string) error {
+func (u *FinanceUsecase) RecordPayment(billID uuid.UUID, amount float64, method string, unitID uint) error {
+	if _, err := u.financeRepo.GetBillByID(billID.String(), unitID); err != nil {
+		return err
+	}
+
 	payment := &domain.Payment{
 		BillID:        billID,
 		Amount:        amount,
@@ -82,20 +92,43 @@ func (u *FinanceUsecase) RecordPayment(billID uuid.UUID, amount float64, method
 }
 
 // Update/Delete Bill
-func (u *FinanceUsecase) UpdateBill(bill *domain.Bill) error {
-	return u.financeRepo.UpdateBill(bill)
+func (u *FinanceUsecase) UpdateBill(bill *domain.Bill, unitID uint) error {
+	existing, err := u.financeRepo.GetBillByID(bill.ID.String(), unitID)
+	if err != nil {
+		return err
+	}
+	if _, err := u.studentRepo.GetByID(bill.StudentID.String(), unitID); err != nil {
+		return err
+	}
+
+	existing.StudentID = bill.StudentID
+	existing.Title = bill.Title
+	existing.Amount = bill.Amount
+	existing.DueDate = bill.DueDate
+	return u.financeRepo.UpdateBill(existing)
 }
 
-func (u *FinanceUsecase) DeleteBill(id string) error {
-	return u.financeRepo.DeleteBill(id)
+func (u *FinanceUsecase) DeleteBill(id string, unitID uint) error {
+	return u.financeRepo.DeleteBill(id, unitID)
 }
 
 // Update/Delete Payment
-func (u *FinanceUsecase) UpdatePayment(payment *domain.Payment) error {
-	return u.financeRepo.UpdatePayment(payment)
+func (u *FinanceUsecase) UpdatePayment(payment *domain.Payment, unitID uint) error {
+	existing, err := u.financeRepo.GetPaymentByID(payment.ID.String(), unitID)
+	if err != nil {
+		return err
+	}
+	if _, err := u.financeRepo.GetBillByID(payment.BillID.String(), unitID); err != nil {
+		return err
+	}
+
+	existing.BillID = payment.BillID
+	existing.Amount = payment.Amount
+	existing.PaymentMethod = payment.PaymentMethod
+	return u.financeRepo.UpdatePayment(existing)
 }
 
-func (u *FinanceUsecase) DeletePayment(id string) error {
-	return u.financeRepo.DeletePayment(id)
+func (u *FinanceUsecase) DeletePayment(id string, unitID uint) error {
+	return u.financeRepo.DeletePayment(id, unitID)
 }
 
@@ -0,0 +1,411 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidScore        = errors.New("invalid score")
+	ErrInvalidGradeWeights = errors.New("invalid grade weights")
+	ErrNotSubjectTeacher   = errors.New("you do not teach this subject in this class")
+)
+
+// DefaultGradeWeights apply to semesters without configured weights.
+var DefaultGradeWeights = map[string]float64{
+	domain.ComponentTask:     20,
+	domain.ComponentQuiz:     20,
+	domain.ComponentMidterm:  20,
+	domain.ComponentFinal:    30,
+	domain.ComponentPractice: 10,
+}
+
+func isGradeComponent(component string) bool {
+	for _, c := range domain.GradeComponents {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+type GradeWeights struct {
+	SemesterID uint               `json:"semester_id"`
+	Weights    map[string]float64 `json:"weights"`
+	IsDefault  bool               `json:"is_default"`
+}
+
+// GetGradeWeights returns the weights of the term's semester, or the active
+// one, falling back to DefaultGradeWeights.
+func (u *AcademicUsecase) GetGradeWeights(unitID uint, term postgres.TermFilter) (*GradeWeights, error) {
+	result := &GradeWeights{}
+	var semester *domain.Semester
+	switch {
+	case term.SemesterID != 0:
+		var err error
+		if semester, err = u.academicRepo.GetSemesterByID(term.SemesterID, unitID); err != nil {
+			return nil, err
+		}
+	case !term.All:
+		semester = activeSemester(u.academicRepo, unitID)
+	}
+	if semester != nil {
+		result.SemesterID = semester.ID
+		unitID = semester.AcademicYear.UnitID
+	}
+
+	var weights []domain.GradeWeight
+	if result.SemesterID != 0 {
+		var err error
+		if weights, err = u.academicRepo.GetGradeWeights(unitID, result.SemesterID); err != nil {
+			return nil, err
+		}
+	}
+	result.Weights = map[string]float64{}
+	for _, w := range weights {
+		result.Weights[w.Component] = w.Weight
+	}
+	if len(weights) == 0 {
+		result.IsDefault = true
+		for component, weight := range DefaultGradeWeights {
+			result.Weights[component] = weight
+		}
+	}
+	return result, nil
+}
+
+// SetGradeWeights replaces the weights of a semester. Every component must be
+// known and the weights must add up to 100.
+func (u *AcademicUsecase) SetGradeWeights(unitID, semesterID uint, weights map[string]float64) error {
+	semester, err := u.academicRepo.GetSemesterByID(semesterID, unitID)
+	if err != nil {
+		return err
+	}
+	unitID = semester.AcademicYear.UnitID
+
+	total := 0.0
+	var records []domain.GradeWeight
+	for _, component := range domain.GradeComponents {
+		weight, ok := weights[component]
+		if !ok {
+			continue
+		}
+		if weight < 0 {
+			return fmt.Errorf("%w: %s must not be negative", ErrInvalidGradeWeights, component)
+		}
+		total += weight
+		records = append(records, domain.GradeWeight{UnitID: unitID, SemesterID: semesterID, Component: component, Weight: weight})
+	}
+	if len(records) != len(weights) {
+		return fmt.Errorf("%w: unknown component", ErrInvalidGradeWeights)
+	}
+	if math.Abs(total-100) > 0.01 {
+		return fmt.Errorf("%w: weights must add up to 100, got %g", ErrInvalidGradeWeights, total)
+	}
+	return u.academicRepo.ReplaceGradeWeights(unitID, semesterID, records)
+}
+
+// checkSubjectTeacher fails unless the teacher has a lesson of the subject in
+// the class this term. An empty teacherID skips the check.
+func (u *AcademicUsecase) checkSubjectTeacher(teacherID string, classID, subjectID, unitID uint) error {
+	if teacherID == "" {
+		return nil
+	}
+	schedules, err := u.academicRepo.GetAllSchedules(unitID, postgres.ScheduleFilter{ClassID: classID, TeacherID: teacherID}, postgres.TermFilter{})
+	if err != nil {
+		return err
+	}
+	for _, s := range schedules {
+		if s.SubjectID == subjectID {
+			return nil
+		}
+	}
+	return ErrNotSubjectTeacher
+}
+
+type ScoreEntry struct {
+	StudentID uuid.UUID `json:"student_id" binding:"required"`
+	Value     float64   `json:"value"`
+}
+
+// RecordScores saves one assessment, e.g. "UH 1", for students of a class in
+// the active semester, tagged with the learning objectives it assesses.
+// Scores already recorded for it are overwritten.
+func (u *AcademicUsecase) RecordScores(classID, subjectID uint, component, title string, objectiveIDs []uint, entries []ScoreEntry, recordedBy uuid.UUID, teacherID string, unitID uint) error {
+	title = strings.TrimSpace(title)
+	if !isGradeComponent(component) {
+		return fmt.Errorf("%w: unknown component %q", ErrInvalidScore, component)
+	}
+	if title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidScore)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("%w: no scores given", ErrInvalidScore)
+	}
+
+	class, err := u.academicRepo.GetClassByID(classID, unitID)
+	if err != nil {
+		return err
+	}
+	if _, err := u.academicRepo.GetSubjectByID(subjectID, class.UnitID); err != nil {
+		return err
+	}
+	if err := u.checkSubjectTeacher(teacherID, classID, subjectID, class.UnitID); err != nil {
+		return err
+	}
+	objectives, err := subjectObjectives(u.academicRepo, subjectID, objectiveIDs)
+	if err != nil {
+		return err
+	}
+	semester := activeSemester(u.academicRepo, class.UnitID)
+	if semester == nil {
+		return fmt.Errorf("%w: the unit has no active semester", ErrInvalidScore)
+	}
+
+	studentIDs := make([]string, 0, len(entries))
+	seen := map[uuid.UUID]bool{}
+	scores := make([]domain.Score, 0, len(entries))
+	for _, e := range entries {
+		if e.Value < 0 || e.Value > 100 {
+			return fmt.Errorf("%w: scores must be between 0 and 100", ErrInvalidScore)
+		}
+		if seen[e.StudentID] {
+			return fmt.Errorf("%w: duplicate student %s", ErrInvalidScore, e.StudentID)
+		}
+		seen[e.StudentID] = true
+		studentIDs = append(studentIDs, e.StudentID.String())
+		scores = append(scores, domain.Score{
+			StudentID:    e.StudentID,
+			SubjectID:    subjectID,
+			SemesterID:   semester.ID,
+			Component:    component,
+			Title:        title,
+			Value:        e.Value,
+			RecordedByID: recordedBy,
+		})
+	}
+
+	count, err := u.academicRepo.CountClassStudents(classID, studentIDs)
+	if err != nil {
+		return err
+	}
+	if int(count) != len(studentIDs) {
+		return fmt.Errorf("%w: every student must be in the class", ErrInvalidScore)
+	}
+	return u.academicRepo.SaveScores(scores, objectives)
+}
+
+// GetScores lists the scores of a class and subject with their KKM. Teachers
+// only see the subjects they teach in the class.
+func (u *AcademicUsecase) GetScores(classID, subjectID uint, teacherID string, unitID uint, term postgres.TermFilter) ([]ScoreResult, error) {
+	class, err := u.academicRepo.GetClassByID(classID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkSubjectTeacher(teacherID, classID, subjectID, class.UnitID); err != nil {
+		return nil, err
+	}
+	scores, err := u.academicRepo.GetScores(unitID, postgres.ScoreFilter{ClassID: classID, SubjectID: subjectID}, term)
+	if err != nil {
+		return nil, err
+	}
+	table, err := u.loadKKM(class.UnitID, term)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ScoreResult, 0, len(scores))
+	for i := range scores {
+		results = append(results, u.scoreResult(&scores[i], table.For(scores[i].SemesterID, scores[i].SubjectID, classID)))
+	}
+	return results, nil
+}
+
+func (u *AcademicUsecase) DeleteScore(id, teacherID string, unitID uint) error {
+	score, err := u.academicRepo.GetScoreByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	if err := u.checkSubjectTeacher(teacherID, score.Student.ClassID, score.SubjectID, score.Student.UnitID); err != nil {
+		return err
+	}
+	return u.academicRepo.DeleteScore(id, unitID)
+}
+
+type ComponentGrade struct {
+	Component string   `json:"component"`
+	Weight    float64  `json:"weight"`
+	Average   *float64 `json:"average"` // Nil without scores
+	Count     int      `json:"count"`
+	BelowKKM  int      `json:"below_kkm"` // Scores still below the KKM
+}
+
+type SubjectGrade struct {
+	SubjectID   uint               `json:"subject_id"`
+	SubjectName string             `json:"subject_name"`
+	Average     float64            `json:"average"`
+	KKM         float64            `json:"kkm"`
+	Passed      bool               `json:"passed"`
+	Components  []ComponentGrade   `json:"components"`
+	Objectives  []ObjectiveMastery `json:"objectives"`
+	Description string             `json:"description"` // Capaian kompetensi written from the objectives
+}
+
+// weighComponents averages each component and weighs the averages into a
+// subject grade. Components without values are left out and the remaining
+// weights scaled up.
+func weighComponents(values map[string][]float64, below map[string]int, weights map[string]float64) ([]ComponentGrade, float64) {
+	var components []ComponentGrade
+	weighted, weightSum := 0.0, 0.0
+	for _, component := range domain.GradeComponents {
+		cg := ComponentGrade{
+			Component: component,
+			Weight:    weights[component],
+			Count:     len(values[component]),
+			BelowKKM:  below[component],
+		}
+		if cg.Count > 0 {
+			total := 0.0
+			for _, v := range values[component] {
+				total += v
+			}
+			average := total / float64(cg.Count)
+			cg.Average = &average
+			weighted += average * cg.Weight
+			weightSum += cg.Weight
+		}
+		components = append(components, cg)
+	}
+	if weightSum == 0 {
+		return components, 0
+	}
+	return components, weighted / weightSum
+}
+
+// termClass is the class the student was in during the term's semester. The
+// active term, or every term, goes by the current class.
+func (u *AcademicUsecase) termClass(student *domain.Student, term postgres.TermFilter) (uint, error) {
+	if term.SemesterID == 0 {
+		return student.ClassID, nil
+	}
+	semester, err := u.academicRepo.GetSemesterByID(term.SemesterID, student.UnitID)
+	if err != nil {
+		return 0, err
+	}
+	return semesterClass(u.enrollmentRepo, student, semester)
+}
+
+// GetStudentReportCard weighs the mean of each component per subject. Graded
+// task submissions count as tugas; ungraded ones are left out. Components
+// without scores are left out and the remaining weights scaled up. Scores
+// count with their remedials applied. Each learning objective is mastered by
+// the mean of the scores and tasks tagged with it. classID is the class the
+// student was in during the term, whose class KKMs apply; 0 resolves it from
+// the enrollment history.
+func (u *AcademicUsecase) GetStudentReportCard(studentID uuid.UUID, classID, unitID uint, term postgres.TermFilter) ([]SubjectGrade, error) {
+	student, err := u.academicRepo.GetStudent(studentID.String(), unitID)
+	if err != nil {
+		return nil, err
+	}
+	if classID == 0 {
+		if classID, err = u.termClass(student, term); err != nil {
+			return nil, err
+		}
+	}
+	weights, err := u.GetGradeWeights(student.UnitID, term)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := u.elearningRepo.GetSubmissionsByStudent(studentID, unitID, term)
+	if err != nil {
+		return nil, err
+	}
+	scores, err := u.academicRepo.GetScores(unitID, postgres.ScoreFilter{StudentID: studentID.String()}, term)
+	if err != nil {
+		return nil, err
+	}
+	table, err := u.loadKKM(student.UnitID, term)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := map[uint]*domain.Subject{}
+	values := map[uint]map[string][]float64{}
+	below := map[uint]map[string]int{}
+	mastery := map[uint][]float64{} // Objective
+	add := func(subject *domain.Subject, component string, value float64, failed bool, objectives []domain.LearningObjective) {
+		if values[subject.ID] == nil {
+			subjects[subject.ID] = subject
+			values[subject.ID] = map[string][]float64{}
+			below[subject.ID] = map[string]int{}
+		}
+		values[subject.ID][component] = append(values[subject.ID][component], value)
+		if failed {
+			below[subject.ID][component]++
+		}
+		for _, o := range objectives {
+			mastery[o.ID] = append(mastery[o.ID], value)
+		}
+	}
+	for i := range submissions {
+		if sub := &submissions[i]; sub.GradedAt != nil {
+			kkm := table.For(weights.SemesterID, sub.Task.SubjectID, classID)
+			add(&sub.Task.Subject, domain.ComponentTask, sub.Grade, sub.Grade < kkm, sub.Task.LearningObjectives)
+		}
+	}
+	for i := range scores {
+		if scores[i].Subject != nil {
+			result := u.scoreResult(&scores[i], table.For(scores[i].SemesterID, scores[i].SubjectID, classID))
+			add(scores[i].Subject, scores[i].Component, result.Final, result.BelowKKM, scores[i].LearningObjectives)
+		}
+	}
+
+	subjectIDs := make([]uint, 0, len(subjects))
+	for id := range subjects {
+		subjectIDs = append(subjectIDs, id)
+	}
+	objectives, err := u.academicRepo.GetLearningObjectives(student.UnitID, subjectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reportCard := []SubjectGrade{}
+	for subjectID, byComponent := range values {
+		grade := SubjectGrade{
+			SubjectID:   subjectID,
+			SubjectName: subjects[subjectID].Name,
+			KKM:         table.For(weights.SemesterID, subjectID, classID),
+		}
+		grade.Components, grade.Average = weighComponents(byComponent, below[subjectID], weights.Weights)
+		grade.Passed = grade.Average >= grade.KKM
+
+		grade.Objectives = []ObjectiveMastery{}
+		for _, o := range objectives {
+			if o.SubjectID != subjectID {
+				continue
+			}
+			om := ObjectiveMastery{ID: o.ID, Code: o.Code, Description: o.Description, Count: len(mastery[o.ID])}
+			if om.Count > 0 {
+				total := 0.0
+				for _, v := range mastery[o.ID] {
+					total += v
+				}
+				average := total / float64(om.Count)
+				om.Average = &average
+				om.Achieved = average >= grade.KKM
+			}
+			grade.Objectives = append(grade.Objectives, om)
+		}
+		grade.Description = describeMastery(grade.Objectives)
+		reportCard = append(reportCard, grade)
+	}
+	sort.Slice(reportCard, func(a, b int) bool { return reportCard[a].SubjectName < reportCard[b].SubjectName })
+	return reportCard, nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"math"
+	"ppi-100-sis/internal/domain"
+	"testing"
+)
+
+func TestWeighComponents(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string][]float64
+		weights map[string]float64
+		want    float64
+	}{
+		{
+			name: "every component",
+			values: map[string][]float64{
+				domain.ComponentTask:     {80, 90},
+				domain.ComponentQuiz:     {70},
+				domain.ComponentMidterm:  {60},
+				domain.ComponentFinal:    {90},
+				domain.ComponentPractice: {100},
+			},
+			weights: DefaultGradeWeights,
+			want:    85*0.2 + 70*0.2 + 60*0.2 + 90*0.3 + 100*0.1,
+		},
+		{
+			name: "missing components scale the rest up",
+			values: map[string][]float64{
+				domain.ComponentTask:  {80},
+				domain.ComponentFinal: {90},
+			},
+			weights: DefaultGradeWeights,
+			want:    (80*20 + 90*30) / 50.0,
+		},
+		{
+			name:    "single component is its own average",
+			values:  map[string][]float64{domain.ComponentQuiz: {60, 70, 80}},
+			weights: DefaultGradeWeights,
+			want:    70,
+		},
+		{
+			name:    "only zero-weight components",
+			values:  map[string][]float64{domain.ComponentPractice: {100}},
+			weights: map[string]float64{domain.ComponentTask: 50, domain.ComponentFinal: 50},
+			want:    0,
+		},
+		{
+			name:    "no values",
+			values:  map[string][]float64{},
+			weights: DefaultGradeWeights,
+			want:    0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := weighComponents(tt.values, nil, tt.weights)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("average = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeighComponentsBreakdown(t *testing.T) {
+	values := map[string][]float64{domain.ComponentTask: {70, 80}}
+	below := map[string]int{domain.ComponentTask: 1}
+	components, _ := weighComponents(values, below, DefaultGradeWeights)
+
+	if len(components) != len(domain.GradeComponents) {
+		t.Fatalf("got %d components, want one per grade component", len(components))
+	}
+	for i, cg := range components {
+		if cg.Component != domain.GradeComponents[i] {
+			t.Errorf("component %d = %q, want %q", i, cg.Component, domain.GradeComponents[i])
+		}
+		if cg.Weight != DefaultGradeWeights[cg.Component] {
+			t.Errorf("%s weight = %v, want %v", cg.Component, cg.Weight, DefaultGradeWeights[cg.Component])
+		}
+		if cg.Component == domain.ComponentTask {
+			if cg.Average == nil || *cg.Average != 75 || cg.Count != 2 || cg.BelowKKM != 1 {
+				t.Errorf("tugas = %+v, want average 75 over 2 with 1 below the KKM", cg)
+			}
+		} else if cg.Average != nil || cg.Count != 0 {
+			t.Errorf("%s has an average without values", cg.Component)
+		}
+	}
+}
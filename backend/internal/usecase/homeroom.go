@@ -0,0 +1,322 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidHomeroomNote = errors.New("invalid homeroom note")
+	ErrInvalidP5           = errors.New("invalid P5 project")
+)
+
+// HomeroomUsecase is the wali kelas's record of their class for the rapor:
+// notes, attitude grades and P5 projects.
+type HomeroomUsecase struct {
+	homeroomRepo   *postgres.HomeroomRepository
+	academicRepo   *postgres.AcademicRepository
+	studentRepo    *postgres.StudentRepository
+	enrollmentRepo *postgres.EnrollmentRepository
+}
+
+func NewHomeroomUsecase(homeroomRepo *postgres.HomeroomRepository, academicRepo *postgres.AcademicRepository, studentRepo *postgres.StudentRepository, enrollmentRepo *postgres.EnrollmentRepository) *HomeroomUsecase {
+	return &HomeroomUsecase{
+		homeroomRepo:   homeroomRepo,
+		academicRepo:   academicRepo,
+		studentRepo:    studentRepo,
+		enrollmentRepo: enrollmentRepo,
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// homeroomClass loads a class the teacher is homeroom teacher of. An empty
+// teacherID allows any class.
+func (u *HomeroomUsecase) homeroomClass(classID uint, teacherID string, unitID uint) (*domain.Class, error) {
+	class, err := u.academicRepo.GetClassWithHomeroom(classID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	return class, checkHomeroom(class, teacherID)
+}
+
+// studentHomeroom checks that teacherID was the homeroom teacher of the class
+// the student was in during the semester, as the rapor resolves it.
+func (u *HomeroomUsecase) studentHomeroom(student *domain.Student, semester *domain.Semester, teacherID string) error {
+	classID, err := semesterClass(u.enrollmentRepo, student, semester)
+	if err != nil {
+		return err
+	}
+	_, err = u.homeroomClass(classID, teacherID, student.UnitID)
+	return err
+}
+
+// roster lists the IDs of the students in the class during the semester.
+func (u *HomeroomUsecase) roster(class *domain.Class, semester *domain.Semester) ([]uuid.UUID, error) {
+	students, err := u.enrollmentRepo.GetClassRoster(class.ID, semester, class.UnitID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(students))
+	for i := range students {
+		ids[i] = students[i].ID
+	}
+	return ids, nil
+}
+
+type HomeroomNotes struct {
+	Semester *domain.Semester      `json:"semester"`
+	Notes    []domain.HomeroomNote `json:"notes"`
+}
+
+// GetClassNotes lists the notes of the students in a class during the term's
+// semester, or the active one.
+func (u *HomeroomUsecase) GetClassNotes(classID uint, teacherID string, unitID uint, term postgres.TermFilter) (*HomeroomNotes, error) {
+	class, err := u.homeroomClass(classID, teacherID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	semester, err := termSemester(u.academicRepo, class.UnitID, term)
+	if err != nil {
+		return nil, err
+	}
+	studentIDs, err := u.roster(class, semester)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := u.homeroomRepo.GetNotesByStudents(studentIDs, semester.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &HomeroomNotes{Semester: semester, Notes: notes}, nil
+}
+
+type HomeroomNoteInput struct {
+	Note                 string `json:"note"`
+	SpiritualGrade       string `json:"spiritual_grade"`
+	SpiritualDescription string `json:"spiritual_description"`
+	SocialGrade          string `json:"social_grade"`
+	SocialDescription    string `json:"social_description"`
+}
+
+func (in *HomeroomNoteInput) validate() error {
+	for _, grade := range []string{in.SpiritualGrade, in.SocialGrade} {
+		if grade != "" && !contains(domain.AttitudeGrades, grade) {
+			return fmt.Errorf("%w: unknown attitude grade %q", ErrInvalidHomeroomNote, grade)
+		}
+	}
+	return nil
+}
+
+func (in *HomeroomNoteInput) apply(note *domain.HomeroomNote, recordedBy uuid.UUID) {
+	note.Note = strings.TrimSpace(in.Note)
+	note.SpiritualGrade = in.SpiritualGrade
+	note.SpiritualDescription = strings.TrimSpace(in.SpiritualDescription)
+	note.SocialGrade = in.SocialGrade
+	note.SocialDescription = strings.TrimSpace(in.SocialDescription)
+	note.RecordedByID = recordedBy
+}
+
+// CreateNote records a student's note and attitude grades for the term's
+// semester, or the active one. A student has one note per semester; later
+// changes go through UpdateNote.
+func (u *HomeroomUsecase) CreateNote(studentID string, input HomeroomNoteInput, recordedBy uuid.UUID, teacherID string, unitID uint, term postgres.TermFilter) (*domain.HomeroomNote, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+
+	student, err := u.studentRepo.GetByID(studentID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	semester, err := termSemester(u.academicRepo, student.UnitID, term)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.studentHomeroom(student, semester, teacherID); err != nil {
+		return nil, err
+	}
+	existing, err := u.homeroomRepo.GetNote(student.ID.String(), semester.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: the student already has a note this semester", ErrInvalidHomeroomNote)
+	}
+
+	note := &domain.HomeroomNote{StudentID: student.ID, SemesterID: semester.ID}
+	input.apply(note, recordedBy)
+	return note, u.homeroomRepo.CreateNote(note)
+}
+
+func (u *HomeroomUsecase) UpdateNote(id uint, input HomeroomNoteInput, recordedBy uuid.UUID, teacherID string, unitID uint) (*domain.HomeroomNote, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	note, err := u.homeroomRepo.GetNoteByID(id, unitID)
+	if err != nil {
+		return nil, err
+	}
+	student, err := u.studentRepo.GetByID(note.StudentID.String(), unitID)
+	if err != nil {
+		return nil, err
+	}
+	semester, err := u.academicRepo.GetSemesterByID(note.SemesterID, student.UnitID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.studentHomeroom(student, semester, teacherID); err != nil {
+		return nil, err
+	}
+
+	input.apply(note, recordedBy)
+	return note, u.homeroomRepo.UpdateNote(note)
+}
+
+// GetClassProjects lists the P5 projects of a class with their assessments.
+func (u *HomeroomUsecase) GetClassProjects(classID uint, teacherID string, unitID uint, term postgres.TermFilter) ([]domain.P5Project, error) {
+	class, err := u.homeroomClass(classID, teacherID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	semester, err := termSemester(u.academicRepo, class.UnitID, term)
+	if err != nil {
+		return nil, err
+	}
+	return u.homeroomRepo.GetProjectsByClass(class.ID, semester.ID)
+}
+
+type P5ProjectInput struct {
+	Theme       string `json:"theme"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func (in *P5ProjectInput) validate() error {
+	in.Theme, in.Title, in.Description = strings.TrimSpace(in.Theme), strings.TrimSpace(in.Title), strings.TrimSpace(in.Description)
+	if in.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidP5)
+	}
+	return nil
+}
+
+// CreateProject starts a P5 project for a class in the active semester.
+func (u *HomeroomUsecase) CreateProject(classID uint, input P5ProjectInput, teacherID string, unitID uint) (*domain.P5Project, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	class, err := u.homeroomClass(classID, teacherID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	semester, err := termSemester(u.academicRepo, class.UnitID, postgres.TermFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	project := &domain.P5Project{ClassID: class.ID, SemesterID: semester.ID, Theme: input.Theme, Title: input.Title, Description: input.Description}
+	return project, u.homeroomRepo.CreateProject(project)
+}
+
+// project loads a P5 project of the teacher's homeroom class.
+func (u *HomeroomUsecase) project(id uint, teacherID string, unitID uint) (*domain.P5Project, *domain.Class, error) {
+	project, err := u.homeroomRepo.GetProjectByID(id, unitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	class, err := u.homeroomClass(project.ClassID, teacherID, unitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return project, class, nil
+}
+
+func (u *HomeroomUsecase) UpdateProject(id uint, input P5ProjectInput, teacherID string, unitID uint) (*domain.P5Project, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	project, _, err := u.project(id, teacherID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	project.Theme, project.Title, project.Description = input.Theme, input.Title, input.Description
+	return project, u.homeroomRepo.UpdateProject(project)
+}
+
+func (u *HomeroomUsecase) DeleteProject(id uint, teacherID string, unitID uint) error {
+	if _, _, err := u.project(id, teacherID, unitID); err != nil {
+		return err
+	}
+	return u.homeroomRepo.DeleteProject(id, unitID)
+}
+
+type P5Entry struct {
+	StudentID uuid.UUID `json:"student_id" binding:"required"`
+	Dimension string    `json:"dimension" binding:"required"`
+	Level     string    `json:"level" binding:"required"`
+}
+
+// AssessProject replaces every assessment of a P5 project. Students must have
+// been in the project's class during its semester; each may get one level per
+// dimension.
+func (u *HomeroomUsecase) AssessProject(id uint, entries []P5Entry, recordedBy uuid.UUID, teacherID string, unitID uint) error {
+	project, class, err := u.project(id, teacherID, unitID)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	students := map[uuid.UUID]bool{}
+	assessments := make([]domain.P5Assessment, 0, len(entries))
+	for _, e := range entries {
+		if !contains(domain.P5Dimensions, e.Dimension) {
+			return fmt.Errorf("%w: unknown dimension %q", ErrInvalidP5, e.Dimension)
+		}
+		if !contains(domain.P5Levels, e.Level) {
+			return fmt.Errorf("%w: unknown level %q", ErrInvalidP5, e.Level)
+		}
+		key := e.StudentID.String() + "|" + e.Dimension
+		if seen[key] {
+			return fmt.Errorf("%w: %s is assessed twice for a student", ErrInvalidP5, e.Dimension)
+		}
+		seen[key] = true
+		students[e.StudentID] = true
+		assessments = append(assessments, domain.P5Assessment{
+			ProjectID:    project.ID,
+			StudentID:    e.StudentID,
+			Dimension:    e.Dimension,
+			Level:        e.Level,
+			RecordedByID: recordedBy,
+		})
+	}
+
+	if len(students) > 0 {
+		semester, err := u.academicRepo.GetSemesterByID(project.SemesterID, class.UnitID)
+		if err != nil {
+			return err
+		}
+		roster, err := u.roster(class, semester)
+		if err != nil {
+			return err
+		}
+		for _, id := range roster {
+			delete(students, id)
+		}
+		if len(students) > 0 {
+			return fmt.Errorf("%w: every student must be in the class", ErrInvalidP5)
+		}
+	}
+	return u.homeroomRepo.ReplaceAssessments(project.ID, assessments)
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"errors"
+	"ppi-100-sis/internal/config"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"ppi-100-sis/pkg/mailer"
+	"ppi-100-sis/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidInvitation = errors.New("invalid or expired invitation")
+
+type InvitationUsecase struct {
+	invitationRepo *postgres.InvitationRepository
+	userRepo       *postgres.UserRepository
+	mailer         mailer.Mailer
+	cfg            *config.Config
+}
+
+func NewInvitationUsecase(invitationRepo *postgres.InvitationRepository, userRepo *postgres.UserRepository, mailSender mailer.Mailer, cfg *config.Config) *InvitationUsecase {
+	return &InvitationUsecase{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		mailer:         mailSender,
+		cfg:            cfg,
+	}
+}
+
+// CreateInvitation stores an invitation and emails its code. Admin roles can
+// only be handed out by Super Admin. The code is returned as well so it can
+// be shared by other means.
+func (u *InvitationUsecase) CreateInvitation(email, name string, roleID, unitID uint, inviterID uuid.UUID, inviterRoleID uint) (*domain.Invitation, string, error) {
+	if roleID < domain.RoleSuperAdmin || roleID > domain.RoleOrangTua {
+		return nil, "", errors.New("invalid role")
+	}
+	if domain.IsAdminRole(roleID) && inviterRoleID != domain.RoleSuperAdmin {
+		return nil, "", errors.New("only Super Admin can invite administrators")
+	}
+	if existing, _ := u.userRepo.FindByEmail(email); existing != nil {
+		return nil, "", errors.New("email already registered")
+	}
+
+	invitation := &domain.Invitation{
+		Email:       email,
+		Name:        name,
+		RoleID:      roleID,
+		UnitID:      unitID,
+		InvitedByID: inviterID,
+		ExpiresAt:   time.Now().Add(u.cfg.InvitationTTL),
+	}
+	if err := u.invitationRepo.Create(invitation); err != nil {
+		return nil, "", err
+	}
+
+	code := utils.SignValue(invitation.ID.String(), u.cfg.JWTSecret)
+	link := u.cfg.AppURL + "/accept-invitation?code=" + code
+	body := "Halo " + name + ",\n\n" +
+		"Anda diundang untuk bergabung dengan Sistem Informasi PPI 100. " +
+		"Buka tautan berikut untuk membuat password akun Anda:\n\n" + link + "\n\n" +
+		"Undangan ini berlaku selama " + u.cfg.InvitationTTL.String() + "."
+	if err := u.mailer.Send(email, "Undangan Akun PPI 100", body); err != nil {
+		return nil, "", err
+	}
+
+	return invitation, code, nil
+}
+
+func (u *InvitationUsecase) GetInvitations(unitID uint) ([]domain.Invitation, error) {
+	return u.invitationRepo.GetAll(unitID)
+}
+
+func (u *InvitationUsecase) DeleteInvitation(id uuid.UUID, unitID uint) error {
+	return u.invitationRepo.Delete(id, unitID)
+}
+
+// AcceptInvitation redeems a signed invite code and creates the account with
+// the role and unit chosen by the inviting admin.
+func (u *InvitationUsecase) AcceptInvitation(code, name, password string) error {
+	value, ok := utils.VerifySignedValue(code, u.cfg.JWTSecret)
+	if !ok {
+		return ErrInvalidInvitation
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return ErrInvalidInvitation
+	}
+
+	invitation, err := u.invitationRepo.GetByID(id)
+	if err != nil {
+		return ErrInvalidInvitation
+	}
+	if invitation.AcceptedAt != nil || time.Now().After(invitation.ExpiresAt) {
+		return ErrInvalidInvitation
+	}
+
+	if name == "" {
+		name = invitation.Name
+	}
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if existing, _ := u.userRepo.FindByEmail(invitation.Email); existing != nil {
+		return errors.New("email already registered")
+	}
+
+	if err := utils.ValidatePassword(password, u.cfg); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	user := &domain.User{
+		Name:         name,
+		Email:        invitation.Email,
+		PasswordHash: hashedPassword,
+		RoleID:       invitation.RoleID,
+		UnitID:       invitation.UnitID,
+	}
+	if err := u.invitationRepo.Accept(invitation, user); err != nil {
+		return ErrInvalidInvitation
+	}
+	return nil
+}
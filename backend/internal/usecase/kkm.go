@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidKKM     = errors.New("invalid KKM")
+	ErrInvalidAttempt = errors.New("invalid remedial attempt")
+)
+
+// Remedial policies
+const (
+	RemedialCap     = "cap"     // A remedial raises the score to at most the KKM
+	RemedialReplace = "replace" // A better remedial result replaces the score
+)
+
+// kkmTable resolves the KKM of a subject for a class in a semester: the
+// class's own value, else the subject's, else the configured default.
+type kkmTable struct {
+	values   map[[3]uint]float64 // Semester, subject, class
+	fallback float64
+}
+
+func (u *AcademicUsecase) loadKKM(unitID uint, term postgres.TermFilter) (*kkmTable, error) {
+	kkms, err := u.academicRepo.GetKKMs(unitID, term)
+	if err != nil {
+		return nil, err
+	}
+	table := &kkmTable{values: map[[3]uint]float64{}, fallback: float64(u.cfg.DefaultKKM)}
+	for _, k := range kkms {
+		table.values[[3]uint{k.SemesterID, k.SubjectID, k.ClassID}] = k.Value
+	}
+	return table, nil
+}
+
+func (t *kkmTable) For(semesterID, subjectID, classID uint) float64 {
+	if v, ok := t.values[[3]uint{semesterID, subjectID, classID}]; ok {
+		return v
+	}
+	if v, ok := t.values[[3]uint{semesterID, subjectID, 0}]; ok {
+		return v
+	}
+	return t.fallback
+}
+
+// finalScore applies the remedial attempts of a score under the policy. A
+// remedial never lowers a score; enrichment attempts don't change it.
+func finalScore(score *domain.Score, kkm float64, policy string) float64 {
+	final := score.Value
+	for _, attempt := range score.Attempts {
+		if attempt.Kind != domain.AttemptRemedial {
+			continue
+		}
+		value := attempt.Value
+		if policy != RemedialReplace && value > kkm {
+			value = kkm
+		}
+		if value > final {
+			final = value
+		}
+	}
+	return final
+}
+
+// ScoreResult is a score with its KKM and its value after remedials.
+type ScoreResult struct {
+	domain.Score
+	KKM      float64 `json:"kkm"`
+	Final    float64 `json:"final"`
+	BelowKKM bool    `json:"below_kkm"`
+}
+
+func (u *AcademicUsecase) scoreResult(score *domain.Score, kkm float64) ScoreResult {
+	final := finalScore(score, kkm, u.cfg.RemedialPolicy)
+	return ScoreResult{Score: *score, KKM: kkm, Final: final, BelowKKM: final < kkm}
+}
+
+func (u *AcademicUsecase) GetKKMs(unitID uint, term postgres.TermFilter) ([]domain.KKM, error) {
+	return u.academicRepo.GetKKMs(unitID, term)
+}
+
+// SetKKM sets the KKM of a subject in a semester, for one class or, with
+// classID 0, for every class.
+func (u *AcademicUsecase) SetKKM(subjectID, classID, semesterID uint, value float64, unitID uint) (*domain.KKM, error) {
+	if value <= 0 || value > 100 {
+		return nil, fmt.Errorf("%w: the KKM must be between 0 and 100", ErrInvalidKKM)
+	}
+	subject, err := u.academicRepo.GetSubjectByID(subjectID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	semester, err := u.academicRepo.GetSemesterByID(semesterID, subject.UnitID)
+	if err != nil {
+		return nil, err
+	}
+	if classID != 0 {
+		if _, err := u.academicRepo.GetClassByID(classID, subject.UnitID); err != nil {
+			return nil, err
+		}
+	}
+
+	kkm := &domain.KKM{SubjectID: subjectID, ClassID: classID, SemesterID: semester.ID, Value: value}
+	return kkm, u.academicRepo.SaveKKM(kkm)
+}
+
+func (u *AcademicUsecase) DeleteKKM(id, unitID uint) error {
+	return u.academicRepo.DeleteKKM(id, unitID)
+}
+
+// RecordScoreAttempt adds a remedial for a score below its KKM, or an
+// enrichment for one that passed.
+func (u *AcademicUsecase) RecordScoreAttempt(scoreID, kind string, value float64, note string, recordedBy uuid.UUID, teacherID string, unitID uint) (*domain.ScoreAttempt, error) {
+	if kind != domain.AttemptRemedial && kind != domain.AttemptEnrichment {
+		return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidAttempt, kind)
+	}
+	if value < 0 || value > 100 {
+		return nil, fmt.Errorf("%w: scores must be between 0 and 100", ErrInvalidAttempt)
+	}
+
+	score, err := u.academicRepo.GetScoreByID(scoreID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkSubjectTeacher(teacherID, score.Student.ClassID, score.SubjectID, score.Student.UnitID); err != nil {
+		return nil, err
+	}
+	table, err := u.loadKKM(score.Student.UnitID, postgres.TermFilter{SemesterID: score.SemesterID})
+	if err != nil {
+		return nil, err
+	}
+	kkm := table.For(score.SemesterID, score.SubjectID, score.Student.ClassID)
+	switch {
+	case kind == domain.AttemptRemedial && score.Value >= kkm:
+		return nil, fmt.Errorf("%w: the score already meets the KKM of %g", ErrInvalidAttempt, kkm)
+	case kind == domain.AttemptEnrichment && score.Value < kkm:
+		return nil, fmt.Errorf("%w: enrichment is for scores meeting the KKM of %g", ErrInvalidAttempt, kkm)
+	}
+
+	attempt := &domain.ScoreAttempt{ScoreID: score.ID, Kind: kind, Value: value, Note: note, RecordedByID: recordedBy}
+	return attempt, u.academicRepo.CreateScoreAttempt(attempt)
+}
+
+func (u *AcademicUsecase) DeleteScoreAttempt(id, teacherID string, unitID uint) error {
+	attempt, err := u.academicRepo.GetScoreAttemptByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	score, err := u.academicRepo.GetScoreByID(attempt.ScoreID.String(), unitID)
+	if err != nil {
+		return err
+	}
+	if err := u.checkSubjectTeacher(teacherID, score.Student.ClassID, score.SubjectID, score.Student.UnitID); err != nil {
+		return err
+	}
+	return u.academicRepo.DeleteScoreAttempt(id, unitID)
+}
+
+// RemedialNeed lists the scores of a student in a subject that are still
+// below the KKM after remedials, and their graded tasks below it.
+type RemedialNeed struct {
+	Student domain.Student `json:"student"`
+	Subject domain.Subject `json:"subject"`
+	KKM     float64        `json:"kkm"`
+	Scores  []ScoreResult  `json:"scores"`
+	Tasks   []RemedialTask `json:"tasks"`
+}
+
+// RemedialTask is a graded submission below the KKM. Tasks have no remedial
+// attempts; the teacher regrades the submission instead.
+type RemedialTask struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	TaskID       uint      `json:"task_id"`
+	Title        string    `json:"title"`
+	Grade        float64   `json:"grade"`
+}
+
+// GetRemedialList lists the students needing remediation in the term. With
+// a teacherID only the classes and subjects the teacher teaches are included.
+func (u *AcademicUsecase) GetRemedialList(teacherID string, unitID uint, term postgres.TermFilter) ([]RemedialNeed, error) {
+	var taught map[[2]uint]bool // Class, subject
+	if teacherID != "" {
+		schedules, err := u.academicRepo.GetAllSchedules(unitID, postgres.ScheduleFilter{TeacherID: teacherID}, term)
+		if err != nil {
+			return nil, err
+		}
+		taught = map[[2]uint]bool{}
+		for _, s := range schedules {
+			taught[[2]uint{s.ClassID, s.SubjectID}] = true
+		}
+	}
+
+	scores, err := u.academicRepo.GetScores(unitID, postgres.ScoreFilter{}, term)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := u.elearningRepo.GetGradedSubmissions(unitID, term)
+	if err != nil {
+		return nil, err
+	}
+	table, err := u.loadKKM(unitID, term)
+	if err != nil {
+		return nil, err
+	}
+
+	needs := []RemedialNeed{}
+	index := map[string]int{}
+	need := func(student *domain.Student, subject *domain.Subject, kkm float64) *RemedialNeed {
+		key := fmt.Sprintf("%s-%d", student.ID, subject.ID)
+		at, ok := index[key]
+		if !ok {
+			at = len(needs)
+			index[key] = at
+			needs = append(needs, RemedialNeed{Student: *student, Subject: *subject, KKM: kkm, Scores: []ScoreResult{}, Tasks: []RemedialTask{}})
+		}
+		return &needs[at]
+	}
+	for i := range scores {
+		score := &scores[i]
+		if score.Student == nil || score.Subject == nil {
+			continue
+		}
+		if taught != nil && !taught[[2]uint{score.Student.ClassID, score.SubjectID}] {
+			continue
+		}
+		result := u.scoreResult(score, table.For(score.SemesterID, score.SubjectID, score.Student.ClassID))
+		if !result.BelowKKM {
+			continue
+		}
+
+		n := need(score.Student, score.Subject, result.KKM)
+		result.Student, result.Subject = nil, nil
+		n.Scores = append(n.Scores, result)
+	}
+	for i := range submissions {
+		sub := &submissions[i]
+		if sub.Student == nil {
+			continue
+		}
+		if taught != nil && !taught[[2]uint{sub.Student.ClassID, sub.Task.SubjectID}] {
+			continue
+		}
+		var semesterID uint
+		if sub.Task.SemesterID != nil {
+			semesterID = *sub.Task.SemesterID
+		}
+		kkm := table.For(semesterID, sub.Task.SubjectID, sub.Student.ClassID)
+		if sub.Grade >= kkm {
+			continue
+		}
+
+		n := need(sub.Student, &sub.Task.Subject, kkm)
+		n.Tasks = append(n.Tasks, RemedialTask{SubmissionID: sub.ID, TaskID: sub.TaskID, Title: sub.Task.Title, Grade: sub.Grade})
+	}
+
+	sort.SliceStable(needs, func(a, b int) bool {
+		x, y := needs[a], needs[b]
+		if x.Student.Class.Name != y.Student.Class.Name {
+			return x.Student.Class.Name < y.Student.Class.Name
+		}
+		if x.Student.User.Name != y.Student.User.Name {
+			return x.Student.User.Name < y.Student.User.Name
+		}
+		return x.Subject.Name < y.Subject.Name
+	})
+	return needs, nil
+}
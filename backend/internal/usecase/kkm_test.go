@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"ppi-100-sis/internal/domain"
+	"testing"
+)
+
+func scoreWith(value float64, attempts ...domain.ScoreAttempt) *domain.Score {
+	return &domain.Score{Value: value, Attempts: attempts}
+}
+
+func remedial(value float64) domain.ScoreAttempt {
+	return domain.ScoreAttempt{Kind: domain.AttemptRemedial, Value: value}
+}
+
+func TestFinalScore(t *testing.T) {
+	const kkm = 75
+	tests := []struct {
+		name   string
+		score  *domain.Score
+		policy string
+		want   float64
+	}{
+		{"no attempts", scoreWith(60), RemedialCap, 60},
+		{"cap limits a remedial to the KKM", scoreWith(60, remedial(90)), RemedialCap, 75},
+		{"cap keeps a remedial below the KKM", scoreWith(60, remedial(70)), RemedialCap, 70},
+		{"replace takes the remedial result", scoreWith(60, remedial(90)), RemedialReplace, 90},
+		{"a worse remedial never lowers the score", scoreWith(60, remedial(50)), RemedialReplace, 60},
+		{"best of several remedials", scoreWith(50, remedial(65), remedial(72), remedial(68)), RemedialReplace, 72},
+		{"cap applies to each remedial", scoreWith(50, remedial(80), remedial(95)), RemedialCap, 75},
+		{"enrichment is recorded only", scoreWith(80, domain.ScoreAttempt{Kind: domain.AttemptEnrichment, Value: 100}), RemedialReplace, 80},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := finalScore(tt.score, kkm, tt.policy); got != tt.want {
+				t.Errorf("finalScore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKKMTableFor(t *testing.T) {
+	table := &kkmTable{
+		values: map[[3]uint]float64{
+			{1, 10, 0}: 70, // Subject 10, every class
+			{1, 10, 5}: 80, // Subject 10, class 5 only
+			{2, 10, 0}: 65, // Another semester
+		},
+		fallback: 75,
+	}
+	tests := []struct {
+		name                           string
+		semesterID, subjectID, classID uint
+		want                           float64
+	}{
+		{"class value wins", 1, 10, 5, 80},
+		{"subject value for other classes", 1, 10, 6, 70},
+		{"semester is part of the key", 2, 10, 5, 65},
+		{"default without any value", 1, 11, 5, 75},
+		{"default in a semester without values", 3, 10, 5, 75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := table.For(tt.semesterID, tt.subjectID, tt.classID); got != tt.want {
+				t.Errorf("For = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
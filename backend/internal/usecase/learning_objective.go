@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrInvalidObjective = errors.New("invalid learning objective")
+
+// subjectObjectives resolves the learning objectives tagged on a task or an
+// assessment. Every id must belong to the subject.
+func subjectObjectives(academicRepo *postgres.AcademicRepository, subjectID uint, ids []uint) ([]domain.LearningObjective, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	unique := map[uint]bool{}
+	for _, id := range ids {
+		unique[id] = true
+	}
+	objectives, err := academicRepo.GetSubjectObjectivesByIDs(subjectID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(objectives) != len(unique) {
+		return nil, fmt.Errorf("%w: every objective must belong to the subject", ErrInvalidObjective)
+	}
+	return objectives, nil
+}
+
+// checkSubjectTaught fails unless the teacher has a lesson of the subject in
+// any class. An empty teacherID skips the check.
+func (u *AcademicUsecase) checkSubjectTaught(teacherID string, subjectID, unitID uint) error {
+	if teacherID == "" {
+		return nil
+	}
+	schedules, err := u.academicRepo.GetAllSchedules(unitID, postgres.ScheduleFilter{TeacherID: teacherID}, postgres.TermFilter{})
+	if err != nil {
+		return err
+	}
+	for _, s := range schedules {
+		if s.SubjectID == subjectID {
+			return nil
+		}
+	}
+	return ErrNotSubjectTeacher
+}
+
+func (u *AcademicUsecase) GetLearningObjectives(subjectID, unitID uint) ([]domain.LearningObjective, error) {
+	subject, err := u.academicRepo.GetSubjectByID(subjectID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	return u.academicRepo.GetLearningObjectives(subject.UnitID, []uint{subject.ID})
+}
+
+func validateObjective(objective *domain.LearningObjective) error {
+	objective.Code = strings.TrimSpace(objective.Code)
+	objective.Description = strings.TrimSpace(objective.Description)
+	if objective.Description == "" {
+		return fmt.Errorf("%w: description is required", ErrInvalidObjective)
+	}
+	return nil
+}
+
+// CreateLearningObjective adds an objective to a subject. Teachers may only
+// add objectives to subjects they teach.
+func (u *AcademicUsecase) CreateLearningObjective(subjectID uint, code, description string, position int, teacherID string, unitID uint) (*domain.LearningObjective, error) {
+	subject, err := u.academicRepo.GetSubjectByID(subjectID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkSubjectTaught(teacherID, subject.ID, subject.UnitID); err != nil {
+		return nil, err
+	}
+	objective := &domain.LearningObjective{SubjectID: subject.ID, Code: code, Description: description, Position: position}
+	if err := validateObjective(objective); err != nil {
+		return nil, err
+	}
+	return objective, u.academicRepo.CreateLearningObjective(objective)
+}
+
+func (u *AcademicUsecase) UpdateLearningObjective(id uint, code, description string, position int, teacherID string, unitID uint) (*domain.LearningObjective, error) {
+	objective, err := u.academicRepo.GetLearningObjectiveByID(id, unitID)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := u.academicRepo.GetSubjectByID(objective.SubjectID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkSubjectTaught(teacherID, subject.ID, subject.UnitID); err != nil {
+		return nil, err
+	}
+	objective.Code, objective.Description, objective.Position = code, description, position
+	if err := validateObjective(objective); err != nil {
+		return nil, err
+	}
+	return objective, u.academicRepo.UpdateLearningObjective(objective)
+}
+
+func (u *AcademicUsecase) DeleteLearningObjective(id uint, teacherID string, unitID uint) error {
+	objective, err := u.academicRepo.GetLearningObjectiveByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	subject, err := u.academicRepo.GetSubjectByID(objective.SubjectID, unitID)
+	if err != nil {
+		return err
+	}
+	if err := u.checkSubjectTaught(teacherID, subject.ID, subject.UnitID); err != nil {
+		return err
+	}
+	return u.academicRepo.DeleteLearningObjective(id, unitID)
+}
+
+// ObjectiveMastery is how well a student achieved one learning objective:
+// the mean of the scores and graded tasks tagged with it.
+type ObjectiveMastery struct {
+	ID          uint     `json:"id"`
+	Code        string   `json:"code"`
+	Description string   `json:"description"`
+	Average     *float64 `json:"average"` // Nil when nothing assessed the objective
+	Count       int      `json:"count"`
+	Achieved    bool     `json:"achieved"` // Average meets the KKM
+}
+
+// objectivePhrase turns an objective into a phrase that reads after
+// "dalam", e.g. "Menganalisis teks." becomes "menganalisis teks".
+func objectivePhrase(description string) string {
+	phrase := strings.TrimRight(strings.TrimSpace(description), ".")
+	first, size := utf8.DecodeRuneInString(phrase)
+	if next, _ := utf8.DecodeRuneInString(phrase[size:]); unicode.IsLower(next) {
+		phrase = string(unicode.ToLower(first)) + phrase[size:]
+	}
+	return phrase
+}
+
+// describeMastery writes the Kurikulum Merdeka capaian kompetensi of a
+// subject: the best achieved objective and the weakest one.
+func describeMastery(objectives []ObjectiveMastery) string {
+	var assessed []ObjectiveMastery
+	for _, o := range objectives {
+		if o.Average != nil {
+			assessed = append(assessed, o)
+		}
+	}
+	if len(assessed) == 0 {
+		return ""
+	}
+	sort.SliceStable(assessed, func(a, b int) bool { return *assessed[a].Average > *assessed[b].Average })
+
+	var sentences []string
+	best, weakest := assessed[0], assessed[len(assessed)-1]
+	if best.Achieved {
+		sentences = append(sentences, "Menunjukkan penguasaan yang baik dalam "+objectivePhrase(best.Description)+".")
+	}
+	if len(assessed) > 1 || !best.Achieved {
+		if weakest.Achieved {
+			sentences = append(sentences, "Perlu peningkatan dalam "+objectivePhrase(weakest.Description)+".")
+		} else {
+			sentences = append(sentences, "Perlu bimbingan dalam "+objectivePhrase(weakest.Description)+".")
+		}
+	}
+	return strings.Join(sentences, " ")
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import "testing"
+
+func mastery(description string, average float64, kkm float64) ObjectiveMastery {
+	return ObjectiveMastery{Description: description, Average: &average, Count: 1, Achieved: average >= kkm}
+}
+
+func TestObjectivePhrase(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Menganalisis struktur teks.", "menganalisis struktur teks"},
+		{"  Menulis puisi...  ", "menulis puisi"},
+		{"PPKn dalam kehidupan", "PPKn dalam kehidupan"}, // Acronyms keep their case
+		{"Ékspresi diri", "ékspresi diri"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := objectivePhrase(tt.in); got != tt.want {
+			t.Errorf("objectivePhrase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeMastery(t *testing.T) {
+	const kkm = 75
+	tests := []struct {
+		name       string
+		objectives []ObjectiveMastery
+		want       string
+	}{
+		{"nothing", nil, ""},
+		{"nothing assessed", []ObjectiveMastery{{Description: "Menulis puisi"}}, ""},
+		{
+			"single achieved objective",
+			[]ObjectiveMastery{mastery("Menulis puisi", 90, kkm)},
+			"Menunjukkan penguasaan yang baik dalam menulis puisi.",
+		},
+		{
+			"single failed objective",
+			[]ObjectiveMastery{mastery("Menulis puisi", 60, kkm)},
+			"Perlu bimbingan dalam menulis puisi.",
+		},
+		{
+			"best and weakest both achieved",
+			[]ObjectiveMastery{mastery("Membaca teks", 80, kkm), mastery("Menulis puisi", 95, kkm)},
+			"Menunjukkan penguasaan yang baik dalam menulis puisi. Perlu peningkatan dalam membaca teks.",
+		},
+		{
+			"weakest below the KKM",
+			[]ObjectiveMastery{mastery("Membaca teks", 90, kkm), mastery("Menulis puisi", 50, kkm), mastery("Berdiskusi", 70, kkm)},
+			"Menunjukkan penguasaan yang baik dalam membaca teks. Perlu bimbingan dalam menulis puisi.",
+		},
+		{
+			"none achieved",
+			[]ObjectiveMastery{mastery("Membaca teks", 70, kkm), mastery("Menulis puisi", 50, kkm)},
+			"Perlu bimbingan dalam menulis puisi.",
+		},
+		{
+			"unassessed objectives are skipped",
+			[]ObjectiveMastery{{Description: "Berdiskusi"}, mastery("Menulis puisi", 90, kkm)},
+			"Menunjukkan penguasaan yang baik dalam menulis puisi.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeMastery(tt.objectives); got != tt.want {
+				t.Errorf("describeMastery =\n%q, want\n%q", got, tt.want)
+			}
+		})
+	}
+}
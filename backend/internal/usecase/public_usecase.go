@@ -3,6 +3,7 @@ package usecase
 import (
 	"ppi-100-sis/internal/domain"
 	"ppi-100-sis/internal/repository/postgres"
+	"strings"
 )
 
 type PublicUsecase struct {
@@ -17,12 +18,28 @@ func (u *PublicUsecase) GetPublicTeachers() ([]domain.PublicTeacher, error) {
 	return u.publicRepo.GetAllPublicTeachers()
 }
 
+// GetDownloads leaves out old "Kalender" uploads; the academic calendar is
+// published as an iCalendar feed instead.
 func (u *PublicUsecase) GetDownloads() ([]domain.Download, error) {
-	return u.publicRepo.GetAllDownloads()
+	downloads, err := u.publicRepo.GetAllDownloads()
+	if err != nil {
+		return nil, err
+	}
+	filtered := downloads[:0]
+	for _, download := range downloads {
+		if !strings.EqualFold(download.Category, "Kalender") {
+			filtered = append(filtered, download)
+		}
+	}
+	return filtered, nil
 }
 
 func (u *PublicUsecase) GetAlumni() ([]domain.Alumni, error) {
-	return u.publicRepo.GetAllAlumni()
+	return u.publicRepo.GetAllAlumni(false)
+}
+
+func (u *PublicUsecase) GetAllAlumni() ([]domain.Alumni, error) {
+	return u.publicRepo.GetAllAlumni(true)
 }
 
 func (u *PublicUsecase) RegisterPPDB(reg domain.PPDBRegistration) error {
@@ -30,16 +47,12 @@ func (u *PublicUsecase) RegisterPPDB(reg domain.PPDBRegistration) error {
 	return u.publicRepo.CreatePPDBRegistration(&reg)
 }
 
-func (u *PublicUsecase) GetAllPPDBRegistrations() ([]domain.PPDBRegistration, error) {
-	return u.publicRepo.GetAllPPDBRegistrations()
-}
-
-func (u *PublicUsecase) GetPPDBRegistrationsByUnit(unitID uint) ([]domain.PPDBRegistration, error) {
-	return u.publicRepo.GetPPDBRegistrationsByUnit(unitID)
+func (u *PublicUsecase) GetPPDBRegistrations(unitID uint) ([]domain.PPDBRegistration, error) {
+	return u.publicRepo.GetPPDBRegistrations(unitID)
 }
 
-func (u *PublicUsecase) UpdatePPDBStatus(id string, status string) error {
-	return u.publicRepo.UpdatePPDBStatus(id, status)
+func (u *PublicUsecase) UpdatePPDBStatus(id string, status string, unitID uint) error {
+	return u.publicRepo.UpdatePPDBStatus(id, status, unitID)
 }
 
 func (u *PublicUsecase) CreatePublicTeacher(teacher domain.PublicTeacher) error {
@@ -82,7 +95,15 @@ func (u *PublicUsecase) DeleteDownload(id string) error {
 	return u.publicRepo.DeleteDownload(id)
 }
 
-func (u *PublicUsecase) UpdateAlumni(alumni domain.Alumni) error {
+// UpdateAlumni keeps the link to the student record, which is not editable.
+func (u *PublicUsecase) UpdateAlumni(id uint, alumni domain.Alumni) error {
+	existing, err := u.publicRepo.GetAlumniByID(id)
+	if err != nil {
+		return err
+	}
+	alumni.ID = existing.ID
+	alumni.StudentID = existing.StudentID
+	alumni.CreatedAt = existing.CreatedAt
 	return u.publicRepo.UpdateAlumni(&alumni)
 }
 
@@ -90,6 +111,6 @@ func (u *PublicUsecase) DeleteAlumni(id string) error {
 	return u.publicRepo.DeleteAlumni(id)
 }
 
-func (u *PublicUsecase) DeletePPDBRegistration(id string) error {
-	return u.publicRepo.DeletePPDBRegistration(id)
+func (u *PublicUsecase) DeletePPDBRegistration(id string, unitID uint) error {
+	return u.publicRepo.DeletePPDBRegistration(id, unitID)
 }
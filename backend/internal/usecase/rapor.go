@@ -0,0 +1,527 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"ppi-100-sis/internal/config"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"ppi-100-sis/pkg/pdf"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotHomeroomTeacher = errors.New("you are not the homeroom teacher of this class")
+	ErrNoSemester         = errors.New("no semester selected and the unit has no active semester")
+	ErrNotEnrolled        = errors.New("the student was not enrolled in that semester")
+)
+
+// RaporUsecase renders the official report card (rapor) as PDF, one student
+// at a time or a whole class as a ZIP.
+type RaporUsecase struct {
+	academicRepo    *postgres.AcademicRepository
+	studentRepo     *postgres.StudentRepository
+	homeroomRepo    *postgres.HomeroomRepository
+	enrollmentRepo  *postgres.EnrollmentRepository
+	academicUsecase *AcademicUsecase
+	studentUsecase  *StudentUsecase
+	cfg             *config.Config
+}
+
+func NewRaporUsecase(academicRepo *postgres.AcademicRepository, studentRepo *postgres.StudentRepository, homeroomRepo *postgres.HomeroomRepository, enrollmentRepo *postgres.EnrollmentRepository, academicUsecase *AcademicUsecase, studentUsecase *StudentUsecase, cfg *config.Config) *RaporUsecase {
+	return &RaporUsecase{
+		academicRepo:    academicRepo,
+		studentRepo:     studentRepo,
+		homeroomRepo:    homeroomRepo,
+		enrollmentRepo:  enrollmentRepo,
+		academicUsecase: academicUsecase,
+		studentUsecase:  studentUsecase,
+		cfg:             cfg,
+	}
+}
+
+// gradePredicate is the letter printed next to a report-card score: D below
+// the KKM, then C, B and A over equal bands from the KKM up to 100.
+func gradePredicate(score, kkm float64) string {
+	band := (100 - kkm) / 3
+	switch {
+	case score < kkm:
+		return "D"
+	case score < kkm+band:
+		return "C"
+	case score < kkm+2*band:
+		return "B"
+	}
+	return "A"
+}
+
+var unitLabels = map[uint]string{
+	domain.UnitMTS: "Madrasah Tsanawiyah",
+	domain.UnitMA:  "Madrasah Aliyah",
+}
+
+var attitudeLabels = map[string]string{
+	domain.AttitudeVeryGood:  "Sangat Baik",
+	domain.AttitudeGood:      "Baik",
+	domain.AttitudeFair:      "Cukup",
+	domain.AttitudeNeedsWork: "Kurang",
+}
+
+var p5DimensionLabels = map[string]string{
+	domain.P5Faith:        "Beriman, Bertakwa kepada Tuhan YME, dan Berakhlak Mulia",
+	domain.P5Diversity:    "Berkebinekaan Global",
+	domain.P5Cooperation:  "Bergotong Royong",
+	domain.P5Independence: "Mandiri",
+	domain.P5CriticalMind: "Bernalar Kritis",
+	domain.P5Creativity:   "Kreatif",
+}
+
+var p5LevelLabels = map[string]string{
+	domain.P5NotYet:     "Belum Berkembang",
+	domain.P5Starting:   "Mulai Berkembang",
+	domain.P5AsExpected: "Berkembang Sesuai Harapan",
+	domain.P5Exceeding:  "Sangat Berkembang",
+}
+
+var monthNames = [...]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+func formatIndonesianDate(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), monthNames[t.Month()-1], t.Year())
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+func raporFileName(student *domain.Student) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(student.User.Name, "-"), "-")
+	return fmt.Sprintf("rapor-%s-%s.pdf", student.NISN, name)
+}
+
+// rapor is everything printed on one student's report card.
+type rapor struct {
+	student    *domain.Student
+	class      *domain.Class
+	semester   *domain.Semester
+	grades     []SubjectGrade
+	attendance *AttendanceRecap
+	note       *domain.HomeroomNote // Nil until the wali kelas writes one
+	projects   []domain.P5Project   // Only the student's own assessments
+}
+
+// termSemester is the semester the term points at, or the unit's active one.
+func termSemester(academicRepo *postgres.AcademicRepository, unitID uint, term postgres.TermFilter) (*domain.Semester, error) {
+	if term.SemesterID != 0 {
+		return academicRepo.GetSemesterByID(term.SemesterID, unitID)
+	}
+	semester, err := academicRepo.GetActiveSemester(unitID)
+	if err != nil {
+		return nil, ErrNoSemester
+	}
+	return semester, nil
+}
+
+// checkHomeroom fails unless teacherID is the homeroom teacher of the class.
+// An empty teacherID skips the check.
+func checkHomeroom(class *domain.Class, teacherID string) error {
+	if teacherID == "" {
+		return nil
+	}
+	if class.HomeroomTeacherID == nil || class.HomeroomTeacherID.String() != teacherID {
+		return ErrNotHomeroomTeacher
+	}
+	return nil
+}
+
+// enrollmentIn returns the class of the last enrollment in force during the
+// semester, mirroring the roster query. Enrollments are newest first.
+func enrollmentIn(enrollments []domain.ClassEnrollment, semester *domain.Semester) (uint, bool) {
+	for _, e := range enrollments {
+		if e.StartedAt.After(semester.EndDate) || (e.EndedAt != nil && !e.EndedAt.After(semester.StartDate)) {
+			continue
+		}
+		if e.AcademicYearID != nil && *e.AcademicYearID != semester.AcademicYearID {
+			continue
+		}
+		return e.ClassID, true
+	}
+	return 0, false
+}
+
+// semesterClass is the class the student was in during the semester.
+func semesterClass(enrollmentRepo *postgres.EnrollmentRepository, student *domain.Student, semester *domain.Semester) (uint, error) {
+	enrollments, err := enrollmentRepo.GetByStudent(student.ID.String(), student.UnitID)
+	if err != nil {
+		return 0, err
+	}
+	if len(enrollments) == 0 {
+		return student.ClassID, nil // Enrolled before the history was recorded
+	}
+	classID, ok := enrollmentIn(enrollments, semester)
+	if !ok {
+		return 0, ErrNotEnrolled
+	}
+	return classID, nil
+}
+
+func (u *RaporUsecase) load(student *domain.Student, class *domain.Class, semester *domain.Semester) (*rapor, error) {
+	term := postgres.TermFilter{SemesterID: semester.ID}
+	grades, err := u.academicUsecase.GetStudentReportCard(student.ID, class.ID, student.UnitID, term)
+	if err != nil {
+		return nil, err
+	}
+	attendance, err := u.studentUsecase.GetAttendanceRecap(student.ID.String(), student.UnitID, term)
+	if err != nil {
+		return nil, err
+	}
+	note, err := u.homeroomRepo.GetNote(student.ID.String(), semester.ID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := u.homeroomRepo.GetProjectsByClass(class.ID, semester.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range projects {
+		var own []domain.P5Assessment
+		for _, a := range projects[i].Assessments {
+			if a.StudentID == student.ID {
+				own = append(own, a)
+			}
+		}
+		projects[i].Assessments = own
+	}
+	return &rapor{student: student, class: class, semester: semester, grades: grades, attendance: attendance, note: note, projects: projects}, nil
+}
+
+// StudentRapor renders one student's rapor and its file name.
+func (u *RaporUsecase) StudentRapor(studentID, teacherID string, unitID uint, term postgres.TermFilter) ([]byte, string, error) {
+	student, err := u.studentRepo.GetByID(studentID, unitID)
+	if err != nil {
+		return nil, "", err
+	}
+	semester, err := termSemester(u.academicRepo, student.UnitID, term)
+	if err != nil {
+		return nil, "", err
+	}
+	classID, err := semesterClass(u.enrollmentRepo, student, semester)
+	if err != nil {
+		return nil, "", err
+	}
+	class, err := u.academicRepo.GetClassWithHomeroom(classID, student.UnitID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkHomeroom(class, teacherID); err != nil {
+		return nil, "", err
+	}
+
+	r, err := u.load(student, class, semester)
+	if err != nil {
+		return nil, "", err
+	}
+	doc := pdf.New("Rapor " + student.User.Name)
+	u.render(doc, r)
+	return doc.Bytes(), raporFileName(student), nil
+}
+
+// ClassRapors renders the rapor of every student in a class, one PDF each,
+// bundled in a ZIP.
+func (u *RaporUsecase) ClassRapors(classID uint, teacherID string, unitID uint, term postgres.TermFilter) ([]byte, string, error) {
+	class, err := u.academicRepo.GetClassWithHomeroom(classID, unitID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkHomeroom(class, teacherID); err != nil {
+		return nil, "", err
+	}
+	semester, err := termSemester(u.academicRepo, class.UnitID, term)
+	if err != nil {
+		return nil, "", err
+	}
+	students, err := u.enrollmentRepo.GetClassRoster(class.ID, semester, class.UnitID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for i := range students {
+		r, err := u.load(&students[i], class, semester)
+		if err != nil {
+			return nil, "", err
+		}
+		doc := pdf.New("Rapor " + students[i].User.Name)
+		u.render(doc, r)
+		w, err := archive.Create(raporFileName(&students[i]))
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := w.Write(doc.Bytes()); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, "", err
+	}
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(class.Name, "-"), "-")
+	return buf.Bytes(), fmt.Sprintf("rapor-%s.zip", name), nil
+}
+
+// Page layout in points
+const (
+	raporLeft   = 50.0
+	raporRight  = pdf.PageWidth - 50
+	raporBottom = pdf.PageHeight - 60
+)
+
+func (u *RaporUsecase) render(doc *pdf.Document, r *rapor) {
+	page := doc.AddPage()
+	center := pdf.PageWidth / 2
+
+	// School header
+	page.TextCenter(center, 60, 14, true, "LAPORAN HASIL BELAJAR PESERTA DIDIK")
+	page.TextCenter(center, 80, 12, true, u.cfg.SchoolName)
+	if label := unitLabels[r.student.UnitID]; label != "" {
+		page.TextCenter(center, 95, 10, false, label)
+	}
+	page.TextCenter(center, 109, 9, false, u.cfg.SchoolAddress)
+	page.Line(raporLeft, 118, raporRight, 118, 1.5)
+
+	// Student identity
+	homeroom, homeroomNIP := "-", ""
+	if t := r.class.HomeroomTeacher; t != nil {
+		homeroom, homeroomNIP = t.User.Name, t.NIP
+	}
+	year := ""
+	if r.semester.AcademicYear != nil {
+		year = r.semester.AcademicYear.Name
+	}
+	identity := [][2][2]string{
+		{{"Nama", r.student.User.Name}, {"Tahun Ajaran", year}},
+		{{"NISN", r.student.NISN}, {"Semester", r.semester.Name}},
+		{{"Kelas", r.class.Name}, {"Wali Kelas", homeroom}},
+	}
+	y := 140.0
+	for _, row := range identity {
+		page.Text(raporLeft, y, 10, false, row[0][0])
+		page.Text(raporLeft+70, y, 10, false, ": "+row[0][1])
+		page.Text(330, y, 10, false, row[1][0])
+		page.Text(400, y, 10, false, ": "+row[1][1])
+		y += 15
+	}
+
+	// Sections are lettered in order; the P5 section only shows when the
+	// class ran a project.
+	section := 'A' - 1
+	heading := func(title string) {
+		section++
+		page.Text(raporLeft, y, 11, true, fmt.Sprintf("%c. %s", section, title))
+		y += 8
+	}
+	// box draws wrapped text in a bordered cell at least minHeight tall.
+	box := func(x, w float64, text string, minHeight float64) {
+		lines := pdf.Wrap(text, 9, false, w-8)
+		h := max(float64(len(lines))*11+8, minHeight)
+		if y+h > raporBottom {
+			page = doc.AddPage()
+			y = 60
+		}
+		page.Rect(x, y, w, h, 0.5)
+		for i, line := range lines {
+			page.Text(x+4, y+12+float64(i)*11, 9, false, line)
+		}
+		y += h
+	}
+
+	// Attitude
+	y += 10
+	heading("Sikap")
+	attitudes := [][3]string{{"Spiritual"}, {"Sosial"}}
+	if n := r.note; n != nil {
+		attitudes[0][1], attitudes[0][2] = attitudeLabels[n.SpiritualGrade], n.SpiritualDescription
+		attitudes[1][1], attitudes[1][2] = attitudeLabels[n.SocialGrade], n.SocialDescription
+	}
+	for _, a := range attitudes {
+		if y+40 > raporBottom {
+			page = doc.AddPage()
+			y = 60
+		}
+		predicate := a[1]
+		if predicate == "" {
+			predicate = "-"
+		}
+		page.FillRect(raporLeft, y, raporRight-raporLeft, 16, 0.9)
+		page.Rect(raporLeft, y, raporRight-raporLeft, 16, 0.5)
+		page.Text(raporLeft+4, y+11.5, 9, true, a[0])
+		page.Text(raporLeft+120, y+11.5, 9, false, "Predikat: "+predicate)
+		y += 16
+		box(raporLeft, raporRight-raporLeft, a[2], 24)
+	}
+
+	// Scores
+	y += 20
+	heading("Nilai Akademik")
+	columns := []struct {
+		title string
+		x, w  float64
+	}{
+		{"No", raporLeft, 25}, {"Mata Pelajaran", raporLeft + 25, 215}, {"KKM", raporLeft + 240, 50},
+		{"Nilai", raporLeft + 290, 60}, {"Predikat", raporLeft + 350, 55}, {"Keterangan", raporLeft + 405, raporRight - raporLeft - 405},
+	}
+	row := func(cells []string, bold bool, shade bool) {
+		if y+16 > raporBottom {
+			page = doc.AddPage()
+			y = 60
+		}
+		if shade {
+			page.FillRect(raporLeft, y, raporRight-raporLeft, 16, 0.9)
+		}
+		for i, c := range columns {
+			page.Rect(c.x, y, c.w, 16, 0.5)
+			if i == 1 {
+				page.Text(c.x+4, y+11.5, 9, bold, cells[i])
+			} else {
+				page.TextCenter(c.x+c.w/2, y+11.5, 9, bold, cells[i])
+			}
+		}
+		y += 16
+	}
+	// Capaian kompetensi, under the subject it describes
+	competency := func(text string) {
+		x, w := columns[1].x, raporRight-columns[1].x
+		lines := pdf.Wrap(text, 8, false, w-8)
+		h := float64(len(lines))*10 + 6
+		if y+h > raporBottom {
+			page = doc.AddPage()
+			y = 60
+		}
+		page.Rect(columns[0].x, y, columns[0].w, h, 0.5)
+		page.Rect(x, y, w, h, 0.5)
+		for i, line := range lines {
+			page.Text(x+4, y+11+float64(i)*10, 8, false, line)
+		}
+		y += h
+	}
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.title
+	}
+	row(header, true, true)
+	for i, g := range r.grades {
+		status := "Tuntas"
+		if !g.Passed {
+			status = "Belum Tuntas"
+		}
+		row([]string{fmt.Sprint(i + 1), g.SubjectName, fmt.Sprintf("%g", g.KKM), fmt.Sprintf("%.2f", g.Average), gradePredicate(g.Average, g.KKM), status}, false, false)
+		if g.Description != "" {
+			competency(g.Description)
+		}
+	}
+	if len(r.grades) == 0 {
+		row([]string{"", "Belum ada nilai", "", "", "", ""}, false, false)
+	}
+
+	// P5 projects, one table per project
+	if len(r.projects) > 0 {
+		if y+90 > raporBottom {
+			page = doc.AddPage()
+			y = 40
+		}
+		y += 20
+		heading("Projek Penguatan Profil Pelajar Pancasila")
+		for _, p := range r.projects {
+			if y+60 > raporBottom {
+				page = doc.AddPage()
+				y = 60
+			}
+			y += 6
+			title := p.Title
+			if p.Theme != "" {
+				title = p.Theme + ": " + p.Title
+			}
+			for _, line := range pdf.Wrap(title, 10, true, raporRight-raporLeft) {
+				y += 13
+				page.Text(raporLeft, y, 10, true, line)
+			}
+			y += 4
+			if p.Description != "" {
+				box(raporLeft, raporRight-raporLeft, p.Description, 0)
+			}
+			levels := map[string]string{}
+			for _, a := range p.Assessments {
+				levels[a.Dimension] = p5LevelLabels[a.Level]
+			}
+			for _, d := range domain.P5Dimensions {
+				if y+16 > raporBottom {
+					page = doc.AddPage()
+					y = 60
+				}
+				level := levels[d]
+				if level == "" {
+					level = "-"
+				}
+				page.Rect(raporLeft, y, 300, 16, 0.5)
+				page.Rect(raporLeft+300, y, raporRight-raporLeft-300, 16, 0.5)
+				page.Text(raporLeft+4, y+11.5, 9, false, p5DimensionLabels[d])
+				page.TextCenter((raporLeft+300+raporRight)/2, y+11.5, 9, false, level)
+				y += 16
+			}
+		}
+	}
+
+	// Attendance, counted in lessons (JP)
+	if y+110 > raporBottom {
+		page = doc.AddPage()
+		y = 40
+	}
+	y += 20
+	heading("Ketidakhadiran")
+	absences := [][2]string{
+		{"Sakit", "Sick"}, {"Izin", "Permission"}, {"Tanpa Keterangan", "Absent"}, {"Terlambat", "Late"},
+	}
+	for _, a := range absences {
+		page.Rect(raporLeft, y, 160, 16, 0.5)
+		page.Rect(raporLeft+160, y, 80, 16, 0.5)
+		page.Text(raporLeft+4, y+11.5, 9, false, a[0])
+		page.TextCenter(raporLeft+200, y+11.5, 9, false, fmt.Sprintf("%d JP", r.attendance.Statuses[a[1]]))
+		y += 16
+	}
+
+	// Homeroom notes
+	if y+200 > raporBottom {
+		page = doc.AddPage()
+		y = 40
+	}
+	y += 20
+	heading("Catatan Wali Kelas")
+	note := ""
+	if r.note != nil {
+		note = r.note.Note
+	}
+	box(raporLeft, raporRight-raporLeft, note, 50)
+
+	// Signatures, kept together when a long note pushed them down
+	if y+200 > raporBottom {
+		page = doc.AddPage()
+		y = 40
+	}
+	y += 30
+	signedAt := time.Now().In(u.cfg.SchoolLocation)
+	page.TextCenter(450, y, 10, false, fmt.Sprintf("%s, %s", u.cfg.SchoolCity, formatIndonesianDate(signedAt)))
+	y += 15
+	page.TextCenter(145, y, 10, false, "Orang Tua/Wali")
+	page.TextCenter(450, y, 10, false, "Wali Kelas")
+	y += 55
+	page.TextCenter(145, y, 10, false, "(.............................................)")
+	page.TextCenter(450, y, 10, true, homeroom)
+	if homeroomNIP != "" {
+		page.TextCenter(450, y+13, 9, false, "NIP. "+homeroomNIP)
+	}
+	y += 35
+	page.TextCenter(center, y, 10, false, "Mengetahui,")
+	page.TextCenter(center, y+13, 10, false, "Kepala Madrasah")
+	page.TextCenter(center, y+68, 10, false, "(.............................................)")
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"ppi-100-sis/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestGradePredicate(t *testing.T) {
+	tests := []struct {
+		score, kkm float64
+		want       string
+	}{
+		{74.9, 75, "D"},
+		{75, 75, "C"},
+		{83.3, 75, "C"},
+		{83.4, 75, "B"},
+		{91.6, 75, "B"},
+		{91.7, 75, "A"},
+		{100, 75, "A"},
+		{60, 70, "D"},
+		{70, 70, "C"},
+		{80, 70, "B"},
+		{90, 70, "A"},
+		{65, 60, "C"}, // A low KKM widens every band
+	}
+	for _, tt := range tests {
+		if got := gradePredicate(tt.score, tt.kkm); got != tt.want {
+			t.Errorf("gradePredicate(%v, %v) = %q, want %q", tt.score, tt.kkm, got, tt.want)
+		}
+	}
+}
+
+func date(month time.Month, day, year int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func enrollment(classID, yearID uint, started time.Time, ended *time.Time) domain.ClassEnrollment {
+	return domain.ClassEnrollment{ClassID: classID, AcademicYearID: &yearID, StartedAt: started, EndedAt: ended}
+}
+
+func TestEnrollmentIn(t *testing.T) {
+	ganjil := &domain.Semester{AcademicYearID: 1, StartDate: date(time.July, 15, 2024), EndDate: date(time.December, 20, 2024)}
+	genap := &domain.Semester{AcademicYearID: 1, StartDate: date(time.January, 6, 2025), EndDate: date(time.June, 20, 2025)}
+	moved, promoted := date(time.October, 1, 2024), date(time.June, 10, 2025)
+
+	// Newest first: promoted to class 3 before Genap ended, moved from
+	// class 1 to class 2 during Ganjil.
+	history := []domain.ClassEnrollment{
+		enrollment(3, 2, promoted, nil),
+		enrollment(2, 1, moved, &promoted),
+		enrollment(1, 1, date(time.July, 15, 2024), &moved),
+	}
+
+	tests := []struct {
+		name        string
+		enrollments []domain.ClassEnrollment
+		semester    *domain.Semester
+		want        uint
+		ok          bool
+	}{
+		{"last class of a semester with a move", history, ganjil, 2, true},
+		{"promotion before the semester ended", history, genap, 2, true},
+		{"before the first enrollment", history[:1], ganjil, 0, false},
+		{"no history", nil, ganjil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := enrollmentIn(tt.enrollments, tt.semester)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("enrollmentIn = %d, %v; want %d, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"ppi-100-sis/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRoom        = errors.New("invalid room")
+	ErrBookingNotOwned    = errors.New("only the person who booked the room or an admin can cancel it")
+	ErrBookingRangeTooBig = errors.New("booking range may span at most 92 days")
+)
+
+var roomTypes = []string{domain.RoomClassroom, domain.RoomLab, domain.RoomHall, domain.RoomLibrary, domain.RoomField, domain.RoomOther}
+
+// RoomConflictError is returned when a booking overlaps lessons or other
+// bookings in the room.
+type RoomConflictError struct {
+	Schedules []domain.Schedule
+	Bookings  []domain.RoomBooking
+}
+
+func (e *RoomConflictError) Error() string {
+	return fmt.Sprintf("room is in use by %d lesson(s) and %d booking(s) at that time", len(e.Schedules), len(e.Bookings))
+}
+
+// RoomOccupancy is what keeps a room busy on one date.
+type RoomOccupancy struct {
+	Room      domain.Room          `json:"room"`
+	Schedules []domain.Schedule    `json:"schedules"`
+	Bookings  []domain.RoomBooking `json:"bookings"`
+}
+
+func validateRoom(room *domain.Room) error {
+	room.Name = strings.TrimSpace(room.Name)
+	if room.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRoom)
+	}
+	if room.Type == "" {
+		room.Type = domain.RoomClassroom
+	}
+	valid := false
+	for _, t := range roomTypes {
+		valid = valid || room.Type == t
+	}
+	if !valid {
+		return fmt.Errorf("%w: type must be one of %s", ErrInvalidRoom, strings.Join(roomTypes, ", "))
+	}
+	if room.Capacity < 0 {
+		return fmt.Errorf("%w: capacity cannot be negative", ErrInvalidRoom)
+	}
+	return nil
+}
+
+func (u *AcademicUsecase) CreateRoom(room domain.Room, unitID uint) (*domain.Room, error) {
+	room.ID = 0
+	room.UnitID = unitID
+	if err := validateRoom(&room); err != nil {
+		return nil, err
+	}
+	return &room, u.academicRepo.CreateRoom(&room)
+}
+
+func (u *AcademicUsecase) GetRooms(unitID uint) ([]domain.Room, error) {
+	return u.academicRepo.GetRooms(unitID)
+}
+
+func (u *AcademicUsecase) UpdateRoom(id uint, req domain.Room, unitID uint) error {
+	room, err := u.academicRepo.GetRoomByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	room.Name = req.Name
+	room.Type = req.Type
+	room.Capacity = req.Capacity
+	if err := validateRoom(room); err != nil {
+		return err
+	}
+	return u.academicRepo.UpdateRoom(room)
+}
+
+func (u *AcademicUsecase) DeleteRoom(id, unitID uint) error {
+	return u.academicRepo.DeleteRoom(id, unitID)
+}
+
+// BookRoom reserves a room for an event on one date if no lesson of that
+// day's timetable or other booking uses it at the same time.
+func (u *AcademicUsecase) BookRoom(booking domain.RoomBooking, bookedBy uuid.UUID, unitID uint) (*domain.RoomBooking, error) {
+	if _, err := u.academicRepo.GetRoomByID(booking.RoomID, unitID); err != nil {
+		return nil, err
+	}
+	booking.ID = 0
+	booking.BookedByID = bookedBy
+	booking.Title = strings.TrimSpace(booking.Title)
+	if booking.Title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidSchedule)
+	}
+	if err := validateScheduleTimes(domain.WeekdayOf(booking.Date), booking.StartTime, booking.EndTime); err != nil {
+		return nil, err
+	}
+	if booking.Date.Before(u.Today()) {
+		return nil, fmt.Errorf("%w: date is in the past", ErrInvalidSchedule)
+	}
+
+	conflict := &RoomConflictError{}
+	schedules, err := u.academicRepo.GetRoomSchedulesOn(0, booking.RoomID, booking.Date)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range schedules {
+		if s.StartTime < booking.EndTime && booking.StartTime < s.EndTime {
+			conflict.Schedules = append(conflict.Schedules, s)
+		}
+	}
+	if conflict.Bookings, err = u.academicRepo.GetOverlappingBookings(&booking); err != nil {
+		return nil, err
+	}
+	if len(conflict.Schedules) > 0 || len(conflict.Bookings) > 0 {
+		return nil, conflict
+	}
+
+	return &booking, u.academicRepo.CreateRoomBooking(&booking)
+}
+
+// GetRoomBookings lists bookings between from and to; roomID 0 means all rooms.
+func (u *AcademicUsecase) GetRoomBookings(unitID, roomID uint, from, to time.Time) ([]domain.RoomBooking, error) {
+	if to.Sub(from) > 92*24*time.Hour {
+		return nil, ErrBookingRangeTooBig
+	}
+	return u.academicRepo.GetRoomBookings(unitID, roomID, from, to)
+}
+
+// CancelRoomBooking deletes a booking. Without manageAll only the user who
+// made it may cancel it.
+func (u *AcademicUsecase) CancelRoomBooking(id uint, userID uuid.UUID, manageAll bool, unitID uint) error {
+	booking, err := u.academicRepo.GetRoomBookingByID(id, unitID)
+	if err != nil {
+		return err
+	}
+	if !manageAll && booking.BookedByID != userID {
+		return ErrBookingNotOwned
+	}
+	return u.academicRepo.DeleteRoomBooking(booking.ID)
+}
+
+// GetRoomOccupancy returns, per room, the lessons and bookings on date.
+// roomID 0 means every room of the unit.
+func (u *AcademicUsecase) GetRoomOccupancy(unitID, roomID uint, date time.Time) ([]RoomOccupancy, error) {
+	var rooms []domain.Room
+	if roomID != 0 {
+		room, err := u.academicRepo.GetRoomByID(roomID, unitID)
+		if err != nil {
+			return nil, err
+		}
+		rooms = []domain.Room{*room}
+	} else {
+		var err error
+		if rooms, err = u.academicRepo.GetRooms(unitID); err != nil {
+			return nil, err
+		}
+	}
+
+	schedules, err := u.academicRepo.GetRoomSchedulesOn(unitID, roomID, date)
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := u.academicRepo.GetRoomBookings(unitID, roomID, date, date)
+	if err != nil {
+		return nil, err
+	}
+
+	occupancy := make([]RoomOccupancy, len(rooms))
+	index := make(map[uint]int, len(rooms))
+	for i, room := range rooms {
+		occupancy[i] = RoomOccupancy{Room: room, Schedules: []domain.Schedule{}, Bookings: []domain.RoomBooking{}}
+		index[room.ID] = i
+	}
+	for _, s := range schedules {
+		if i, ok := index[*s.RoomID]; ok {
+			occupancy[i].Schedules = append(occupancy[i].Schedules, s)
+		}
+	}
+	for _, b := range bookings {
+		if i, ok := index[b.RoomID]; ok {
+			occupancy[i].Bookings = append(occupancy[i].Bookings, b)
+		}
+	}
+	return occupancy, nil
+}
+
+// Today is the current date in the school's time zone, as stored in date
+// columns.
+func (u *AcademicUsecase) Today() time.Time {
+	return schoolToday(u.cfg)
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// Conflict reasons
+const (
+	ConflictTeacher = "teacher"
+	ConflictClass   = "class"
+	ConflictRoom    = "room"
+	ConflictInvalid = "invalid" // a stored row with an unknown day or empty time range
+)
+
+// ScheduleConflict is an existing schedule clashing with the one being saved.
+type ScheduleConflict struct {
+	Reason   string          `json:"reason"`
+	Schedule domain.Schedule `json:"schedule"`
+}
+
+// ScheduleConflictError is returned when a schedule overlaps existing ones.
+type ScheduleConflictError struct {
+	Conflicts []ScheduleConflict
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return fmt.Sprintf("schedule overlaps %d existing schedule(s)", len(e.Conflicts))
+}
+
+// ScheduleClash is a pair of saved schedules that overlap, or a single
+// invalid one, as listed by the conflict report.
+type ScheduleClash struct {
+	Reason    string            `json:"reason"`
+	Schedules []domain.Schedule `json:"schedules"`
+}
+
+// validateScheduleTimes checks a day and time range.
+func validateScheduleTimes(day domain.Weekday, startTime, endTime domain.TimeOfDay) error {
+	if !day.Valid() {
+		return fmt.Errorf("%w: day is required", ErrInvalidSchedule)
+	}
+	if endTime <= startTime {
+		return fmt.Errorf("%w: end_time must be after start_time", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// checkScheduleConflicts rejects schedule if its teacher, class or room is
+// already booked at an overlapping time in the same term.
+func checkScheduleConflicts(academicRepo *postgres.AcademicRepository, schedule *domain.Schedule) error {
+	overlaps, err := academicRepo.GetOverlappingSchedules(schedule)
+	if err != nil {
+		return err
+	}
+	if len(overlaps) == 0 {
+		return nil
+	}
+
+	conflicts := make([]ScheduleConflict, 0, len(overlaps))
+	for _, other := range overlaps {
+		conflicts = append(conflicts, ScheduleConflict{Reason: conflictReason(schedule, &other), Schedule: other})
+	}
+	return &ScheduleConflictError{Conflicts: conflicts}
+}
+
+func conflictReason(a, b *domain.Schedule) string {
+	switch {
+	case a.TeacherID == b.TeacherID:
+		return ConflictTeacher
+	case a.ClassID == b.ClassID:
+		return ConflictClass
+	}
+	return ConflictRoom
+}
+
+func sameRoom(a, b *domain.Schedule) bool {
+	return a.RoomID != nil && b.RoomID != nil && *a.RoomID == *b.RoomID
+}
+
+func schedulesOverlap(a, b *domain.Schedule) bool {
+	if a.Day != b.Day || a.StartTime >= b.EndTime || b.StartTime >= a.EndTime {
+		return false
+	}
+	if (a.SemesterID == nil) != (b.SemesterID == nil) || (a.SemesterID != nil && *a.SemesterID != *b.SemesterID) {
+		return false
+	}
+	return a.TeacherID == b.TeacherID || a.ClassID == b.ClassID || sameRoom(a, b)
+}
+
+// GetScheduleConflicts reports every pair of overlapping schedules already
+// stored for the unit and term.
+func (u *AcademicUsecase) GetScheduleConflicts(unitID uint, term postgres.TermFilter) ([]ScheduleClash, error) {
+	schedules, err := u.academicRepo.GetAllSchedules(unitID, postgres.ScheduleFilter{}, term)
+	if err != nil {
+		return nil, err
+	}
+
+	clashes := []ScheduleClash{}
+	for i := range schedules {
+		if validateScheduleTimes(schedules[i].Day, schedules[i].StartTime, schedules[i].EndTime) != nil {
+			clashes = append(clashes, ScheduleClash{Reason: ConflictInvalid, Schedules: []domain.Schedule{schedules[i]}})
+			continue
+		}
+		for j := i + 1; j < len(schedules); j++ {
+			if schedulesOverlap(&schedules[i], &schedules[j]) {
+				clashes = append(clashes, ScheduleClash{
+					Reason:    conflictReason(&schedules[i], &schedules[j]),
+					Schedules: []domain.Schedule{schedules[i], schedules[j]},
+				})
+			}
+		}
+	}
+	return clashes, nil
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"errors"
+	"ppi-100-sis/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+func lesson(day domain.Weekday, start, end domain.TimeOfDay, classID uint, teacherID uuid.UUID, roomID *uint) domain.Schedule {
+	return domain.Schedule{ClassID: classID, TeacherID: teacherID, Day: day, StartTime: start, EndTime: end, SemesterID: uintPtr(1), RoomID: roomID}
+}
+
+func TestValidateScheduleTimes(t *testing.T) {
+	tests := []struct {
+		name       string
+		day        domain.Weekday
+		start, end domain.TimeOfDay
+		ok         bool
+	}{
+		{"valid", domain.Monday, domain.Clock(7, 0), domain.Clock(7, 40), true},
+		{"no day", 0, domain.Clock(7, 0), domain.Clock(7, 40), false},
+		{"day out of range", domain.Sunday + 1, domain.Clock(7, 0), domain.Clock(7, 40), false},
+		{"empty range", domain.Monday, domain.Clock(7, 0), domain.Clock(7, 0), false},
+		{"reversed range", domain.Monday, domain.Clock(8, 0), domain.Clock(7, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateScheduleTimes(tt.day, tt.start, tt.end)
+			if tt.ok && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidSchedule) {
+				t.Fatalf("err = %v, want ErrInvalidSchedule", err)
+			}
+		})
+	}
+}
+
+func TestSchedulesOverlap(t *testing.T) {
+	teacherA, teacherB := uuid.New(), uuid.New()
+	base := lesson(domain.Monday, domain.Clock(7, 0), domain.Clock(8, 0), 1, teacherA, uintPtr(10))
+
+	tests := []struct {
+		name  string
+		other func() domain.Schedule
+		want  bool
+	}{
+		{"same teacher, overlapping", func() domain.Schedule {
+			return lesson(domain.Monday, domain.Clock(7, 30), domain.Clock(8, 30), 2, teacherA, nil)
+		}, true},
+		{"same class, contained", func() domain.Schedule {
+			return lesson(domain.Monday, domain.Clock(7, 10), domain.Clock(7, 20), 1, teacherB, nil)
+		}, true},
+		{"same room, overlapping", func() domain.Schedule {
+			return lesson(domain.Monday, domain.Clock(6, 30), domain.Clock(7, 1), 2, teacherB, uintPtr(10))
+		}, true},
+		{"ends as the other starts", func() domain.Schedule {
+			return lesson(domain.Monday, domain.Clock(6, 0), domain.Clock(7, 0), 1, teacherA, uintPtr(10))
+		}, false},
+		{"starts as the other ends", func() domain.Schedule {
+			return lesson(domain.Monday, domain.Clock(8, 0), domain.Clock(9, 0), 1, teacherA, uintPtr(10))
+		}, false},
+		{"other day", func() domain.Schedule {
+			return lesson(domain.Tuesday, domain.Clock(7, 0), domain.Clock(8, 0), 1, teacherA, uintPtr(10))
+		}, false},
+		{"nothing shared", func() domain.Schedule {
+			return lesson(domain.Monday, domain.Clock(7, 0), domain.Clock(8, 0), 2, teacherB, uintPtr(11))
+		}, false},
+		{"other semester", func() domain.Schedule {
+			s := lesson(domain.Monday, domain.Clock(7, 0), domain.Clock(8, 0), 1, teacherA, uintPtr(10))
+			s.SemesterID = uintPtr(2)
+			return s
+		}, false},
+		{"one without semester", func() domain.Schedule {
+			s := lesson(domain.Monday, domain.Clock(7, 0), domain.Clock(8, 0), 1, teacherA, uintPtr(10))
+			s.SemesterID = nil
+			return s
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := base, tt.other()
+			if got := schedulesOverlap(&a, &b); got != tt.want {
+				t.Errorf("schedulesOverlap(a, b) = %v, want %v", got, tt.want)
+			}
+			if got := schedulesOverlap(&b, &a); got != tt.want {
+				t.Errorf("schedulesOverlap(b, a) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no room on one side", func(t *testing.T) {
+		a := base
+		b := lesson(domain.Monday, domain.Clock(7, 0), domain.Clock(8, 0), 2, teacherB, nil)
+		if schedulesOverlap(&a, &b) {
+			t.Error("a missing room must not count as the same room")
+		}
+	})
+}
+
+func TestConflictReason(t *testing.T) {
+	teacherA, teacherB := uuid.New(), uuid.New()
+	a := lesson(domain.Monday, domain.Clock(7, 0), domain.Clock(8, 0), 1, teacherA, uintPtr(10))
+
+	tests := []struct {
+		name  string
+		other domain.Schedule
+		want  string
+	}{
+		{"teacher wins over class", lesson(domain.Monday, domain.Clock(7, 0), domain.Clock(8, 0), 1, teacherA, nil), ConflictTeacher},
+		{"class", lesson(domain.Monday, domain.Clock(7, 0), domain.Clock(8, 0), 1, teacherB, nil), ConflictClass},
+		{"room", lesson(domain.Monday, domain.Clock(7, 0), domain.Clock(8, 0), 2, teacherB, uintPtr(10)), ConflictRoom},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conflictReason(&a, &tt.other); got != tt.want {
+				t.Errorf("conflictReason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,49 +2,67 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
+	"ppi-100-sis/internal/config"
 	"ppi-100-sis/internal/domain"
 	"ppi-100-sis/internal/repository/postgres"
+	"ppi-100-sis/pkg/utils"
 	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ErrHomeroomStudentField rejects a homeroom teacher's change to the fields
+// that tie a student to an account, a parent or a class.
+var ErrHomeroomStudentField = errors.New("homeroom teachers may only change a student's name and NISN")
+
 type StudentUsecase struct {
 	studentRepo    *postgres.StudentRepository
 	attendanceRepo *postgres.AttendanceRepository
 	userRepo       *postgres.UserRepository
+	academicRepo   *postgres.AcademicRepository
+	enrollmentRepo *postgres.EnrollmentRepository
+	cfg            *config.Config
 }
 
-func NewStudentUsecase(studentRepo *postgres.StudentRepository, attendanceRepo *postgres.AttendanceRepository, userRepo *postgres.UserRepository) *StudentUsecase {
+func NewStudentUsecase(studentRepo *postgres.StudentRepository, attendanceRepo *postgres.AttendanceRepository, userRepo *postgres.UserRepository, academicRepo *postgres.AcademicRepository, enrollmentRepo *postgres.EnrollmentRepository, cfg *config.Config) *StudentUsecase {
 	return &StudentUsecase{
 		studentRepo:    studentRepo,
 		attendanceRepo: attendanceRepo,
 		userRepo:       userRepo,
+		academicRepo:   academicRepo,
+		enrollmentRepo: enrollmentRepo,
+		cfg:            cfg,
 	}
 }
 
-func (u *StudentUsecase) GetAllStudents(unitID uint) ([]domain.Student, error) {
-	return u.studentRepo.GetAll(unitID)
+func (u *StudentUsecase) GetAllStudents(unitID uint, term postgres.TermFilter) ([]domain.Student, error) {
+	return u.studentRepo.GetAll(unitID, term)
 }
 
 func (u *StudentUsecase) CreateStudent(name, email, password, nisn string, classID, unitID uint, parentID *uuid.UUID) error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	class, err := u.academicRepo.GetClassByID(classID, unitID)
 	if err != nil {
+		return errors.New("class not found in unit")
+	}
+	if err := utils.ValidatePassword(password, u.cfg); err != nil {
 		return err
 	}
 
-	user := &domain.User{
-		ID:       uuid.New(),
-		Name:     name,
-		Email:    email,
-		PasswordHash: string(hashedPassword),
-		RoleID:   6, // Student
-		UnitID:   unitID,
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
 	}
 
-	if err := u.userRepo.Create(user); err != nil {
-		return err
+	user := &domain.User{
+		ID:                 uuid.New(),
+		Name:               name,
+		Email:              email,
+		PasswordHash:       string(hashedPassword),
+		RoleID:             6, // Student
+		UnitID:             unitID,
+		MustChangePassword: true, // Admin-assigned
 	}
 
 	student := &domain.Student{
@@ -56,24 +74,45 @@ func (u *StudentUsecase) CreateStudent(name, email, password, nisn string, class
 		ParentID: parentID,
 	}
 
-	return u.studentRepo.Create(student)
+	return u.studentRepo.CreateWithUser(user, student, &domain.ClassEnrollment{
+		StudentID:      student.ID,
+		ClassID:        class.ID,
+		AcademicYearID: class.AcademicYearID,
+		Status:         domain.EnrollmentActive,
+		StartedAt:      time.Now(),
+	})
 }
 
-func (u *StudentUsecase) UpdateStudent(id string, name, email, nisn string, classID, unitID uint, parentID *uuid.UUID) error {
-	student, err := u.studentRepo.GetByID(id)
+// UpdateStudent edits a student. A non-empty teacherID confines a homeroom
+// teacher to the students of their class and to their name and NISN.
+func (u *StudentUsecase) UpdateStudent(id string, name, email, nisn string, classID, unitID uint, parentID *uuid.UUID, teacherID string, scopeUnitID uint) error {
+	student, err := u.studentRepo.GetByID(id, scopeUnitID)
 	if err != nil {
 		return err
 	}
+	if err := checkHomeroom(&student.Class, teacherID); err != nil {
+		return err
+	}
+	class, err := u.academicRepo.GetClassByID(classID, unitID)
+	if err != nil {
+		return errors.New("class not found in unit")
+	}
 
 	// Update User
 	user, err := u.userRepo.FindByID(student.UserID.String())
 	if err != nil {
 		return err
 	}
+	if teacherID != "" && (email != user.Email || classID != student.ClassID || unitID != student.UnitID || !sameParent(parentID, student.ParentID)) {
+		return ErrHomeroomStudentField
+	}
 	user.Name = name
 	user.Email = email
-	if err := u.userRepo.Update(user); err != nil {
-		return err
+
+	// A class change mid-year is recorded in the enrollment history
+	var moveTo *domain.Class
+	if student.ClassID != classID {
+		moveTo = class
 	}
 
 	// Update Student
@@ -81,17 +120,25 @@ func (u *StudentUsecase) UpdateStudent(id string, name, email, nisn string, clas
 	student.ClassID = classID
 	student.UnitID = unitID
 	student.ParentID = parentID
-	return u.studentRepo.Update(student)
+	student.Class = *class
+	return u.studentRepo.UpdateWithUser(user, student, moveTo)
 }
 
-func (u *StudentUsecase) DeleteStudent(id string) error {
-	student, err := u.studentRepo.GetByID(id)
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (u *StudentUsecase) DeleteStudent(id string, unitID uint) error {
+	student, err := u.studentRepo.GetByID(id, unitID)
 	if err != nil {
 		return err
 	}
 
 	// Delete Student first (FK constraint usually requires this, or cascade)
-	if err := u.studentRepo.Delete(id); err != nil {
+	if err := u.studentRepo.Delete(id, unitID); err != nil {
 		return err
 	}
 
@@ -99,7 +146,29 @@ func (u *StudentUsecase) DeleteStudent(id string) error {
 	return u.userRepo.Delete(student.UserID.String())
 }
 
-func (u *StudentUsecase) RecordAttendance(studentID uuid.UUID, scheduleID uint, method, status string) error {
+func (u *StudentUsecase) RecordAttendance(studentID uuid.UUID, scheduleID uint, method, status string, unitID uint) error {
+	student, err := u.studentRepo.GetByID(studentID.String(), unitID)
+	if err != nil {
+		return err
+	}
+	schedule, err := u.academicRepo.GetScheduleByID(scheduleID, unitID)
+	if err != nil {
+		return err
+	}
+	today := schoolToday(u.cfg)
+	cal, err := loadTeachingCalendar(u.academicRepo, student.UnitID, today, today)
+	if err != nil {
+		return err
+	}
+	if reason := cal.DayOff(today); reason != "" {
+		return fmt.Errorf("%w: %s", ErrNonTeachingDay, reason)
+	}
+	// Credit the lesson to today's substitute, if any
+	teacherID := schedule.TeacherID
+	if sub, err := u.academicRepo.GetSubstitutionFor(schedule.ID, today); err == nil {
+		teacherID = sub.TeacherID
+	}
+
 	// Check if already attended today for this schedule
 	exists, err := u.attendanceRepo.CheckExistence(studentID.String(), scheduleID, time.Now())
 	if err != nil {
@@ -112,6 +181,7 @@ func (u *StudentUsecase) RecordAttendance(studentID uuid.UUID, scheduleID uint,
 	attendance := &domain.Attendance{
 		StudentID:  studentID,
 		ScheduleID: scheduleID,
+		TeacherID:  &teacherID,
 		Timestamp:  time.Now(),
 		Method:     method,
 		Status:     status,
@@ -120,12 +190,90 @@ func (u *StudentUsecase) RecordAttendance(studentID uuid.UUID, scheduleID uint,
 	return u.attendanceRepo.Create(attendance)
 }
 
-func (u *StudentUsecase) GetStudentAttendance(studentID string) ([]domain.Attendance, error) {
-	return u.attendanceRepo.GetByStudent(studentID)
+func (u *StudentUsecase) GetStudentAttendance(studentID string, unitID uint, term postgres.TermFilter) ([]domain.Attendance, error) {
+	return u.attendanceRepo.GetByStudent(studentID, unitID, term)
+}
+
+// AttendanceRecap sums up a student's attendance over a semester, up to today.
+// Sundays and non-teaching days on the school calendar are left out.
+type AttendanceRecap struct {
+	Semester        *domain.Semester `json:"semester"`
+	From            time.Time        `json:"from"`
+	To              time.Time        `json:"to"`
+	TeachingDays    int              `json:"teaching_days"`
+	ExpectedLessons int              `json:"expected_lessons"`
+	Statuses        map[string]int   `json:"statuses"` // Present, Late, Permission, Sick, Absent
+	Unrecorded      int              `json:"unrecorded"`
+}
+
+func (u *StudentUsecase) GetAttendanceRecap(studentID string, unitID uint, term postgres.TermFilter) (*AttendanceRecap, error) {
+	student, err := u.studentRepo.GetByID(studentID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	var semester *domain.Semester
+	if term.SemesterID != 0 {
+		semester, err = u.academicRepo.GetSemesterByID(term.SemesterID, student.UnitID)
+	} else {
+		semester, err = u.academicRepo.GetActiveSemester(student.UnitID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dateOf := func(t time.Time) time.Time {
+		y, m, d := t.In(u.cfg.SchoolLocation).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	from, to := dateOf(semester.StartDate), dateOf(semester.EndDate)
+	if today := schoolToday(u.cfg); today.Before(to) {
+		to = today
+	}
+	recap := &AttendanceRecap{Semester: semester, From: from, To: to, Statuses: map[string]int{}}
+	if to.Before(from) {
+		return recap, nil
+	}
+
+	cal, err := loadTeachingCalendar(u.academicRepo, student.UnitID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := u.academicRepo.GetAllSchedules(student.UnitID, postgres.ScheduleFilter{ClassID: student.ClassID}, postgres.TermFilter{SemesterID: semester.ID})
+	if err != nil {
+		return nil, err
+	}
+	lessonsPerDay := map[domain.Weekday]int{}
+	for _, schedule := range schedules {
+		lessonsPerDay[schedule.Day]++
+	}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if cal.DayOff(d) == "" {
+			recap.TeachingDays++
+			recap.ExpectedLessons += lessonsPerDay[domain.WeekdayOf(d)]
+		}
+	}
+
+	attendances, err := u.attendanceRepo.GetByStudent(studentID, student.UnitID, postgres.TermFilter{SemesterID: semester.ID})
+	if err != nil {
+		return nil, err
+	}
+	recorded := 0
+	for _, attendance := range attendances {
+		day := dateOf(attendance.Timestamp)
+		if day.Before(from) || day.After(to) || cal.DayOff(day) != "" {
+			continue
+		}
+		recap.Statuses[attendance.Status]++
+		recorded++
+	}
+	if recorded < recap.ExpectedLessons {
+		recap.Unrecorded = recap.ExpectedLessons - recorded
+	}
+	return recap, nil
 }
 
-func (u *StudentUsecase) GetScheduleAttendance(scheduleID uint) ([]domain.Attendance, error) {
-	return u.attendanceRepo.GetBySchedule(scheduleID)
+func (u *StudentUsecase) GetScheduleAttendance(scheduleID, unitID uint) ([]domain.Attendance, error) {
+	return u.attendanceRepo.GetBySchedule(scheduleID, unitID)
 }
 
 func (u *StudentUsecase) GetChildren(parentID string) ([]domain.Student, error) {
@@ -142,3 +290,159 @@ func (u *StudentUsecase) GetChildrenByUserID(userID string) ([]domain.Student, e
 	}
 	return u.studentRepo.GetByParent(user.Parent.ID.String())
 }
+
+// OwnsStudent reports whether studentID is the user's own student record or,
+// for a parent, one of their children.
+func (u *StudentUsecase) OwnsStudent(userID, studentID string) bool {
+	user, err := u.userRepo.FindByID(userID)
+	if err != nil {
+		return false
+	}
+	if user.Student != nil && user.Student.ID.String() == studentID {
+		return true
+	}
+	if user.Parent == nil {
+		return false
+	}
+	children, err := u.studentRepo.GetByParent(user.Parent.ID.String())
+	if err != nil {
+		return false
+	}
+	for _, child := range children {
+		if child.ID.String() == studentID {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *StudentUsecase) GetEnrollmentHistory(studentID string, unitID uint) ([]domain.ClassEnrollment, error) {
+	if _, err := u.studentRepo.GetByID(studentID, unitID); err != nil {
+		return nil, err
+	}
+	return u.enrollmentRepo.GetByStudent(studentID, unitID)
+}
+
+// PromotionRule moves every student of FromClassID into ToClassID, or
+// graduates them when Graduate is set.
+type PromotionRule struct {
+	FromClassID uint
+	ToClassID   uint
+	Graduate    bool
+}
+
+// Repeater keeps a student of one of the promoted classes back in ClassID.
+type Repeater struct {
+	StudentID uuid.UUID
+	ClassID   uint
+}
+
+type PromotionItem struct {
+	StudentID uuid.UUID `json:"student_id"`
+	Name      string    `json:"name"`
+	FromClass string    `json:"from_class"`
+	ToClass   string    `json:"to_class,omitempty"`
+	Status    string    `json:"status"`
+}
+
+type PromotionResult struct {
+	DryRun    bool            `json:"dry_run"`
+	Promoted  int             `json:"promoted"`
+	Retained  int             `json:"retained"`
+	Graduated int             `json:"graduated"`
+	Students  []PromotionItem `json:"students"`
+}
+
+// PromoteStudents moves the students of the given classes into academic year
+// academicYearID. Target classes must already exist in that year. With dryRun
+// the plan is returned without being applied.
+func (u *StudentUsecase) PromoteStudents(academicYearID uint, rules []PromotionRule, repeaters []Repeater, dryRun bool, unitID uint) (*PromotionResult, error) {
+	year, err := u.academicRepo.GetAcademicYearByID(academicYearID, unitID)
+	if err != nil {
+		return nil, errors.New("academic year not found in unit")
+	}
+
+	// targetClass checks that a class is in the target year
+	targetClass := func(id uint) (*domain.Class, error) {
+		class, err := u.academicRepo.GetClassByID(id, year.UnitID)
+		if err != nil || class.AcademicYearID == nil || *class.AcademicYearID != year.ID {
+			return nil, fmt.Errorf("class %d is not a class of academic year %s", id, year.Name)
+		}
+		return class, nil
+	}
+
+	var steps []postgres.PromotionStep
+	var items []PromotionItem
+	fromClasses := make(map[uint]bool)
+	studentIndex := make(map[uuid.UUID]int)
+	for _, rule := range rules {
+		if fromClasses[rule.FromClassID] {
+			return nil, fmt.Errorf("class %d is listed more than once", rule.FromClassID)
+		}
+		fromClasses[rule.FromClassID] = true
+
+		from, err := u.academicRepo.GetClassByID(rule.FromClassID, year.UnitID)
+		if err != nil {
+			return nil, fmt.Errorf("class %d not found in unit", rule.FromClassID)
+		}
+		if from.AcademicYearID != nil && *from.AcademicYearID == year.ID {
+			return nil, fmt.Errorf("class %s already belongs to academic year %s", from.Name, year.Name)
+		}
+
+		status := domain.EnrollmentGraduated
+		var to *domain.Class
+		if !rule.Graduate {
+			if to, err = targetClass(rule.ToClassID); err != nil {
+				return nil, err
+			}
+			status = domain.EnrollmentPromoted
+		}
+
+		students, err := u.studentRepo.GetByClass(from.ID, year.UnitID)
+		if err != nil {
+			return nil, err
+		}
+		for _, student := range students {
+			item := PromotionItem{StudentID: student.ID, Name: student.User.Name, FromClass: from.Name, Status: status}
+			step := postgres.PromotionStep{Student: student, Status: status}
+			if to != nil {
+				item.ToClass = to.Name
+				step.ToClassID = to.ID
+			}
+			studentIndex[student.ID] = len(steps)
+			steps = append(steps, step)
+			items = append(items, item)
+		}
+	}
+
+	for _, repeater := range repeaters {
+		i, ok := studentIndex[repeater.StudentID]
+		if !ok {
+			return nil, fmt.Errorf("student %s is not in any of the promoted classes", repeater.StudentID)
+		}
+		class, err := targetClass(repeater.ClassID)
+		if err != nil {
+			return nil, err
+		}
+		steps[i].Status = domain.EnrollmentRetained
+		steps[i].ToClassID = class.ID
+		items[i].Status = domain.EnrollmentRetained
+		items[i].ToClass = class.Name
+	}
+
+	result := &PromotionResult{DryRun: dryRun, Students: items}
+	for _, item := range items {
+		switch item.Status {
+		case domain.EnrollmentPromoted:
+			result.Promoted++
+		case domain.EnrollmentRetained:
+			result.Retained++
+		case domain.EnrollmentGraduated:
+			result.Graduated++
+		}
+	}
+	if dryRun {
+		return result, nil
+	}
+	return result, u.enrollmentRepo.ApplyPromotion(steps, year)
+}
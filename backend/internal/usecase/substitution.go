@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"ppi-100-sis/internal/config"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidSubstitution = errors.New("invalid substitution")
+
+// SubstituteCandidate is a teacher free during a lesson.
+type SubstituteCandidate struct {
+	Teacher     domain.Teacher `json:"teacher"`
+	SameSubject bool           `json:"same_subject"` // teaches the lesson's subject this term
+	Lessons     int            `json:"lessons"`      // lessons already held that day
+}
+
+// schoolToday is the current date in the school's time zone, as stored in
+// date columns.
+func schoolToday(cfg *config.Config) time.Time {
+	y, m, d := time.Now().In(cfg.SchoolLocation).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// lessonTerm is the term filter matching the semester a lesson belongs to.
+func lessonTerm(schedule *domain.Schedule) postgres.TermFilter {
+	if schedule.SemesterID == nil {
+		return postgres.TermFilter{}
+	}
+	return postgres.TermFilter{SemesterID: *schedule.SemesterID}
+}
+
+func lessonsOverlap(a, b *domain.Schedule) bool {
+	return a.Day == b.Day && a.StartTime < b.EndTime && b.StartTime < a.EndTime
+}
+
+// CreateSubstitution assigns a substitute to one occurrence of a lesson and
+// notifies them.
+func (u *AcademicUsecase) CreateSubstitution(scheduleID uint, date time.Time, teacherID uuid.UUID, reason string, createdBy uuid.UUID, unitID uint) (*domain.Substitution, error) {
+	schedule, err := u.academicRepo.GetScheduleByID(scheduleID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.WeekdayOf(date) != schedule.Day {
+		return nil, fmt.Errorf("%w: the lesson is held on %s, not on %s", ErrInvalidSubstitution, schedule.Day, domain.WeekdayOf(date))
+	}
+	if teacherID == schedule.TeacherID {
+		return nil, fmt.Errorf("%w: the substitute must be another teacher", ErrInvalidSubstitution)
+	}
+	class, err := u.academicRepo.GetClassByID(schedule.ClassID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	teacher, err := u.teacherRepo.GetByID(teacherID.String(), class.UnitID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: teacher not found in the class's unit", ErrInvalidSubstitution)
+	}
+	if _, err := u.academicRepo.GetSubstitutionFor(schedule.ID, date); err == nil {
+		return nil, fmt.Errorf("%w: the lesson already has a substitute on that date", ErrInvalidSubstitution)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	busy, err := u.teacherLessonsOn(teacherID, schedule, date)
+	if err != nil {
+		return nil, err
+	}
+	if len(busy) > 0 {
+		conflicts := make([]ScheduleConflict, 0, len(busy))
+		for _, other := range busy {
+			conflicts = append(conflicts, ScheduleConflict{Reason: ConflictTeacher, Schedule: other})
+		}
+		return nil, &ScheduleConflictError{Conflicts: conflicts}
+	}
+
+	substitution := &domain.Substitution{ScheduleID: schedule.ID, Date: date, TeacherID: teacher.ID, Reason: reason, CreatedByID: createdBy}
+	if err := u.academicRepo.CreateSubstitution(substitution); err != nil {
+		return nil, err
+	}
+	if substitution, err = u.academicRepo.GetSubstitutionByID(substitution.ID, 0); err != nil {
+		return nil, err
+	}
+
+	// The substitution stands even if the teacher could not be notified
+	lesson := substitution.Schedule
+	if err := u.notificationUsecase.SendNotification(
+		teacher.UserID,
+		"Tugas Guru Pengganti",
+		fmt.Sprintf("Anda menggantikan %s mengajar %s di kelas %s pada %s, %s-%s.",
+			lesson.Teacher.User.Name, lesson.Subject.Name, lesson.Class.Name, date.Format("02-01-2006"), lesson.StartTime, lesson.EndTime),
+		"substitution",
+		strconv.FormatUint(uint64(substitution.ID), 10),
+	); err != nil {
+		log.Printf("substitution %d: failed to notify teacher %s: %v", substitution.ID, teacher.ID, err)
+	}
+	return substitution, nil
+}
+
+// teacherLessonsOn returns what keeps teacherID busy on date during lesson:
+// their own lessons not handed to a substitute, and lessons they substitute.
+func (u *AcademicUsecase) teacherLessonsOn(teacherID uuid.UUID, lesson *domain.Schedule, date time.Time) ([]domain.Schedule, error) {
+	own, err := u.academicRepo.GetAllSchedules(0, postgres.ScheduleFilter{TeacherID: teacherID.String()}, lessonTerm(lesson))
+	if err != nil {
+		return nil, err
+	}
+	var busy []domain.Schedule
+	for i := range own {
+		if own[i].ID == lesson.ID || !lessonsOverlap(&own[i], lesson) {
+			continue
+		}
+		if _, err := u.academicRepo.GetSubstitutionFor(own[i].ID, date); err == nil {
+			continue
+		}
+		busy = append(busy, own[i])
+	}
+
+	covering, err := u.academicRepo.GetSubstitutions(0, teacherID.String(), date, date)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range covering {
+		if s.ScheduleID != lesson.ID && lessonsOverlap(s.Schedule, lesson) {
+			busy = append(busy, *s.Schedule)
+		}
+	}
+	return busy, nil
+}
+
+// SuggestSubstitutes lists the unit's teachers free during a lesson on date,
+// those teaching the same subject first, then the least loaded that day.
+func (u *AcademicUsecase) SuggestSubstitutes(scheduleID uint, date time.Time, unitID uint) ([]SubstituteCandidate, error) {
+	schedule, err := u.academicRepo.GetScheduleByID(scheduleID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.WeekdayOf(date) != schedule.Day {
+		return nil, fmt.Errorf("%w: the lesson is held on %s, not on %s", ErrInvalidSubstitution, schedule.Day, domain.WeekdayOf(date))
+	}
+	class, err := u.academicRepo.GetClassByID(schedule.ClassID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	teachers, err := u.teacherRepo.GetAll(class.UnitID)
+	if err != nil {
+		return nil, err
+	}
+	lessons, err := u.academicRepo.GetAllSchedules(class.UnitID, postgres.ScheduleFilter{}, lessonTerm(schedule))
+	if err != nil {
+		return nil, err
+	}
+	substitutions, err := u.academicRepo.GetSubstitutions(class.UnitID, "", date, date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Who actually teaches each lesson of that day
+	taughtBy := map[uint]uuid.UUID{}
+	for i := range lessons {
+		if lessons[i].Day == schedule.Day {
+			taughtBy[lessons[i].ID] = lessons[i].TeacherID
+		}
+	}
+	for _, s := range substitutions {
+		taughtBy[s.ScheduleID] = s.TeacherID
+	}
+
+	sameSubject := map[uuid.UUID]bool{}
+	busy := map[uuid.UUID]bool{schedule.TeacherID: true}
+	load := map[uuid.UUID]int{}
+	for i := range lessons {
+		l := &lessons[i]
+		if l.SubjectID == schedule.SubjectID {
+			sameSubject[l.TeacherID] = true
+		}
+		teacher, ok := taughtBy[l.ID]
+		if !ok {
+			continue
+		}
+		load[teacher]++
+		if l.ID != schedule.ID && lessonsOverlap(l, schedule) {
+			busy[teacher] = true
+		}
+	}
+
+	candidates := []SubstituteCandidate{}
+	for _, t := range teachers {
+		if !busy[t.ID] {
+			candidates = append(candidates, SubstituteCandidate{Teacher: t, SameSubject: sameSubject[t.ID], Lessons: load[t.ID]})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].SameSubject != candidates[j].SameSubject {
+			return candidates[i].SameSubject
+		}
+		return candidates[i].Lessons < candidates[j].Lessons
+	})
+	return candidates, nil
+}
+
+func (u *AcademicUsecase) GetSubstitutions(unitID uint, teacherID string, from, to time.Time) ([]domain.Substitution, error) {
+	if to.Before(from) || to.Sub(from) > 92*24*time.Hour {
+		return nil, fmt.Errorf("%w: the range may span at most 92 days", ErrInvalidSubstitution)
+	}
+	return u.academicRepo.GetSubstitutions(unitID, teacherID, from, to)
+}
+
+func (u *AcademicUsecase) DeleteSubstitution(id, unitID uint) error {
+	if _, err := u.academicRepo.GetSubstitutionByID(id, unitID); err != nil {
+		return err
+	}
+	return u.academicRepo.DeleteSubstitution(id)
+}
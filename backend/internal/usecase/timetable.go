@@ -0,0 +1,401 @@
+package usecase
+
+import (
+	"fmt"
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// timetableSearchBudget caps the backtracking search before falling back to
+// a greedy placement that reports what did not fit.
+const timetableSearchBudget = 200000
+
+// TimetablePeriod is one teaching period of the week.
+type TimetablePeriod struct {
+	Day       domain.Weekday
+	StartTime domain.TimeOfDay
+	EndTime   domain.TimeOfDay
+}
+
+// TimetableRequirement asks for HoursPerWeek periods of a subject in a class.
+type TimetableRequirement struct {
+	ClassID      uint
+	SubjectID    uint
+	TeacherID    uuid.UUID
+	HoursPerWeek int
+}
+
+// TeacherUnavailability blocks a teacher for a time range on one day.
+type TeacherUnavailability struct {
+	TeacherID uuid.UUID
+	Day       domain.Weekday
+	StartTime domain.TimeOfDay
+	EndTime   domain.TimeOfDay
+}
+
+type TimetableInput struct {
+	// Periods defaults to the unit's stored teaching periods when empty.
+	Periods      []TimetablePeriod
+	Requirements []TimetableRequirement
+	Unavailable  []TeacherUnavailability
+	// MaxTeacherHours limits each teacher's weekly periods, 0 for no limit.
+	MaxTeacherHours int
+	// MaxDailySubjectHours limits periods of one subject per class and day, 0 for no limit.
+	MaxDailySubjectHours int
+	// AllowPartial stores the timetable even when some lessons did not fit.
+	AllowPartial bool
+}
+
+type UnplacedLesson struct {
+	ClassID   uint      `json:"class_id"`
+	SubjectID uint      `json:"subject_id"`
+	TeacherID uuid.UUID `json:"teacher_id"`
+	Hours     int       `json:"hours"`
+}
+
+type TimetableResult struct {
+	DryRun    bool              `json:"dry_run"`
+	Schedules []domain.Schedule `json:"schedules"`
+	Unplaced  []UnplacedLesson  `json:"unplaced"`
+}
+
+// GenerateTimetable proposes a conflict-free weekly timetable for the classes
+// in input and, unless dryRun is set, stores it in the active term. Classes
+// must not have schedules in that term yet, teachers are kept clear of their
+// lessons in every unit, and a timetable with unplaced lessons is only stored
+// with input.AllowPartial. Generation is deterministic, so a committed
+// timetable matches its preview.
+func (u *AcademicUsecase) GenerateTimetable(input TimetableInput, dryRun bool, unitID uint) (*TimetableResult, error) {
+	if len(input.Requirements) == 0 {
+		return nil, fmt.Errorf("%w: requirements are required", ErrInvalidSchedule)
+	}
+
+	var unit uint
+	classes := make(map[uint]*domain.Class)
+	for _, req := range input.Requirements {
+		if req.HoursPerWeek <= 0 {
+			return nil, fmt.Errorf("%w: hours_per_week must be positive", ErrInvalidSchedule)
+		}
+		if _, ok := classes[req.ClassID]; !ok {
+			class, err := u.academicRepo.GetClassByID(req.ClassID, unitID)
+			if err != nil {
+				return nil, fmt.Errorf("%w: class %d not found in unit", ErrInvalidSchedule, req.ClassID)
+			}
+			if unit != 0 && class.UnitID != unit {
+				return nil, fmt.Errorf("%w: all classes must belong to one unit", ErrInvalidSchedule)
+			}
+			unit = class.UnitID
+			classes[req.ClassID] = class
+		}
+		if _, err := u.academicRepo.GetSubjectByID(req.SubjectID, unit); err != nil {
+			return nil, fmt.Errorf("%w: subject %d not found in unit", ErrInvalidSchedule, req.SubjectID)
+		}
+		if _, err := u.teacherRepo.GetByID(req.TeacherID.String(), unit); err != nil {
+			return nil, fmt.Errorf("%w: teacher %s not found in unit", ErrInvalidSchedule, req.TeacherID)
+		}
+	}
+
+	periods := input.Periods
+	if len(periods) == 0 {
+		stored, err := u.academicRepo.GetPeriods(unit)
+		if err != nil {
+			return nil, err
+		}
+		for _, period := range stored {
+			if !period.IsBreak {
+				periods = append(periods, TimetablePeriod{Day: period.Day, StartTime: period.StartTime, EndTime: period.EndTime})
+			}
+		}
+		if len(periods) == 0 {
+			return nil, fmt.Errorf("%w: no periods given and none defined for the unit", ErrInvalidSchedule)
+		}
+	}
+	for _, period := range periods {
+		if err := validateScheduleTimes(period.Day, period.StartTime, period.EndTime); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(periods, func(i, j int) bool {
+		if periods[i].Day != periods[j].Day {
+			return periods[i].Day < periods[j].Day
+		}
+		return periods[i].StartTime < periods[j].StartTime
+	})
+	for i := 1; i < len(periods); i++ {
+		if periods[i].Day == periods[i-1].Day && periods[i].StartTime < periods[i-1].EndTime {
+			return nil, fmt.Errorf("%w: periods %s %s and %s overlap", ErrInvalidSchedule, periods[i].Day, periods[i-1].StartTime, periods[i].StartTime)
+		}
+	}
+
+	existing, err := u.academicRepo.GetAllSchedules(unit, postgres.ScheduleFilter{}, postgres.TermFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, schedule := range existing {
+		if class, ok := classes[schedule.ClassID]; ok {
+			return nil, fmt.Errorf("%w: class %s already has schedules in the active term", ErrInvalidSchedule, class.Name)
+		}
+	}
+
+	// Requested teachers may also teach in the other unit; those lessons block
+	// them too
+	busy := existing
+	seen := make(map[uuid.UUID]bool)
+	for _, req := range input.Requirements {
+		if seen[req.TeacherID] {
+			continue
+		}
+		seen[req.TeacherID] = true
+		lessons, err := u.academicRepo.GetAllSchedules(0, postgres.ScheduleFilter{TeacherID: req.TeacherID.String()}, postgres.TermFilter{})
+		if err != nil {
+			return nil, err
+		}
+		for _, schedule := range lessons {
+			if schedule.Class.UnitID != unit {
+				busy = append(busy, schedule)
+			}
+		}
+	}
+
+	// Teacher load: the requested hours plus what they already teach
+	load := make(map[uuid.UUID]int)
+	for _, schedule := range busy {
+		load[schedule.TeacherID]++
+	}
+	for _, req := range input.Requirements {
+		load[req.TeacherID] += req.HoursPerWeek
+	}
+	if input.MaxTeacherHours > 0 {
+		for teacherID, hours := range load {
+			if hours > input.MaxTeacherHours {
+				return nil, fmt.Errorf("%w: teacher %s would teach %d periods, the limit is %d", ErrInvalidSchedule, teacherID, hours, input.MaxTeacherHours)
+			}
+		}
+	}
+
+	gen := newTimetableGenerator(periods, input, load)
+	for _, schedule := range busy {
+		gen.block(schedule.TeacherID, schedule.Day, schedule.StartTime, schedule.EndTime)
+	}
+	for _, busy := range input.Unavailable {
+		gen.block(busy.TeacherID, busy.Day, busy.StartTime, busy.EndTime)
+	}
+	gen.run()
+
+	semesterID := activeSemesterID(u.academicRepo, unit)
+	result := &TimetableResult{DryRun: dryRun, Schedules: []domain.Schedule{}, Unplaced: []UnplacedLesson{}}
+	unplaced := make(map[int]int)
+	for i, lesson := range gen.lessons {
+		req := input.Requirements[lesson.req]
+		if gen.assigned[i] < 0 {
+			unplaced[lesson.req]++
+			continue
+		}
+		period := periods[gen.assigned[i]]
+		result.Schedules = append(result.Schedules, domain.Schedule{
+			ClassID:    req.ClassID,
+			SubjectID:  req.SubjectID,
+			TeacherID:  req.TeacherID,
+			Day:        period.Day,
+			StartTime:  period.StartTime,
+			EndTime:    period.EndTime,
+			SemesterID: semesterID,
+		})
+	}
+	for i, req := range input.Requirements {
+		if hours := unplaced[i]; hours > 0 {
+			result.Unplaced = append(result.Unplaced, UnplacedLesson{ClassID: req.ClassID, SubjectID: req.SubjectID, TeacherID: req.TeacherID, Hours: hours})
+		}
+	}
+	sort.SliceStable(result.Schedules, func(i, j int) bool {
+		a, b := result.Schedules[i], result.Schedules[j]
+		if a.ClassID != b.ClassID {
+			return a.ClassID < b.ClassID
+		}
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		return a.StartTime < b.StartTime
+	})
+
+	if dryRun || len(result.Schedules) == 0 {
+		return result, nil
+	}
+	if len(result.Unplaced) > 0 && !input.AllowPartial {
+		hours := 0
+		for _, lesson := range result.Unplaced {
+			hours += lesson.Hours
+		}
+		return nil, fmt.Errorf("%w: %d lessons could not be placed; set allow_partial to save the timetable without them", ErrInvalidSchedule, hours)
+	}
+	// Lessons saved since the existing ones were read must not be overlapped
+	for i := range result.Schedules {
+		if err := checkScheduleConflicts(u.academicRepo, &result.Schedules[i]); err != nil {
+			return nil, err
+		}
+	}
+	return result, u.academicRepo.CreateSchedules(result.Schedules)
+}
+
+type timetableLesson struct {
+	req     int
+	classID uint
+	subject uint
+	teacher uuid.UUID
+	prev    int // previous lesson of the same requirement, or -1
+}
+
+type subjectDay struct {
+	classID uint
+	subject uint
+	day     domain.Weekday
+}
+
+// timetableGenerator assigns every lesson to a period index so that no class
+// or teacher is booked twice.
+type timetableGenerator struct {
+	periods       []TimetablePeriod
+	lessons       []timetableLesson
+	assigned      []int
+	maxDaily      int
+	classBusy     map[uint][]bool
+	teacherBusy   map[uuid.UUID][]bool
+	subjectPerDay map[subjectDay]int
+	steps         int
+}
+
+func newTimetableGenerator(periods []TimetablePeriod, input TimetableInput, load map[uuid.UUID]int) *timetableGenerator {
+	g := &timetableGenerator{
+		periods:       periods,
+		maxDaily:      input.MaxDailySubjectHours,
+		classBusy:     make(map[uint][]bool),
+		teacherBusy:   make(map[uuid.UUID][]bool),
+		subjectPerDay: make(map[subjectDay]int),
+	}
+
+	// Most loaded teachers first: they are the hardest to fit
+	order := make([]int, len(input.Requirements))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return load[input.Requirements[order[i]].TeacherID] > load[input.Requirements[order[j]].TeacherID]
+	})
+	for _, i := range order {
+		req := input.Requirements[i]
+		prev := -1
+		for h := 0; h < req.HoursPerWeek; h++ {
+			g.lessons = append(g.lessons, timetableLesson{req: i, classID: req.ClassID, subject: req.SubjectID, teacher: req.TeacherID, prev: prev})
+			prev = len(g.lessons) - 1
+		}
+		g.classSlots(req.ClassID)
+		g.teacherSlots(req.TeacherID)
+	}
+	g.assigned = make([]int, len(g.lessons))
+	for i := range g.assigned {
+		g.assigned[i] = -1
+	}
+	return g
+}
+
+func (g *timetableGenerator) classSlots(id uint) []bool {
+	if g.classBusy[id] == nil {
+		g.classBusy[id] = make([]bool, len(g.periods))
+	}
+	return g.classBusy[id]
+}
+
+func (g *timetableGenerator) teacherSlots(id uuid.UUID) []bool {
+	if g.teacherBusy[id] == nil {
+		g.teacherBusy[id] = make([]bool, len(g.periods))
+	}
+	return g.teacherBusy[id]
+}
+
+// block marks every period overlapping the given range as taken for teacherID.
+func (g *timetableGenerator) block(teacherID uuid.UUID, day domain.Weekday, startTime, endTime domain.TimeOfDay) {
+	slots := g.teacherSlots(teacherID)
+	for i, period := range g.periods {
+		if period.Day == day && period.StartTime < endTime && startTime < period.EndTime {
+			slots[i] = true
+		}
+	}
+}
+
+func (g *timetableGenerator) fits(lesson timetableLesson, slot int) bool {
+	if g.classBusy[lesson.classID][slot] || g.teacherBusy[lesson.teacher][slot] {
+		return false
+	}
+	if lesson.prev >= 0 && g.assigned[lesson.prev] >= slot {
+		return false
+	}
+	key := subjectDay{lesson.classID, lesson.subject, g.periods[slot].Day}
+	return g.maxDaily == 0 || g.subjectPerDay[key] < g.maxDaily
+}
+
+func (g *timetableGenerator) place(i, slot int, on bool) {
+	lesson := g.lessons[i]
+	g.classBusy[lesson.classID][slot] = on
+	g.teacherBusy[lesson.teacher][slot] = on
+	key := subjectDay{lesson.classID, lesson.subject, g.periods[slot].Day}
+	if on {
+		g.assigned[i] = slot
+		g.subjectPerDay[key]++
+	} else {
+		g.assigned[i] = -1
+		g.subjectPerDay[key]--
+	}
+}
+
+// candidates orders the free periods for a lesson, preferring days on which
+// the class has the fewest periods of that subject.
+func (g *timetableGenerator) candidates(i int) []int {
+	lesson := g.lessons[i]
+	var slots []int
+	for slot := range g.periods {
+		if g.fits(lesson, slot) {
+			slots = append(slots, slot)
+		}
+	}
+	sort.SliceStable(slots, func(a, b int) bool {
+		ka := subjectDay{lesson.classID, lesson.subject, g.periods[slots[a]].Day}
+		kb := subjectDay{lesson.classID, lesson.subject, g.periods[slots[b]].Day}
+		return g.subjectPerDay[ka] < g.subjectPerDay[kb]
+	})
+	return slots
+}
+
+func (g *timetableGenerator) search(i int) bool {
+	if i == len(g.lessons) {
+		return true
+	}
+	g.steps++
+	if g.steps > timetableSearchBudget {
+		return false
+	}
+	for _, slot := range g.candidates(i) {
+		g.place(i, slot, true)
+		if g.search(i + 1) {
+			return true
+		}
+		g.place(i, slot, false)
+	}
+	return false
+}
+
+// run tries a complete assignment first and otherwise places greedily,
+// leaving lessons that do not fit unassigned.
+func (g *timetableGenerator) run() {
+	if g.search(0) {
+		return
+	}
+	// The failed search has undone all of its placements
+	for i := range g.lessons {
+		if slots := g.candidates(i); len(slots) > 0 {
+			g.place(i, slots[0], true)
+		}
+	}
+}
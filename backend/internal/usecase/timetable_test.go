@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"ppi-100-sis/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// weekPeriods returns perDay 40-minute periods from 07:00 on each day.
+func weekPeriods(perDay int, days ...domain.Weekday) []TimetablePeriod {
+	var periods []TimetablePeriod
+	for _, day := range days {
+		for p := 0; p < perDay; p++ {
+			start := domain.Clock(7, 0) + domain.TimeOfDay(p*40)
+			periods = append(periods, TimetablePeriod{Day: day, StartTime: start, EndTime: start + 40})
+		}
+	}
+	return periods
+}
+
+func generate(periods []TimetablePeriod, input TimetableInput) *timetableGenerator {
+	load := make(map[uuid.UUID]int)
+	for _, req := range input.Requirements {
+		load[req.TeacherID] += req.HoursPerWeek
+	}
+	return newTimetableGenerator(periods, input, load)
+}
+
+// checkNoDoubleBooking fails if a class or teacher holds two lessons in one period.
+func checkNoDoubleBooking(t *testing.T, g *timetableGenerator) {
+	t.Helper()
+	type classSlot struct {
+		class uint
+		slot  int
+	}
+	type teacherSlot struct {
+		teacher uuid.UUID
+		slot    int
+	}
+	classes := map[classSlot]bool{}
+	teachers := map[teacherSlot]bool{}
+	for i, slot := range g.assigned {
+		if slot < 0 {
+			continue
+		}
+		lesson := g.lessons[i]
+		ck, tk := classSlot{lesson.classID, slot}, teacherSlot{lesson.teacher, slot}
+		if classes[ck] || teachers[tk] {
+			t.Fatalf("lesson %d double-books period %d", i, slot)
+		}
+		classes[ck], teachers[tk] = true, true
+	}
+}
+
+func TestTimetableGeneratorPlacesEverything(t *testing.T) {
+	teacherA, teacherB := uuid.New(), uuid.New()
+	input := TimetableInput{Requirements: []TimetableRequirement{
+		{ClassID: 1, SubjectID: 1, TeacherID: teacherA, HoursPerWeek: 3},
+		{ClassID: 1, SubjectID: 2, TeacherID: teacherB, HoursPerWeek: 2},
+		{ClassID: 2, SubjectID: 1, TeacherID: teacherA, HoursPerWeek: 3},
+		{ClassID: 2, SubjectID: 2, TeacherID: teacherB, HoursPerWeek: 2},
+	}}
+	g := generate(weekPeriods(3, domain.Monday, domain.Tuesday), input)
+	g.run()
+
+	for i, slot := range g.assigned {
+		if slot < 0 {
+			t.Fatalf("lesson %d was not placed", i)
+		}
+		if prev := g.lessons[i].prev; prev >= 0 && g.assigned[prev] >= slot {
+			t.Errorf("lesson %d is not after lesson %d of the same requirement", i, prev)
+		}
+	}
+	checkNoDoubleBooking(t, g)
+}
+
+func TestTimetableGeneratorBacktracks(t *testing.T) {
+	// Greedily, teacherA takes the first period and teacherB, who is away
+	// for the second, has nowhere to go. The search must swap them.
+	teacherA, teacherB := uuid.New(), uuid.New()
+	periods := weekPeriods(2, domain.Monday)
+	input := TimetableInput{Requirements: []TimetableRequirement{
+		{ClassID: 1, SubjectID: 1, TeacherID: teacherA, HoursPerWeek: 1},
+		{ClassID: 1, SubjectID: 2, TeacherID: teacherB, HoursPerWeek: 1},
+	}}
+	g := generate(periods, input)
+	g.block(teacherB, domain.Monday, periods[1].StartTime, periods[1].EndTime)
+	g.run()
+
+	if g.assigned[0] != 1 || g.assigned[1] != 0 {
+		t.Fatalf("assigned = %v, want [1 0]", g.assigned)
+	}
+}
+
+func TestTimetableGeneratorFallsBackToPartial(t *testing.T) {
+	teacherA, teacherB := uuid.New(), uuid.New()
+	input := TimetableInput{Requirements: []TimetableRequirement{
+		{ClassID: 1, SubjectID: 1, TeacherID: teacherA, HoursPerWeek: 2},
+		{ClassID: 1, SubjectID: 2, TeacherID: teacherB, HoursPerWeek: 1},
+	}}
+	g := generate(weekPeriods(2, domain.Monday), input)
+	g.run()
+
+	placed := 0
+	for _, slot := range g.assigned {
+		if slot >= 0 {
+			placed++
+		}
+	}
+	if placed != 2 {
+		t.Fatalf("placed %d lessons, want the 2 that fit", placed)
+	}
+	checkNoDoubleBooking(t, g)
+}
+
+func TestTimetableGeneratorDailySubjectLimit(t *testing.T) {
+	teacher := uuid.New()
+	input := TimetableInput{
+		Requirements:         []TimetableRequirement{{ClassID: 1, SubjectID: 1, TeacherID: teacher, HoursPerWeek: 3}},
+		MaxDailySubjectHours: 1,
+	}
+	g := generate(weekPeriods(3, domain.Monday, domain.Tuesday), input)
+	g.run()
+
+	days := map[domain.Weekday]int{}
+	unplaced := 0
+	for _, slot := range g.assigned {
+		if slot < 0 {
+			unplaced++
+			continue
+		}
+		days[g.periods[slot].Day]++
+	}
+	if days[domain.Monday] != 1 || days[domain.Tuesday] != 1 || unplaced != 1 {
+		t.Fatalf("per day = %v, unplaced = %d; want one a day and one unplaced", days, unplaced)
+	}
+}
+
+func TestTimetableGeneratorBlockOverlap(t *testing.T) {
+	teacher := uuid.New()
+	periods := weekPeriods(3, domain.Monday)
+	g := generate(periods, TimetableInput{Requirements: []TimetableRequirement{{ClassID: 1, SubjectID: 1, TeacherID: teacher, HoursPerWeek: 1}}})
+
+	// 07:30-07:50 touches the first two periods only
+	g.block(teacher, domain.Monday, domain.Clock(7, 30), domain.Clock(7, 50))
+	want := []bool{true, true, false}
+	for i, busy := range g.teacherBusy[teacher] {
+		if busy != want[i] {
+			t.Fatalf("teacherBusy = %v, want %v", g.teacherBusy[teacher], want)
+		}
+	}
+
+	// Ranges touching a period's edges or on another day do not block it
+	other := uuid.New()
+	g.block(other, domain.Monday, domain.Clock(6, 0), periods[0].StartTime)
+	g.block(other, domain.Monday, periods[2].EndTime, domain.Clock(12, 0))
+	g.block(other, domain.Tuesday, periods[1].StartTime, periods[1].EndTime)
+	for i, busy := range g.teacherBusy[other] {
+		if busy {
+			t.Fatalf("period %d blocked by a range that does not overlap it", i)
+		}
+	}
+}
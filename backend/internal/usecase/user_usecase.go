@@ -1,63 +1,153 @@
 package usecase
 
 import (
+	"errors"
+	"ppi-100-sis/internal/config"
 	"ppi-100-sis/internal/domain"
 	"ppi-100-sis/internal/repository/postgres"
 	"ppi-100-sis/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrAdminRoleForbidden = errors.New("only Super Admin can manage administrators")
 )
 
 type UserUsecase struct {
-	userRepo *postgres.UserRepository
+	userRepo  *postgres.UserRepository
+	tokenRepo *postgres.TokenRepository
+	cfg       *config.Config
 }
 
-func NewUserUsecase(userRepo *postgres.UserRepository) *UserUsecase {
-	return &UserUsecase{userRepo: userRepo}
+func NewUserUsecase(userRepo *postgres.UserRepository, tokenRepo *postgres.TokenRepository, cfg *config.Config) *UserUsecase {
+	return &UserUsecase{userRepo: userRepo, tokenRepo: tokenRepo, cfg: cfg}
+}
+
+// checkAdminRole fails unless the actor is Super Admin whenever roleID is an
+// admin role, so unit admins cannot hand out or touch administrator accounts.
+func checkAdminRole(roleID, actorRoleID uint) error {
+	if domain.IsAdminRole(roleID) && actorRoleID != domain.RoleSuperAdmin {
+		return ErrAdminRoleForbidden
+	}
+	return nil
 }
 
-func (u *UserUsecase) GetAllUsers() ([]domain.User, error) {
-	return u.userRepo.GetAll()
+func (u *UserUsecase) GetAllUsers(unitID uint) ([]domain.User, error) {
+	return u.userRepo.GetAll(unitID)
 }
 
-func (u *UserUsecase) CreateUser(name, email, password string, roleID, unitID uint) error {
+// CreateUser creates an account with an admin-assigned password, which the
+// user must replace on first login.
+func (u *UserUsecase) CreateUser(name, email, password string, roleID, unitID, actorRoleID uint) error {
+	if roleID < domain.RoleSuperAdmin || roleID > domain.RoleOrangTua {
+		return ErrInvalidRole
+	}
+	if err := checkAdminRole(roleID, actorRoleID); err != nil {
+		return err
+	}
+	if err := utils.ValidatePassword(password, u.cfg); err != nil {
+		return err
+	}
+
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
 		return err
 	}
 
 	user := &domain.User{
-		Name:         name,
-		Email:        email,
-		PasswordHash: hashedPassword,
-		RoleID:       roleID,
-		UnitID:       unitID,
+		Name:               name,
+		Email:              email,
+		PasswordHash:       hashedPassword,
+		RoleID:             roleID,
+		UnitID:             unitID,
+		MustChangePassword: true,
 	}
 
 	return u.userRepo.Create(user)
 }
 
-func (u *UserUsecase) UpdateUser(user *domain.User) error {
+// UpdateUser applies the non-empty fields of user onto the stored user.
+// user.PasswordHash carries a new plain password, if any. Changing the
+// password, role or unit revokes every token already issued to the user; a
+// password set here must be replaced by the user on next login. unitID
+// confines unit admins to their own users; 0 means any unit.
+func (u *UserUsecase) UpdateUser(user *domain.User, unitID, actorRoleID uint) error {
+	existing, err := u.userRepo.FindInUnit(user.ID.String(), unitID)
+	if err != nil {
+		return err
+	}
+	if err := checkAdminRole(existing.RoleID, actorRoleID); err != nil {
+		return err
+	}
+	if user.RoleID != 0 {
+		if user.RoleID > domain.RoleOrangTua {
+			return ErrInvalidRole
+		}
+		if err := checkAdminRole(user.RoleID, actorRoleID); err != nil {
+			return err
+		}
+	}
+
+	revoke := false
+	if user.Name != "" {
+		existing.Name = user.Name
+	}
+	if user.Email != "" {
+		existing.Email = user.Email
+	}
+	if user.RoleID != 0 && user.RoleID != existing.RoleID {
+		existing.RoleID = user.RoleID
+		revoke = true
+	}
+	if user.UnitID != 0 && user.UnitID != existing.UnitID {
+		existing.UnitID = user.UnitID
+		revoke = true
+	}
 	if user.PasswordHash != "" {
-		hashedPassword, err := utils.HashPassword(user.PasswordHash)
-		if err != nil {
+		if err := utils.ValidatePassword(user.PasswordHash, u.cfg); err != nil {
 			return err
 		}
-		user.PasswordHash = hashedPassword
-	} else {
-		// Fetch existing user to keep old password if not provided
-		existingUser, err := u.userRepo.FindByID(user.ID.String())
+		hashedPassword, err := utils.HashPassword(user.PasswordHash)
 		if err != nil {
 			return err
 		}
-		user.PasswordHash = existingUser.PasswordHash
+		existing.PasswordHash = hashedPassword
+		existing.MustChangePassword = true
+		revoke = true
+	}
+
+	if revoke {
+		existing.TokenVersion++
+	}
+	if err := u.userRepo.Update(existing); err != nil {
+		return err
 	}
-	return u.userRepo.Update(user)
+	if revoke {
+		return u.tokenRepo.RevokeAllForUser(existing.ID)
+	}
+	return nil
 }
 
-func (u *UserUsecase) DeleteUser(id string) error {
-	return u.userRepo.Delete(id)
+func (u *UserUsecase) DeleteUser(id string, unitID, actorRoleID uint) error {
+	existing, err := u.userRepo.FindInUnit(id, unitID)
+	if err != nil {
+		return err
+	}
+	if err := checkAdminRole(existing.RoleID, actorRoleID); err != nil {
+		return err
+	}
+	if err := u.userRepo.Delete(id); err != nil {
+		return err
+	}
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+	return u.tokenRepo.RevokeAllForUser(userID)
 }
 
 func (u *UserUsecase) GetUserByID(id string) (*domain.User, error) {
 	return u.userRepo.FindByID(id)
 }
-
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"ppi-100-sis/internal/domain"
+	"ppi-100-sis/internal/repository/postgres"
+	"sort"
+)
+
+// CertificationMinJP is the weekly teaching load required for teacher
+// certification (sertifikasi guru).
+const CertificationMinJP = 24
+
+// Workload statuses
+const (
+	WorkloadUnder   = "under" // Below CertificationMinJP
+	WorkloadOK      = "ok"
+	WorkloadOver    = "over"    // Above the configured maximum
+	WorkloadPartial = "partial" // Teacher of another unit, only this unit's lessons counted
+)
+
+type SubjectWorkload struct {
+	Subject domain.Subject `json:"subject"`
+	Lessons int            `json:"lessons"` // Schedule rows per week
+	JP      int            `json:"jp"`
+}
+
+type TeacherWorkload struct {
+	Teacher  domain.Teacher    `json:"teacher"`
+	Subjects []SubjectWorkload `json:"subjects"`
+	JP       int               `json:"jp"`
+	Status   string            `json:"status"`
+}
+
+type WorkloadReport struct {
+	MinJP    int               `json:"min_jp"`
+	MaxJP    int               `json:"max_jp"`
+	Teachers []TeacherWorkload `json:"teachers"`
+}
+
+// lessonJP counts the JP a weekly lesson takes: the teaching periods of its
+// unit's bell schedule it covers, or else its length in LessonMinutes.
+func lessonJP(schedule *domain.Schedule, periods []domain.Period, lessonMinutes int) int {
+	jp := 0
+	for _, p := range periods {
+		if p.Day == schedule.Day && !p.IsBreak && p.StartTime < schedule.EndTime && schedule.StartTime < p.EndTime {
+			jp++
+		}
+	}
+	if jp > 0 {
+		return jp
+	}
+	if lessonMinutes <= 0 {
+		lessonMinutes = 40
+	}
+	// Round to the nearest JP, but never count a lesson as zero
+	jp = (int(schedule.EndTime-schedule.StartTime) + lessonMinutes/2) / lessonMinutes
+	if jp < 1 {
+		jp = 1
+	}
+	return jp
+}
+
+// GetTeacherWorkload sums weekly JP per teacher and subject over the term's
+// schedules. Teachers without lessons are listed with zero JP. Under a unit
+// scope, teachers of other units only have their lessons in the unit counted,
+// so they are never flagged under the minimum.
+func (u *AcademicUsecase) GetTeacherWorkload(unitID uint, term postgres.TermFilter) (*WorkloadReport, error) {
+	schedules, err := u.academicRepo.GetAllSchedules(unitID, postgres.ScheduleFilter{}, term)
+	if err != nil {
+		return nil, err
+	}
+	teachers, err := u.teacherRepo.GetAll(unitID)
+	if err != nil {
+		return nil, err
+	}
+	allPeriods, err := u.academicRepo.GetPeriods(unitID)
+	if err != nil {
+		return nil, err
+	}
+	periods := map[uint][]domain.Period{}
+	for _, p := range allPeriods {
+		periods[p.UnitID] = append(periods[p.UnitID], p)
+	}
+
+	report := &WorkloadReport{MinJP: CertificationMinJP, MaxJP: u.cfg.TeachingLoadMax}
+	index := map[string]int{}
+	for _, teacher := range teachers {
+		index[teacher.ID.String()] = len(report.Teachers)
+		report.Teachers = append(report.Teachers, TeacherWorkload{Teacher: teacher, Subjects: []SubjectWorkload{}})
+	}
+
+	for i := range schedules {
+		s := &schedules[i]
+		// Teachers of another unit can still teach classes of this one
+		at, ok := index[s.TeacherID.String()]
+		if !ok {
+			at = len(report.Teachers)
+			index[s.TeacherID.String()] = at
+			report.Teachers = append(report.Teachers, TeacherWorkload{Teacher: s.Teacher, Subjects: []SubjectWorkload{}})
+		}
+		load := &report.Teachers[at]
+		jp := lessonJP(s, periods[s.Class.UnitID], u.cfg.LessonMinutes)
+		load.JP += jp
+
+		found := false
+		for j := range load.Subjects {
+			if load.Subjects[j].Subject.ID == s.SubjectID {
+				load.Subjects[j].Lessons++
+				load.Subjects[j].JP += jp
+				found = true
+				break
+			}
+		}
+		if !found {
+			load.Subjects = append(load.Subjects, SubjectWorkload{Subject: s.Subject, Lessons: 1, JP: jp})
+		}
+	}
+
+	for i := range report.Teachers {
+		load := &report.Teachers[i]
+		switch {
+		case report.MaxJP > 0 && load.JP > report.MaxJP:
+			load.Status = WorkloadOver
+		case unitID != 0 && load.Teacher.UnitID != unitID:
+			load.Status = WorkloadPartial
+		case load.JP < report.MinJP:
+			load.Status = WorkloadUnder
+		default:
+			load.Status = WorkloadOK
+		}
+		sort.Slice(load.Subjects, func(a, b int) bool { return load.Subjects[a].Subject.Name < load.Subjects[b].Subject.Name })
+	}
+	sort.SliceStable(report.Teachers, func(a, b int) bool {
+		return report.Teachers[a].Teacher.User.Name < report.Teachers[b].Teacher.User.Name
+	})
+	return report, nil
+}
@@ -0,0 +1,119 @@
+// Package ical writes iCalendar (RFC 5545) feeds.
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+// Event is a VEVENT. AllDay events use only the dates of Start and End, with
+// End inclusive. A timed event with a zero End is a point in time.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Categories  string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	RRule       string      // e.g. FREQ=WEEKLY;UNTIL=20261220T000000Z
+	ExDates     []time.Time // Skipped occurrences of RRule, same kind as Start
+}
+
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+)
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// FormatUTC formats t as a UTC date-time, e.g. for an RRULE UNTIL part.
+func FormatUTC(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// Marshal renders the calendar with CRLF line endings and folded lines.
+func (c *Calendar) Marshal() []byte {
+	var b bytes.Buffer
+	now := time.Now().UTC().Format(dateTimeLayout)
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//PPI 100 Banjarsari//SIS//ID")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escaper.Replace(c.Name))
+	}
+	for _, e := range c.Events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+e.UID)
+		writeLine(&b, "DTSTAMP:"+now)
+		if e.AllDay {
+			writeLine(&b, "DTSTART;VALUE=DATE:"+e.Start.Format(dateLayout))
+			// DTEND of all-day events is exclusive
+			writeLine(&b, "DTEND;VALUE=DATE:"+e.End.AddDate(0, 0, 1).Format(dateLayout))
+		} else {
+			writeLine(&b, "DTSTART:"+e.Start.UTC().Format(dateTimeLayout))
+			if !e.End.IsZero() {
+				writeLine(&b, "DTEND:"+e.End.UTC().Format(dateTimeLayout))
+			}
+		}
+		if e.RRule != "" {
+			writeLine(&b, "RRULE:"+e.RRule)
+		}
+		if len(e.ExDates) > 0 {
+			dates := make([]string, len(e.ExDates))
+			for i, d := range e.ExDates {
+				if e.AllDay {
+					dates[i] = d.Format(dateLayout)
+				} else {
+					dates[i] = d.UTC().Format(dateTimeLayout)
+				}
+			}
+			if e.AllDay {
+				writeLine(&b, "EXDATE;VALUE=DATE:"+strings.Join(dates, ","))
+			} else {
+				writeLine(&b, "EXDATE:"+strings.Join(dates, ","))
+			}
+		}
+		writeLine(&b, "SUMMARY:"+escaper.Replace(e.Summary))
+		if e.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escaper.Replace(e.Description))
+		}
+		if e.Location != "" {
+			writeLine(&b, "LOCATION:"+escaper.Replace(e.Location))
+		}
+		if e.Categories != "" {
+			writeLine(&b, "CATEGORIES:"+escaper.Replace(e.Categories))
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return b.Bytes()
+}
+
+// writeLine folds content lines longer than 75 octets without splitting a
+// UTF-8 sequence.
+func writeLine(b *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
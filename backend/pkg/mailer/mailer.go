@@ -0,0 +1,19 @@
+package mailer
+
+import (
+	"ppi-100-sis/internal/config"
+)
+
+// Mailer sends plain-text email.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// New returns the Mailer selected by MAIL_DRIVER: "smtp" sends real mail,
+// anything else appends messages to MAIL_LOG_PATH for local development.
+func New(cfg *config.Config) Mailer {
+	if cfg.MailDriver == "smtp" {
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+	return NewLogMailer(cfg.MailLogPath)
+}
@@ -9,20 +9,54 @@ import (
 	"github.com/google/uuid"
 )
 
+const mfaChallengeAudience = "mfa_challenge"
+
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	RoleID uint      `json:"role_id"`
-	UnitID uint      `json:"unit_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	RoleID       uint      `json:"role_id"`
+	UnitID       uint      `json:"unit_id"`
+	TokenVersion int       `json:"token_version"`
+	// Set when the role enforces 2FA and the user has not enrolled yet
+	MFAEnrollmentRequired bool `json:"mfa_enrollment_required,omitempty"`
+	// Set while an admin-assigned password has not been replaced yet
+	PasswordChangeRequired bool `json:"password_change_required,omitempty"`
+	// Set on impersonation tokens: the Super Admin acting as UserID
+	ImpersonatorID *uuid.UUID `json:"impersonator_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// GenerateToken issues a short-lived access token. TokenVersion must match
+// User.TokenVersion for the token to be accepted by AuthMiddleware.
+// Impersonation tokens get their own, usually shorter, lifetime.
+func GenerateToken(claims *Claims, cfg *config.Config) (string, error) {
+	ttl := cfg.AccessTokenTTL
+	if claims.ImpersonatorID != nil {
+		ttl = cfg.ImpersonationTTL
+	}
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(cfg.JWTSecret))
+}
+
+type MFAChallengeClaims struct {
+	UserID       uuid.UUID `json:"user_id"`
+	TokenVersion int       `json:"token_version"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uuid.UUID, roleID, unitID uint, cfg *config.Config) (string, error) {
-	claims := &Claims{
-		UserID: userID,
-		RoleID: roleID,
-		UnitID: unitID,
+// GenerateMFAChallengeToken issues the token handed out after a correct
+// password when a second factor is still needed. It is not an access token.
+func GenerateMFAChallengeToken(userID uuid.UUID, tokenVersion int, cfg *config.Config) (string, error) {
+	claims := &MFAChallengeClaims{
+		UserID:       userID,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			Audience:  jwt.ClaimStrings{mfaChallengeAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.MFAChallengeTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -31,16 +65,33 @@ func GenerateToken(userID uuid.UUID, roleID, unitID uint, cfg *config.Config) (s
 	return token.SignedString([]byte(cfg.JWTSecret))
 }
 
+func ValidateMFAChallengeToken(tokenString string, cfg *config.Config) (*MFAChallengeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &MFAChallengeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(cfg.JWTSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(mfaChallengeAudience))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*MFAChallengeClaims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
+
 func ValidateToken(tokenString string, cfg *config.Config) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(cfg.JWTSecret), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	// Access tokens carry no audience; anything with one (MFA challenge) is not an access token
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && len(claims.Audience) == 0 {
 		return claims, nil
 	}
 
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"ppi-100-sis/internal/config"
+	"strings"
+	"unicode"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = loadCommonPasswords(commonPasswordsFile)
+
+// ErrWeakPassword wraps every policy violation so callers can map it to 400.
+var ErrWeakPassword = errors.New("password does not meet the policy")
+
+// ValidatePassword checks password against the configured policy: minimum
+// length, required character classes and the embedded common-password list.
+func ValidatePassword(password string, cfg *config.Config) error {
+	if len([]rune(password)) < cfg.PasswordMinLength {
+		return fmt.Errorf("%w: it must be at least %d characters", ErrWeakPassword, cfg.PasswordMinLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	var missing []string
+	if cfg.PasswordRequireUpper && !hasUpper {
+		missing = append(missing, "an uppercase letter")
+	}
+	if cfg.PasswordRequireLower && !hasLower {
+		missing = append(missing, "a lowercase letter")
+	}
+	if cfg.PasswordRequireDigit && !hasDigit {
+		missing = append(missing, "a digit")
+	}
+	if cfg.PasswordRequireSymbol && !hasSymbol {
+		missing = append(missing, "a symbol")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: it must contain %s", ErrWeakPassword, strings.Join(missing, ", "))
+	}
+
+	if cfg.PasswordDenylist && commonPasswords[strings.ToLower(password)] {
+		return fmt.Errorf("%w: it is too common", ErrWeakPassword)
+	}
+	return nil
+}
+
+func loadCommonPasswords(data string) map[string]bool {
+	passwords := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = true
+	}
+	return passwords
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// GenerateRandomToken returns a URL-safe random string for opaque tokens
+// (refresh tokens, reset links). Only its HashToken digest should be stored.
+func GenerateRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex SHA-256 digest of an opaque token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SignValue returns value with an HMAC-SHA256 signature appended, so it can
+// be handed out and later checked with VerifySignedValue.
+func SignValue(value, secret string) string {
+	return value + "." + sign(value, secret)
+}
+
+// VerifySignedValue returns the value from a SignValue string, or false if
+// the signature does not match.
+func VerifySignedValue(signed, secret string) (string, bool) {
+	i := strings.LastIndex(signed, ".")
+	if i < 0 {
+		return "", false
+	}
+	value, sig := signed[:i], signed[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(sign(value, secret))) {
+		return "", false
+	}
+	return value, true
+}
+
+func sign(value, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of RFC 6238 appendix B, "12345678901234567890", base32 encoded.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	key, err := totpEncoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The RFC lists 8 digits; a 6 digit code is their last six
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/totpPeriod); got != tt.want {
+			t.Errorf("totpCode at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := now.Unix() / totpPeriod
+	key, err := totpEncoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codeAt := func(offset int64) string { return totpCode(key, step+offset) }
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", rfc6238Secret, codeAt(0), step, true},
+		{"previous step within skew", rfc6238Secret, codeAt(-1), step - 1, true},
+		{"next step within skew", rfc6238Secret, codeAt(1), step + 1, true},
+		{"two steps old", rfc6238Secret, codeAt(-2), 0, false},
+		{"two steps ahead", rfc6238Secret, codeAt(2), 0, false},
+		{"lower-case secret with spaces", " gezdgnbvgy3tqojqgezdgnbvgy3tqojq ", codeAt(0), step, true},
+		{"wrong length", rfc6238Secret, codeAt(0)[:5], 0, false},
+		{"wrong code", rfc6238Secret, "000000", 0, false},
+		{"undecodable secret", "not base32!", codeAt(0), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, gotOK := ValidateTOTP(tt.secret, tt.code, now)
+			if gotOK != tt.wantOK || gotStep != tt.wantStep {
+				t.Errorf("ValidateTOTP = (%d, %v), want (%d, %v)", gotStep, gotOK, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+// A code stays valid for the skew after its step, but reports the step it was
+// issued for, so a replay is not newer than the last accepted step.
+func TestValidateTOTPReplayKeepsStep(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code := "005924"
+	first, ok := ValidateTOTP(rfc6238Secret, code, now)
+	if !ok {
+		t.Fatal("code not accepted at its own step")
+	}
+	again, ok := ValidateTOTP(rfc6238Secret, code, now.Add(totpPeriod*time.Second))
+	if !ok {
+		t.Fatal("code not accepted one step later")
+	}
+	if again != first {
+		t.Errorf("replayed code reports step %d, want %d from its first use", again, first)
+	}
+}
@@ -2,8 +2,19 @@ import React from 'react';
 import { BrowserRouter as Router, Routes, Route, Navigate } from 'react-router-dom';
 import { AuthProvider, useAuth } from './context/AuthContext';
 import Login from './pages/Login';
+import ForgotPassword from './pages/ForgotPassword';
+import ResetPassword from './pages/ResetPassword';
+import AcceptInvitation from './pages/AcceptInvitation';
 import DashboardLayout from './components/layouts/DashboardLayout';
 import Academic from './pages/admin/Academic';
+import AcademicTerms from './pages/admin/AcademicTerms';
+import StudentPromotion from './pages/admin/StudentPromotion';
+import TimetableGenerator from './pages/admin/TimetableGenerator';
+import Rooms from './pages/admin/Rooms';
+import Substitutions from './pages/admin/Substitutions';
+import SchoolCalendar from './pages/admin/SchoolCalendar';
+import TeacherWorkloadReport from './pages/admin/TeacherWorkload';
+import GradeWeightSettings from './pages/admin/GradeWeights';
 import UserManagement from './pages/admin/UserManagement';
 import Students from './pages/admin/Students';
 import Settings from './pages/Settings';
@@ -14,10 +25,15 @@ import Elearning from './pages/admin/Elearning';
 import Notifications from './pages/admin/Notifications';
 import TeacherSchedule from './pages/teacher/TeacherSchedule';
 import TeacherGrades from './pages/teacher/TeacherGrades';
+import Gradebook from './pages/teacher/Gradebook';
+import RemedialList from './pages/teacher/RemedialList';
+import LearningObjectives from './pages/teacher/LearningObjectives';
 import HomeroomStudents from './pages/teacher/HomeroomStudents';
 import TeacherAttendance from './pages/teacher/TeacherAttendance';
 import HomeroomClass from './pages/teacher/HomeroomClass';
 import HomeroomReportCards from './pages/teacher/HomeroomReportCards';
+import HomeroomNotes from './pages/teacher/HomeroomNotes';
+import P5Projects from './pages/teacher/P5Projects';
 import TeacherBKReport from './pages/teacher/TeacherBKReport';
 import PublicLayout from './components/layouts/PublicLayout';
 import Home from './pages/public/Home';
@@ -58,6 +74,9 @@ function App() {
             <Router>
                 <Routes>
                     <Route path="/login" element={<Login />} />
+                    <Route path="/forgot-password" element={<ForgotPassword />} />
+                    <Route path="/reset-password" element={<ResetPassword />} />
+                    <Route path="/accept-invitation" element={<AcceptInvitation />} />
                     <Route path="/dashboard/*" element={
                         <PrivateRoute>
                             <DashboardLayout>
@@ -65,6 +84,14 @@ function App() {
                                     <Route path="/" element={<DashboardHome />} />
                                     <Route path="academic" element={<Academic />} />
                                     <Route path="admin/academic" element={<Academic />} />
+                                    <Route path="admin/terms" element={<AcademicTerms />} />
+                                    <Route path="admin/promotion" element={<StudentPromotion />} />
+                                    <Route path="admin/timetable" element={<TimetableGenerator />} />
+                                    <Route path="rooms" element={<Rooms />} />
+                                    <Route path="admin/substitutions" element={<Substitutions />} />
+                                    <Route path="admin/calendar" element={<SchoolCalendar />} />
+                                    <Route path="admin/workload" element={<TeacherWorkloadReport />} />
+                                    <Route path="admin/grade-weights" element={<GradeWeightSettings />} />
                                     <Route path="users" element={<UserManagement />} />
                                     <Route path="settings" element={<Settings />} />
 
@@ -91,9 +118,14 @@ function App() {
                                     <Route path="teacher/schedule" element={<TeacherSchedule />} />
                                     <Route path="teacher/students" element={<HomeroomStudents />} />
                                     <Route path="teacher/grades" element={<TeacherGrades />} />
+                                    <Route path="gradebook" element={<Gradebook />} />
+                                    <Route path="remedial" element={<RemedialList />} />
+                                    <Route path="learning-objectives" element={<LearningObjectives />} />
                                     <Route path="attendance/:scheduleId" element={<TeacherAttendance />} />
                                     <Route path="homeroom" element={<HomeroomClass />} />
                                     <Route path="homeroom/report-card/:studentId" element={<HomeroomReportCards />} />
+                                    <Route path="homeroom/notes" element={<HomeroomNotes />} />
+                                    <Route path="homeroom/p5" element={<P5Projects />} />
                                     <Route path="student/schedule" element={<StudentSchedule />} />
                                     <Route path="student/elearning" element={<StudentElearning />} />
                                     <Route path="student/grades" element={<StudentGrades />} />
@@ -0,0 +1,43 @@
+import React from 'react';
+import { useQuery } from '@tanstack/react-query';
+import api from '../../services/api';
+import { LearningObjective } from '../../lib/gradebook';
+
+interface ObjectiveChecklistProps {
+    subjectId?: number | string;
+    value: number[];
+    onChange: (ids: number[]) => void;
+}
+
+// Picks the learning objectives a task or an assessment covers
+const ObjectiveChecklist: React.FC<ObjectiveChecklistProps> = ({ subjectId, value, onChange }) => {
+    const { data: objectives } = useQuery({
+        queryKey: ['learning-objectives', String(subjectId)],
+        queryFn: async () => (await api.get(`/academic/subjects/${subjectId}/objectives`)).data as LearningObjective[],
+        enabled: !!subjectId,
+    });
+
+    if (!subjectId) return null;
+
+    const toggle = (id: number) => onChange(value.includes(id) ? value.filter((v) => v !== id) : [...value, id]);
+
+    return (
+        <div>
+            <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Tujuan Pembelajaran</label>
+            {objectives?.length ? (
+                <div className="max-h-40 overflow-y-auto space-y-1 rounded-lg border border-slate-200 bg-white/50 p-2">
+                    {objectives.map((o) => (
+                        <label key={o.id} className="flex items-start gap-2 text-sm text-slate-700">
+                            <input type="checkbox" className="mt-1" checked={value.includes(o.id)} onChange={() => toggle(o.id)} />
+                            <span>{o.code && <span className="font-medium">{o.code} </span>}{o.description}</span>
+                        </label>
+                    ))}
+                </div>
+            ) : (
+                <p className="text-xs text-slate-500 ml-1">Mata pelajaran ini belum memiliki tujuan pembelajaran.</p>
+            )}
+        </div>
+    );
+};
+
+export default ObjectiveChecklist;
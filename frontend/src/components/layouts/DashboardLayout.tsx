@@ -1,7 +1,8 @@
 import React, { useState } from 'react';
 import Sidebar from './Sidebar';
 import Header from './Header';
-import { Menu } from 'lucide-react';
+import { Menu, Eye } from 'lucide-react';
+import { useAuth } from '../../context/AuthContext';
 
 interface DashboardLayoutProps {
     children: React.ReactNode;
@@ -9,6 +10,7 @@ interface DashboardLayoutProps {
 
 const DashboardLayout: React.FC<DashboardLayoutProps> = ({ children }) => {
     const [isSidebarOpen, setIsSidebarOpen] = useState(false);
+    const { user, isImpersonating, stopImpersonation } = useAuth();
 
     return (
         <div className="min-h-screen flex relative">
@@ -21,6 +23,17 @@ const DashboardLayout: React.FC<DashboardLayoutProps> = ({ children }) => {
             <Sidebar isOpen={isSidebarOpen} onClose={() => setIsSidebarOpen(false)} />
 
             <div className="flex-1 flex flex-col min-h-screen relative z-10">
+                {isImpersonating && (
+                    <div className="sticky top-0 z-20 px-4 py-2 flex items-center justify-between gap-4 bg-amber-500 text-white text-sm font-medium">
+                        <span className="flex items-center gap-2">
+                            <Eye size={16} />
+                            Mode impersonasi: Anda melihat sebagai {user?.name || '...'} (hanya baca)
+                        </span>
+                        <button onClick={stopImpersonation} className="px-3 py-1 rounded-lg bg-white/20 hover:bg-white/30">
+                            Keluar
+                        </button>
+                    </div>
+                )}
                 {/* Mobile Header for Sidebar Toggle */}
                 <div className="lg:hidden p-4 flex items-center justify-between bg-white/80 backdrop-blur-md border-b border-slate-200">
                     <h1 className="text-xl font-bold text-slate-900">SIS PPI 100</h1>
@@ -3,7 +3,7 @@ import { Link, useLocation } from 'react-router-dom';
 import {
     LayoutDashboard, Users, BookOpen, Calendar, FileText,
     Settings, LogOut, Bell, Menu, X, GraduationCap,
-    DollarSign, AlertTriangle, MessageSquare, CreditCard, Mail, Send
+    DollarSign, AlertTriangle, MessageSquare, CreditCard, Mail, Send, Clock, DoorOpen, UserCheck, CalendarDays, BarChart3, ClipboardList, Scale, RotateCcw, Target, NotebookPen, Sprout
 } from 'lucide-react';
 import { useAuth } from '../../context/AuthContext';
 import clsx from 'clsx';
@@ -26,6 +26,17 @@ const Sidebar: React.FC<SidebarProps> = ({ isOpen, onClose }) => {
         const admin = [
             { icon: Users, label: 'Manajemen User', path: '/dashboard/users' },
             { icon: BookOpen, label: 'Akademik', path: '/dashboard/academic' },
+            { icon: Calendar, label: 'Tahun Ajaran', path: '/dashboard/admin/terms' },
+            { icon: CalendarDays, label: 'Kalender Akademik', path: '/dashboard/admin/calendar' },
+            { icon: GraduationCap, label: 'Kenaikan Kelas', path: '/dashboard/admin/promotion' },
+            { icon: Clock, label: 'Generator Jadwal', path: '/dashboard/admin/timetable' },
+            { icon: UserCheck, label: 'Guru Pengganti', path: '/dashboard/admin/substitutions' },
+            { icon: BarChart3, label: 'Beban Mengajar', path: '/dashboard/admin/workload' },
+            { icon: ClipboardList, label: 'Buku Nilai', path: '/dashboard/gradebook' },
+            { icon: RotateCcw, label: 'Daftar Remedial', path: '/dashboard/remedial' },
+            { icon: Target, label: 'Tujuan Pembelajaran', path: '/dashboard/learning-objectives' },
+            { icon: Scale, label: 'Bobot Nilai & KKM', path: '/dashboard/admin/grade-weights' },
+            { icon: DoorOpen, label: 'Ruangan', path: '/dashboard/rooms' },
             { icon: CreditCard, label: 'Keuangan', path: '/dashboard/finance' },
             { icon: AlertTriangle, label: 'BK', path: '/dashboard/bk' },
             { icon: Bell, label: 'Notifikasi', path: '/dashboard/notifications' },
@@ -42,8 +53,18 @@ const Sidebar: React.FC<SidebarProps> = ({ isOpen, onClose }) => {
             { icon: Calendar, label: 'Jadwal Mengajar', path: '/dashboard/teacher/schedule' },
             { icon: Users, label: 'Data Siswa', path: '/dashboard/teacher/students' },
             { icon: FileText, label: 'Input Nilai', path: '/dashboard/teacher/grades' },
+            { icon: ClipboardList, label: 'Buku Nilai', path: '/dashboard/gradebook' },
+            { icon: RotateCcw, label: 'Daftar Remedial', path: '/dashboard/remedial' },
+            { icon: Target, label: 'Tujuan Pembelajaran', path: '/dashboard/learning-objectives' },
             { icon: BookOpen, label: 'E-Learning', path: '/dashboard/elearning' },
             { icon: AlertTriangle, label: 'Lapor BK', path: '/dashboard/teacher/bk-report' },
+            { icon: DoorOpen, label: 'Ruangan', path: '/dashboard/rooms' },
+        ];
+
+        const homeroom = [
+            { icon: Users, label: 'Kelas Perwalian', path: '/dashboard/homeroom' },
+            { icon: NotebookPen, label: 'Catatan & Sikap', path: '/dashboard/homeroom/notes' },
+            { icon: Sprout, label: 'Projek P5', path: '/dashboard/homeroom/p5' },
         ];
 
         const student = [
@@ -67,8 +88,9 @@ const Sidebar: React.FC<SidebarProps> = ({ isOpen, onClose }) => {
             case 3: // Admin MA
                 return [...common, ...admin];
             case 4: // Guru
-            case 5: // Wali Kelas
                 return [...common, ...teacher];
+            case 5: // Wali Kelas
+                return [...common, ...teacher, ...homeroom];
             case 6: // Siswa
                 return [...common, ...student];
             case 7: // Orang Tua
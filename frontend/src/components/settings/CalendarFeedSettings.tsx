@@ -0,0 +1,86 @@
+import React, { useState } from 'react';
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+import { CalendarDays } from 'lucide-react';
+import ButtonGlass from '../ui/glass/ButtonGlass';
+import api from '../../services/api';
+
+interface FeedStatus {
+    active: boolean;
+    created_at?: string;
+    last_used_at?: string;
+}
+
+const formatDateTime = (value: string) => new Date(value).toLocaleString('id-ID', { dateStyle: 'medium', timeStyle: 'short' });
+
+const CalendarFeedSettings: React.FC = () => {
+    const queryClient = useQueryClient();
+    const [feedUrl, setFeedUrl] = useState('');
+
+    const { data: status } = useQuery<FeedStatus>({
+        queryKey: ['calendar-feed-status'],
+        queryFn: async () => (await api.get('/profile/calendar-feed')).data,
+    });
+
+    const onError = (error: any) => alert(error.response?.data?.error || 'Terjadi kesalahan');
+
+    const createMutation = useMutation({
+        mutationFn: async () => (await api.post('/profile/calendar-feed')).data,
+        onSuccess: (data) => {
+            setFeedUrl(`${window.location.origin}${data.path}`);
+            queryClient.invalidateQueries({ queryKey: ['calendar-feed-status'] });
+        },
+        onError,
+    });
+
+    const revokeMutation = useMutation({
+        mutationFn: async () => api.delete('/profile/calendar-feed'),
+        onSuccess: () => {
+            setFeedUrl('');
+            queryClient.invalidateQueries({ queryKey: ['calendar-feed-status'] });
+        },
+        onError,
+    });
+
+    return (
+        <div className="space-y-6 max-w-md">
+            <div className="flex items-center gap-3">
+                <CalendarDays className={status?.active ? 'text-green-600' : 'text-slate-400'} size={24} />
+                <div>
+                    <p className="font-medium text-slate-900">
+                        {status?.active ? 'Tautan kalender aktif' : 'Belum ada tautan kalender'}
+                    </p>
+                    {status?.created_at && <p className="text-sm text-slate-500">Dibuat {formatDateTime(status.created_at)}</p>}
+                    {status?.last_used_at && <p className="text-sm text-slate-500">Terakhir diambil {formatDateTime(status.last_used_at)}</p>}
+                </div>
+            </div>
+
+            <p className="text-sm text-slate-600">
+                Langganan jadwal pelajaran dan tenggat tugas Anda di Google Calendar, Outlook atau kalender ponsel.
+                Siapa pun yang memiliki tautan dapat melihat jadwal Anda; buat ulang tautan untuk menonaktifkan yang lama.
+            </p>
+
+            {feedUrl && (
+                <div className="p-4 rounded-xl bg-yellow-500/10 border border-yellow-500/20 text-sm space-y-2">
+                    <p className="font-medium text-slate-900">Salin tautan berikut. Tautan hanya ditampilkan sekali.</p>
+                    <p className="font-mono break-all text-slate-700">{feedUrl}</p>
+                </div>
+            )}
+
+            <div className="flex gap-3">
+                <ButtonGlass
+                    onClick={() => (!status?.active || confirm('Tautan lama akan berhenti berfungsi. Lanjutkan?')) && createMutation.mutate()}
+                    disabled={createMutation.isPending}
+                >
+                    {status?.active ? 'Buat Ulang Tautan' : 'Buat Tautan'}
+                </ButtonGlass>
+                {status?.active && (
+                    <ButtonGlass variant="secondary" onClick={() => revokeMutation.mutate()} disabled={revokeMutation.isPending}>
+                        Cabut Tautan
+                    </ButtonGlass>
+                )}
+            </div>
+        </div>
+    );
+};
+
+export default CalendarFeedSettings;
@@ -0,0 +1,150 @@
+import React, { useState } from 'react';
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+import { Lock, ShieldCheck } from 'lucide-react';
+import InputGlass from '../ui/glass/InputGlass';
+import ButtonGlass from '../ui/glass/ButtonGlass';
+import { useAuth } from '../../context/AuthContext';
+import api from '../../services/api';
+
+interface MFAStatus {
+    enabled: boolean;
+    required: boolean;
+    recovery_codes_left: number;
+}
+
+const TwoFactorSettings: React.FC = () => {
+    const { login } = useAuth();
+    const queryClient = useQueryClient();
+    const [setup, setSetup] = useState<{ secret: string; uri: string } | null>(null);
+    const [code, setCode] = useState('');
+    const [password, setPassword] = useState('');
+    const [recoveryCodes, setRecoveryCodes] = useState<string[]>([]);
+
+    const { data: status } = useQuery<MFAStatus>({
+        queryKey: ['mfa-status'],
+        queryFn: async () => (await api.get('/profile/2fa')).data,
+    });
+
+    const onError = (error: any) => alert(error.response?.data?.error || 'Terjadi kesalahan');
+
+    const setupMutation = useMutation({
+        mutationFn: async () => (await api.post('/profile/2fa/setup')).data,
+        onSuccess: (data) => setSetup(data),
+        onError,
+    });
+
+    const enableMutation = useMutation({
+        mutationFn: async () => (await api.post('/profile/2fa/enable', { code })).data,
+        onSuccess: (data) => {
+            // New tokens no longer carry the enrollment requirement
+            login(data.tokens.token, data.tokens.refresh_token);
+            setRecoveryCodes(data.recovery_codes);
+            setSetup(null);
+            setCode('');
+            queryClient.invalidateQueries({ queryKey: ['mfa-status'] });
+        },
+        onError,
+    });
+
+    const disableMutation = useMutation({
+        mutationFn: async () => api.post('/profile/2fa/disable', { password, code }),
+        onSuccess: () => {
+            alert('Autentikasi dua faktor dinonaktifkan.');
+            setPassword('');
+            setCode('');
+            queryClient.invalidateQueries({ queryKey: ['mfa-status'] });
+        },
+        onError,
+    });
+
+    const regenerateMutation = useMutation({
+        mutationFn: async () => (await api.post('/profile/2fa/recovery-codes', { code })).data,
+        onSuccess: (data) => {
+            setRecoveryCodes(data.recovery_codes);
+            setCode('');
+            queryClient.invalidateQueries({ queryKey: ['mfa-status'] });
+        },
+        onError,
+    });
+
+    return (
+        <div className="space-y-6 max-w-md">
+            <div className="flex items-center gap-3">
+                <ShieldCheck className={status?.enabled ? 'text-green-600' : 'text-slate-400'} size={24} />
+                <div>
+                    <p className="font-medium text-slate-900">
+                        {status?.enabled ? 'Autentikasi dua faktor aktif' : 'Autentikasi dua faktor belum aktif'}
+                    </p>
+                    {status?.required && <p className="text-sm text-slate-500">Wajib untuk peran Anda</p>}
+                    {status?.enabled && (
+                        <p className="text-sm text-slate-500">Sisa kode pemulihan: {status.recovery_codes_left}</p>
+                    )}
+                </div>
+            </div>
+
+            {recoveryCodes.length > 0 && (
+                <div className="p-4 rounded-xl bg-yellow-500/10 border border-yellow-500/20 text-sm space-y-2">
+                    <p className="font-medium text-slate-900">Simpan kode pemulihan berikut. Kode hanya ditampilkan sekali.</p>
+                    <div className="grid grid-cols-2 gap-1 font-mono text-slate-700">
+                        {recoveryCodes.map((c) => <span key={c}>{c}</span>)}
+                    </div>
+                </div>
+            )}
+
+            {!status?.enabled && !setup && (
+                <ButtonGlass onClick={() => setupMutation.mutate()} disabled={setupMutation.isPending}>
+                    Aktifkan 2FA
+                </ButtonGlass>
+            )}
+
+            {setup && (
+                <div className="space-y-4">
+                    <p className="text-sm text-slate-600">
+                        Tambahkan akun ini di aplikasi authenticator (Google Authenticator, Authy, dll.) dengan kunci berikut:
+                    </p>
+                    <p className="font-mono text-sm break-all p-3 rounded-xl bg-white/40 border border-white/20">{setup.secret}</p>
+                    <a href={setup.uri} className="text-sm text-purple-600 underline">Buka di aplikasi authenticator</a>
+                    <InputGlass
+                        placeholder="Kode 6 digit"
+                        value={code}
+                        onChange={(e) => setCode(e.target.value)}
+                        icon={Lock}
+                    />
+                    <ButtonGlass onClick={() => enableMutation.mutate()} disabled={enableMutation.isPending}>
+                        Konfirmasi
+                    </ButtonGlass>
+                </div>
+            )}
+
+            {status?.enabled && (
+                <div className="space-y-4">
+                    <InputGlass
+                        placeholder="Kode 6 digit"
+                        value={code}
+                        onChange={(e) => setCode(e.target.value)}
+                        icon={Lock}
+                    />
+                    <ButtonGlass onClick={() => regenerateMutation.mutate()} disabled={regenerateMutation.isPending}>
+                        Buat Ulang Kode Pemulihan
+                    </ButtonGlass>
+                    {!status.required && (
+                        <>
+                            <InputGlass
+                                type="password"
+                                placeholder="Password"
+                                value={password}
+                                onChange={(e) => setPassword(e.target.value)}
+                                icon={Lock}
+                            />
+                            <ButtonGlass onClick={() => disableMutation.mutate()} disabled={disableMutation.isPending}>
+                                Nonaktifkan 2FA
+                            </ButtonGlass>
+                        </>
+                    )}
+                </div>
+            )}
+        </div>
+    );
+};
+
+export default TwoFactorSettings;
@@ -8,10 +8,12 @@ interface User {
     role_id: number;
     unit_id: number;
     photo_url?: string;
+    must_change_password?: boolean;
     student?: {
         id: string;
         nisn: string;
         class_id: number;
+        class?: { name: string };
     };
     teacher?: {
         id: string;
@@ -26,8 +28,11 @@ interface User {
 interface AuthContextType {
     user: User | null;
     token: string | null;
-    login: (token: string) => void;
+    login: (token: string, refreshToken: string) => void;
     logout: () => void;
+    startImpersonation: (token: string) => void;
+    stopImpersonation: () => void;
+    isImpersonating: boolean;
     isAuthenticated: boolean;
 }
 
@@ -46,19 +51,52 @@ export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({ children
         }
     }, [token]);
 
-    const login = (newToken: string) => {
+    const login = (newToken: string, refreshToken: string) => {
         localStorage.setItem('token', newToken);
+        localStorage.setItem('refresh_token', refreshToken);
         setToken(newToken);
     };
 
+    // The admin's own session is parked while impersonating and restored afterwards
+    const startImpersonation = (impersonationToken: string) => {
+        localStorage.setItem('admin_token', localStorage.getItem('token') || '');
+        localStorage.setItem('admin_refresh_token', localStorage.getItem('refresh_token') || '');
+        localStorage.setItem('token', impersonationToken);
+        localStorage.removeItem('refresh_token');
+        setToken(impersonationToken);
+    };
+
+    const stopImpersonation = () => {
+        const adminToken = localStorage.getItem('admin_token');
+        localStorage.setItem('refresh_token', localStorage.getItem('admin_refresh_token') || '');
+        localStorage.removeItem('admin_token');
+        localStorage.removeItem('admin_refresh_token');
+        if (adminToken) {
+            localStorage.setItem('token', adminToken);
+        } else {
+            localStorage.removeItem('token');
+        }
+        setToken(adminToken);
+    };
+
     const logout = () => {
+        if (localStorage.getItem('admin_token')) {
+            stopImpersonation();
+            return;
+        }
+        const refreshToken = localStorage.getItem('refresh_token');
+        if (refreshToken) {
+            // Revoke server-side; local state is cleared regardless
+            api.post('/auth/logout', { refresh_token: refreshToken }).catch(() => undefined);
+        }
         localStorage.removeItem('token');
+        localStorage.removeItem('refresh_token');
         setToken(null);
         setUser(null);
     };
 
     return (
-        <AuthContext.Provider value={{ user, token, login, logout, isAuthenticated: !!token }}>
+        <AuthContext.Provider value={{ user, token, login, logout, startImpersonation, stopImpersonation, isImpersonating: !!localStorage.getItem('admin_token'), isAuthenticated: !!token }}>
             {children}
         </AuthContext.Provider>
     );
@@ -0,0 +1,28 @@
+// Assessment components of the gradebook, in report-card order
+export const gradeComponents = ['tugas', 'uh', 'pts', 'pas', 'praktik'] as const;
+
+export const gradeComponentLabels: Record<string, string> = {
+    tugas: 'Tugas Harian',
+    uh: 'Ulangan Harian',
+    pts: 'PTS',
+    pas: 'PAS',
+    praktik: 'Praktik',
+};
+
+// Tujuan pembelajaran (TP) of a subject under Kurikulum Merdeka
+export interface LearningObjective {
+    id: number;
+    subject_id: number;
+    code: string;
+    description: string;
+    position: number;
+}
+
+export interface ObjectiveMastery {
+    id: number;
+    code: string;
+    description: string;
+    average: number | null;
+    count: number;
+    achieved: boolean;
+}
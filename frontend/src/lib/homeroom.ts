@@ -0,0 +1,92 @@
+import { useQuery } from '@tanstack/react-query';
+import api from '../services/api';
+import { useAuth } from '../context/AuthContext';
+
+// Attitude predicates of the rapor
+export const attitudeGrades = ['SB', 'B', 'C', 'K'] as const;
+
+export const attitudeLabels: Record<string, string> = {
+    SB: 'Sangat Baik',
+    B: 'Baik',
+    C: 'Cukup',
+    K: 'Kurang',
+};
+
+// Dimensions of the Profil Pelajar Pancasila
+export const p5Dimensions = ['beriman', 'berkebinekaan', 'gotong_royong', 'mandiri', 'bernalar_kritis', 'kreatif'] as const;
+
+export const p5DimensionLabels: Record<string, string> = {
+    beriman: 'Beriman & Berakhlak Mulia',
+    berkebinekaan: 'Berkebinekaan Global',
+    gotong_royong: 'Bergotong Royong',
+    mandiri: 'Mandiri',
+    bernalar_kritis: 'Bernalar Kritis',
+    kreatif: 'Kreatif',
+};
+
+// P5 achievement levels, lowest first
+export const p5Levels = ['BB', 'MB', 'BSH', 'SB'] as const;
+
+export const p5LevelLabels: Record<string, string> = {
+    BB: 'Belum Berkembang',
+    MB: 'Mulai Berkembang',
+    BSH: 'Berkembang Sesuai Harapan',
+    SB: 'Sangat Berkembang',
+};
+
+export interface HomeroomNote {
+    id: number;
+    student_id: string;
+    semester_id: number;
+    note: string;
+    spiritual_grade: string;
+    spiritual_description: string;
+    social_grade: string;
+    social_description: string;
+}
+
+export interface P5Assessment {
+    student_id: string;
+    dimension: string;
+    level: string;
+}
+
+export interface P5Project {
+    id: number;
+    class_id: number;
+    semester_id: number;
+    theme: string;
+    title: string;
+    description: string;
+    assessments?: P5Assessment[];
+}
+
+export interface HomeroomStudent {
+    id: string;
+    nisn: string;
+    user: { name: string };
+}
+
+// useHomeroomClass loads the wali kelas's class and its students.
+export const useHomeroomClass = () => {
+    const { user } = useAuth();
+
+    const { data: myClass, isLoading } = useQuery({
+        queryKey: ['homeroom-class', user?.teacher?.id],
+        queryFn: async () => (await api.get(`/academic/classes/homeroom?teacher_id=${user?.teacher?.id}`)).data,
+        enabled: !!user?.teacher?.id,
+    });
+
+    const { data: students } = useQuery({
+        queryKey: ['class-students', myClass?.id],
+        queryFn: async () => {
+            const res = await api.get(`/students/?unit_id=${myClass.unit_id}`);
+            return (res.data as any[])
+                .filter((s) => s.class_id === myClass.id)
+                .sort((a, b) => a.user.name.localeCompare(b.user.name)) as HomeroomStudent[];
+        },
+        enabled: !!myClass?.id,
+    });
+
+    return { myClass, students, isLoading: isLoading || !user?.teacher?.id };
+};
@@ -0,0 +1,102 @@
+import React, { useState } from 'react';
+import { Link, useNavigate, useSearchParams } from 'react-router-dom';
+import CardGlass from '../components/ui/glass/CardGlass';
+import InputGlass from '../components/ui/glass/InputGlass';
+import ButtonGlass from '../components/ui/glass/ButtonGlass';
+import { ArrowRight, Lock, User } from 'lucide-react';
+import api from '../services/api';
+
+const AcceptInvitation: React.FC = () => {
+    const [searchParams] = useSearchParams();
+    const [name, setName] = useState('');
+    const [newPassword, setNewPassword] = useState('');
+    const [confirmPassword, setConfirmPassword] = useState('');
+    const [error, setError] = useState('');
+    const navigate = useNavigate();
+
+    const handleSubmit = async (e: React.FormEvent) => {
+        e.preventDefault();
+        if (newPassword !== confirmPassword) {
+            setError('Konfirmasi password tidak cocok');
+            return;
+        }
+        try {
+            await api.post('/auth/invitations/accept', {
+                code: searchParams.get('code'),
+                name,
+                password: newPassword,
+            });
+            alert('Akun berhasil dibuat. Silakan login.');
+            navigate('/login');
+        } catch (err: any) {
+            setError(err.response?.data?.error || 'Gagal membuat akun');
+        }
+    };
+
+    return (
+        <div className="min-h-screen flex items-center justify-center p-6 relative overflow-hidden">
+            <div className="absolute top-[-10%] left-[-10%] w-[50%] h-[50%] rounded-full bg-purple-600/20 blur-[120px]" />
+            <div className="absolute bottom-[-10%] right-[-10%] w-[50%] h-[50%] rounded-full bg-indigo-600/20 blur-[120px]" />
+
+            <CardGlass className="w-full max-w-md relative z-10 backdrop-blur-2xl bg-black/40 border-white/10">
+                <div className="text-center mb-8">
+                    <h1 className="text-2xl font-bold text-slate-900 mb-2">Terima Undangan</h1>
+                    <p className="text-slate-300-400">Lengkapi data untuk mengaktifkan akun Anda</p>
+                </div>
+
+                {error && (
+                    <div className="mb-6 p-4 rounded-xl bg-red-500/10 border border-red-500/20 text-red-400 text-sm text-center">
+                        {error}
+                    </div>
+                )}
+
+                <form onSubmit={handleSubmit} className="space-y-6">
+                    <div className="space-y-4">
+                        <div className="relative">
+                            <User className="absolute left-4 top-1/2 -translate-y-1/2 text-slate-300-400" size={20} />
+                            <InputGlass
+                                type="text"
+                                placeholder="Nama Lengkap"
+                                value={name}
+                                onChange={(e) => setName(e.target.value)}
+                                className="pl-12"
+                            />
+                        </div>
+                        <div className="relative">
+                            <Lock className="absolute left-4 top-1/2 -translate-y-1/2 text-slate-300-400" size={20} />
+                            <InputGlass
+                                type="password"
+                                placeholder="Password"
+                                value={newPassword}
+                                onChange={(e) => setNewPassword(e.target.value)}
+                                className="pl-12"
+                                required
+                            />
+                        </div>
+                        <div className="relative">
+                            <Lock className="absolute left-4 top-1/2 -translate-y-1/2 text-slate-300-400" size={20} />
+                            <InputGlass
+                                type="password"
+                                placeholder="Konfirmasi Password"
+                                value={confirmPassword}
+                                onChange={(e) => setConfirmPassword(e.target.value)}
+                                className="pl-12"
+                                required
+                            />
+                        </div>
+                    </div>
+
+                    <ButtonGlass type="submit" className="w-full py-3 text-lg group">
+                        Buat Akun <ArrowRight size={20} className="group-hover:translate-x-1 transition-transform" />
+                    </ButtonGlass>
+                </form>
+
+                <div className="mt-8 text-center text-sm text-slate-300-500">
+                    <Link to="/login">Kembali ke halaman login</Link>
+                </div>
+            </CardGlass>
+        </div>
+    );
+};
+
+export default AcceptInvitation;
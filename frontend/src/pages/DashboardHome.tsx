@@ -203,15 +203,7 @@ const StudentDashboard = () => {
     const days = ['Sunday', 'Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday'];
     const today = days[new Date().getDay()];
 
-    // Fetch Student Profile
-    const { data: students } = useQuery({
-        queryKey: ['students'],
-        queryFn: async () => {
-            const res = await api.get('/students/');
-            return res.data;
-        }
-    });
-    const currentStudent = students?.find((s: any) => s.user.id === user?.id);
+    const currentStudent = user?.student;
 
     // Fetch Schedule
     const { data: schedules } = useQuery({
@@ -0,0 +1,73 @@
+import React, { useState } from 'react';
+import { Link } from 'react-router-dom';
+import CardGlass from '../components/ui/glass/CardGlass';
+import InputGlass from '../components/ui/glass/InputGlass';
+import ButtonGlass from '../components/ui/glass/ButtonGlass';
+import { ArrowRight, Mail } from 'lucide-react';
+import api from '../services/api';
+
+const ForgotPassword: React.FC = () => {
+    const [email, setEmail] = useState('');
+    const [message, setMessage] = useState('');
+    const [error, setError] = useState('');
+
+    const handleSubmit = async (e: React.FormEvent) => {
+        e.preventDefault();
+        setError('');
+        try {
+            await api.post('/auth/forgot-password', { email });
+            setMessage('Jika email terdaftar, tautan reset password telah dikirim.');
+        } catch (err) {
+            setError('Gagal mengirim email reset password');
+        }
+    };
+
+    return (
+        <div className="min-h-screen flex items-center justify-center p-6 relative overflow-hidden">
+            <div className="absolute top-[-10%] left-[-10%] w-[50%] h-[50%] rounded-full bg-purple-600/20 blur-[120px]" />
+            <div className="absolute bottom-[-10%] right-[-10%] w-[50%] h-[50%] rounded-full bg-indigo-600/20 blur-[120px]" />
+
+            <CardGlass className="w-full max-w-md relative z-10 backdrop-blur-2xl bg-black/40 border-white/10">
+                <div className="text-center mb-8">
+                    <h1 className="text-2xl font-bold text-slate-900 mb-2">Lupa Password</h1>
+                    <p className="text-slate-300-400">Masukkan email akun Anda</p>
+                </div>
+
+                {error && (
+                    <div className="mb-6 p-4 rounded-xl bg-red-500/10 border border-red-500/20 text-red-400 text-sm text-center">
+                        {error}
+                    </div>
+                )}
+                {message && (
+                    <div className="mb-6 p-4 rounded-xl bg-green-500/10 border border-green-500/20 text-green-400 text-sm text-center">
+                        {message}
+                    </div>
+                )}
+
+                <form onSubmit={handleSubmit} className="space-y-6">
+                    <div className="relative">
+                        <Mail className="absolute left-4 top-1/2 -translate-y-1/2 text-slate-300-400" size={20} />
+                        <InputGlass
+                            type="email"
+                            placeholder="Email Address"
+                            value={email}
+                            onChange={(e) => setEmail(e.target.value)}
+                            className="pl-12"
+                            required
+                        />
+                    </div>
+
+                    <ButtonGlass type="submit" className="w-full py-3 text-lg group">
+                        Kirim Tautan <ArrowRight size={20} className="group-hover:translate-x-1 transition-transform" />
+                    </ButtonGlass>
+                </form>
+
+                <div className="mt-8 text-center text-sm text-slate-300-500">
+                    <Link to="/login">Kembali ke halaman login</Link>
+                </div>
+            </CardGlass>
+        </div>
+    );
+};
+
+export default ForgotPassword;
@@ -1,5 +1,5 @@
 import React, { useState } from 'react';
-import { useNavigate } from 'react-router-dom';
+import { Link, useNavigate } from 'react-router-dom';
 import { useAuth } from '../context/AuthContext';
 import CardGlass from '../components/ui/glass/CardGlass';
 import InputGlass from '../components/ui/glass/InputGlass';
@@ -11,6 +11,8 @@ const Login: React.FC = () => {
     const [email, setEmail] = useState('');
     const [password, setPassword] = useState('');
     const [error, setError] = useState('');
+    const [mfaToken, setMfaToken] = useState('');
+    const [code, setCode] = useState('');
     const { login } = useAuth();
     const navigate = useNavigate();
 
@@ -18,10 +20,27 @@ const Login: React.FC = () => {
         e.preventDefault();
         try {
             const response = await api.post('/auth/login', { email, password });
-            login(response.data.token);
+            if (response.data.mfa_required) {
+                setMfaToken(response.data.mfa_token);
+                setError('');
+                return;
+            }
+            login(response.data.token, response.data.refresh_token);
             navigate('/dashboard');
-        } catch (err) {
-            setError('Invalid email or password');
+        } catch (err: any) {
+            // 429 carries the lockout / retry message from the server
+            setError(err.response?.status === 429 ? err.response.data.error : 'Invalid email or password');
+        }
+    };
+
+    const handleMfaSubmit = async (e: React.FormEvent) => {
+        e.preventDefault();
+        try {
+            const response = await api.post('/auth/login/mfa', { mfa_token: mfaToken, code });
+            login(response.data.token, response.data.refresh_token);
+            navigate('/dashboard');
+        } catch (err: any) {
+            setError(err.response?.data?.error || 'Invalid code');
         }
     };
 
@@ -46,6 +65,29 @@ const Login: React.FC = () => {
                     </div>
                 )}
 
+                {mfaToken ? (
+                <form onSubmit={handleMfaSubmit} className="space-y-6">
+                    <p className="text-sm text-slate-300-400 text-center">
+                        Enter the 6-digit code from your authenticator app, or a recovery code.
+                    </p>
+                    <div className="relative">
+                        <Lock className="absolute left-4 top-1/2 -translate-y-1/2 text-slate-300-400" size={20} />
+                        <InputGlass
+                            type="text"
+                            placeholder="Authentication code"
+                            value={code}
+                            onChange={(e) => setCode(e.target.value)}
+                            className="pl-12"
+                            autoComplete="one-time-code"
+                            required
+                        />
+                    </div>
+
+                    <ButtonGlass type="submit" className="w-full py-3 text-lg group">
+                        Verify <ArrowRight size={20} className="group-hover:translate-x-1 transition-transform" />
+                    </ButtonGlass>
+                </form>
+                ) : (
                 <form onSubmit={handleSubmit} className="space-y-6">
                     <div className="space-y-4">
                         <div className="relative">
@@ -76,8 +118,10 @@ const Login: React.FC = () => {
                         Sign In <ArrowRight size={20} className="group-hover:translate-x-1 transition-transform" />
                     </ButtonGlass>
                 </form>
+                )}
 
                 <div className="mt-8 text-center text-sm text-slate-300-500">
+                    <p className="mb-2"><Link to="/forgot-password">Forgot password?</Link></p>
                     <p>Don't have an account? Contact Administrator</p>
                 </div>
             </CardGlass>
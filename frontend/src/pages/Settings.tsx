@@ -1,4 +1,4 @@
-import React, { useState } from 'react';
+import React, { useEffect, useState } from 'react';
 import { useAuth } from '../context/AuthContext';
 import CardGlass from '../components/ui/glass/CardGlass';
 import InputGlass from '../components/ui/glass/InputGlass';
@@ -6,10 +6,14 @@ import ButtonGlass from '../components/ui/glass/ButtonGlass';
 import { User, Lock, Save, Camera } from 'lucide-react';
 import api from '../services/api';
 import { useMutation } from '@tanstack/react-query';
+import TwoFactorSettings from '../components/settings/TwoFactorSettings';
+import CalendarFeedSettings from '../components/settings/CalendarFeedSettings';
 
 const Settings: React.FC = () => {
-    const { user } = useAuth(); // login is used to refresh token if needed, but here we might just need to refetch profile
-    const [activeTab, setActiveTab] = useState<'profile' | 'password'>('profile');
+    const { user, login } = useAuth();
+    const [activeTab, setActiveTab] = useState<'profile' | 'password' | '2fa' | 'calendar'>('profile');
+    // Personal calendar feeds cover teachers and students
+    const hasCalendarFeed = [4, 5, 6].includes(user?.role_id ?? 0);
 
     // Profile State
     const [name, setName] = useState(user?.name || '');
@@ -20,6 +24,12 @@ const Settings: React.FC = () => {
     const [newPassword, setNewPassword] = useState('');
     const [confirmPassword, setConfirmPassword] = useState('');
 
+    useEffect(() => {
+        if (user?.must_change_password) {
+            setActiveTab('password');
+        }
+    }, [user?.must_change_password]);
+
     const updateProfileMutation = useMutation({
         mutationFn: async (data: { name: string; email: string }) => {
             return await api.put('/profile', data);
@@ -37,7 +47,9 @@ const Settings: React.FC = () => {
         mutationFn: async (data: any) => {
             return await api.put('/profile/password', data);
         },
-        onSuccess: () => {
+        onSuccess: (response) => {
+            // Changing the password revokes old sessions; keep this one with the new tokens
+            login(response.data.tokens.token, response.data.tokens.refresh_token);
             alert('Password berhasil diubah.');
             setOldPassword('');
             setNewPassword('');
@@ -87,6 +99,28 @@ const Settings: React.FC = () => {
                         <div className="absolute bottom-0 left-0 w-full h-0.5 bg-purple-600 rounded-t-full" />
                     )}
                 </button>
+                <button
+                    onClick={() => setActiveTab('2fa')}
+                    className={`pb-3 px-4 text-sm font-medium transition-colors relative ${activeTab === '2fa' ? 'text-purple-600' : 'text-slate-500 hover:text-slate-900'
+                        }`}
+                >
+                    Autentikasi Dua Faktor
+                    {activeTab === '2fa' && (
+                        <div className="absolute bottom-0 left-0 w-full h-0.5 bg-purple-600 rounded-t-full" />
+                    )}
+                </button>
+                {hasCalendarFeed && (
+                    <button
+                        onClick={() => setActiveTab('calendar')}
+                        className={`pb-3 px-4 text-sm font-medium transition-colors relative ${activeTab === 'calendar' ? 'text-purple-600' : 'text-slate-500 hover:text-slate-900'
+                            }`}
+                    >
+                        Kalender
+                        {activeTab === 'calendar' && (
+                            <div className="absolute bottom-0 left-0 w-full h-0.5 bg-purple-600 rounded-t-full" />
+                        )}
+                    </button>
+                )}
             </div>
 
             <div className="grid lg:grid-cols-3 gap-8">
@@ -177,8 +211,17 @@ const Settings: React.FC = () => {
                                     </ButtonGlass>
                                 </div>
                             </form>
+                        ) : activeTab === '2fa' ? (
+                            <TwoFactorSettings />
+                        ) : activeTab === 'calendar' ? (
+                            <CalendarFeedSettings />
                         ) : (
                             <form onSubmit={handleChangePassword} className="space-y-6 max-w-md">
+                                {user?.must_change_password && (
+                                    <div className="p-3 rounded-lg bg-amber-50 border border-amber-200 text-sm text-amber-700">
+                                        Password Anda dibuat oleh admin. Silakan ganti password sebelum melanjutkan.
+                                    </div>
+                                )}
                                 <div className="space-y-2">
                                     <label className="text-sm text-slate-600">Password Saat Ini</label>
                                     <InputGlass
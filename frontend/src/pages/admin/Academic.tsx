@@ -9,6 +9,12 @@ import ModalGlass from '../../components/ui/glass/ModalGlass';
 import api from '../../services/api';
 import { useAuth } from '../../context/AuthContext';
 
+const dayLabels: Record<string, string> = {
+    Monday: 'Senin', Tuesday: 'Selasa', Wednesday: 'Rabu', Thursday: 'Kamis', Friday: 'Jumat', Saturday: 'Sabtu',
+};
+
+const conflictLabels: Record<string, string> = { teacher: 'Guru', class: 'Kelas', room: 'Ruangan' };
+
 const Academic = () => {
     const { user } = useAuth();
     const queryClient = useQueryClient();
@@ -47,6 +53,14 @@ const Academic = () => {
         }
     });
 
+    const { data: rooms } = useQuery({
+        queryKey: ['academic_rooms', unitID],
+        queryFn: async () => {
+            const res = await api.get(`/academic/rooms?unit_id=${unitID}`);
+            return res.data || [];
+        },
+    });
+
     const { data: teachers } = useQuery({
         queryKey: ['teachers_list', unitID],
         queryFn: async () => {
@@ -67,7 +81,15 @@ const Academic = () => {
         },
         onError: (error: any) => {
             console.error('Error saving data:', error);
-            alert('Failed to save data');
+            const conflicts = error.response?.data?.conflicts as any[] | undefined;
+            if (conflicts?.length) {
+                const lines = conflicts.map((c) =>
+                    `- ${conflictLabels[c.reason] || c.reason} bentrok: ${c.schedule.subject?.name} (${c.schedule.class?.name}) ${c.schedule.day} ${c.schedule.start_time}-${c.schedule.end_time}`
+                );
+                alert('Jadwal bentrok dengan jadwal lain:\n' + lines.join('\n'));
+                return;
+            }
+            alert('Failed to save data: ' + (error.response?.data?.error || error.message));
         }
     };
 
@@ -164,6 +186,7 @@ const Academic = () => {
         // Convert IDs to numbers for classes and subjects if they are strings
         if (payload.class_id) payload.class_id = Number(payload.class_id);
         if (payload.subject_id) payload.subject_id = Number(payload.subject_id);
+        if (activeTab === 'schedules') payload.room_id = payload.room_id ? Number(payload.room_id) : null;
 
         if (activeTab === 'classes') {
             if (editingId) updateClassMutation.mutate(payload);
@@ -256,14 +279,15 @@ const Academic = () => {
             case 'schedules':
                 return (
                     <TableGlass
-                        headers={['ID', 'Kelas', 'Mapel', 'Guru', 'Hari', 'Jam', 'Aksi']}
+                        headers={['ID', 'Kelas', 'Mapel', 'Guru', 'Hari', 'Jam', 'Ruangan', 'Aksi']}
                         data={schedules?.map((sch: any) => ({
                             id: sch.id,
                             class: sch.class?.name || sch.class_id,
                             subject: sch.subject?.name || sch.subject_id,
                             teacher: sch.teacher?.user?.name || sch.teacher?.name || sch.teacher_id, // Display teacher name
-                            day: sch.day,
+                            day: dayLabels[sch.day] || sch.day,
                             time: `${sch.start_time} - ${sch.end_time}`,
+                            room: sch.room?.name || '-',
                             actions: (
                                 <div className="flex space-x-2">
                                     <button onClick={() => handleOpenModal(sch)} className="text-blue-600 hover:text-blue-500"><Edit size={16} /></button>
@@ -352,13 +376,35 @@ const Academic = () => {
                             </select>
                         </div>
 
-                        <InputGlass
-                            label="Hari"
-                            name="day"
-                            value={formData.day || ''}
-                            onChange={handleInputChange}
-                            placeholder="Monday"
-                        />
+                        <div className="space-y-2">
+                            <label className="text-sm text-slate-600">Ruangan</label>
+                            <select
+                                name="room_id"
+                                value={formData.room_id || ''}
+                                onChange={handleInputChange}
+                                className="w-full bg-white/40 border border-slate-200 rounded-xl px-4 py-2 text-slate-900 focus:outline-none focus:border-blue-500/50"
+                            >
+                                <option value="" className="bg-white text-slate-900">Tanpa ruangan</option>
+                                {rooms?.map((r: any) => (
+                                    <option key={r.id} value={r.id} className="bg-white text-slate-900">{r.name}</option>
+                                ))}
+                            </select>
+                        </div>
+
+                        <div className="space-y-2">
+                            <label className="text-sm text-slate-600">Hari</label>
+                            <select
+                                name="day"
+                                value={formData.day || ''}
+                                onChange={handleInputChange}
+                                className="w-full bg-white/40 border border-slate-200 rounded-xl px-4 py-2 text-slate-900 focus:outline-none focus:border-blue-500/50"
+                            >
+                                <option value="" className="bg-white text-slate-900">Pilih Hari</option>
+                                {Object.entries(dayLabels).map(([day, label]) => (
+                                    <option key={day} value={day} className="bg-white text-slate-900">{label}</option>
+                                ))}
+                            </select>
+                        </div>
                         <div className="grid grid-cols-2 gap-4">
                             <InputGlass
                                 label="Jam Mulai"
@@ -0,0 +1,222 @@
+import React, { useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import api from '../../services/api';
+import { Plus, Trash2, CheckCircle, Calendar } from 'lucide-react';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import InputGlass from '../../components/ui/glass/InputGlass';
+import ModalGlass from '../../components/ui/glass/ModalGlass';
+
+interface Semester {
+    id: number;
+    academic_year_id: number;
+    name: string;
+    start_date: string;
+    end_date: string;
+    is_active: boolean;
+}
+
+interface AcademicYear {
+    id: number;
+    name: string;
+    start_date: string;
+    end_date: string;
+    is_active: boolean;
+    semesters?: Semester[];
+}
+
+const formatDate = (value: string) => new Date(value).toLocaleDateString('id-ID');
+
+const AcademicTerms: React.FC = () => {
+    const queryClient = useQueryClient();
+    const [isModalOpen, setIsModalOpen] = useState(false);
+    // null: adding a year, otherwise adding a semester to this year
+    const [semesterYearId, setSemesterYearId] = useState<number | null>(null);
+    const [formData, setFormData] = useState({ name: '', start_date: '', end_date: '' });
+
+    const { data: years, isLoading } = useQuery({
+        queryKey: ['academic-years'],
+        queryFn: async () => {
+            const res = await api.get('/academic/years');
+            return res.data as AcademicYear[];
+        },
+    });
+
+    const onError = (err: any) => {
+        alert('Gagal menyimpan data: ' + (err.response?.data?.error || err.message));
+    };
+
+    const createMutation = useMutation({
+        mutationFn: (data: any) => semesterYearId
+            ? api.post('/academic/semesters', { ...data, academic_year_id: semesterYearId })
+            : api.post('/academic/years', data),
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['academic-years'] });
+            handleCloseModal();
+        },
+        onError,
+    });
+
+    const activateMutation = useMutation({
+        mutationFn: (id: number) => api.post(`/academic/semesters/${id}/activate`),
+        onSuccess: () => queryClient.invalidateQueries(),
+        onError,
+    });
+
+    const deleteMutation = useMutation({
+        mutationFn: ({ kind, id }: { kind: 'years' | 'semesters'; id: number }) => api.delete(`/academic/${kind}/${id}`),
+        onSuccess: () => queryClient.invalidateQueries({ queryKey: ['academic-years'] }),
+        onError,
+    });
+
+    const handleOpenModal = (yearId: number | null) => {
+        setSemesterYearId(yearId);
+        setFormData({ name: yearId ? 'Ganjil' : '', start_date: '', end_date: '' });
+        setIsModalOpen(true);
+    };
+
+    const handleCloseModal = () => {
+        setIsModalOpen(false);
+        setSemesterYearId(null);
+    };
+
+    const handleSubmit = (e: React.FormEvent) => {
+        e.preventDefault();
+        createMutation.mutate(formData);
+    };
+
+    const handleDelete = (kind: 'years' | 'semesters', id: number) => {
+        if (confirm('Apakah Anda yakin ingin menghapus data ini?')) {
+            deleteMutation.mutate({ kind, id });
+        }
+    };
+
+    return (
+        <div className="space-y-6">
+            <div className="flex justify-between items-center">
+                <div>
+                    <h1 className="text-3xl font-bold text-slate-900 mb-2">Tahun Ajaran</h1>
+                    <p className="text-slate-600">Kelola tahun ajaran dan semester aktif</p>
+                </div>
+                <ButtonGlass onClick={() => handleOpenModal(null)} icon={Plus}>
+                    Tambah Tahun Ajaran
+                </ButtonGlass>
+            </div>
+
+            {isLoading ? (
+                <CardGlass className="p-6 text-center text-slate-600">Loading...</CardGlass>
+            ) : years?.length === 0 ? (
+                <CardGlass className="p-6 text-center text-slate-600">Belum ada tahun ajaran</CardGlass>
+            ) : (
+                years?.map((year) => (
+                    <CardGlass key={year.id} className="p-6 space-y-4">
+                        <div className="flex justify-between items-center">
+                            <div className="flex items-center gap-3">
+                                <Calendar size={20} className="text-purple-600" />
+                                <div>
+                                    <h2 className="text-lg font-semibold text-slate-900">
+                                        {year.name}
+                                        {year.is_active && (
+                                            <span className="ml-2 px-2 py-0.5 rounded bg-green-100 text-xs font-medium text-green-700">Aktif</span>
+                                        )}
+                                    </h2>
+                                    <p className="text-sm text-slate-500">{formatDate(year.start_date)} - {formatDate(year.end_date)}</p>
+                                </div>
+                            </div>
+                            <div className="flex gap-2">
+                                <ButtonGlass variant="secondary" onClick={() => handleOpenModal(year.id)} icon={Plus}>
+                                    Semester
+                                </ButtonGlass>
+                                {!year.is_active && (
+                                    <button onClick={() => handleDelete('years', year.id)} className="p-2 hover:bg-slate-100 rounded-lg text-red-600 transition-colors">
+                                        <Trash2 size={16} />
+                                    </button>
+                                )}
+                            </div>
+                        </div>
+
+                        <div className="divide-y divide-slate-100">
+                            {year.semesters?.map((semester) => (
+                                <div key={semester.id} className="flex justify-between items-center py-2">
+                                    <div>
+                                        <span className="font-medium text-slate-900">Semester {semester.name}</span>
+                                        <span className="ml-3 text-sm text-slate-500">{formatDate(semester.start_date)} - {formatDate(semester.end_date)}</span>
+                                    </div>
+                                    {semester.is_active ? (
+                                        <span className="flex items-center gap-1 text-sm font-medium text-green-700">
+                                            <CheckCircle size={16} /> Aktif
+                                        </span>
+                                    ) : (
+                                        <div className="flex gap-2">
+                                            <button onClick={() => activateMutation.mutate(semester.id)} className="px-3 py-1 text-sm rounded-lg hover:bg-slate-100 text-purple-600 transition-colors">
+                                                Aktifkan
+                                            </button>
+                                            <button onClick={() => handleDelete('semesters', semester.id)} className="p-2 hover:bg-slate-100 rounded-lg text-red-600 transition-colors">
+                                                <Trash2 size={16} />
+                                            </button>
+                                        </div>
+                                    )}
+                                </div>
+                            ))}
+                        </div>
+                    </CardGlass>
+                ))
+            )}
+
+            <ModalGlass
+                isOpen={isModalOpen}
+                onClose={handleCloseModal}
+                title={semesterYearId ? 'Tambah Semester' : 'Tambah Tahun Ajaran'}
+            >
+                <form onSubmit={handleSubmit} className="space-y-4">
+                    {semesterYearId ? (
+                        <div>
+                            <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Semester</label>
+                            <select
+                                className="w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500"
+                                value={formData.name}
+                                onChange={(e) => setFormData({ ...formData, name: e.target.value })}
+                            >
+                                <option value="Ganjil" className="bg-white">Ganjil</option>
+                                <option value="Genap" className="bg-white">Genap</option>
+                            </select>
+                        </div>
+                    ) : (
+                        <InputGlass
+                            label="Nama"
+                            value={formData.name}
+                            onChange={(e) => setFormData({ ...formData, name: e.target.value })}
+                            placeholder="2025/2026"
+                            required
+                        />
+                    )}
+                    <InputGlass
+                        label="Tanggal Mulai"
+                        type="date"
+                        value={formData.start_date}
+                        onChange={(e) => setFormData({ ...formData, start_date: e.target.value })}
+                        required
+                    />
+                    <InputGlass
+                        label="Tanggal Selesai"
+                        type="date"
+                        value={formData.end_date}
+                        onChange={(e) => setFormData({ ...formData, end_date: e.target.value })}
+                        required
+                    />
+
+                    <div className="flex justify-end gap-3 pt-4">
+                        <ButtonGlass type="button" variant="ghost" onClick={handleCloseModal}>
+                            Batal
+                        </ButtonGlass>
+                        <ButtonGlass type="submit">
+                            Simpan
+                        </ButtonGlass>
+                    </div>
+                </form>
+            </ModalGlass>
+        </div>
+    );
+};
+
+export default AcademicTerms;
@@ -15,6 +15,7 @@ interface Alumni {
     profession: string;
     testimony: string;
     photo_url: string;
+    is_published: boolean;
 }
 
 const AdminAlumni: React.FC = () => {
@@ -29,12 +30,13 @@ const AdminAlumni: React.FC = () => {
         profession: '',
         testimony: '',
         photo_url: '',
+        is_published: true,
     });
 
     const { data: alumni, isLoading } = useQuery({
         queryKey: ['admin-alumni'],
         queryFn: async () => {
-            const res = await api.get('/public/alumni');
+            const res = await api.get('/public-content/alumni');
             return res.data;
         },
     });
@@ -80,6 +82,7 @@ const AdminAlumni: React.FC = () => {
                 profession: alumni.profession,
                 testimony: alumni.testimony,
                 photo_url: alumni.photo_url,
+                is_published: alumni.is_published,
             });
         } else {
             setEditingAlumni(null);
@@ -89,6 +92,7 @@ const AdminAlumni: React.FC = () => {
                 profession: '',
                 testimony: '',
                 photo_url: '',
+                is_published: true,
             });
         }
         setIsModalOpen(true);
@@ -103,6 +107,7 @@ const AdminAlumni: React.FC = () => {
             profession: '',
             testimony: '',
             photo_url: '',
+            is_published: true,
         });
     };
 
@@ -185,6 +190,9 @@ const AdminAlumni: React.FC = () => {
                                                 )}
                                             </div>
                                             <span className="font-medium text-slate-900">{item.name}</span>
+                                            {!item.is_published && (
+                                                <span className="px-2 py-0.5 rounded bg-slate-100 text-xs font-medium text-slate-500">Disembunyikan</span>
+                                            )}
                                         </div>
                                     </TableCellGlass>
                                     <TableCellGlass>
@@ -259,6 +267,14 @@ const AdminAlumni: React.FC = () => {
                         onChange={(e) => setFormData({ ...formData, photo_url: e.target.value })}
                         placeholder="https://..."
                     />
+                    <label className="flex items-center gap-2 ml-1 text-sm text-slate-700">
+                        <input
+                            type="checkbox"
+                            checked={formData.is_published}
+                            onChange={(e) => setFormData({ ...formData, is_published: e.target.checked })}
+                        />
+                        Tampilkan di website publik
+                    </label>
 
                     <div className="flex justify-end gap-3 pt-4">
                         <ButtonGlass type="button" variant="ghost" onClick={handleCloseModal}>
@@ -215,7 +215,6 @@ const AdminDownloads: React.FC = () => {
                             onChange={(e) => setFormData({ ...formData, category: e.target.value })}
                         >
                             <option value="Brosur" className="bg-white">Brosur</option>
-                            <option value="Kalender" className="bg-white">Kalender</option>
                             <option value="Dokumen" className="bg-white">Dokumen</option>
                             <option value="Lainnya" className="bg-white">Lainnya</option>
                         </select>
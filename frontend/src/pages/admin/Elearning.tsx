@@ -6,6 +6,7 @@ import CardGlass from '../../components/ui/glass/CardGlass';
 import ButtonGlass from '../../components/ui/glass/ButtonGlass';
 import InputGlass from '../../components/ui/glass/InputGlass';
 import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+import ObjectiveChecklist from '../../components/gradebook/ObjectiveChecklist';
 import ModalGlass from '../../components/ui/glass/ModalGlass';
 import { useAuth } from '../../context/AuthContext';
 
@@ -33,6 +34,7 @@ interface Task {
     subject_id: number;
     class_id: number;
     teacher_id: string;
+    learning_objectives?: { id: number; code: string }[];
     subject: {
         name: string;
     };
@@ -163,7 +165,7 @@ const Elearning: React.FC = () => {
         onSuccess: () => queryClient.invalidateQueries({ queryKey: ['materials'] }),
     });
 
-    const [newTask, setNewTask] = useState({ id: '', title: '', description: '', deadline: '', class_id: '', subject_id: '', teacher_id: '' });
+    const [newTask, setNewTask] = useState({ id: '', title: '', description: '', deadline: '', class_id: '', subject_id: '', teacher_id: '', learning_objective_ids: [] as number[] });
     const [editingTask, setEditingTask] = useState<boolean>(false);
 
     const createTaskMutation = useMutation({
@@ -244,7 +246,8 @@ const Elearning: React.FC = () => {
                 deadline: task.deadline.split('T')[0],
                 class_id: task.class_id.toString(),
                 subject_id: task.subject_id.toString(),
-                teacher_id: task.teacher_id
+                teacher_id: task.teacher_id,
+                learning_objective_ids: task.learning_objectives?.map((o) => o.id) || []
             });
             setEditingTask(true);
         } else {
@@ -255,7 +258,8 @@ const Elearning: React.FC = () => {
                 deadline: '',
                 class_id: selectedClassId,
                 teacher_id: currentTeacher ? currentTeacher.id : '',
-                subject_id: ''
+                subject_id: '',
+                learning_objective_ids: []
             });
             setEditingTask(false);
         }
@@ -264,7 +268,7 @@ const Elearning: React.FC = () => {
 
     const handleCloseTaskModal = () => {
         setIsTaskModalOpen(false);
-        setNewTask({ id: '', title: '', description: '', deadline: '', class_id: '', subject_id: '', teacher_id: '' });
+        setNewTask({ id: '', title: '', description: '', deadline: '', class_id: '', subject_id: '', teacher_id: '', learning_objective_ids: [] });
         setEditingTask(false);
     };
 
@@ -584,7 +588,7 @@ const Elearning: React.FC = () => {
                             <select
                                 className="w-full glass-input"
                                 value={newTask.subject_id}
-                                onChange={(e) => setNewTask({ ...newTask, subject_id: e.target.value })}
+                                onChange={(e) => setNewTask({ ...newTask, subject_id: e.target.value, learning_objective_ids: [] })}
                             >
                                 <option value="" className="bg-gray-900">-- Pilih Mapel --</option>
                                 {subjects?.map((s: Subject) => (
@@ -607,6 +611,11 @@ const Elearning: React.FC = () => {
                             ))}
                         </select>
                     </div>
+                    <ObjectiveChecklist
+                        subjectId={newTask.subject_id}
+                        value={newTask.learning_objective_ids}
+                        onChange={(ids) => setNewTask({ ...newTask, learning_objective_ids: ids })}
+                    />
                     <div className="flex justify-end gap-2 mt-6">
                         <ButtonGlass variant="secondary" onClick={handleCloseTaskModal}>Batal</ButtonGlass>
                         <ButtonGlass onClick={() => editingTask ? updateTaskMutation.mutate(newTask) : createTaskMutation.mutate(newTask)}>Simpan</ButtonGlass>
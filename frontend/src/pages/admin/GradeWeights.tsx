@@ -0,0 +1,199 @@
+import React, { useEffect, useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import api from '../../services/api';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import InputGlass from '../../components/ui/glass/InputGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+import { Trash2 } from 'lucide-react';
+import { gradeComponents, gradeComponentLabels } from '../../lib/gradebook';
+
+interface AcademicYear {
+    id: number;
+    name: string;
+    semesters?: { id: number; name: string; is_active: boolean }[];
+}
+
+interface GradeWeights {
+    semester_id: number;
+    weights: Record<string, number>;
+    is_default: boolean;
+}
+
+interface KKM {
+    id: number;
+    subject_id: number;
+    class_id: number;
+    value: number;
+    subject?: { name: string };
+}
+
+const selectClassName = 'w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500';
+
+const GradeWeightSettings: React.FC = () => {
+    const queryClient = useQueryClient();
+    const [semesterId, setSemesterId] = useState('');
+    const [weights, setWeights] = useState<Record<string, string>>({});
+    const [kkmForm, setKkmForm] = useState({ subject_id: '', class_id: '', value: '75' });
+
+    const { data: years } = useQuery({
+        queryKey: ['academic-years'],
+        queryFn: async () => (await api.get('/academic/years')).data as AcademicYear[],
+    });
+
+    const { data: current } = useQuery({
+        queryKey: ['grade-weights', semesterId],
+        queryFn: async () => (await api.get('/academic/grade-weights', { params: semesterId ? { semester_id: semesterId } : {} })).data as GradeWeights,
+    });
+
+    useEffect(() => {
+        if (!current) return;
+        if (!semesterId && current.semester_id) setSemesterId(String(current.semester_id));
+        setWeights(Object.fromEntries(gradeComponents.map((c) => [c, String(current.weights[c] ?? 0)])));
+    }, [current]);
+
+    const { data: subjects } = useQuery({
+        queryKey: ['subjects'],
+        queryFn: async () => (await api.get('/academic/subjects')).data as { id: number; name: string }[],
+    });
+
+    const { data: classes } = useQuery({
+        queryKey: ['classes'],
+        queryFn: async () => (await api.get('/academic/classes')).data as { id: number; name: string }[],
+    });
+
+    const { data: kkms } = useQuery({
+        queryKey: ['kkm', semesterId],
+        queryFn: async () => (await api.get('/academic/kkm', { params: { semester_id: semesterId } })).data as KKM[],
+        enabled: !!semesterId,
+    });
+
+    const total = gradeComponents.reduce((sum, c) => sum + (parseFloat(weights[c]) || 0), 0);
+
+    const saveMutation = useMutation({
+        mutationFn: () => api.put('/academic/grade-weights', {
+            semester_id: Number(semesterId),
+            weights: Object.fromEntries(gradeComponents.map((c) => [c, parseFloat(weights[c]) || 0])),
+        }),
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['grade-weights'] });
+            alert('Bobot nilai disimpan');
+        },
+        onError: (err: any) => alert('Gagal menyimpan: ' + (err.response?.data?.error || err.message)),
+    });
+
+    const kkmMutation = useMutation({
+        mutationFn: () => api.put('/academic/kkm', {
+            semester_id: Number(semesterId),
+            subject_id: Number(kkmForm.subject_id),
+            class_id: Number(kkmForm.class_id) || 0,
+            value: parseFloat(kkmForm.value),
+        }),
+        onSuccess: () => queryClient.invalidateQueries({ queryKey: ['kkm'] }),
+        onError: (err: any) => alert('Gagal menyimpan: ' + (err.response?.data?.error || err.message)),
+    });
+
+    const deleteKkmMutation = useMutation({
+        mutationFn: (id: number) => api.delete(`/academic/kkm/${id}`),
+        onSuccess: () => queryClient.invalidateQueries({ queryKey: ['kkm'] }),
+    });
+
+    const classLabel = (id: number) => id === 0 ? 'Semua kelas' : classes?.find((c) => c.id === id)?.name || id;
+
+    return (
+        <div className="space-y-6">
+            <div>
+                <h1 className="text-3xl font-bold text-slate-900 mb-2">Bobot Nilai & KKM</h1>
+                <p className="text-slate-600">Bobot komponen penilaian dan kriteria ketuntasan minimal per semester</p>
+            </div>
+
+            <CardGlass className="p-6 space-y-4 max-w-lg">
+                <div>
+                    <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Semester</label>
+                    <select className={selectClassName} value={semesterId} onChange={(e) => setSemesterId(e.target.value)}>
+                        <option value="" className="bg-white">Pilih semester</option>
+                        {years?.map((year) => year.semesters?.map((semester) => (
+                            <option key={semester.id} value={semester.id} className="bg-white">{year.name} - {semester.name}</option>
+                        )))}
+                    </select>
+                </div>
+
+                {current?.is_default && <p className="text-sm text-amber-600">Semester ini memakai bobot bawaan.</p>}
+
+                {gradeComponents.map((c) => (
+                    <InputGlass
+                        key={c}
+                        label={`${gradeComponentLabels[c]} (%)`}
+                        type="number"
+                        min={0}
+                        max={100}
+                        value={weights[c] ?? ''}
+                        onChange={(e) => setWeights({ ...weights, [c]: e.target.value })}
+                    />
+                ))}
+
+                <div className="flex justify-between items-center">
+                    <span className={`font-medium ${Math.abs(total - 100) < 0.01 ? 'text-green-600' : 'text-red-600'}`}>Total {total}%</span>
+                    <ButtonGlass onClick={() => saveMutation.mutate()} disabled={!semesterId || saveMutation.isPending}>
+                        Simpan
+                    </ButtonGlass>
+                </div>
+            </CardGlass>
+
+            {semesterId && (
+                <CardGlass className="p-6 space-y-4">
+                    <h3 className="font-bold text-slate-900">KKM</h3>
+                    <p className="text-sm text-slate-500">Mata pelajaran tanpa KKM memakai nilai bawaan sekolah. KKM per kelas menggantikan KKM mata pelajaran.</p>
+                    <form
+                        onSubmit={(e) => { e.preventDefault(); kkmMutation.mutate(); }}
+                        className="grid md:grid-cols-4 gap-4 items-end"
+                    >
+                        <div>
+                            <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Mata Pelajaran</label>
+                            <select className={selectClassName} value={kkmForm.subject_id} onChange={(e) => setKkmForm({ ...kkmForm, subject_id: e.target.value })} required>
+                                <option value="" className="bg-white">Pilih...</option>
+                                {subjects?.map((s) => <option key={s.id} value={s.id} className="bg-white">{s.name}</option>)}
+                            </select>
+                        </div>
+                        <div>
+                            <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Kelas</label>
+                            <select className={selectClassName} value={kkmForm.class_id} onChange={(e) => setKkmForm({ ...kkmForm, class_id: e.target.value })}>
+                                <option value="" className="bg-white">Semua kelas</option>
+                                {classes?.map((c) => <option key={c.id} value={c.id} className="bg-white">{c.name}</option>)}
+                            </select>
+                        </div>
+                        <InputGlass label="KKM" type="number" min={1} max={100} value={kkmForm.value} onChange={(e) => setKkmForm({ ...kkmForm, value: e.target.value })} required />
+                        <ButtonGlass type="submit" disabled={kkmMutation.isPending}>Simpan KKM</ButtonGlass>
+                    </form>
+
+                    <TableGlass>
+                        <TableHeaderGlass>
+                            <TableRowGlass>
+                                <TableHeadGlass>Mata Pelajaran</TableHeadGlass>
+                                <TableHeadGlass>Kelas</TableHeadGlass>
+                                <TableHeadGlass>KKM</TableHeadGlass>
+                                <TableHeadGlass>Aksi</TableHeadGlass>
+                            </TableRowGlass>
+                        </TableHeaderGlass>
+                        <TableBodyGlass>
+                            {kkms?.map((kkm) => (
+                                <TableRowGlass key={kkm.id}>
+                                    <TableCellGlass>{kkm.subject?.name}</TableCellGlass>
+                                    <TableCellGlass>{classLabel(kkm.class_id)}</TableCellGlass>
+                                    <TableCellGlass className="font-bold">{kkm.value}</TableCellGlass>
+                                    <TableCellGlass>
+                                        <button onClick={() => confirm('Hapus KKM ini?') && deleteKkmMutation.mutate(kkm.id)} className="text-red-600 hover:text-red-500">
+                                            <Trash2 size={16} />
+                                        </button>
+                                    </TableCellGlass>
+                                </TableRowGlass>
+                            ))}
+                        </TableBodyGlass>
+                    </TableGlass>
+                </CardGlass>
+            )}
+        </div>
+    );
+};
+
+export default GradeWeightSettings;
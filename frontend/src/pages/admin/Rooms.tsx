@@ -0,0 +1,251 @@
+import React, { useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import api from '../../services/api';
+import { Plus, Trash2, Edit, CalendarPlus } from 'lucide-react';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import InputGlass from '../../components/ui/glass/InputGlass';
+import ModalGlass from '../../components/ui/glass/ModalGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+import { useAuth } from '../../context/AuthContext';
+
+interface Room {
+    id: number;
+    unit_id: number;
+    name: string;
+    type: string;
+    capacity: number;
+}
+
+interface Booking {
+    id: number;
+    room_id: number;
+    title: string;
+    notes: string;
+    date: string;
+    start_time: string;
+    end_time: string;
+    booked_by_id: string;
+    booked_by?: { name: string };
+}
+
+interface Occupancy {
+    room: Room;
+    schedules: { id: number; start_time: string; end_time: string; class?: { name: string }; subject?: { name: string } }[];
+    bookings: Booking[];
+}
+
+const roomTypeLabels: Record<string, string> = {
+    classroom: 'Ruang Kelas',
+    lab: 'Laboratorium',
+    hall: 'Aula',
+    library: 'Perpustakaan',
+    field: 'Lapangan',
+    other: 'Lainnya',
+};
+
+const selectClassName = 'w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500';
+const today = () => new Date().toLocaleDateString('en-CA');
+
+const Rooms: React.FC = () => {
+    const { user } = useAuth();
+    const queryClient = useQueryClient();
+    const isAdmin = [1, 2, 3].includes(user?.role_id ?? 0);
+    const [date, setDate] = useState(today());
+    const [roomForm, setRoomForm] = useState<Partial<Room> | null>(null);
+    const [bookingForm, setBookingForm] = useState<Record<string, string> | null>(null);
+
+    const { data: occupancy } = useQuery({
+        queryKey: ['room-occupancy', date],
+        queryFn: async () => {
+            const res = await api.get('/academic/rooms/occupancy', { params: { date } });
+            return res.data as Occupancy[];
+        },
+    });
+
+    const invalidate = () => {
+        queryClient.invalidateQueries({ queryKey: ['room-occupancy'] });
+    };
+
+    const onError = (err: any) => {
+        const data = err.response?.data;
+        if (data?.schedules || data?.bookings) {
+            const lines = [
+                ...(data.schedules || []).map((s: any) => `- Pelajaran ${s.subject?.name} (${s.class?.name}) ${s.start_time}-${s.end_time}`),
+                ...(data.bookings || []).map((b: Booking) => `- ${b.title} ${b.start_time}-${b.end_time}`),
+            ];
+            alert('Ruangan sudah terpakai:\n' + lines.join('\n'));
+            return;
+        }
+        alert('Gagal menyimpan: ' + (data?.error || err.message));
+    };
+
+    const saveRoomMutation = useMutation({
+        mutationFn: (room: Partial<Room>) => room.id
+            ? api.put(`/academic/rooms/${room.id}`, room)
+            : api.post('/academic/rooms', { ...room, unit_id: user?.unit_id }),
+        onSuccess: () => {
+            invalidate();
+            setRoomForm(null);
+        },
+        onError,
+    });
+
+    const deleteRoomMutation = useMutation({
+        mutationFn: (id: number) => api.delete(`/academic/rooms/${id}`),
+        onSuccess: invalidate,
+        onError,
+    });
+
+    const bookMutation = useMutation({
+        mutationFn: (form: Record<string, string>) => api.post('/academic/rooms/bookings', { ...form, room_id: Number(form.room_id) }),
+        onSuccess: () => {
+            invalidate();
+            setBookingForm(null);
+        },
+        onError,
+    });
+
+    const cancelBookingMutation = useMutation({
+        mutationFn: (id: number) => api.delete(`/academic/rooms/bookings/${id}`),
+        onSuccess: invalidate,
+        onError,
+    });
+
+    const handleRoomSubmit = (e: React.FormEvent) => {
+        e.preventDefault();
+        if (roomForm) saveRoomMutation.mutate({ ...roomForm, capacity: Number(roomForm.capacity) || 0 });
+    };
+
+    const handleBookingSubmit = (e: React.FormEvent) => {
+        e.preventDefault();
+        if (bookingForm) bookMutation.mutate(bookingForm);
+    };
+
+    return (
+        <div className="space-y-6">
+            <div className="flex justify-between items-center">
+                <div>
+                    <h1 className="text-3xl font-bold text-slate-900 mb-2">Ruangan</h1>
+                    <p className="text-slate-600">Pemakaian ruangan dan peminjaman untuk kegiatan</p>
+                </div>
+                <div className="flex gap-3">
+                    {isAdmin && (
+                        <ButtonGlass variant="secondary" icon={Plus} onClick={() => setRoomForm({ type: 'classroom' })}>
+                            Ruangan
+                        </ButtonGlass>
+                    )}
+                    <ButtonGlass icon={CalendarPlus} onClick={() => setBookingForm({ date, room_id: '', title: '', start_time: '', end_time: '', notes: '' })}>
+                        Pinjam Ruangan
+                    </ButtonGlass>
+                </div>
+            </div>
+
+            <CardGlass className="p-6 space-y-4">
+                <div className="max-w-xs">
+                    <InputGlass label="Tanggal" type="date" value={date} onChange={(e) => setDate(e.target.value)} />
+                </div>
+                <TableGlass>
+                    <TableHeaderGlass>
+                        <TableRowGlass>
+                            <TableHeadGlass>Ruangan</TableHeadGlass>
+                            <TableHeadGlass>Jenis</TableHeadGlass>
+                            <TableHeadGlass>Kapasitas</TableHeadGlass>
+                            <TableHeadGlass>Pemakaian</TableHeadGlass>
+                            {isAdmin && <TableHeadGlass>Aksi</TableHeadGlass>}
+                        </TableRowGlass>
+                    </TableHeaderGlass>
+                    <TableBodyGlass>
+                        {occupancy?.map(({ room, schedules, bookings }) => (
+                            <TableRowGlass key={room.id}>
+                                <TableCellGlass className="font-medium">{room.name}</TableCellGlass>
+                                <TableCellGlass>{roomTypeLabels[room.type] || room.type}</TableCellGlass>
+                                <TableCellGlass>{room.capacity}</TableCellGlass>
+                                <TableCellGlass>
+                                    {schedules.length === 0 && bookings.length === 0 && <span className="text-slate-400">Kosong</span>}
+                                    <ul className="space-y-1 text-sm">
+                                        {schedules.map((s) => (
+                                            <li key={`s${s.id}`}>{s.start_time}-{s.end_time} {s.subject?.name} ({s.class?.name})</li>
+                                        ))}
+                                        {bookings.map((b) => (
+                                            <li key={`b${b.id}`} className="flex items-center gap-2 text-purple-700">
+                                                {b.start_time}-{b.end_time} {b.title}{b.booked_by && ` · ${b.booked_by.name}`}
+                                                {(isAdmin || b.booked_by_id === user?.id) && (
+                                                    <button
+                                                        onClick={() => confirm('Batalkan peminjaman ini?') && cancelBookingMutation.mutate(b.id)}
+                                                        className="text-red-600 hover:text-red-500"
+                                                    >
+                                                        <Trash2 size={14} />
+                                                    </button>
+                                                )}
+                                            </li>
+                                        ))}
+                                    </ul>
+                                </TableCellGlass>
+                                {isAdmin && (
+                                    <TableCellGlass>
+                                        <div className="flex space-x-2">
+                                            <button onClick={() => setRoomForm(room)} className="text-blue-600 hover:text-blue-500"><Edit size={16} /></button>
+                                            <button
+                                                onClick={() => confirm('Hapus ruangan beserta peminjamannya?') && deleteRoomMutation.mutate(room.id)}
+                                                className="text-red-600 hover:text-red-500"
+                                            >
+                                                <Trash2 size={16} />
+                                            </button>
+                                        </div>
+                                    </TableCellGlass>
+                                )}
+                            </TableRowGlass>
+                        ))}
+                    </TableBodyGlass>
+                </TableGlass>
+            </CardGlass>
+
+            <ModalGlass isOpen={roomForm !== null} onClose={() => setRoomForm(null)} title={roomForm?.id ? 'Edit Ruangan' : 'Tambah Ruangan'}>
+                <form onSubmit={handleRoomSubmit} className="space-y-4">
+                    <InputGlass label="Nama" value={roomForm?.name || ''} onChange={(e) => setRoomForm({ ...roomForm, name: e.target.value })} required />
+                    <div>
+                        <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Jenis</label>
+                        <select className={selectClassName} value={roomForm?.type || 'classroom'} onChange={(e) => setRoomForm({ ...roomForm, type: e.target.value })}>
+                            {Object.entries(roomTypeLabels).map(([value, label]) => (
+                                <option key={value} value={value} className="bg-white">{label}</option>
+                            ))}
+                        </select>
+                    </div>
+                    <InputGlass label="Kapasitas" type="number" min={0} value={roomForm?.capacity ?? ''} onChange={(e) => setRoomForm({ ...roomForm, capacity: Number(e.target.value) })} />
+                    <div className="flex justify-end">
+                        <ButtonGlass type="submit">Simpan</ButtonGlass>
+                    </div>
+                </form>
+            </ModalGlass>
+
+            <ModalGlass isOpen={bookingForm !== null} onClose={() => setBookingForm(null)} title="Pinjam Ruangan">
+                {bookingForm && (
+                    <form onSubmit={handleBookingSubmit} className="space-y-4">
+                        <div>
+                            <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Ruangan</label>
+                            <select className={selectClassName} value={bookingForm.room_id} onChange={(e) => setBookingForm({ ...bookingForm, room_id: e.target.value })} required>
+                                <option value="" className="bg-white">Pilih Ruangan</option>
+                                {occupancy?.map(({ room }) => (
+                                    <option key={room.id} value={room.id} className="bg-white">{room.name}</option>
+                                ))}
+                            </select>
+                        </div>
+                        <InputGlass label="Kegiatan" value={bookingForm.title} onChange={(e) => setBookingForm({ ...bookingForm, title: e.target.value })} required />
+                        <InputGlass label="Tanggal" type="date" value={bookingForm.date} onChange={(e) => setBookingForm({ ...bookingForm, date: e.target.value })} required />
+                        <div className="grid grid-cols-2 gap-4">
+                            <InputGlass label="Mulai" type="time" value={bookingForm.start_time} onChange={(e) => setBookingForm({ ...bookingForm, start_time: e.target.value })} required />
+                            <InputGlass label="Selesai" type="time" value={bookingForm.end_time} onChange={(e) => setBookingForm({ ...bookingForm, end_time: e.target.value })} required />
+                        </div>
+                        <InputGlass label="Catatan" value={bookingForm.notes} onChange={(e) => setBookingForm({ ...bookingForm, notes: e.target.value })} />
+                        <div className="flex justify-end">
+                            <ButtonGlass type="submit">Pinjam</ButtonGlass>
+                        </div>
+                    </form>
+                )}
+            </ModalGlass>
+        </div>
+    );
+};
+
+export default Rooms;
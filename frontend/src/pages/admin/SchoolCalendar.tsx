@@ -0,0 +1,182 @@
+import React, { useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import api from '../../services/api';
+import { Plus, Trash2, Edit, Link as LinkIcon } from 'lucide-react';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import InputGlass from '../../components/ui/glass/InputGlass';
+import ModalGlass from '../../components/ui/glass/ModalGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+import { useAuth } from '../../context/AuthContext';
+
+interface CalendarEvent {
+    id: number;
+    title: string;
+    description: string;
+    type: string;
+    start_date: string;
+    end_date: string;
+    non_teaching: boolean;
+}
+
+interface EventForm {
+    id?: number;
+    title: string;
+    description: string;
+    type: string;
+    start_date: string;
+    end_date: string;
+    non_teaching: boolean;
+}
+
+const typeLabels: Record<string, string> = {
+    holiday: 'Libur',
+    exam: 'Pekan Ujian',
+    event: 'Kegiatan',
+};
+
+const selectClassName = 'w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500';
+const formatDate = (date: string) => new Date(date).toLocaleDateString('id-ID', { day: 'numeric', month: 'long', year: 'numeric', timeZone: 'UTC' });
+const emptyForm = (): EventForm => ({ title: '', description: '', type: 'holiday', start_date: '', end_date: '', non_teaching: true });
+
+const SchoolCalendar: React.FC = () => {
+    const { user } = useAuth();
+    const queryClient = useQueryClient();
+    const [form, setForm] = useState<EventForm | null>(null);
+
+    const { data: events } = useQuery({
+        queryKey: ['calendar-events'],
+        queryFn: async () => (await api.get('/academic/calendar')).data as CalendarEvent[],
+    });
+
+    const onError = (err: any) => alert('Gagal menyimpan: ' + (err.response?.data?.error || err.message));
+
+    const saveMutation = useMutation({
+        mutationFn: (event: EventForm) => event.id
+            ? api.put(`/academic/calendar/${event.id}`, event)
+            : api.post('/academic/calendar', { ...event, unit_id: user?.unit_id }),
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['calendar-events'] });
+            setForm(null);
+        },
+        onError,
+    });
+
+    const deleteMutation = useMutation({
+        mutationFn: (id: number) => api.delete(`/academic/calendar/${id}`),
+        onSuccess: () => queryClient.invalidateQueries({ queryKey: ['calendar-events'] }),
+        onError,
+    });
+
+    const handleSubmit = (e: React.FormEvent) => {
+        e.preventDefault();
+        if (form) saveMutation.mutate(form);
+    };
+
+    const feedUrl = `${window.location.origin}/api/public/calendar.ics?unit_id=${user?.unit_id || 1}`;
+
+    return (
+        <div className="space-y-6">
+            <div className="flex justify-between items-center">
+                <div>
+                    <h1 className="text-3xl font-bold text-slate-900 mb-2">Kalender Akademik</h1>
+                    <p className="text-slate-600">Hari libur, pekan ujian dan kegiatan sekolah</p>
+                </div>
+                <ButtonGlass icon={Plus} onClick={() => setForm(emptyForm())}>
+                    Tambah Agenda
+                </ButtonGlass>
+            </div>
+
+            <CardGlass className="p-4 flex items-center gap-3 text-sm text-slate-600">
+                <LinkIcon size={16} className="shrink-0" />
+                <span>Langganan kalender (.ics):</span>
+                <code className="px-2 py-1 rounded bg-white/60 text-slate-900 break-all">{feedUrl}</code>
+            </CardGlass>
+
+            <CardGlass className="p-6">
+                <TableGlass>
+                    <TableHeaderGlass>
+                        <TableRowGlass>
+                            <TableHeadGlass>Tanggal</TableHeadGlass>
+                            <TableHeadGlass>Agenda</TableHeadGlass>
+                            <TableHeadGlass>Jenis</TableHeadGlass>
+                            <TableHeadGlass>KBM</TableHeadGlass>
+                            <TableHeadGlass>Aksi</TableHeadGlass>
+                        </TableRowGlass>
+                    </TableHeaderGlass>
+                    <TableBodyGlass>
+                        {events?.map((event) => (
+                            <TableRowGlass key={event.id}>
+                                <TableCellGlass>
+                                    {formatDate(event.start_date)}
+                                    {event.end_date !== event.start_date && ` - ${formatDate(event.end_date)}`}
+                                </TableCellGlass>
+                                <TableCellGlass>
+                                    <p className="font-medium">{event.title}</p>
+                                    {event.description && <p className="text-xs text-slate-500">{event.description}</p>}
+                                </TableCellGlass>
+                                <TableCellGlass>{typeLabels[event.type] || event.type}</TableCellGlass>
+                                <TableCellGlass>
+                                    {event.non_teaching
+                                        ? <span className="text-red-600">Libur</span>
+                                        : <span className="text-slate-500">Tetap berjalan</span>}
+                                </TableCellGlass>
+                                <TableCellGlass>
+                                    <div className="flex space-x-2">
+                                        <button
+                                            onClick={() => setForm({ ...event, start_date: event.start_date.slice(0, 10), end_date: event.end_date.slice(0, 10) })}
+                                            className="text-blue-600 hover:text-blue-500"
+                                        >
+                                            <Edit size={16} />
+                                        </button>
+                                        <button
+                                            onClick={() => confirm('Hapus agenda ini?') && deleteMutation.mutate(event.id)}
+                                            className="text-red-600 hover:text-red-500"
+                                        >
+                                            <Trash2 size={16} />
+                                        </button>
+                                    </div>
+                                </TableCellGlass>
+                            </TableRowGlass>
+                        ))}
+                    </TableBodyGlass>
+                </TableGlass>
+                {events?.length === 0 && <p className="text-center text-slate-500 mt-4">Belum ada agenda</p>}
+            </CardGlass>
+
+            <ModalGlass isOpen={form !== null} onClose={() => setForm(null)} title={form?.id ? 'Edit Agenda' : 'Tambah Agenda'}>
+                {form && (
+                    <form onSubmit={handleSubmit} className="space-y-4">
+                        <InputGlass label="Judul" value={form.title} onChange={(e) => setForm({ ...form, title: e.target.value })} required />
+                        <div>
+                            <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Jenis</label>
+                            <select
+                                className={selectClassName}
+                                value={form.type}
+                                onChange={(e) => setForm({ ...form, type: e.target.value, non_teaching: e.target.value === 'holiday' })}
+                            >
+                                {Object.entries(typeLabels).map(([value, label]) => (
+                                    <option key={value} value={value} className="bg-white">{label}</option>
+                                ))}
+                            </select>
+                        </div>
+                        <div className="grid grid-cols-2 gap-4">
+                            <InputGlass label="Mulai" type="date" value={form.start_date} onChange={(e) => setForm({ ...form, start_date: e.target.value })} required />
+                            <InputGlass label="Selesai" type="date" value={form.end_date} onChange={(e) => setForm({ ...form, end_date: e.target.value })} />
+                        </div>
+                        <InputGlass label="Keterangan" value={form.description} onChange={(e) => setForm({ ...form, description: e.target.value })} />
+                        <label className="flex items-center gap-2 text-sm text-slate-700">
+                            <input type="checkbox" checked={form.non_teaching} onChange={(e) => setForm({ ...form, non_teaching: e.target.checked })} />
+                            Tidak ada KBM (absensi dan tenggat tugas dilewati)
+                        </label>
+                        <div className="flex justify-end">
+                            <ButtonGlass type="submit" disabled={saveMutation.isPending}>Simpan</ButtonGlass>
+                        </div>
+                    </form>
+                )}
+            </ModalGlass>
+        </div>
+    );
+};
+
+export default SchoolCalendar;
@@ -0,0 +1,213 @@
+import React, { useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import api from '../../services/api';
+import { Eye, GraduationCap } from 'lucide-react';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+
+interface AcademicYear {
+    id: number;
+    name: string;
+}
+
+interface ClassItem {
+    id: number;
+    name: string;
+    academic_year_id: number | null;
+}
+
+interface PromotionItem {
+    student_id: string;
+    name: string;
+    from_class: string;
+    to_class?: string;
+    status: string;
+}
+
+interface PromotionResult {
+    dry_run: boolean;
+    promoted: number;
+    retained: number;
+    graduated: number;
+    students: PromotionItem[] | null;
+}
+
+const GRADUATE = 'graduate';
+
+const statusLabels: Record<string, string> = {
+    Promoted: 'Naik kelas',
+    Retained: 'Tinggal kelas',
+    Graduated: 'Lulus',
+};
+
+const selectClassName = 'w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500';
+
+const StudentPromotion: React.FC = () => {
+    const queryClient = useQueryClient();
+    const [yearId, setYearId] = useState<number | null>(null);
+    // from class id -> target class id or GRADUATE
+    const [targets, setTargets] = useState<Record<number, string>>({});
+    // student id -> class id the student repeats in
+    const [repeaters, setRepeaters] = useState<Record<string, string>>({});
+    const [result, setResult] = useState<PromotionResult | null>(null);
+
+    const { data: years } = useQuery({
+        queryKey: ['academic-years'],
+        queryFn: async () => {
+            const res = await api.get('/academic/years');
+            return res.data as AcademicYear[];
+        },
+    });
+
+    const { data: classes } = useQuery({
+        queryKey: ['classes', 'all-terms'],
+        queryFn: async () => {
+            const res = await api.get('/academic/classes', { params: { semester_id: 'all' } });
+            return res.data as ClassItem[];
+        },
+    });
+
+    const sourceClasses = classes?.filter((c) => c.academic_year_id !== yearId) ?? [];
+    const targetClasses = classes?.filter((c) => c.academic_year_id === yearId) ?? [];
+
+    const buildPayload = (dryRun: boolean) => ({
+        academic_year_id: yearId,
+        dry_run: dryRun,
+        classes: Object.entries(targets)
+            .filter(([, target]) => target !== '')
+            .map(([from, target]) => target === GRADUATE
+                ? { from_class_id: Number(from), graduate: true }
+                : { from_class_id: Number(from), to_class_id: Number(target) }),
+        repeaters: Object.entries(repeaters)
+            .filter(([, classId]) => classId !== '')
+            .map(([studentId, classId]) => ({ student_id: studentId, class_id: Number(classId) })),
+    });
+
+    const promotionMutation = useMutation({
+        mutationFn: (dryRun: boolean) => api.post('/academic/promotions', buildPayload(dryRun)),
+        onSuccess: (res) => {
+            const data = res.data as PromotionResult;
+            setResult(data);
+            if (!data.dry_run) {
+                setTargets({});
+                setRepeaters({});
+                queryClient.invalidateQueries();
+                alert(`Kenaikan kelas selesai: ${data.promoted} naik, ${data.retained} tinggal kelas, ${data.graduated} lulus`);
+            }
+        },
+        onError: (err: any) => {
+            alert('Gagal memproses kenaikan kelas: ' + (err.response?.data?.error || err.message));
+        },
+    });
+
+    const handleYearChange = (value: string) => {
+        setYearId(value ? Number(value) : null);
+        setTargets({});
+        setRepeaters({});
+        setResult(null);
+    };
+
+    const handleApply = () => {
+        if (confirm('Terapkan kenaikan kelas? Proses ini tidak dapat dibatalkan.')) {
+            promotionMutation.mutate(false);
+        }
+    };
+
+    return (
+        <div className="space-y-6">
+            <div>
+                <h1 className="text-3xl font-bold text-slate-900 mb-2">Kenaikan Kelas</h1>
+                <p className="text-slate-600">Naikkan kelas, tinggal kelas, dan luluskan siswa ke tahun ajaran baru</p>
+            </div>
+
+            <CardGlass className="p-6 space-y-4">
+                <div>
+                    <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Tahun Ajaran Tujuan</label>
+                    <select className={selectClassName} value={yearId ?? ''} onChange={(e) => handleYearChange(e.target.value)}>
+                        <option value="" className="bg-white">Pilih tahun ajaran</option>
+                        {years?.map((year) => (
+                            <option key={year.id} value={year.id} className="bg-white">{year.name}</option>
+                        ))}
+                    </select>
+                </div>
+
+                {yearId && (
+                    <div className="space-y-2">
+                        {sourceClasses.map((c) => (
+                            <div key={c.id} className="grid grid-cols-2 gap-4 items-center">
+                                <span className="font-medium text-slate-900">{c.name}</span>
+                                <select
+                                    className={selectClassName}
+                                    value={targets[c.id] ?? ''}
+                                    onChange={(e) => setTargets({ ...targets, [c.id]: e.target.value })}
+                                >
+                                    <option value="" className="bg-white">Tidak diproses</option>
+                                    {targetClasses.map((t) => (
+                                        <option key={t.id} value={t.id} className="bg-white">{t.name}</option>
+                                    ))}
+                                    <option value={GRADUATE} className="bg-white">Lulus</option>
+                                </select>
+                            </div>
+                        ))}
+                    </div>
+                )}
+
+                <div className="flex justify-end gap-3">
+                    <ButtonGlass variant="secondary" icon={Eye} onClick={() => promotionMutation.mutate(true)} disabled={!yearId}>
+                        Pratinjau
+                    </ButtonGlass>
+                    <ButtonGlass icon={GraduationCap} onClick={handleApply} disabled={!result?.dry_run}>
+                        Terapkan
+                    </ButtonGlass>
+                </div>
+            </CardGlass>
+
+            {result && (
+                <CardGlass className="p-6 space-y-4">
+                    <p className="text-slate-600">
+                        {result.promoted} naik kelas, {result.retained} tinggal kelas, {result.graduated} lulus
+                    </p>
+                    {result.dry_run && result.graduated > 0 && (
+                        <p className="text-sm text-slate-500">Akun siswa yang lulus akan dinonaktifkan setelah diterapkan.</p>
+                    )}
+                    <TableGlass>
+                        <TableHeaderGlass>
+                            <TableRowGlass>
+                                <TableHeadGlass>Nama</TableHeadGlass>
+                                <TableHeadGlass>Kelas Asal</TableHeadGlass>
+                                <TableHeadGlass>Hasil</TableHeadGlass>
+                                <TableHeadGlass>Tinggal Kelas</TableHeadGlass>
+                            </TableRowGlass>
+                        </TableHeaderGlass>
+                        <TableBodyGlass>
+                            {result.students?.map((item) => (
+                                <TableRowGlass key={item.student_id}>
+                                    <TableCellGlass>{item.name}</TableCellGlass>
+                                    <TableCellGlass>{item.from_class}</TableCellGlass>
+                                    <TableCellGlass>
+                                        {statusLabels[item.status] ?? item.status}{item.to_class && ` → ${item.to_class}`}
+                                    </TableCellGlass>
+                                    <TableCellGlass>
+                                        <select
+                                            className={selectClassName}
+                                            value={repeaters[item.student_id] ?? ''}
+                                            onChange={(e) => setRepeaters({ ...repeaters, [item.student_id]: e.target.value })}
+                                        >
+                                            <option value="" className="bg-white">-</option>
+                                            {targetClasses.map((t) => (
+                                                <option key={t.id} value={t.id} className="bg-white">{t.name}</option>
+                                            ))}
+                                        </select>
+                                    </TableCellGlass>
+                                </TableRowGlass>
+                            ))}
+                        </TableBodyGlass>
+                    </TableGlass>
+                </CardGlass>
+            )}
+        </div>
+    );
+};
+
+export default StudentPromotion;
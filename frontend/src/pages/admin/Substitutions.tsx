@@ -0,0 +1,187 @@
+import React, { useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import api from '../../services/api';
+import { Trash2, UserPlus } from 'lucide-react';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import InputGlass from '../../components/ui/glass/InputGlass';
+import ModalGlass from '../../components/ui/glass/ModalGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+
+interface Teacher {
+    id: string;
+    user?: { name: string };
+}
+
+interface Schedule {
+    id: number;
+    day: string;
+    start_time: string;
+    end_time: string;
+    teacher_id: string;
+    class?: { name: string };
+    subject?: { name: string };
+    teacher?: Teacher;
+}
+
+interface Substitution {
+    id: number;
+    schedule_id: number;
+    reason: string;
+    teacher?: Teacher;
+}
+
+interface Candidate {
+    teacher: Teacher;
+    same_subject: boolean;
+    lessons: number;
+}
+
+const weekdays = ['Sunday', 'Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday'];
+const reasons = ['Sakit', 'Izin', 'Dinas Luar', 'Cuti'];
+
+const Substitutions: React.FC = () => {
+    const queryClient = useQueryClient();
+    const [date, setDate] = useState(new Date().toLocaleDateString('en-CA'));
+    const [lesson, setLesson] = useState<Schedule | null>(null);
+    const [reason, setReason] = useState(reasons[0]);
+
+    const day = weekdays[new Date(`${date}T00:00:00`).getDay()];
+
+    const { data: schedules } = useQuery({
+        queryKey: ['academic_schedules'],
+        queryFn: async () => (await api.get('/academic/schedules')).data as Schedule[],
+    });
+
+    const { data: substitutions } = useQuery({
+        queryKey: ['substitutions', date],
+        queryFn: async () => (await api.get('/academic/substitutions', { params: { from: date, to: date } })).data as Substitution[],
+    });
+
+    const { data: candidates, isLoading: isLoadingCandidates } = useQuery({
+        queryKey: ['substitute-candidates', lesson?.id, date],
+        queryFn: async () => (await api.get('/academic/substitutions/suggestions', { params: { schedule_id: lesson?.id, date } })).data as Candidate[],
+        enabled: lesson !== null,
+    });
+
+    const onError = (err: any) => {
+        const conflicts = err.response?.data?.conflicts as any[] | undefined;
+        if (conflicts?.length) {
+            const lines = conflicts.map((c) => `- ${c.schedule.subject?.name} (${c.schedule.class?.name}) ${c.schedule.start_time}-${c.schedule.end_time}`);
+            alert('Guru sedang mengajar:\n' + lines.join('\n'));
+            return;
+        }
+        alert('Gagal menyimpan: ' + (err.response?.data?.error || err.message));
+    };
+
+    const assignMutation = useMutation({
+        mutationFn: (teacherId: string) => api.post('/academic/substitutions', { schedule_id: lesson?.id, date, teacher_id: teacherId, reason }),
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['substitutions'] });
+            setLesson(null);
+        },
+        onError,
+    });
+
+    const deleteMutation = useMutation({
+        mutationFn: (id: number) => api.delete(`/academic/substitutions/${id}`),
+        onSuccess: () => queryClient.invalidateQueries({ queryKey: ['substitutions'] }),
+        onError,
+    });
+
+    const lessons = schedules?.filter((s) => s.day === day).sort((a, b) => a.start_time.localeCompare(b.start_time)) ?? [];
+    const substitutionFor = (scheduleId: number) => substitutions?.find((s) => s.schedule_id === scheduleId);
+
+    return (
+        <div className="space-y-6">
+            <div>
+                <h1 className="text-3xl font-bold text-slate-900 mb-2">Guru Pengganti</h1>
+                <p className="text-slate-600">Atur guru pengganti untuk guru yang berhalangan hadir</p>
+            </div>
+
+            <CardGlass className="p-6 space-y-4">
+                <div className="max-w-xs">
+                    <InputGlass label="Tanggal" type="date" value={date} onChange={(e) => setDate(e.target.value)} />
+                </div>
+                <TableGlass>
+                    <TableHeaderGlass>
+                        <TableRowGlass>
+                            <TableHeadGlass>Jam</TableHeadGlass>
+                            <TableHeadGlass>Kelas</TableHeadGlass>
+                            <TableHeadGlass>Mapel</TableHeadGlass>
+                            <TableHeadGlass>Guru</TableHeadGlass>
+                            <TableHeadGlass>Pengganti</TableHeadGlass>
+                            <TableHeadGlass>Aksi</TableHeadGlass>
+                        </TableRowGlass>
+                    </TableHeaderGlass>
+                    <TableBodyGlass>
+                        {lessons.map((s) => {
+                            const sub = substitutionFor(s.id);
+                            return (
+                                <TableRowGlass key={s.id}>
+                                    <TableCellGlass>{s.start_time} - {s.end_time}</TableCellGlass>
+                                    <TableCellGlass>{s.class?.name}</TableCellGlass>
+                                    <TableCellGlass>{s.subject?.name}</TableCellGlass>
+                                    <TableCellGlass>{s.teacher?.user?.name}</TableCellGlass>
+                                    <TableCellGlass>
+                                        {sub ? `${sub.teacher?.user?.name} (${sub.reason})` : <span className="text-slate-400">-</span>}
+                                    </TableCellGlass>
+                                    <TableCellGlass>
+                                        {sub ? (
+                                            <button
+                                                onClick={() => confirm('Hapus guru pengganti?') && deleteMutation.mutate(sub.id)}
+                                                className="text-red-600 hover:text-red-500"
+                                            >
+                                                <Trash2 size={16} />
+                                            </button>
+                                        ) : (
+                                            <button onClick={() => setLesson(s)} className="text-purple-600 hover:text-purple-500">
+                                                <UserPlus size={16} />
+                                            </button>
+                                        )}
+                                    </TableCellGlass>
+                                </TableRowGlass>
+                            );
+                        })}
+                    </TableBodyGlass>
+                </TableGlass>
+                {lessons.length === 0 && <p className="text-center text-slate-500">Tidak ada jadwal pada tanggal ini</p>}
+            </CardGlass>
+
+            <ModalGlass isOpen={lesson !== null} onClose={() => setLesson(null)} title="Pilih Guru Pengganti">
+                {lesson && (
+                    <div className="space-y-4">
+                        <p className="text-slate-600">
+                            {lesson.subject?.name} · {lesson.class?.name} · {lesson.start_time}-{lesson.end_time}
+                        </p>
+                        <div>
+                            <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Alasan</label>
+                            <select className="w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500" value={reason} onChange={(e) => setReason(e.target.value)}>
+                                {reasons.map((r) => <option key={r} value={r} className="bg-white">{r}</option>)}
+                            </select>
+                        </div>
+                        {isLoadingCandidates && <p className="text-slate-500">Memuat...</p>}
+                        {candidates?.length === 0 && <p className="text-slate-500">Tidak ada guru yang kosong pada jam ini</p>}
+                        <div className="space-y-2">
+                            {candidates?.map((c) => (
+                                <div key={c.teacher.id} className="flex items-center justify-between p-3 rounded-xl bg-white/40">
+                                    <div>
+                                        <p className="font-medium text-slate-900">{c.teacher.user?.name}</p>
+                                        <p className="text-xs text-slate-500">
+                                            {c.same_subject ? 'Mengajar mapel yang sama · ' : ''}{c.lessons} jam hari ini
+                                        </p>
+                                    </div>
+                                    <ButtonGlass onClick={() => assignMutation.mutate(c.teacher.id)} disabled={assignMutation.isPending}>
+                                        Tugaskan
+                                    </ButtonGlass>
+                                </div>
+                            ))}
+                        </div>
+                    </div>
+                )}
+            </ModalGlass>
+        </div>
+    );
+};
+
+export default Substitutions;
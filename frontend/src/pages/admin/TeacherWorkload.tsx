@@ -0,0 +1,134 @@
+import React, { useState } from 'react';
+import { useQuery } from '@tanstack/react-query';
+import api from '../../services/api';
+import { Download } from 'lucide-react';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+
+interface AcademicYear {
+    id: number;
+    name: string;
+    semesters?: { id: number; name: string }[];
+}
+
+interface TeacherWorkload {
+    teacher: { id: string; nip: string; user?: { name: string } };
+    subjects: { subject: { id: number; name: string }; lessons: number; jp: number }[];
+    jp: number;
+    status: 'under' | 'ok' | 'over' | 'partial';
+}
+
+interface WorkloadReport {
+    min_jp: number;
+    max_jp: number;
+    teachers: TeacherWorkload[];
+}
+
+const statusStyles: Record<string, string> = {
+    under: 'bg-amber-100 text-amber-700',
+    ok: 'bg-green-100 text-green-700',
+    over: 'bg-red-100 text-red-700',
+    partial: 'bg-slate-100 text-slate-600',
+};
+
+const selectClassName = 'w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500';
+
+const TeacherWorkloadReport: React.FC = () => {
+    const [semesterId, setSemesterId] = useState('');
+    const params = semesterId ? { semester_id: semesterId } : {};
+
+    const { data: years } = useQuery({
+        queryKey: ['academic-years'],
+        queryFn: async () => (await api.get('/academic/years')).data as AcademicYear[],
+    });
+
+    const { data: report, isLoading } = useQuery({
+        queryKey: ['teacher-workload', semesterId],
+        queryFn: async () => (await api.get('/academic/workload', { params })).data as WorkloadReport,
+    });
+
+    const handleExport = async () => {
+        const res = await api.get('/academic/workload/export', { params, responseType: 'blob' });
+        const url = URL.createObjectURL(res.data);
+        const link = document.createElement('a');
+        link.href = url;
+        link.download = 'beban-mengajar.csv';
+        link.click();
+        URL.revokeObjectURL(url);
+    };
+
+    const statusLabel = (status: string) => {
+        if (status === 'under') return `< ${report?.min_jp} JP`;
+        if (status === 'over') return `> ${report?.max_jp} JP`;
+        if (status === 'partial') return 'Guru unit lain';
+        return 'Sesuai';
+    };
+
+    return (
+        <div className="space-y-6">
+            <div className="flex justify-between items-center">
+                <div>
+                    <h1 className="text-3xl font-bold text-slate-900 mb-2">Beban Mengajar</h1>
+                    <p className="text-slate-600">Jam pelajaran (JP) per minggu untuk sertifikasi dan penggajian</p>
+                </div>
+                <ButtonGlass icon={Download} onClick={handleExport}>
+                    Ekspor CSV
+                </ButtonGlass>
+            </div>
+
+            <CardGlass className="p-6 space-y-4">
+                <div className="max-w-xs">
+                    <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Semester</label>
+                    <select className={selectClassName} value={semesterId} onChange={(e) => setSemesterId(e.target.value)}>
+                        <option value="" className="bg-white">Semester aktif</option>
+                        {years?.map((year) => year.semesters?.map((semester) => (
+                            <option key={semester.id} value={semester.id} className="bg-white">{year.name} - {semester.name}</option>
+                        )))}
+                    </select>
+                </div>
+
+                {isLoading ? (
+                    <p className="text-slate-500">Memuat...</p>
+                ) : (
+                    <TableGlass>
+                        <TableHeaderGlass>
+                            <TableRowGlass>
+                                <TableHeadGlass>Guru</TableHeadGlass>
+                                <TableHeadGlass>Mata Pelajaran</TableHeadGlass>
+                                <TableHeadGlass>Total JP</TableHeadGlass>
+                                <TableHeadGlass>Status</TableHeadGlass>
+                            </TableRowGlass>
+                        </TableHeaderGlass>
+                        <TableBodyGlass>
+                            {report?.teachers.map((load) => (
+                                <TableRowGlass key={load.teacher.id}>
+                                    <TableCellGlass>
+                                        <p className="font-medium">{load.teacher.user?.name}</p>
+                                        {load.teacher.nip && <p className="text-xs text-slate-500">NIP {load.teacher.nip}</p>}
+                                    </TableCellGlass>
+                                    <TableCellGlass>
+                                        {load.subjects.length === 0 && <span className="text-slate-400">-</span>}
+                                        <ul className="text-sm space-y-1">
+                                            {load.subjects.map((s) => (
+                                                <li key={s.subject.id}>{s.subject.name}: {s.jp} JP</li>
+                                            ))}
+                                        </ul>
+                                    </TableCellGlass>
+                                    <TableCellGlass className="font-bold">{load.jp}</TableCellGlass>
+                                    <TableCellGlass>
+                                        <span className={`px-2 py-1 rounded-full text-xs font-medium ${statusStyles[load.status]}`}>
+                                            {statusLabel(load.status)}
+                                        </span>
+                                    </TableCellGlass>
+                                </TableRowGlass>
+                            ))}
+                        </TableBodyGlass>
+                    </TableGlass>
+                )}
+            </CardGlass>
+        </div>
+    );
+};
+
+export default TeacherWorkloadReport;
@@ -0,0 +1,239 @@
+import React, { useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import api from '../../services/api';
+import { Plus, Trash2, Eye, Save } from 'lucide-react';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import InputGlass from '../../components/ui/glass/InputGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+
+interface Period {
+    start_time: string;
+    end_time: string;
+}
+
+interface Requirement {
+    class_id: string;
+    subject_id: string;
+    teacher_id: string;
+    hours_per_week: string;
+}
+
+interface TimetableResult {
+    dry_run: boolean;
+    schedules: { class_id: number; subject_id: number; teacher_id: string; day: string; start_time: string; end_time: string }[];
+    unplaced: { class_id: number; subject_id: number; teacher_id: string; hours: number }[];
+}
+
+const DAYS = ['Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday'];
+const dayLabels: Record<string, string> = {
+    Monday: 'Senin', Tuesday: 'Selasa', Wednesday: 'Rabu', Thursday: 'Kamis', Friday: 'Jumat', Saturday: 'Sabtu',
+};
+
+const selectClassName = 'w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500';
+const emptyRequirement: Requirement = { class_id: '', subject_id: '', teacher_id: '', hours_per_week: '2' };
+
+const TimetableGenerator: React.FC = () => {
+    const queryClient = useQueryClient();
+    const [days, setDays] = useState<string[]>(DAYS.slice(0, 5));
+    const [periods, setPeriods] = useState<Period[]>([{ start_time: '07:00', end_time: '07:40' }]);
+    // Use the unit's bell schedule instead of the periods entered here
+    const [useStoredPeriods, setUseStoredPeriods] = useState(true);
+    const [requirements, setRequirements] = useState<Requirement[]>([{ ...emptyRequirement }]);
+    const [maxTeacherHours, setMaxTeacherHours] = useState('24');
+    const [maxDailySubjectHours, setMaxDailySubjectHours] = useState('2');
+    const [result, setResult] = useState<TimetableResult | null>(null);
+
+    const { data: classes } = useQuery({
+        queryKey: ['academic_classes'],
+        queryFn: async () => (await api.get('/academic/classes')).data || [],
+    });
+    const { data: subjects } = useQuery({
+        queryKey: ['academic_subjects'],
+        queryFn: async () => (await api.get('/academic/subjects')).data || [],
+    });
+    const { data: teachers } = useQuery({
+        queryKey: ['teachers_list'],
+        queryFn: async () => (await api.get('/teachers')).data || [],
+    });
+
+    const nameOf = (list: any[] | undefined, id: number | string) => {
+        const item = list?.find((x: any) => String(x.id) === String(id));
+        return item?.user?.name || item?.name || id;
+    };
+
+    const buildPayload = (dryRun: boolean, allowPartial: boolean) => ({
+        dry_run: dryRun,
+        allow_partial: allowPartial,
+        periods: useStoredPeriods ? [] : days.flatMap((day) => periods.map((p) => ({ day, ...p }))),
+        requirements: requirements.map((r) => ({
+            class_id: Number(r.class_id),
+            subject_id: Number(r.subject_id),
+            teacher_id: r.teacher_id,
+            hours_per_week: Number(r.hours_per_week),
+        })),
+        max_teacher_hours: Number(maxTeacherHours) || 0,
+        max_daily_subject_hours: Number(maxDailySubjectHours) || 0,
+    });
+
+    const generateMutation = useMutation({
+        mutationFn: ({ dryRun, allowPartial = false }: { dryRun: boolean; allowPartial?: boolean }) =>
+            api.post('/academic/timetable', buildPayload(dryRun, allowPartial)),
+        onSuccess: (res) => {
+            const data = res.data as TimetableResult;
+            setResult(data);
+            if (!data.dry_run) {
+                queryClient.invalidateQueries({ queryKey: ['academic_schedules'] });
+                alert(`${data.schedules.length} jadwal berhasil disimpan`);
+            }
+        },
+        onError: (err: any) => {
+            alert('Gagal membuat jadwal: ' + (err.response?.data?.error || err.message));
+        },
+    });
+
+    const updatePeriod = (index: number, field: keyof Period, value: string) => {
+        setPeriods(periods.map((p, i) => (i === index ? { ...p, [field]: value } : p)));
+        setResult(null);
+    };
+
+    const updateRequirement = (index: number, field: keyof Requirement, value: string) => {
+        setRequirements(requirements.map((r, i) => (i === index ? { ...r, [field]: value } : r)));
+        setResult(null);
+    };
+
+    const toggleDay = (day: string) => {
+        setDays(days.includes(day) ? days.filter((d) => d !== day) : DAYS.filter((d) => d === day || days.includes(d)));
+        setResult(null);
+    };
+
+    const handleCommit = () => {
+        const unplacedHours = result?.unplaced.reduce((sum, u) => sum + u.hours, 0) || 0;
+        const message = unplacedHours > 0
+            ? `${unplacedHours} jam pelajaran belum terjadwal. Tetap simpan jadwal sebagian ini ke semester aktif?`
+            : 'Simpan jadwal ini ke semester aktif?';
+        if (confirm(message)) {
+            generateMutation.mutate({ dryRun: false, allowPartial: unplacedHours > 0 });
+        }
+    };
+
+    return (
+        <div className="space-y-6">
+            <div>
+                <h1 className="text-3xl font-bold text-slate-900 mb-2">Generator Jadwal</h1>
+                <p className="text-slate-600">Susun jadwal pelajaran mingguan secara otomatis tanpa bentrok</p>
+            </div>
+
+            <CardGlass className="p-6 space-y-4">
+                <h2 className="text-lg font-semibold text-slate-900">Hari dan Jam Pelajaran</h2>
+                <label className="flex items-center gap-2 text-sm text-slate-700">
+                    <input type="checkbox" checked={useStoredPeriods} onChange={(e) => { setUseStoredPeriods(e.target.checked); setResult(null); }} />
+                    Gunakan jam pelajaran sekolah yang tersimpan
+                </label>
+                {!useStoredPeriods && (
+                    <>
+                        <div className="flex flex-wrap gap-4">
+                            {DAYS.map((day) => (
+                                <label key={day} className="flex items-center gap-2 text-sm text-slate-700">
+                                    <input type="checkbox" checked={days.includes(day)} onChange={() => toggleDay(day)} />
+                                    {dayLabels[day]}
+                                </label>
+                            ))}
+                        </div>
+                        {periods.map((period, index) => (
+                            <div key={index} className="grid grid-cols-[auto_1fr_1fr_auto] gap-4 items-end">
+                                <span className="pb-3 text-sm text-slate-600">Jam ke-{index + 1}</span>
+                                <InputGlass label="Mulai" type="time" value={period.start_time} onChange={(e) => updatePeriod(index, 'start_time', e.target.value)} />
+                                <InputGlass label="Selesai" type="time" value={period.end_time} onChange={(e) => updatePeriod(index, 'end_time', e.target.value)} />
+                                <button onClick={() => setPeriods(periods.filter((_, i) => i !== index))} className="p-2 mb-1 hover:bg-slate-100 rounded-lg text-red-600 transition-colors">
+                                    <Trash2 size={16} />
+                                </button>
+                            </div>
+                        ))}
+                        <ButtonGlass variant="secondary" icon={Plus} onClick={() => setPeriods([...periods, { start_time: periods[periods.length - 1]?.end_time || '07:00', end_time: '' }])}>
+                            Jam Pelajaran
+                        </ButtonGlass>
+                    </>
+                )}
+            </CardGlass>
+
+            <CardGlass className="p-6 space-y-4">
+                <h2 className="text-lg font-semibold text-slate-900">Kebutuhan Jam per Kelas</h2>
+                {requirements.map((req, index) => (
+                    <div key={index} className="grid grid-cols-[1fr_1fr_1fr_100px_auto] gap-4 items-center">
+                        <select className={selectClassName} value={req.class_id} onChange={(e) => updateRequirement(index, 'class_id', e.target.value)}>
+                            <option value="" className="bg-white">Pilih Kelas</option>
+                            {classes?.map((c: any) => <option key={c.id} value={c.id} className="bg-white">{c.name}</option>)}
+                        </select>
+                        <select className={selectClassName} value={req.subject_id} onChange={(e) => updateRequirement(index, 'subject_id', e.target.value)}>
+                            <option value="" className="bg-white">Pilih Mapel</option>
+                            {subjects?.map((s: any) => <option key={s.id} value={s.id} className="bg-white">{s.name}</option>)}
+                        </select>
+                        <select className={selectClassName} value={req.teacher_id} onChange={(e) => updateRequirement(index, 'teacher_id', e.target.value)}>
+                            <option value="" className="bg-white">Pilih Guru</option>
+                            {teachers?.map((t: any) => <option key={t.id} value={t.id} className="bg-white">{t.user?.name}</option>)}
+                        </select>
+                        <InputGlass type="number" min={1} value={req.hours_per_week} onChange={(e) => updateRequirement(index, 'hours_per_week', e.target.value)} />
+                        <button onClick={() => setRequirements(requirements.filter((_, i) => i !== index))} className="p-2 hover:bg-slate-100 rounded-lg text-red-600 transition-colors">
+                            <Trash2 size={16} />
+                        </button>
+                    </div>
+                ))}
+                <ButtonGlass variant="secondary" icon={Plus} onClick={() => setRequirements([...requirements, { ...emptyRequirement }])}>
+                    Kebutuhan
+                </ButtonGlass>
+                <div className="grid grid-cols-2 gap-4">
+                    <InputGlass label="Maks. jam mengajar guru per minggu" type="number" value={maxTeacherHours} onChange={(e) => setMaxTeacherHours(e.target.value)} />
+                    <InputGlass label="Maks. jam mapel per kelas per hari" type="number" value={maxDailySubjectHours} onChange={(e) => setMaxDailySubjectHours(e.target.value)} />
+                </div>
+                <div className="flex justify-end gap-3">
+                    <ButtonGlass variant="secondary" icon={Eye} onClick={() => generateMutation.mutate({ dryRun: true })}>
+                        Pratinjau
+                    </ButtonGlass>
+                    <ButtonGlass icon={Save} onClick={handleCommit} disabled={!result?.dry_run}>
+                        Simpan Jadwal
+                    </ButtonGlass>
+                </div>
+            </CardGlass>
+
+            {result && (
+                <CardGlass className="p-6 space-y-4">
+                    {result.unplaced.length > 0 && (
+                        <div className="p-4 rounded-xl bg-amber-50 text-amber-800 text-sm">
+                            Tidak semua jam dapat dijadwalkan:
+                            <ul className="list-disc ml-5">
+                                {result.unplaced.map((u, i) => (
+                                    <li key={i}>{nameOf(classes, u.class_id)} - {nameOf(subjects, u.subject_id)}: {u.hours} jam</li>
+                                ))}
+                            </ul>
+                        </div>
+                    )}
+                    <TableGlass>
+                        <TableHeaderGlass>
+                            <TableRowGlass>
+                                <TableHeadGlass>Kelas</TableHeadGlass>
+                                <TableHeadGlass>Hari</TableHeadGlass>
+                                <TableHeadGlass>Jam</TableHeadGlass>
+                                <TableHeadGlass>Mapel</TableHeadGlass>
+                                <TableHeadGlass>Guru</TableHeadGlass>
+                            </TableRowGlass>
+                        </TableHeaderGlass>
+                        <TableBodyGlass>
+                            {result.schedules.map((s, i) => (
+                                <TableRowGlass key={i}>
+                                    <TableCellGlass>{nameOf(classes, s.class_id)}</TableCellGlass>
+                                    <TableCellGlass>{dayLabels[s.day] || s.day}</TableCellGlass>
+                                    <TableCellGlass>{s.start_time} - {s.end_time}</TableCellGlass>
+                                    <TableCellGlass>{nameOf(subjects, s.subject_id)}</TableCellGlass>
+                                    <TableCellGlass>{nameOf(teachers, s.teacher_id)}</TableCellGlass>
+                                </TableRowGlass>
+                            ))}
+                        </TableBodyGlass>
+                    </TableGlass>
+                </CardGlass>
+            )}
+        </div>
+    );
+};
+
+export default TimetableGenerator;
@@ -1,7 +1,7 @@
 import React, { useState } from 'react';
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import api from '../../services/api';
-import { Plus, Trash2, User as UserIcon, Mail, Lock, Shield, School, Edit2, CreditCard, BookOpen } from 'lucide-react';
+import { Plus, Trash2, User as UserIcon, Mail, Lock, Shield, School, Edit2, CreditCard, BookOpen, Eye } from 'lucide-react';
 import CardGlass from '../../components/ui/glass/CardGlass';
 import ButtonGlass from '../../components/ui/glass/ButtonGlass';
 import InputGlass from '../../components/ui/glass/InputGlass';
@@ -23,11 +23,13 @@ interface Class {
 }
 
 const UserManagement: React.FC = () => {
-    const { user } = useAuth();
+    const { user, startImpersonation } = useAuth();
     const queryClient = useQueryClient();
     const [isModalOpen, setIsModalOpen] = useState(false);
     const [editingUser, setEditingUser] = useState<User | null>(null);
 
+    const currentUserIsSuperAdmin = user?.role_id === 1;
+
     // Initialize unit_id based on user role
     const initialUnitId = user?.role_id === 1 ? 1 : user?.unit_id || 1;
 
@@ -129,6 +131,17 @@ const UserManagement: React.FC = () => {
         onSuccess: () => queryClient.invalidateQueries({ queryKey: ['users'] }),
     });
 
+    const impersonateMutation = useMutation({
+        mutationFn: (id: string) => api.post(`/admin/impersonate/${id}`),
+        onSuccess: (response) => {
+            startImpersonation(response.data.token);
+            window.location.href = '/dashboard';
+        },
+        onError: (error: any) => {
+            alert(error.response?.data?.error || 'Gagal masuk sebagai user');
+        }
+    });
+
     const handleCloseModal = () => {
         setIsModalOpen(false);
         setEditingUser(null);
@@ -267,6 +280,16 @@ const UserManagement: React.FC = () => {
                                     </TableCellGlass>
                                     <TableCellGlass className="text-right">
                                         <div className="flex justify-end gap-2">
+                                            {currentUserIsSuperAdmin && user.role_id !== 1 && (
+                                                <ButtonGlass
+                                                    variant="secondary"
+                                                    onClick={() => impersonateMutation.mutate(user.id)}
+                                                    className="p-2"
+                                                    title="Lihat sebagai user ini"
+                                                >
+                                                    <Eye size={16} />
+                                                </ButtonGlass>
+                                            )}
                                             <ButtonGlass
                                                 variant="secondary"
                                                 onClick={() => handleEdit(user)}
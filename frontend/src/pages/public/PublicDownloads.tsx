@@ -14,6 +14,11 @@ interface DownloadItem {
     created_at: string;
 }
 
+const calendarFeeds = [
+    { unitId: 1, title: 'Kalender Akademik MTs' },
+    { unitId: 2, title: 'Kalender Akademik MA' },
+];
+
 const PublicDownloads: React.FC = () => {
     const { data: downloads, isLoading } = useQuery({
         queryKey: ['public-downloads'],
@@ -26,7 +31,6 @@ const PublicDownloads: React.FC = () => {
     const getIcon = (category: string) => {
         switch (category.toLowerCase()) {
             case 'brosur': return <FileText size={24} className="text-blue-500" />;
-            case 'kalender': return <Calendar size={24} className="text-green-500" />;
             default: return <FileText size={24} className="text-slate-400" />;
         }
     };
@@ -66,6 +70,28 @@ const PublicDownloads: React.FC = () => {
                     <div className="text-center text-slate-600">Loading...</div>
                 ) : (
                     <div className="space-y-4">
+                        {calendarFeeds.map((feed) => (
+                            <CardGlass key={feed.unitId} className="p-6 flex items-center justify-between gap-4">
+                                <div className="flex items-center gap-4">
+                                    <div className="p-3 bg-slate-100 rounded-xl">
+                                        <Calendar size={24} className="text-green-500" />
+                                    </div>
+                                    <div>
+                                        <h3 className="text-lg font-bold text-slate-900">{feed.title}</h3>
+                                        <p className="text-sm text-slate-500 mt-1">
+                                            Selalu terbaru · tambahkan ke Google Calendar, Outlook atau kalender ponsel
+                                        </p>
+                                    </div>
+                                </div>
+                                <a href={`/api/public/calendar.ics?unit_id=${feed.unitId}`}>
+                                    <ButtonGlass variant="secondary" className="flex items-center gap-2">
+                                        <Download size={18} />
+                                        <span className="hidden sm:inline">.ics</span>
+                                    </ButtonGlass>
+                                </a>
+                            </CardGlass>
+                        ))}
+
                         {downloads?.map((item: DownloadItem, idx: number) => (
                             <motion.div
                                 key={item.id}
@@ -17,16 +17,7 @@ interface BKCall {
 const StudentBK: React.FC = () => {
     const { user } = useAuth();
 
-    // Fetch student profile to get student_id
-    const { data: students } = useQuery({
-        queryKey: ['students'],
-        queryFn: async () => {
-            const response = await api.get('/students/');
-            return response.data;
-        }
-    });
-
-    const currentStudent = students?.find((s: any) => s.user.id === user?.id);
+    const currentStudent = user?.student;
 
     const { data: calls, isLoading } = useQuery({
         queryKey: ['my-bk-calls', currentStudent?.id],
@@ -32,15 +32,7 @@ const StudentElearning: React.FC = () => {
     const [submissionFile, setSubmissionFile] = useState('');
 
 
-    // Fetch Student Profile to get Class ID
-    const { data: students } = useQuery({
-        queryKey: ['students'],
-        queryFn: async () => {
-            const res = await api.get('/students/');
-            return res.data;
-        }
-    });
-    const currentStudent = students?.find((s: any) => s.user.id === user?.id);
+    const currentStudent = user?.student;
 
     // Fetch Materials
     const { data: materials, isLoading: isLoadingMaterials } = useQuery({
@@ -0,0 +1,336 @@
+import React, { useMemo, useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import api from '../../services/api';
+import { Plus, Trash2, RotateCcw } from 'lucide-react';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import InputGlass from '../../components/ui/glass/InputGlass';
+import ModalGlass from '../../components/ui/glass/ModalGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+import ObjectiveChecklist from '../../components/gradebook/ObjectiveChecklist';
+import { gradeComponents, gradeComponentLabels, LearningObjective } from '../../lib/gradebook';
+
+interface Schedule {
+    class_id: number;
+    subject_id: number;
+    class: { id: number; name: string };
+    subject: { id: number; name: string };
+}
+
+interface Student {
+    id: string;
+    nisn: string;
+    class_id: number;
+    user: { name: string };
+}
+
+interface ScoreAttempt {
+    id: string;
+    kind: 'remedial' | 'pengayaan';
+    value: number;
+    note: string;
+}
+
+interface Score {
+    id: string;
+    student_id: string;
+    component: string;
+    title: string;
+    value: number;
+    kkm: number;
+    final: number;
+    below_kkm: boolean;
+    attempts?: ScoreAttempt[];
+    learning_objectives?: LearningObjective[];
+}
+
+interface AttemptForm {
+    score: Score;
+    kind: 'remedial' | 'pengayaan';
+    value: string;
+    note: string;
+}
+
+interface AssessmentForm {
+    component: string;
+    title: string;
+    objectiveIds: number[];
+    values: Record<string, string>;
+}
+
+const selectClassName = 'w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500';
+
+const Gradebook: React.FC = () => {
+    const queryClient = useQueryClient();
+    const [lessonKey, setLessonKey] = useState('');
+    const [form, setForm] = useState<AssessmentForm | null>(null);
+    const [attemptForm, setAttemptForm] = useState<AttemptForm | null>(null);
+
+    // Class and subject pairs come from the caller's own lessons; admins see all
+    const { data: schedules } = useQuery({
+        queryKey: ['gradebook-lessons'],
+        queryFn: async () => (await api.get('/academic/schedules')).data as Schedule[],
+    });
+
+    const lessons = useMemo(() => {
+        const unique = new Map<string, Schedule>();
+        schedules?.forEach((s) => unique.set(`${s.class_id}-${s.subject_id}`, s));
+        return [...unique.entries()].sort(([, a], [, b]) => `${a.class.name} ${a.subject.name}`.localeCompare(`${b.class.name} ${b.subject.name}`));
+    }, [schedules]);
+    const lesson = lessons.find(([key]) => key === lessonKey)?.[1];
+
+    const { data: allStudents } = useQuery({
+        queryKey: ['students'],
+        queryFn: async () => (await api.get('/students/')).data as Student[],
+    });
+    const students = (allStudents || [])
+        .filter((s) => s.class_id === lesson?.class_id)
+        .sort((a, b) => a.user.name.localeCompare(b.user.name));
+
+    const { data: scores } = useQuery({
+        queryKey: ['scores', lessonKey],
+        queryFn: async () => (await api.get('/academic/scores', { params: { class_id: lesson?.class_id, subject_id: lesson?.subject_id } })).data as Score[],
+        enabled: !!lesson,
+    });
+
+    // One column per assessment, ordered by component
+    const assessments = useMemo(() => {
+        const unique = new Map<string, { component: string; title: string }>();
+        scores?.forEach((s) => unique.set(`${s.component}|${s.title}`, { component: s.component, title: s.title }));
+        return [...unique.values()].sort((a, b) =>
+            gradeComponents.indexOf(a.component as typeof gradeComponents[number]) - gradeComponents.indexOf(b.component as typeof gradeComponents[number])
+            || a.title.localeCompare(b.title));
+    }, [scores]);
+
+    const scoreOf = (studentId: string, component: string, title: string) =>
+        scores?.find((s) => s.student_id === studentId && s.component === component && s.title === title);
+
+    const onError = (err: any) => alert('Gagal menyimpan: ' + (err.response?.data?.error || err.message));
+
+    const saveMutation = useMutation({
+        mutationFn: (assessment: AssessmentForm) => api.post('/academic/scores', {
+            class_id: lesson?.class_id,
+            subject_id: lesson?.subject_id,
+            component: assessment.component,
+            title: assessment.title,
+            learning_objective_ids: assessment.objectiveIds,
+            scores: Object.entries(assessment.values)
+                .filter(([, value]) => value !== '')
+                .map(([studentId, value]) => ({ student_id: studentId, value: parseFloat(value) })),
+        }),
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['scores', lessonKey] });
+            setForm(null);
+        },
+        onError,
+    });
+
+    const deleteMutation = useMutation({
+        mutationFn: (id: string) => api.delete(`/academic/scores/${id}`),
+        onSuccess: () => queryClient.invalidateQueries({ queryKey: ['scores', lessonKey] }),
+        onError,
+    });
+
+    const attemptMutation = useMutation({
+        mutationFn: (attempt: AttemptForm) => api.post(`/academic/scores/${attempt.score.id}/attempts`, {
+            kind: attempt.kind,
+            value: parseFloat(attempt.value),
+            note: attempt.note,
+        }),
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['scores', lessonKey] });
+            setAttemptForm(null);
+        },
+        onError,
+    });
+
+    const deleteAttemptMutation = useMutation({
+        mutationFn: (id: string) => api.delete(`/academic/scores/attempts/${id}`),
+        onSuccess: () => queryClient.invalidateQueries({ queryKey: ['scores', lessonKey] }),
+        onError,
+    });
+
+    const openAssessment = (component: string, title: string) => {
+        const values: Record<string, string> = {};
+        students.forEach((s) => {
+            const score = scoreOf(s.id, component, title);
+            values[s.id] = score ? String(score.value) : '';
+        });
+        const tagged = scores?.find((s) => s.component === component && s.title === title)?.learning_objectives || [];
+        setForm({ component, title, objectiveIds: tagged.map((o) => o.id), values });
+    };
+
+    const handleSubmit = (e: React.FormEvent) => {
+        e.preventDefault();
+        if (form) saveMutation.mutate(form);
+    };
+
+    return (
+        <div className="space-y-6">
+            <div className="flex justify-between items-center">
+                <div>
+                    <h1 className="text-3xl font-bold text-slate-900 mb-2">Buku Nilai</h1>
+                    <p className="text-slate-600">Nilai ulangan, PTS, PAS dan praktik per mata pelajaran</p>
+                </div>
+                <ButtonGlass icon={Plus} onClick={() => openAssessment('uh', '')} disabled={!lesson}>
+                    Tambah Penilaian
+                </ButtonGlass>
+            </div>
+
+            <CardGlass className="p-6 space-y-4">
+                <div className="max-w-sm">
+                    <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Kelas dan Mata Pelajaran</label>
+                    <select className={selectClassName} value={lessonKey} onChange={(e) => setLessonKey(e.target.value)}>
+                        <option value="" className="bg-white">Pilih...</option>
+                        {lessons.map(([key, s]) => (
+                            <option key={key} value={key} className="bg-white">{s.class.name} - {s.subject.name}</option>
+                        ))}
+                    </select>
+                </div>
+
+                {lesson && (
+                    <TableGlass>
+                        <TableHeaderGlass>
+                            <TableRowGlass>
+                                <TableHeadGlass>Siswa</TableHeadGlass>
+                                {assessments.map((a) => (
+                                    <TableHeadGlass key={`${a.component}|${a.title}`}>
+                                        <button className="text-left hover:text-purple-600" onClick={() => openAssessment(a.component, a.title)}>
+                                            <span className="block text-xs text-slate-500">{gradeComponentLabels[a.component]}</span>
+                                            {a.title}
+                                        </button>
+                                    </TableHeadGlass>
+                                ))}
+                            </TableRowGlass>
+                        </TableHeaderGlass>
+                        <TableBodyGlass>
+                            {students.map((student) => (
+                                <TableRowGlass key={student.id}>
+                                    <TableCellGlass>
+                                        <p className="font-medium">{student.user.name}</p>
+                                        <p className="text-xs text-slate-500">{student.nisn}</p>
+                                    </TableCellGlass>
+                                    {assessments.map((a) => {
+                                        const score = scoreOf(student.id, a.component, a.title);
+                                        return (
+                                            <TableCellGlass key={`${a.component}|${a.title}`}>
+                                                {score ? (
+                                                    <span className="inline-flex items-center gap-2">
+                                                        <span className={score.below_kkm ? 'text-red-600 font-medium' : ''} title={`KKM ${score.kkm}`}>
+                                                            {score.final !== score.value ? `${score.value} → ${score.final}` : score.value}
+                                                        </span>
+                                                        <button
+                                                            onClick={() => setAttemptForm({ score, kind: score.value < score.kkm ? 'remedial' : 'pengayaan', value: '', note: '' })}
+                                                            className="text-purple-600 hover:text-purple-500"
+                                                            title={score.value < score.kkm ? 'Remedial' : 'Pengayaan'}
+                                                        >
+                                                            <RotateCcw size={12} />
+                                                        </button>
+                                                        <button
+                                                            onClick={() => confirm('Hapus nilai ini?') && deleteMutation.mutate(score.id)}
+                                                            className="text-red-600 hover:text-red-500"
+                                                        >
+                                                            <Trash2 size={12} />
+                                                        </button>
+                                                    </span>
+                                                ) : <span className="text-slate-400">-</span>}
+                                            </TableCellGlass>
+                                        );
+                                    })}
+                                </TableRowGlass>
+                            ))}
+                        </TableBodyGlass>
+                    </TableGlass>
+                )}
+                {lesson && assessments.length === 0 && <p className="text-center text-slate-500">Belum ada penilaian</p>}
+                <p className="text-xs text-slate-500">
+                    Nilai merah masih di bawah KKM. Nilai tugas e-learning yang sudah dinilai otomatis masuk komponen Tugas Harian.
+                </p>
+            </CardGlass>
+
+            <ModalGlass isOpen={form !== null} onClose={() => setForm(null)} title="Input Nilai Penilaian">
+                {form && (
+                    <form onSubmit={handleSubmit} className="space-y-4">
+                        <div className="grid grid-cols-2 gap-4">
+                            <div>
+                                <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Komponen</label>
+                                <select className={selectClassName} value={form.component} onChange={(e) => setForm({ ...form, component: e.target.value })}>
+                                    {gradeComponents.map((c) => (
+                                        <option key={c} value={c} className="bg-white">{gradeComponentLabels[c]}</option>
+                                    ))}
+                                </select>
+                            </div>
+                            <InputGlass label="Judul" placeholder="UH 1" value={form.title} onChange={(e) => setForm({ ...form, title: e.target.value })} required />
+                        </div>
+                        <ObjectiveChecklist
+                            subjectId={lesson?.subject_id}
+                            value={form.objectiveIds}
+                            onChange={(objectiveIds) => setForm({ ...form, objectiveIds })}
+                        />
+                        <div className="max-h-96 overflow-y-auto space-y-2">
+                            {students.map((student) => (
+                                <div key={student.id} className="flex items-center justify-between gap-4">
+                                    <span className="text-sm text-slate-700">{student.user.name}</span>
+                                    <input
+                                        type="number"
+                                        min={0}
+                                        max={100}
+                                        step="0.01"
+                                        className="glass-input w-24"
+                                        value={form.values[student.id] ?? ''}
+                                        onChange={(e) => setForm({ ...form, values: { ...form.values, [student.id]: e.target.value } })}
+                                    />
+                                </div>
+                            ))}
+                        </div>
+                        <div className="flex justify-end">
+                            <ButtonGlass type="submit" disabled={saveMutation.isPending}>Simpan</ButtonGlass>
+                        </div>
+                    </form>
+                )}
+            </ModalGlass>
+
+            <ModalGlass isOpen={attemptForm !== null} onClose={() => setAttemptForm(null)} title={attemptForm?.kind === 'remedial' ? 'Remedial' : 'Pengayaan'}>
+                {attemptForm && (
+                    <form onSubmit={(e) => { e.preventDefault(); attemptMutation.mutate(attemptForm); }} className="space-y-4">
+                        <p className="text-sm text-slate-600">
+                            {attemptForm.score.title}: nilai {attemptForm.score.value}, KKM {attemptForm.score.kkm}
+                        </p>
+                        {attemptForm.score.attempts && attemptForm.score.attempts.length > 0 && (
+                            <ul className="text-sm space-y-1">
+                                {attemptForm.score.attempts.map((a) => (
+                                    <li key={a.id} className="flex items-center justify-between">
+                                        <span>{a.kind === 'remedial' ? 'Remedial' : 'Pengayaan'}: {a.value}{a.note && ` (${a.note})`}</span>
+                                        <button
+                                            type="button"
+                                            onClick={() => confirm('Hapus percobaan ini?') && deleteAttemptMutation.mutate(a.id, { onSuccess: () => setAttemptForm(null) })}
+                                            className="text-red-600 hover:text-red-500"
+                                        >
+                                            <Trash2 size={14} />
+                                        </button>
+                                    </li>
+                                ))}
+                            </ul>
+                        )}
+                        <InputGlass
+                            label="Nilai"
+                            type="number"
+                            min={0}
+                            max={100}
+                            step="0.01"
+                            value={attemptForm.value}
+                            onChange={(e) => setAttemptForm({ ...attemptForm, value: e.target.value })}
+                            required
+                        />
+                        <InputGlass label="Catatan" value={attemptForm.note} onChange={(e) => setAttemptForm({ ...attemptForm, note: e.target.value })} />
+                        <div className="flex justify-end">
+                            <ButtonGlass type="submit" disabled={attemptMutation.isPending}>Simpan</ButtonGlass>
+                        </div>
+                    </form>
+                )}
+            </ModalGlass>
+        </div>
+    );
+};
+
+export default Gradebook;
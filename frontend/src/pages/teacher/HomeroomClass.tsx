@@ -3,7 +3,9 @@ import { useQuery } from '@tanstack/react-query';
 import api from '../../services/api';
 import CardGlass from '../../components/ui/glass/CardGlass';
 import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
-import { Users, Phone } from 'lucide-react';
+import { Users, Phone, Download, NotebookPen, Sprout } from 'lucide-react';
+import { useNavigate } from 'react-router-dom';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
 import { useAuth } from '../../context/AuthContext';
 
 interface Student {
@@ -22,6 +24,7 @@ interface Student {
 
 const HomeroomClass: React.FC = () => {
     const { user } = useAuth();
+    const navigate = useNavigate();
 
     // Fetch Homeroom Class
     const { data: myClass, isLoading: isLoadingClass } = useQuery({
@@ -54,6 +57,16 @@ const HomeroomClass: React.FC = () => {
         enabled: !!myClass?.id
     });
 
+    const handleDownloadRapors = async () => {
+        const res = await api.get(`/academic/classes/${myClass.id}/report-cards`, { responseType: 'blob' });
+        const url = URL.createObjectURL(res.data);
+        const link = document.createElement('a');
+        link.href = url;
+        link.download = `rapor-${myClass.name}.zip`;
+        link.click();
+        URL.revokeObjectURL(url);
+    };
+
     if (!user?.teacher) {
         return <div className="text-slate-900 p-6">Data Guru tidak ditemukan.</div>;
     }
@@ -73,9 +86,22 @@ const HomeroomClass: React.FC = () => {
 
     return (
         <div className="space-y-6">
-            <div>
-                <h1 className="text-2xl font-bold text-slate-900">Kelas Saya: {myClass.name}</h1>
-                <p className="text-slate-600">Daftar siswa di kelas perwalian Anda</p>
+            <div className="flex justify-between items-center">
+                <div>
+                    <h1 className="text-2xl font-bold text-slate-900">Kelas Saya: {myClass.name}</h1>
+                    <p className="text-slate-600">Daftar siswa di kelas perwalian Anda</p>
+                </div>
+                <div className="flex gap-2">
+                    <ButtonGlass variant="secondary" icon={NotebookPen} onClick={() => navigate('/dashboard/homeroom/notes')}>
+                        Catatan & Sikap
+                    </ButtonGlass>
+                    <ButtonGlass variant="secondary" icon={Sprout} onClick={() => navigate('/dashboard/homeroom/p5')}>
+                        Projek P5
+                    </ButtonGlass>
+                    <ButtonGlass icon={Download} onClick={handleDownloadRapors}>
+                        Unduh Semua Rapor (ZIP)
+                    </ButtonGlass>
+                </div>
             </div>
 
             <div className="grid grid-cols-1 md:grid-cols-3 gap-6">
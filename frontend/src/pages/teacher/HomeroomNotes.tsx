@@ -0,0 +1,158 @@
+import React, { useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { useNavigate } from 'react-router-dom';
+import api from '../../services/api';
+import { ArrowLeft, Edit } from 'lucide-react';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import ModalGlass from '../../components/ui/glass/ModalGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+import { attitudeGrades, attitudeLabels, HomeroomNote, HomeroomStudent, useHomeroomClass } from '../../lib/homeroom';
+
+interface NoteForm {
+    student: HomeroomStudent;
+    noteId?: number;
+    note: string;
+    spiritual_grade: string;
+    spiritual_description: string;
+    social_grade: string;
+    social_description: string;
+}
+
+const selectClassName = 'w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500';
+const textareaClassName = 'w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500 min-h-[80px]';
+
+const HomeroomNotes: React.FC = () => {
+    const navigate = useNavigate();
+    const queryClient = useQueryClient();
+    const { myClass, students, isLoading } = useHomeroomClass();
+    const [form, setForm] = useState<NoteForm | null>(null);
+
+    const { data } = useQuery({
+        queryKey: ['homeroom-notes', myClass?.id],
+        queryFn: async () => (await api.get(`/academic/classes/${myClass.id}/homeroom-notes`)).data as {
+            semester: { name: string };
+            notes: HomeroomNote[];
+        },
+        enabled: !!myClass?.id,
+    });
+
+    const saveMutation = useMutation({
+        mutationFn: ({ student, noteId, ...body }: NoteForm) =>
+            noteId
+                ? api.put(`/academic/homeroom-notes/${noteId}`, body)
+                : api.post('/academic/homeroom-notes', { student_id: student.id, ...body }),
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['homeroom-notes', myClass?.id] });
+            setForm(null);
+        },
+        onError: (err: any) => alert('Gagal menyimpan: ' + (err.response?.data?.error || err.message)),
+    });
+
+    const openForm = (student: HomeroomStudent) => {
+        const note = data?.notes.find((n) => n.student_id === student.id);
+        setForm({
+            student,
+            noteId: note?.id,
+            note: note?.note || '',
+            spiritual_grade: note?.spiritual_grade || '',
+            spiritual_description: note?.spiritual_description || '',
+            social_grade: note?.social_grade || '',
+            social_description: note?.social_description || '',
+        });
+    };
+
+    if (isLoading) {
+        return <div className="text-slate-900 p-6">Loading data kelas...</div>;
+    }
+    if (!myClass) {
+        return <div className="text-slate-900 p-6">Anda belum ditugaskan sebagai Wali Kelas.</div>;
+    }
+
+    return (
+        <div className="space-y-6">
+            <div className="flex items-center gap-4">
+                <button onClick={() => navigate('/dashboard/homeroom')} className="p-2 hover:bg-slate-100 rounded-full text-slate-600">
+                    <ArrowLeft size={20} />
+                </button>
+                <div>
+                    <h1 className="text-2xl font-bold text-slate-900">Catatan & Sikap: {myClass.name}</h1>
+                    <p className="text-slate-600">Semester {data?.semester.name || '-'} — dicetak di rapor tiap siswa</p>
+                </div>
+            </div>
+
+            <CardGlass className="p-6">
+                <TableGlass>
+                    <TableHeaderGlass>
+                        <TableRowGlass>
+                            <TableHeadGlass>Nama Siswa</TableHeadGlass>
+                            <TableHeadGlass>Sikap Spiritual</TableHeadGlass>
+                            <TableHeadGlass>Sikap Sosial</TableHeadGlass>
+                            <TableHeadGlass>Catatan Wali Kelas</TableHeadGlass>
+                            <TableHeadGlass className="text-right">Aksi</TableHeadGlass>
+                        </TableRowGlass>
+                    </TableHeaderGlass>
+                    <TableBodyGlass>
+                        {students?.map((s) => {
+                            const note = data?.notes.find((n) => n.student_id === s.id);
+                            return (
+                                <TableRowGlass key={s.id}>
+                                    <TableCellGlass className="font-medium">{s.user.name}</TableCellGlass>
+                                    <TableCellGlass>{attitudeLabels[note?.spiritual_grade || ''] || '-'}</TableCellGlass>
+                                    <TableCellGlass>{attitudeLabels[note?.social_grade || ''] || '-'}</TableCellGlass>
+                                    <TableCellGlass className="max-w-md truncate text-slate-600">{note?.note || '-'}</TableCellGlass>
+                                    <TableCellGlass className="text-right">
+                                        <button onClick={() => openForm(s)} className="text-purple-600 hover:text-purple-500">
+                                            <Edit size={16} />
+                                        </button>
+                                    </TableCellGlass>
+                                </TableRowGlass>
+                            );
+                        })}
+                    </TableBodyGlass>
+                </TableGlass>
+            </CardGlass>
+
+            <ModalGlass isOpen={form !== null} onClose={() => setForm(null)} title={`Catatan ${form?.student.user.name || ''}`}>
+                {form && (
+                    <form onSubmit={(e) => { e.preventDefault(); saveMutation.mutate(form); }} className="space-y-4">
+                        {([['spiritual', 'Sikap Spiritual'], ['social', 'Sikap Sosial']] as const).map(([key, label]) => (
+                            <div key={key} className="space-y-2">
+                                <label className="block text-sm font-medium text-slate-700 ml-1">{label}</label>
+                                <select
+                                    className={selectClassName}
+                                    value={form[`${key}_grade`]}
+                                    onChange={(e) => setForm({ ...form, [`${key}_grade`]: e.target.value })}
+                                >
+                                    <option value="" className="bg-white">Belum dinilai</option>
+                                    {attitudeGrades.map((g) => (
+                                        <option key={g} value={g} className="bg-white">{attitudeLabels[g]}</option>
+                                    ))}
+                                </select>
+                                <textarea
+                                    className={textareaClassName}
+                                    placeholder="Deskripsi sikap"
+                                    value={form[`${key}_description`]}
+                                    onChange={(e) => setForm({ ...form, [`${key}_description`]: e.target.value })}
+                                />
+                            </div>
+                        ))}
+                        <div className="space-y-2">
+                            <label className="block text-sm font-medium text-slate-700 ml-1">Catatan Wali Kelas</label>
+                            <textarea
+                                className={textareaClassName}
+                                value={form.note}
+                                onChange={(e) => setForm({ ...form, note: e.target.value })}
+                            />
+                        </div>
+                        <div className="flex justify-end">
+                            <ButtonGlass type="submit" disabled={saveMutation.isPending}>Simpan</ButtonGlass>
+                        </div>
+                    </form>
+                )}
+            </ModalGlass>
+        </div>
+    );
+};
+
+export default HomeroomNotes;
@@ -4,11 +4,25 @@ import { useQuery } from '@tanstack/react-query';
 import api from '../../services/api';
 import CardGlass from '../../components/ui/glass/CardGlass';
 import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
-import { ArrowLeft, Printer } from 'lucide-react';
+import { ArrowLeft, Printer, Download } from 'lucide-react';
+import { gradeComponentLabels, ObjectiveMastery } from '../../lib/gradebook';
+
+interface ComponentGrade {
+    component: string;
+    weight: number;
+    average: number | null;
+    count: number;
+    below_kkm: number;
+}
 
 interface SubjectGrade {
     subject_name: string;
     average: number;
+    kkm: number;
+    passed: boolean;
+    components: ComponentGrade[];
+    objectives: ObjectiveMastery[];
+    description: string;
 }
 
 const HomeroomReportCards: React.FC = () => {
@@ -29,6 +43,16 @@ const HomeroomReportCards: React.FC = () => {
         window.print();
     };
 
+    const handleDownload = async () => {
+        const res = await api.get(`/academic/report-cards/${studentId}/pdf`, { responseType: 'blob' });
+        const url = URL.createObjectURL(res.data);
+        const link = document.createElement('a');
+        link.href = url;
+        link.download = `rapor-${studentId}.pdf`;
+        link.click();
+        URL.revokeObjectURL(url);
+    };
+
     return (
         <div className="space-y-6">
             <style>
@@ -59,13 +83,22 @@ const HomeroomReportCards: React.FC = () => {
                         <p className="text-slate-600">Laporan Hasil Belajar</p>
                     </div>
                 </div>
-                <button
-                    onClick={handlePrint}
-                    className="flex items-center gap-2 px-4 py-2 bg-green-600 hover:bg-green-500 text-slate-900 rounded-xl transition-colors"
-                >
-                    <Printer size={20} />
-                    <span>Cetak Rapor</span>
-                </button>
+                <div className="flex items-center gap-2">
+                    <button
+                        onClick={handleDownload}
+                        className="flex items-center gap-2 px-4 py-2 bg-blue-600 hover:bg-blue-500 text-white rounded-xl transition-colors"
+                    >
+                        <Download size={20} />
+                        <span>Unduh PDF</span>
+                    </button>
+                    <button
+                        onClick={handlePrint}
+                        className="flex items-center gap-2 px-4 py-2 bg-green-600 hover:bg-green-500 text-slate-900 rounded-xl transition-colors"
+                    >
+                        <Printer size={20} />
+                        <span>Cetak Rapor</span>
+                    </button>
+                </div>
             </div>
 
             <div id="report-card-content">
@@ -82,28 +115,58 @@ const HomeroomReportCards: React.FC = () => {
                         <TableHeaderGlass>
                             <TableRowGlass className="print:border-b print:border-black">
                                 <TableHeadGlass className="print:text-black print:font-bold">Mata Pelajaran</TableHeadGlass>
-                                <TableHeadGlass className="print:text-black print:font-bold">Nilai Rata-rata</TableHeadGlass>
+                                <TableHeadGlass className="print:text-black print:font-bold">Komponen</TableHeadGlass>
+                                <TableHeadGlass className="print:text-black print:font-bold">Nilai Akhir</TableHeadGlass>
+                                <TableHeadGlass className="print:text-black print:font-bold">KKM</TableHeadGlass>
                                 <TableHeadGlass className="print:text-black print:font-bold">Predikat</TableHeadGlass>
                             </TableRowGlass>
                         </TableHeaderGlass>
                         <TableBodyGlass>
                             {isLoading ? (
                                 <TableRowGlass>
-                                    <TableCellGlass colSpan={3} className="text-center py-8 text-slate-600 print:text-black">Loading...</TableCellGlass>
+                                    <TableCellGlass colSpan={5} className="text-center py-8 text-slate-600 print:text-black">Loading...</TableCellGlass>
                                 </TableRowGlass>
                             ) : reportCard?.length === 0 ? (
                                 <TableRowGlass>
-                                    <TableCellGlass colSpan={3} className="text-center py-8 text-slate-600 print:text-black">Belum ada data nilai.</TableCellGlass>
+                                    <TableCellGlass colSpan={5} className="text-center py-8 text-slate-600 print:text-black">Belum ada data nilai.</TableCellGlass>
                                 </TableRowGlass>
                             ) : (
                                 reportCard?.map((grade, index) => (
                                     <TableRowGlass key={index} className="print:border-b print:border-gray-200">
                                         <TableCellGlass>
                                             <span className="font-medium text-slate-900 print:text-black">{grade.subject_name}</span>
+                                            {grade.description && (
+                                                <p className="mt-1 max-w-xs text-xs text-slate-600 print:text-black">{grade.description}</p>
+                                            )}
+                                        </TableCellGlass>
+                                        <TableCellGlass>
+                                            <ul className="text-xs text-slate-600 print:text-black space-y-0.5">
+                                                {grade.components.filter((c) => c.average !== null).map((c) => (
+                                                    <li key={c.component}>
+                                                        {gradeComponentLabels[c.component]} ({c.weight}%): {c.average?.toFixed(2)}
+                                                        {c.below_kkm > 0 && <span className="text-red-600 print:text-black"> · {c.below_kkm} di bawah KKM</span>}
+                                                    </li>
+                                                ))}
+                                            </ul>
+                                            {grade.objectives.some((o) => o.average !== null) && (
+                                                <ul className="mt-2 text-xs text-slate-600 print:hidden space-y-0.5">
+                                                    {grade.objectives.filter((o) => o.average !== null).map((o) => (
+                                                        <li key={o.id} title={o.description}>
+                                                            {o.code || o.description}: <span className={o.achieved ? '' : 'text-red-600'}>{o.average?.toFixed(2)}</span>
+                                                        </li>
+                                                    ))}
+                                                </ul>
+                                            )}
                                         </TableCellGlass>
                                         <TableCellGlass>
                                             <span className="text-slate-900 font-bold print:text-black">{grade.average.toFixed(2)}</span>
                                         </TableCellGlass>
+                                        <TableCellGlass>
+                                            <span className="text-slate-900 print:text-black">{grade.kkm}</span>
+                                            <span className={`block text-xs font-medium print:text-black ${grade.passed ? 'text-green-600' : 'text-red-600'}`}>
+                                                {grade.passed ? 'Tuntas' : 'Belum Tuntas'}
+                                            </span>
+                                        </TableCellGlass>
                                         <TableCellGlass>
                                             <span className={`px-2 py-1 rounded text-xs font-bold print:border print:border-black print:bg-transparent print:text-black ${grade.average >= 90 ? 'bg-green-100 text-green-600' :
                                                 grade.average >= 80 ? 'bg-blue-100 text-blue-600' :
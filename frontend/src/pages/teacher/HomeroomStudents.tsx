@@ -235,7 +235,7 @@ const HomeroomStudents: React.FC = () => {
                         type="email"
                         value={formData.email}
                         onChange={(e) => setFormData({ ...formData, email: e.target.value })}
-                        required
+                        disabled
                     />
                     <InputGlass
                         label="NISN"
@@ -249,7 +249,7 @@ const HomeroomStudents: React.FC = () => {
                             value={formData.class_id}
                             onChange={(e) => setFormData({ ...formData, class_id: e.target.value })}
                             className="w-full glass-input"
-                            required
+                            disabled
                         >
                             <option value="" className="bg-gray-900">-- Pilih Kelas --</option>
                             {classes?.map((c: any) => (
@@ -264,7 +264,7 @@ const HomeroomStudents: React.FC = () => {
                             value={formData.unit_id}
                             onChange={(e) => setFormData({ ...formData, unit_id: Number(e.target.value) })}
                             className="w-full glass-input"
-                            required
+                            disabled
                         >
                             <option value={1} className="bg-gray-900">MTS</option>
                             <option value={2} className="bg-gray-900">MA</option>
@@ -277,6 +277,7 @@ const HomeroomStudents: React.FC = () => {
                             value={formData.parent_id}
                             onChange={(e) => setFormData({ ...formData, parent_id: e.target.value })}
                             className="w-full glass-input"
+                            disabled
                         >
                             <option value="" className="bg-gray-900">-- Pilih Orang Tua (Opsional) --</option>
                             {parents?.map((p: any) => (
@@ -285,6 +286,8 @@ const HomeroomStudents: React.FC = () => {
                         </select>
                     </div>
 
+                    <p className="text-xs text-slate-500">Email, kelas dan orang tua hanya dapat diubah oleh admin.</p>
+
                     <div className="flex justify-end gap-3 pt-4">
                         <ButtonGlass type="button" variant="ghost" onClick={() => setIsModalOpen(false)}>
                             Batal
@@ -0,0 +1,164 @@
+import React, { useMemo, useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import api from '../../services/api';
+import { Plus, Edit, Trash2 } from 'lucide-react';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import InputGlass from '../../components/ui/glass/InputGlass';
+import ModalGlass from '../../components/ui/glass/ModalGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+import { LearningObjective } from '../../lib/gradebook';
+
+interface Schedule {
+    subject: { id: number; name: string };
+}
+
+interface ObjectiveForm {
+    id?: number;
+    code: string;
+    description: string;
+    position: string;
+}
+
+const selectClassName = 'w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500';
+
+const LearningObjectives: React.FC = () => {
+    const queryClient = useQueryClient();
+    const [subjectId, setSubjectId] = useState('');
+    const [form, setForm] = useState<ObjectiveForm | null>(null);
+
+    // Subjects come from the caller's own lessons; admins see all
+    const { data: schedules } = useQuery({
+        queryKey: ['gradebook-lessons'],
+        queryFn: async () => (await api.get('/academic/schedules')).data as Schedule[],
+    });
+
+    const subjects = useMemo(() => {
+        const unique = new Map<number, string>();
+        schedules?.forEach((s) => unique.set(s.subject.id, s.subject.name));
+        return [...unique.entries()].sort(([, a], [, b]) => a.localeCompare(b));
+    }, [schedules]);
+
+    const { data: objectives, isLoading } = useQuery({
+        queryKey: ['learning-objectives', subjectId],
+        queryFn: async () => (await api.get(`/academic/subjects/${subjectId}/objectives`)).data as LearningObjective[],
+        enabled: !!subjectId,
+    });
+
+    const onSuccess = () => {
+        queryClient.invalidateQueries({ queryKey: ['learning-objectives', subjectId] });
+        setForm(null);
+    };
+    const onError = (err: any) => alert('Gagal menyimpan: ' + (err.response?.data?.error || err.message));
+
+    const saveMutation = useMutation({
+        mutationFn: (objective: ObjectiveForm) => {
+            const body = { code: objective.code, description: objective.description, position: Number(objective.position) || 0 };
+            return objective.id
+                ? api.put(`/academic/objectives/${objective.id}`, body)
+                : api.post(`/academic/subjects/${subjectId}/objectives`, body);
+        },
+        onSuccess,
+        onError,
+    });
+
+    const deleteMutation = useMutation({
+        mutationFn: (id: number) => api.delete(`/academic/objectives/${id}`),
+        onSuccess,
+        onError,
+    });
+
+    const openNew = () => {
+        const next = (objectives?.length || 0) + 1;
+        setForm({ code: `TP ${next}`, description: '', position: String(next) });
+    };
+
+    return (
+        <div className="space-y-6">
+            <div className="flex justify-between items-center">
+                <div>
+                    <h1 className="text-3xl font-bold text-slate-900 mb-2">Tujuan Pembelajaran</h1>
+                    <p className="text-slate-600">Capaian kompetensi di rapor ditulis dari penguasaan tiap tujuan pembelajaran</p>
+                </div>
+                <ButtonGlass icon={Plus} onClick={openNew} disabled={!subjectId}>
+                    Tambah TP
+                </ButtonGlass>
+            </div>
+
+            <CardGlass className="p-6 space-y-4">
+                <div className="max-w-sm">
+                    <label className="block text-sm font-medium text-slate-700 mb-1 ml-1">Mata Pelajaran</label>
+                    <select className={selectClassName} value={subjectId} onChange={(e) => setSubjectId(e.target.value)}>
+                        <option value="" className="bg-white">Pilih...</option>
+                        {subjects.map(([id, name]) => (
+                            <option key={id} value={id} className="bg-white">{name}</option>
+                        ))}
+                    </select>
+                </div>
+
+                {subjectId && (isLoading ? (
+                    <p className="text-slate-500">Memuat...</p>
+                ) : objectives?.length === 0 ? (
+                    <p className="text-center text-slate-500">Belum ada tujuan pembelajaran</p>
+                ) : (
+                    <TableGlass>
+                        <TableHeaderGlass>
+                            <TableRowGlass>
+                                <TableHeadGlass>Kode</TableHeadGlass>
+                                <TableHeadGlass>Tujuan Pembelajaran</TableHeadGlass>
+                                <TableHeadGlass className="text-right">Aksi</TableHeadGlass>
+                            </TableRowGlass>
+                        </TableHeaderGlass>
+                        <TableBodyGlass>
+                            {objectives?.map((o) => (
+                                <TableRowGlass key={o.id}>
+                                    <TableCellGlass className="font-medium">{o.code || '-'}</TableCellGlass>
+                                    <TableCellGlass>{o.description}</TableCellGlass>
+                                    <TableCellGlass className="text-right">
+                                        <div className="flex justify-end gap-2">
+                                            <button
+                                                onClick={() => setForm({ id: o.id, code: o.code, description: o.description, position: String(o.position) })}
+                                                className="text-purple-600 hover:text-purple-500"
+                                            >
+                                                <Edit size={16} />
+                                            </button>
+                                            <button
+                                                onClick={() => confirm('Hapus tujuan pembelajaran ini? Tandanya pada tugas dan penilaian ikut terhapus.') && deleteMutation.mutate(o.id)}
+                                                className="text-red-600 hover:text-red-500"
+                                            >
+                                                <Trash2 size={16} />
+                                            </button>
+                                        </div>
+                                    </TableCellGlass>
+                                </TableRowGlass>
+                            ))}
+                        </TableBodyGlass>
+                    </TableGlass>
+                ))}
+            </CardGlass>
+
+            <ModalGlass isOpen={form !== null} onClose={() => setForm(null)} title={form?.id ? 'Edit Tujuan Pembelajaran' : 'Tambah Tujuan Pembelajaran'}>
+                {form && (
+                    <form onSubmit={(e) => { e.preventDefault(); saveMutation.mutate(form); }} className="space-y-4">
+                        <div className="grid grid-cols-2 gap-4">
+                            <InputGlass label="Kode" placeholder="TP 1" value={form.code} onChange={(e) => setForm({ ...form, code: e.target.value })} />
+                            <InputGlass label="Urutan" type="number" value={form.position} onChange={(e) => setForm({ ...form, position: e.target.value })} />
+                        </div>
+                        <InputGlass
+                            label="Deskripsi"
+                            placeholder="Menganalisis struktur teks eksposisi"
+                            value={form.description}
+                            onChange={(e) => setForm({ ...form, description: e.target.value })}
+                            required
+                        />
+                        <div className="flex justify-end">
+                            <ButtonGlass type="submit" disabled={saveMutation.isPending}>Simpan</ButtonGlass>
+                        </div>
+                    </form>
+                )}
+            </ModalGlass>
+        </div>
+    );
+};
+
+export default LearningObjectives;
@@ -0,0 +1,224 @@
+import React, { useEffect, useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { useNavigate } from 'react-router-dom';
+import api from '../../services/api';
+import { ArrowLeft, Plus, Edit, Trash2, Save } from 'lucide-react';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import ButtonGlass from '../../components/ui/glass/ButtonGlass';
+import InputGlass from '../../components/ui/glass/InputGlass';
+import ModalGlass from '../../components/ui/glass/ModalGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+import { p5Dimensions, p5DimensionLabels, p5Levels, p5LevelLabels, P5Project, useHomeroomClass } from '../../lib/homeroom';
+
+interface ProjectForm {
+    id?: number;
+    theme: string;
+    title: string;
+    description: string;
+}
+
+// Levels keyed by `${studentId}|${dimension}`
+type LevelGrid = Record<string, string>;
+
+const P5Projects: React.FC = () => {
+    const navigate = useNavigate();
+    const queryClient = useQueryClient();
+    const { myClass, students, isLoading } = useHomeroomClass();
+    const [form, setForm] = useState<ProjectForm | null>(null);
+    const [selectedId, setSelectedId] = useState<number | null>(null);
+    const [levels, setLevels] = useState<LevelGrid>({});
+
+    const { data: projects } = useQuery({
+        queryKey: ['p5-projects', myClass?.id],
+        queryFn: async () => (await api.get(`/academic/classes/${myClass.id}/p5-projects`)).data as P5Project[],
+        enabled: !!myClass?.id,
+    });
+
+    const selected = projects?.find((p) => p.id === selectedId);
+
+    useEffect(() => {
+        const grid: LevelGrid = {};
+        selected?.assessments?.forEach((a) => { grid[`${a.student_id}|${a.dimension}`] = a.level; });
+        setLevels(grid);
+    }, [selected]);
+
+    const onError = (err: any) => alert('Gagal menyimpan: ' + (err.response?.data?.error || err.message));
+    const refresh = () => queryClient.invalidateQueries({ queryKey: ['p5-projects', myClass?.id] });
+
+    const saveMutation = useMutation({
+        mutationFn: ({ id, ...body }: ProjectForm) =>
+            id ? api.put(`/academic/p5-projects/${id}`, body) : api.post('/academic/p5-projects', { ...body, class_id: myClass.id }),
+        onSuccess: () => {
+            refresh();
+            setForm(null);
+        },
+        onError,
+    });
+
+    const deleteMutation = useMutation({
+        mutationFn: (id: number) => api.delete(`/academic/p5-projects/${id}`),
+        onSuccess: (_, id) => {
+            refresh();
+            if (id === selectedId) setSelectedId(null);
+        },
+        onError,
+    });
+
+    const assessMutation = useMutation({
+        mutationFn: () => {
+            const assessments = Object.entries(levels)
+                .filter(([, level]) => level)
+                .map(([key, level]) => {
+                    const [student_id, dimension] = key.split('|');
+                    return { student_id, dimension, level };
+                });
+            return api.put(`/academic/p5-projects/${selectedId}/assessments`, { assessments });
+        },
+        onSuccess: () => {
+            refresh();
+            alert('Penilaian P5 tersimpan');
+        },
+        onError,
+    });
+
+    if (isLoading) {
+        return <div className="text-slate-900 p-6">Loading data kelas...</div>;
+    }
+    if (!myClass) {
+        return <div className="text-slate-900 p-6">Anda belum ditugaskan sebagai Wali Kelas.</div>;
+    }
+
+    return (
+        <div className="space-y-6">
+            <div className="flex justify-between items-center">
+                <div className="flex items-center gap-4">
+                    <button onClick={() => navigate('/dashboard/homeroom')} className="p-2 hover:bg-slate-100 rounded-full text-slate-600">
+                        <ArrowLeft size={20} />
+                    </button>
+                    <div>
+                        <h1 className="text-2xl font-bold text-slate-900">Projek P5: {myClass.name}</h1>
+                        <p className="text-slate-600">Projek Penguatan Profil Pelajar Pancasila semester aktif</p>
+                    </div>
+                </div>
+                <ButtonGlass icon={Plus} onClick={() => setForm({ theme: '', title: '', description: '' })}>
+                    Tambah Projek
+                </ButtonGlass>
+            </div>
+
+            <CardGlass className="p-6">
+                {projects?.length === 0 ? (
+                    <p className="text-center text-slate-500">Belum ada projek P5</p>
+                ) : (
+                    <TableGlass>
+                        <TableHeaderGlass>
+                            <TableRowGlass>
+                                <TableHeadGlass>Tema</TableHeadGlass>
+                                <TableHeadGlass>Judul</TableHeadGlass>
+                                <TableHeadGlass className="text-right">Aksi</TableHeadGlass>
+                            </TableRowGlass>
+                        </TableHeaderGlass>
+                        <TableBodyGlass>
+                            {projects?.map((p) => (
+                                <TableRowGlass key={p.id} className={p.id === selectedId ? 'bg-purple-50' : ''}>
+                                    <TableCellGlass>{p.theme || '-'}</TableCellGlass>
+                                    <TableCellGlass className="font-medium">{p.title}</TableCellGlass>
+                                    <TableCellGlass className="text-right">
+                                        <div className="flex justify-end gap-2">
+                                            <button onClick={() => setSelectedId(p.id)} className="text-sm text-green-600 hover:text-green-500 font-medium">
+                                                Nilai
+                                            </button>
+                                            <button
+                                                onClick={() => setForm({ id: p.id, theme: p.theme, title: p.title, description: p.description })}
+                                                className="text-purple-600 hover:text-purple-500"
+                                            >
+                                                <Edit size={16} />
+                                            </button>
+                                            <button
+                                                onClick={() => confirm('Hapus projek ini beserta penilaiannya?') && deleteMutation.mutate(p.id)}
+                                                className="text-red-600 hover:text-red-500"
+                                            >
+                                                <Trash2 size={16} />
+                                            </button>
+                                        </div>
+                                    </TableCellGlass>
+                                </TableRowGlass>
+                            ))}
+                        </TableBodyGlass>
+                    </TableGlass>
+                )}
+            </CardGlass>
+
+            {selected && (
+                <CardGlass className="p-6 space-y-4">
+                    <div className="flex justify-between items-center">
+                        <h3 className="text-lg font-bold text-slate-900">Penilaian: {selected.title}</h3>
+                        <ButtonGlass icon={Save} onClick={() => assessMutation.mutate()} disabled={assessMutation.isPending}>
+                            Simpan Penilaian
+                        </ButtonGlass>
+                    </div>
+                    <p className="text-sm text-slate-500">
+                        {p5Levels.map((l) => `${l} = ${p5LevelLabels[l]}`).join(' · ')}
+                    </p>
+                    <div className="overflow-x-auto">
+                        <TableGlass>
+                            <TableHeaderGlass>
+                                <TableRowGlass>
+                                    <TableHeadGlass>Nama Siswa</TableHeadGlass>
+                                    {p5Dimensions.map((d) => (
+                                        <TableHeadGlass key={d} className="text-center">{p5DimensionLabels[d]}</TableHeadGlass>
+                                    ))}
+                                </TableRowGlass>
+                            </TableHeaderGlass>
+                            <TableBodyGlass>
+                                {students?.map((s) => (
+                                    <TableRowGlass key={s.id}>
+                                        <TableCellGlass className="font-medium whitespace-nowrap">{s.user.name}</TableCellGlass>
+                                        {p5Dimensions.map((d) => {
+                                            const key = `${s.id}|${d}`;
+                                            return (
+                                                <TableCellGlass key={d} className="text-center">
+                                                    <select
+                                                        className="glass-input text-slate-900 bg-white/50 border-slate-200 py-1"
+                                                        value={levels[key] || ''}
+                                                        onChange={(e) => setLevels({ ...levels, [key]: e.target.value })}
+                                                    >
+                                                        <option value="" className="bg-white">-</option>
+                                                        {p5Levels.map((l) => (
+                                                            <option key={l} value={l} className="bg-white">{l}</option>
+                                                        ))}
+                                                    </select>
+                                                </TableCellGlass>
+                                            );
+                                        })}
+                                    </TableRowGlass>
+                                ))}
+                            </TableBodyGlass>
+                        </TableGlass>
+                    </div>
+                </CardGlass>
+            )}
+
+            <ModalGlass isOpen={form !== null} onClose={() => setForm(null)} title={form?.id ? 'Edit Projek P5' : 'Tambah Projek P5'}>
+                {form && (
+                    <form onSubmit={(e) => { e.preventDefault(); saveMutation.mutate(form); }} className="space-y-4">
+                        <InputGlass label="Tema" placeholder="Gaya Hidup Berkelanjutan" value={form.theme} onChange={(e) => setForm({ ...form, theme: e.target.value })} />
+                        <InputGlass label="Judul" value={form.title} onChange={(e) => setForm({ ...form, title: e.target.value })} required />
+                        <div className="space-y-2">
+                            <label className="block text-sm font-medium text-slate-700 ml-1">Deskripsi</label>
+                            <textarea
+                                className="w-full glass-input text-slate-900 bg-white/50 border-slate-200 focus:border-purple-500 min-h-[80px]"
+                                value={form.description}
+                                onChange={(e) => setForm({ ...form, description: e.target.value })}
+                            />
+                        </div>
+                        <div className="flex justify-end">
+                            <ButtonGlass type="submit" disabled={saveMutation.isPending}>Simpan</ButtonGlass>
+                        </div>
+                    </form>
+                )}
+            </ModalGlass>
+        </div>
+    );
+};
+
+export default P5Projects;
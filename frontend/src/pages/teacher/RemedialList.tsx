@@ -0,0 +1,79 @@
+import React from 'react';
+import { useQuery } from '@tanstack/react-query';
+import api from '../../services/api';
+import CardGlass from '../../components/ui/glass/CardGlass';
+import { TableGlass, TableHeaderGlass, TableBodyGlass, TableRowGlass, TableHeadGlass, TableCellGlass } from '../../components/ui/glass/TableGlass';
+import { gradeComponentLabels } from '../../lib/gradebook';
+
+interface RemedialNeed {
+    student: { id: string; nisn: string; user: { name: string }; class: { name: string } };
+    subject: { id: number; name: string };
+    kkm: number;
+    scores: { id: string; component: string; title: string; value: number; final: number }[];
+    tasks: { submission_id: string; title: string; grade: number }[];
+}
+
+const RemedialList: React.FC = () => {
+    const { data: needs, isLoading } = useQuery({
+        queryKey: ['remedial-list'],
+        queryFn: async () => (await api.get('/academic/report-cards/remedial')).data as RemedialNeed[],
+    });
+
+    return (
+        <div className="space-y-6">
+            <div>
+                <h1 className="text-3xl font-bold text-slate-900 mb-2">Daftar Remedial</h1>
+                <p className="text-slate-600">Siswa dengan nilai di bawah KKM semester ini</p>
+            </div>
+
+            <CardGlass className="p-6">
+                {isLoading ? (
+                    <p className="text-slate-500">Memuat...</p>
+                ) : needs?.length === 0 ? (
+                    <p className="text-center text-slate-500">Semua nilai sudah tuntas</p>
+                ) : (
+                    <TableGlass>
+                        <TableHeaderGlass>
+                            <TableRowGlass>
+                                <TableHeadGlass>Siswa</TableHeadGlass>
+                                <TableHeadGlass>Kelas</TableHeadGlass>
+                                <TableHeadGlass>Mata Pelajaran</TableHeadGlass>
+                                <TableHeadGlass>KKM</TableHeadGlass>
+                                <TableHeadGlass>Nilai Belum Tuntas</TableHeadGlass>
+                            </TableRowGlass>
+                        </TableHeaderGlass>
+                        <TableBodyGlass>
+                            {needs?.map((need) => (
+                                <TableRowGlass key={`${need.student.id}-${need.subject.id}`}>
+                                    <TableCellGlass>
+                                        <p className="font-medium">{need.student.user.name}</p>
+                                        <p className="text-xs text-slate-500">{need.student.nisn}</p>
+                                    </TableCellGlass>
+                                    <TableCellGlass>{need.student.class.name}</TableCellGlass>
+                                    <TableCellGlass>{need.subject.name}</TableCellGlass>
+                                    <TableCellGlass>{need.kkm}</TableCellGlass>
+                                    <TableCellGlass>
+                                        <ul className="text-sm space-y-1">
+                                            {need.scores.map((s) => (
+                                                <li key={s.id}>
+                                                    {gradeComponentLabels[s.component]} {s.title}: <span className="text-red-600 font-medium">{s.final}</span>
+                                                </li>
+                                            ))}
+                                            {need.tasks.map((t) => (
+                                                <li key={t.submission_id}>
+                                                    {gradeComponentLabels.tugas} {t.title}: <span className="text-red-600 font-medium">{t.grade}</span>
+                                                </li>
+                                            ))}
+                                        </ul>
+                                    </TableCellGlass>
+                                </TableRowGlass>
+                            ))}
+                        </TableBodyGlass>
+                    </TableGlass>
+                )}
+            </CardGlass>
+        </div>
+    );
+};
+
+export default RemedialList;
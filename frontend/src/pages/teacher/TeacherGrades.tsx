@@ -22,6 +22,7 @@ interface Submission {
     student: { user: { name: string }, nisn: string };
     file_url: string;
     grade: number;
+    graded_at?: string;
     created_at: string;
 }
 
@@ -189,7 +190,7 @@ const TeacherGrades: React.FC = () => {
                                                     </a>
                                                 </TableCellGlass>
                                                 <TableCellGlass>
-                                                    {sub.grade > 0 ? (
+                                                    {sub.graded_at ? (
                                                         <span className="text-green-600 font-bold">{sub.grade}</span>
                                                     ) : (
                                                         <span className="text-slate-500">-</span>
@@ -10,11 +10,19 @@ interface Schedule {
     id: number;
     class: { name: string };
     subject: { name: string };
+    teacher?: { user?: { name: string } };
     day: string;
     start_time: string;
     end_time: string;
 }
 
+interface Substitution {
+    id: number;
+    date: string;
+    reason: string;
+    schedule: Schedule;
+}
+
 const TeacherSchedule: React.FC = () => {
     // const { user } = useAuth();
 
@@ -54,6 +62,19 @@ const TeacherSchedule: React.FC = () => {
         }
     });
 
+    // Lessons covered as guru pengganti this coming week
+    const { data: substitutions } = useQuery({
+        queryKey: ['substitutions', 'teacher'],
+        queryFn: async () => {
+            const from = new Date();
+            const to = new Date(from.getTime() + 7 * 24 * 60 * 60 * 1000);
+            const response = await api.get('/academic/substitutions', {
+                params: { from: from.toLocaleDateString('en-CA'), to: to.toLocaleDateString('en-CA') },
+            });
+            return response.data as Substitution[];
+        }
+    });
+
     const days = ['Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday'];
     const dayNames: { [key: string]: string } = {
         'Monday': 'Senin', 'Tuesday': 'Selasa', 'Wednesday': 'Rabu',
@@ -67,6 +88,40 @@ const TeacherSchedule: React.FC = () => {
                 <p className="text-slate-600">Jadwal pelajaran Anda minggu ini</p>
             </div>
 
+            {substitutions && substitutions.length > 0 && (
+                <CardGlass className="p-6">
+                    <h3 className="text-xl font-bold text-slate-900 mb-4">Tugas Guru Pengganti</h3>
+                    <TableGlass>
+                        <TableHeaderGlass>
+                            <TableRowGlass>
+                                <TableHeadGlass>Tanggal</TableHeadGlass>
+                                <TableHeadGlass>Waktu</TableHeadGlass>
+                                <TableHeadGlass>Mata Pelajaran</TableHeadGlass>
+                                <TableHeadGlass>Kelas</TableHeadGlass>
+                                <TableHeadGlass>Menggantikan</TableHeadGlass>
+                                <TableHeadGlass>Aksi</TableHeadGlass>
+                            </TableRowGlass>
+                        </TableHeaderGlass>
+                        <TableBodyGlass>
+                            {substitutions.map((sub) => (
+                                <TableRowGlass key={sub.id}>
+                                    <TableCellGlass>{new Date(sub.date).toLocaleDateString('id-ID')}</TableCellGlass>
+                                    <TableCellGlass>{sub.schedule.start_time} - {sub.schedule.end_time}</TableCellGlass>
+                                    <TableCellGlass>{sub.schedule.subject.name}</TableCellGlass>
+                                    <TableCellGlass>{sub.schedule.class.name}</TableCellGlass>
+                                    <TableCellGlass>{sub.schedule.teacher?.user?.name}</TableCellGlass>
+                                    <TableCellGlass>
+                                        <a href={`/dashboard/teacher/attendance/${sub.schedule.id}`} className="text-purple-600 hover:text-purple-500 text-sm font-medium">
+                                            Absen
+                                        </a>
+                                    </TableCellGlass>
+                                </TableRowGlass>
+                            ))}
+                        </TableBodyGlass>
+                    </TableGlass>
+                </CardGlass>
+            )}
+
             <div className="grid gap-6">
                 {days.map(day => {
                     const daySchedules = schedules?.filter((s: Schedule) => s.day === day)
@@ -15,4 +15,63 @@ api.interceptors.request.use((config) => {
     return config;
 });
 
+// Share one refresh call between requests that fail at the same time
+let refreshing: Promise<string> | null = null;
+
+const refreshAccessToken = async (): Promise<string> => {
+    const refreshToken = localStorage.getItem('refresh_token');
+    if (!refreshToken) {
+        throw new Error('No refresh token');
+    }
+    const response = await axios.post('/api/auth/refresh', { refresh_token: refreshToken });
+    localStorage.setItem('token', response.data.token);
+    localStorage.setItem('refresh_token', response.data.refresh_token);
+    return response.data.token;
+};
+
+api.interceptors.response.use(
+    (response) => response,
+    async (error) => {
+        const original = error.config;
+        if (error.response?.data?.password_change_required && !window.location.pathname.endsWith('/settings')) {
+            // Admin-assigned password: the user must pick their own first
+            window.location.href = '/dashboard/settings';
+            return Promise.reject(error);
+        }
+        if (error.response?.data?.mfa_enrollment_required && !window.location.pathname.endsWith('/settings')) {
+            // Role requires 2FA: send the user to enroll first
+            window.location.href = '/dashboard/settings';
+            return Promise.reject(error);
+        }
+        if (error.response?.status !== 401 || !original || original._retry || original.url?.startsWith('/auth/')) {
+            return Promise.reject(error);
+        }
+        original._retry = true;
+
+        if (localStorage.getItem('admin_token')) {
+            // Impersonation tokens cannot be refreshed: fall back to the admin session
+            localStorage.setItem('token', localStorage.getItem('admin_token') || '');
+            localStorage.setItem('refresh_token', localStorage.getItem('admin_refresh_token') || '');
+            localStorage.removeItem('admin_token');
+            localStorage.removeItem('admin_refresh_token');
+            window.location.href = '/dashboard/users';
+            return Promise.reject(error);
+        }
+
+        try {
+            refreshing = refreshing ?? refreshAccessToken();
+            const token = await refreshing;
+            original.headers.Authorization = `Bearer ${token}`;
+            return api(original);
+        } catch (refreshError) {
+            localStorage.removeItem('token');
+            localStorage.removeItem('refresh_token');
+            window.location.href = '/login';
+            return Promise.reject(refreshError);
+        } finally {
+            refreshing = null;
+        }
+    }
+);
+
 export default api;